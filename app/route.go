@@ -52,12 +52,73 @@ func RegisterRoutes(router *mux.Router, validator *validation.Validate, config C
 		apiRouter.HandleFunc("/assets/by-address/{address}", middlewares.NewMiddleware(logger, config, userAssetController.GetUserAssetByAddress).ValidateAuthToken(utility.Permissions["GetUserAssets"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
 		apiRouter.HandleFunc("/assets/{assetId}/all-addresses", middlewares.NewMiddleware(logger, config, userAssetController.GetAllAssetAddresses).ValidateAuthToken(utility.Permissions["GetAssetAddress"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
 		apiRouter.HandleFunc("/assets/transactions/{reference}", middlewares.NewMiddleware(logger, config, userAssetController.GetTransaction).ValidateAuthToken(utility.Permissions["GetTransaction"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/assets/transactions/{reference}/travel-rule", middlewares.NewMiddleware(logger, config, userAssetController.GetTravelRuleRecord).ValidateAuthToken(utility.Permissions["GetTravelRule"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
 		apiRouter.HandleFunc("/assets/{assetId}/transactions", middlewares.NewMiddleware(logger, config, userAssetController.GetTransactionsByAssetId).ValidateAuthToken(utility.Permissions["GetTransaction"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
 		apiRouter.HandleFunc("/assets/transfer-external", middlewares.NewMiddleware(logger, config, userAssetController.ExternalTransfer).ValidateAuthToken(utility.Permissions["ExternalTransfer"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
 		apiRouter.HandleFunc("/assets/confirm-transaction", middlewares.NewMiddleware(logger, config, userAssetController.ConfirmTransaction).ValidateAuthToken(utility.Permissions["ConfirmTransaction"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
 		apiRouter.HandleFunc("/assets/process-transaction", middlewares.NewMiddleware(logger, config, userAssetController.ProcessTransactions).LogAPIRequests().Build()).Methods(http.MethodPost)
 		apiRouter.HandleFunc("/assets/process-batched-transactions", middlewares.NewMiddleware(logger, config, BatchController.ProcessBatchBTCTransactions).LogAPIRequests().Build()).Methods(http.MethodPost)
 		apiRouter.HandleFunc("/trigger-float-manager", middlewares.NewMiddleware(logger, config, userAssetController.TriggerFloat).ValidateAuthToken(utility.Permissions["TriggerFloat"]).LogAPIRequests().Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/float-simulations", middlewares.NewMiddleware(logger, config, userAssetController.SimulateFloat).ValidateAuthToken(utility.Permissions["SimulateFloat"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/float-params", middlewares.NewMiddleware(logger, config, userAssetController.GetFloatParams).ValidateAuthToken(utility.Permissions["ManageFloatParams"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/float-params", middlewares.NewMiddleware(logger, config, userAssetController.SaveFloatParams).ValidateAuthToken(utility.Permissions["ManageFloatParams"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPut)
+		apiRouter.HandleFunc("/float-params/{paramId}/versions", middlewares.NewMiddleware(logger, config, userAssetController.GetFloatParamVersions).ValidateAuthToken(utility.Permissions["ManageFloatParams"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/float-params/{paramId}/pause", middlewares.NewMiddleware(logger, config, userAssetController.PauseFloat).ValidateAuthToken(utility.Permissions["ManageFloatParams"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/float-params/{paramId}/resume", middlewares.NewMiddleware(logger, config, userAssetController.ResumeFloat).ValidateAuthToken(utility.Permissions["ManageFloatParams"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/assets/dust-report", middlewares.NewMiddleware(logger, config, userAssetController.GetDustReport).ValidateAuthToken(utility.Permissions["GetDustReport"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/assets/screening-holds", middlewares.NewMiddleware(logger, config, userAssetController.GetScreeningHolds).ValidateAuthToken(utility.Permissions["ReviewScreeningHold"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/assets/screening-holds/{holdId}/release", middlewares.NewMiddleware(logger, config, userAssetController.ReleaseScreeningHold).ValidateAuthToken(utility.Permissions["ReviewScreeningHold"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/assets/screening-holds/{holdId}/reject", middlewares.NewMiddleware(logger, config, userAssetController.RejectScreeningHold).ValidateAuthToken(utility.Permissions["ReviewScreeningHold"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/assets/address-pool", middlewares.NewMiddleware(logger, config, userAssetController.GetAddressPoolDepth).ValidateAuthToken(utility.Permissions["ManageAddressPool"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/trigger-address-pool-refill", middlewares.NewMiddleware(logger, config, userAssetController.TriggerAddressPoolRefill).ValidateAuthToken(utility.Permissions["ManageAddressPool"]).LogAPIRequests().Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/assets/{assetId}/rotate-address", middlewares.NewMiddleware(logger, config, userAssetController.RotateAssetAddress).ValidateAuthToken(utility.Permissions["ManageAddresses"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/assets/by-address/{address}/invalidate", middlewares.NewMiddleware(logger, config, userAssetController.InvalidateAddress).ValidateAuthToken(utility.Permissions["ManageAddresses"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/trigger-address-rotation", middlewares.NewMiddleware(logger, config, userAssetController.TriggerAddressRotation).ValidateAuthToken(utility.Permissions["ManageAddresses"]).LogAPIRequests().Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/assets/address-subscriptions", middlewares.NewMiddleware(logger, config, userAssetController.GetUnwatchedAddresses).ValidateAuthToken(utility.Permissions["ManageAddresses"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/trigger-subscription-reconciliation", middlewares.NewMiddleware(logger, config, userAssetController.TriggerSubscriptionReconciliation).ValidateAuthToken(utility.Permissions["ManageAddresses"]).LogAPIRequests().Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/memos/duplicates", middlewares.NewMiddleware(logger, config, userAssetController.GetDuplicateMemos).ValidateAuthToken(utility.Permissions["ManageAddresses"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/asset-config/denominations", middlewares.NewMiddleware(logger, config, userAssetController.GetAssetConfig).ValidateAuthToken(utility.Permissions["ManageAssetConfig"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/asset-config/denominations", middlewares.NewMiddleware(logger, config, userAssetController.CreateDenomination).ValidateAuthToken(utility.Permissions["ManageAssetConfig"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/asset-config/denominations/{assetSymbol}", middlewares.NewMiddleware(logger, config, userAssetController.UpdateDenomination).ValidateAuthToken(utility.Permissions["ManageAssetConfig"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPut)
+		apiRouter.HandleFunc("/asset-config/denominations/{assetSymbol}", middlewares.NewMiddleware(logger, config, userAssetController.DeleteDenomination).ValidateAuthToken(utility.Permissions["ManageAssetConfig"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodDelete)
+		apiRouter.HandleFunc("/asset-config/denominations/{assetSymbol}/overrides", middlewares.NewMiddleware(logger, config, userAssetController.GetAssetConfigOverrides).ValidateAuthToken(utility.Permissions["ManageAssetConfig"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/asset-config/denominations/{assetSymbol}/networks", middlewares.NewMiddleware(logger, config, userAssetController.CreateNetwork).ValidateAuthToken(utility.Permissions["ManageAssetConfig"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/asset-config/denominations/{assetSymbol}/networks/{network}", middlewares.NewMiddleware(logger, config, userAssetController.UpdateNetwork).ValidateAuthToken(utility.Permissions["ManageAssetConfig"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPut)
+		apiRouter.HandleFunc("/asset-config/denominations/{assetSymbol}/networks/{network}", middlewares.NewMiddleware(logger, config, userAssetController.DeleteNetwork).ValidateAuthToken(utility.Permissions["ManageAssetConfig"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodDelete)
+		apiRouter.HandleFunc("/asset-config/changes", middlewares.NewMiddleware(logger, config, userAssetController.GetAssetConfigChanges).ValidateAuthToken(utility.Permissions["ManageAssetConfig"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/maintenance-windows", middlewares.NewMiddleware(logger, config, userAssetController.GetMaintenanceStatus).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/maintenance-windows", middlewares.NewMiddleware(logger, config, userAssetController.CreateMaintenanceWindow).ValidateAuthToken(utility.Permissions["ManageMaintenance"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/maintenance-windows/{windowId}/cancel", middlewares.NewMiddleware(logger, config, userAssetController.CancelMaintenanceWindow).ValidateAuthToken(utility.Permissions["ManageMaintenance"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/sweep/plan", middlewares.NewMiddleware(logger, config, userAssetController.GetSweepPlan).ValidateAuthToken(utility.Permissions["GetSweepRuns"]).LogAPIRequests().Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/sweep-runs", middlewares.NewMiddleware(logger, config, userAssetController.GetSweepRuns).ValidateAuthToken(utility.Permissions["GetSweepRuns"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/sweep-runs/{runId}/items", middlewares.NewMiddleware(logger, config, userAssetController.GetSweepItems).ValidateAuthToken(utility.Permissions["GetSweepRuns"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/sweep-policies", middlewares.NewMiddleware(logger, config, userAssetController.GetSweepPolicies).ValidateAuthToken(utility.Permissions["ManageSweepPolicies"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/sweep-policies", middlewares.NewMiddleware(logger, config, userAssetController.SaveSweepPolicy).ValidateAuthToken(utility.Permissions["ManageSweepPolicies"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPut)
+		apiRouter.HandleFunc("/sweep-policies/{policyId}", middlewares.NewMiddleware(logger, config, userAssetController.DeleteSweepPolicy).ValidateAuthToken(utility.Permissions["ManageSweepPolicies"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodDelete)
+		apiRouter.HandleFunc("/batch-policies", middlewares.NewMiddleware(logger, config, userAssetController.GetBatchPolicies).ValidateAuthToken(utility.Permissions["ManageBatchPolicies"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/batch-policies", middlewares.NewMiddleware(logger, config, userAssetController.SaveBatchPolicy).ValidateAuthToken(utility.Permissions["ManageBatchPolicies"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPut)
+		apiRouter.HandleFunc("/batch-policies/{policyId}", middlewares.NewMiddleware(logger, config, userAssetController.DeleteBatchPolicy).ValidateAuthToken(utility.Permissions["ManageBatchPolicies"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodDelete)
+		apiRouter.HandleFunc("/batches", middlewares.NewMiddleware(logger, config, BatchController.GetBatches).ValidateAuthToken(utility.Permissions["ManageBatches"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/batches/{batchId}", middlewares.NewMiddleware(logger, config, BatchController.GetBatch).ValidateAuthToken(utility.Permissions["ManageBatches"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/batches/{batchId}/close", middlewares.NewMiddleware(logger, config, BatchController.CloseBatch).ValidateAuthToken(utility.Permissions["ManageBatches"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/batches/{batchId}/unbatch", middlewares.NewMiddleware(logger, config, BatchController.UnbatchTransactions).ValidateAuthToken(utility.Permissions["ManageBatches"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/batches/{batchId}/retry", middlewares.NewMiddleware(logger, config, BatchController.RetryBatch).ValidateAuthToken(utility.Permissions["ManageBatches"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/gas-station/fee-wallets", middlewares.NewMiddleware(logger, config, userAssetController.GetFeeWallets).ValidateAuthToken(utility.Permissions["ManageGasStation"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/gas-station/fee-wallets", middlewares.NewMiddleware(logger, config, userAssetController.SaveFeeWallet).ValidateAuthToken(utility.Permissions["ManageGasStation"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPut)
+		apiRouter.HandleFunc("/gas-station/fundings", middlewares.NewMiddleware(logger, config, userAssetController.GetGasFundings).ValidateAuthToken(utility.Permissions["ManageGasStation"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/cold-wallets", middlewares.NewMiddleware(logger, config, userAssetController.GetColdWallets).ValidateAuthToken(utility.Permissions["ManageColdWallets"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/cold-wallets", middlewares.NewMiddleware(logger, config, userAssetController.CreateColdWallet).ValidateAuthToken(utility.Permissions["ManageColdWallets"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/cold-wallets/{walletId}", middlewares.NewMiddleware(logger, config, userAssetController.UpdateColdWallet).ValidateAuthToken(utility.Permissions["ManageColdWallets"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPut)
+		apiRouter.HandleFunc("/cold-wallets/{walletId}/limits", middlewares.NewMiddleware(logger, config, userAssetController.UpdateColdWalletLimits).ValidateAuthToken(utility.Permissions["ManageColdTransferLimits"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPut)
+		apiRouter.HandleFunc("/cold-wallets/{walletId}/verify", middlewares.NewMiddleware(logger, config, userAssetController.VerifyColdWallet).ValidateAuthToken(utility.Permissions["ManageColdWallets"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/cold-transfers", middlewares.NewMiddleware(logger, config, userAssetController.GetColdTransfers).ValidateAuthToken(utility.Permissions["ManageColdWallets"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/cold-transfers/{transferId}/approve", middlewares.NewMiddleware(logger, config, userAssetController.ApproveColdTransfer).ValidateAuthToken(utility.Permissions["ApproveColdTransfers"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/cold-transfers/{transferId}/reject", middlewares.NewMiddleware(logger, config, userAssetController.RejectColdTransfer).ValidateAuthToken(utility.Permissions["ApproveColdTransfers"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/funding-requests", middlewares.NewMiddleware(logger, config, userAssetController.GetFundingRequests).ValidateAuthToken(utility.Permissions["ManageFundingRequests"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/funding-requests/acknowledge/{token}", middlewares.NewMiddleware(logger, config, userAssetController.ShowFundingRequestAcknowledgement).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/funding-requests/acknowledge/{token}", middlewares.NewMiddleware(logger, config, userAssetController.AcknowledgeFundingRequest).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodPost)
+		apiRouter.HandleFunc("/assets/{assetId}/payment-request", middlewares.NewMiddleware(logger, config, userAssetController.GetPaymentRequest).ValidateAuthToken(utility.Permissions["GetAssetAddress"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
+		apiRouter.HandleFunc("/assets/{assetId}/payment-request/qr", middlewares.NewMiddleware(logger, config, userAssetController.GetPaymentRequestQRCode).ValidateAuthToken(utility.Permissions["GetAssetAddress"]).LogAPIRequests().Timeout(requestTimeout).Build()).Methods(http.MethodGet)
 		apiRouter.HandleFunc("/assets/{assetId}/create-auxiliary-address", middlewares.NewMiddleware(logger, config, userAssetController.CreateAuxiliaryAddress).ValidateAuthToken(utility.Permissions["GetAssetAddress"]).LogAPIRequests().Build()).Methods(http.MethodPost)
 
 	})
@@ -46,11 +46,38 @@ type Data struct {
 	FloatPercentage           int           `mapstructure:"floatPercentage"  yaml:"floatPercentage,omitempty"`
 	EnableFloatManager        bool          `mapstructure:"enableFloatManager"  yaml:"enableFloatManager,omitempty"`
 	SweepCronInterval         string        `mapstructure:"sweepCronInterval"  yaml:"sweepCronInterval,omitempty"`
+	SweepWorkers              int           `mapstructure:"sweepWorkers"  yaml:"sweepWorkers,omitempty"`
+	SweepNetworkConcurrency   int           `mapstructure:"sweepNetworkConcurrency"  yaml:"sweepNetworkConcurrency,omitempty"`
+	SweepNetworkConcurrencyLimits map[string]int `mapstructure:"sweepNetworkConcurrencyLimits"  yaml:"sweepNetworkConcurrencyLimits,omitempty"`
+	SweepLockTTL              int64         `mapstructure:"sweepLockTtl"  yaml:"sweepLockTtl,omitempty"`
+	GasStationFeeBufferPercent int        `mapstructure:"gasStationFeeBufferPercent"  yaml:"gasStationFeeBufferPercent,omitempty"`
 	FloatCronInterval         string        `mapstructure:"floatCronInterval"  yaml:"floatCronInterval,omitempty"`
+	FloatForecastWindowDays   int           `mapstructure:"floatForecastWindowDays"  yaml:"floatForecastWindowDays,omitempty"`
+	FloatForecastHorizonHours int           `mapstructure:"floatForecastHorizonHours"  yaml:"floatForecastHorizonHours,omitempty"`
+	FundingRequestAckURL      string        `mapstructure:"fundingRequestAckUrl"  yaml:"fundingRequestAckUrl,omitempty"`
+	FundingRequestEscalationEmails string   `mapstructure:"fundingRequestEscalationEmails"  yaml:"fundingRequestEscalationEmails,omitempty"`
+	FundingRequestEscalationMinutes int     `mapstructure:"fundingRequestEscalationMinutes"  yaml:"fundingRequestEscalationMinutes,omitempty"`
+	FundingRequestExpiryHours int           `mapstructure:"fundingRequestExpiryHours"  yaml:"fundingRequestExpiryHours,omitempty"`
+	TreasuryAddresses         string        `mapstructure:"treasuryAddresses"  yaml:"treasuryAddresses,omitempty"`
+	AddressPoolCronInterval   string        `mapstructure:"addressPoolCronInterval"  yaml:"addressPoolCronInterval,omitempty"`
+	AddressRotationCronInterval string      `mapstructure:"addressRotationCronInterval"  yaml:"addressRotationCronInterval,omitempty"`
+	SubscriptionReconcilerCronInterval string `mapstructure:"subscriptionReconcilerCronInterval"  yaml:"subscriptionReconcilerCronInterval,omitempty"`
+	BatcherCronInterval       string        `mapstructure:"batcherCronInterval"  yaml:"batcherCronInterval,omitempty"`
+	SubscriptionBatchSize     int           `mapstructure:"subscriptionBatchSize"  yaml:"subscriptionBatchSize,omitempty"`
+	AssetSeedingMode          string        `mapstructure:"assetSeedingMode"  yaml:"assetSeedingMode,omitempty"`
+	AssetCataloguePath        string        `mapstructure:"assetCataloguePath"  yaml:"assetCataloguePath,omitempty"`
 	DBMigrationPath           string        `mapstructure:"dbMigrationPath"  yaml:"dbMigrationPath,omitempty"`
 	SentryDsn                 string        `mapstructure:"SENTRY_DSN"  yaml:"SENTRY_DSN,omitempty"`
 	SENTRY_ENVIRONMENT        string        `mapstructure:"SENTRY_ENVIRONMENT"  yaml:"SENTRY_ENVIRONMENT,omitempty"`
 	BinanceBrokerageServiceURL        string        `mapstructure:"binanceBrokerageServiceUrl"  yaml:"binanceBrokerageServiceUrl,omitempty"`
+	ScreeningProvider         string        `mapstructure:"screeningProvider"  yaml:"screeningProvider,omitempty"`
+	SanctionsListPath         string        `mapstructure:"sanctionsListPath"  yaml:"sanctionsListPath,omitempty"`
+	ScreeningServiceURL       string        `mapstructure:"screeningServiceUrl"  yaml:"screeningServiceUrl,omitempty"`
+	ScreeningServiceKey       string        `mapstructure:"SCREENING_SERVICE_KEY"  yaml:"SCREENING_SERVICE_KEY,omitempty"`
+	TravelRuleEncryptionKey   string        `mapstructure:"TRAVEL_RULE_ENCRYPTION_KEY"  yaml:"TRAVEL_RULE_ENCRYPTION_KEY,omitempty"`
+	TravelRuleFiatCurrency    string        `mapstructure:"travelRuleFiatCurrency"  yaml:"travelRuleFiatCurrency,omitempty"`
+	VaspName                  string        `mapstructure:"vaspName"  yaml:"vaspName,omitempty"`
+	VaspLEI                   string        `mapstructure:"vaspLEI"  yaml:"vaspLEI,omitempty"`
 
 }
 
@@ -73,7 +100,19 @@ func (c *Data) Init(configDir string) {
 	viper.BindEnv("DB_PASSWORD")
 	viper.BindEnv("DB_NAME")
 	viper.BindEnv("SENTRY_ENVIRONMENT")
+	viper.BindEnv("SCREENING_SERVICE_KEY")
+	viper.BindEnv("TRAVEL_RULE_ENCRYPTION_KEY")
 	viper.BindEnv("MINIMUMSWEEP")
+	viper.BindEnv("MINIMUMDEPOSIT")
+	viper.BindEnv("DUSTPOLICY")
+	viper.BindEnv("TRAVELRULETHRESHOLD")
+	viper.BindEnv("ADDRESSPOOLLOWWATERMARK")
+	viper.BindEnv("ADDRESSPOOLREFILLSIZE")
+	viper.BindEnv("HDXPUB")
+	viper.BindEnv("HDPURPOSE")
+	viper.BindEnv("ADDRESSROTATIONDEPOSITS")
+	viper.BindEnv("ADDRESSROTATIONHOURS")
+	viper.BindEnv("RETIREDADDRESSPOLICY")
 
 	viper.SetConfigName("config")
 	viper.AddConfigPath("../")
@@ -71,6 +71,9 @@ func (controller UserAssetController) ExternalTransfer(responseWriter http.Respo
 	userAssetService := services.NewService(controller.Cache, controller.Logger, batchService.Config)
 	isActive, err := userAssetService.IsWithdrawalActive(debitReferenceTransaction.AssetSymbol, requestData.Network, controller.Repository)
 	if err != nil {
+		if returnMaintenanceError(responseWriter, "ExternalTransfer", err, controller.Logger) {
+			return
+		}
 		ReturnError(responseWriter, "ExternalTransfer", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
 		return
 	}
@@ -103,20 +106,41 @@ func (controller UserAssetController) ExternalTransfer(responseWriter http.Respo
 		return
 	}
 
-	// Batch transaction, if asset is batchable
+	// Transfers valued at or above the asset travel rule threshold must carry originator and beneficiary information
+	isTravelRuleRequired, fiatValue, err := services.IsTravelRuleRequired(controller.Cache, controller.Logger, controller.Config, debitReferenceTransaction.AssetSymbol, value)
+	if err != nil {
+		ReturnError(responseWriter, "ExternalTransfer", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.TRAVEL_RULE_VALUATION_ERR), controller.Logger)
+		return
+	}
+	if isTravelRuleRequired && requestData.TravelRule == nil {
+		ReturnError(responseWriter, "ExternalTransfer", http.StatusBadRequest, errorcode.TRAVEL_RULE_REQUIRED, apiResponse.PlainError(errorcode.TRAVEL_RULE_REQUIRED_CODE, errorcode.TRAVEL_RULE_REQUIRED), controller.Logger)
+		return
+	}
+
+	// Withdrawals to addresses that match a sanctions screen are held for review and not queued for processing
+	transactionStatus := model.TransactionStatus.PENDING
+	screeningResult := services.ScreenCounterparty(services.NewScreener(controller.Cache, controller.Logger, controller.Config), controller.Logger, requestData.RecipientAddress, debitReferenceTransaction.AssetSymbol, requestData.Network)
+	if screeningResult.Hit {
+		controller.Logger.Info("ExternalTransfer logs : withdrawal %s to %s held for screening review : %s", requestData.TransactionReference, requestData.RecipientAddress, screeningResult.Reason)
+		transactionStatus = model.TransactionStatus.SCREENING_HOLD
+	}
+
+	// Batch transaction, if asset is batchable. A held withdrawal is not batched, it is queued for single processing once released
 	isBatchable, err := userAssetService.IsBatchable(debitReferenceTransaction.AssetSymbol, requestData.Network, controller.Repository)
 	if err != nil {
 		ReturnError(responseWriter, "ExternalTransfer", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
 		return
 	}
 	var activeBatchId uuid.UUID
-	if isBatchable {
-		activeBatchId, err = batchService.GetWaitingBatchId(controller.Repository, debitReferenceTransaction.AssetSymbol, requestData.Network)
+	if isBatchable && !screeningResult.Hit {
+		// the batch stays locked until the withdrawal is queued in it
+		var releaseBatch func()
+		activeBatchId, releaseBatch, err = batchService.JoinWaitingBatch(controller.Repository, debitReferenceTransaction.AssetSymbol, requestData.Network, value)
 		if err != nil {
 			ReturnError(responseWriter, "ExternalTransfer", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
 			return
 		}
-
+		defer releaseBatch()
 	}
 
 	// Build transaction object
@@ -129,6 +153,7 @@ func (controller UserAssetController) ExternalTransfer(responseWriter http.Respo
 		Memo:                 debitReferenceTransaction.Memo,
 		TransactionType:      model.TransactionType.ONCHAIN,
 		TransactionTag:       model.TransactionTag.WITHDRAW,
+		TransactionStatus:    transactionStatus,
 		Value:                value.String(),
 		PreviousBalance:      debitReferenceTransaction.PreviousBalance,
 		AvailableBalance:     debitReferenceTransaction.AvailableBalance,
@@ -170,6 +195,7 @@ func (controller UserAssetController) ExternalTransfer(responseWriter http.Respo
 		Network:    requestData.Network,
 		TransactionId:  transaction.ID,
 		BatchID:        activeBatchId,
+		TransactionStatus: transactionStatus,
 	}
 	if !strings.EqualFold(debitReferenceTransaction.Memo, utility.NO_MEMO) {
 		queue.Memo = debitReferenceTransaction.Memo
@@ -181,6 +207,39 @@ func (controller UserAssetController) ExternalTransfer(responseWriter http.Respo
 		return
 	}
 
+	if requestData.TravelRule != nil {
+		travelRuleRecord, err := services.BuildTravelRuleRecord(controller.Config, transaction, requestData.TravelRule, fiatValue)
+		if err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, "ExternalTransfer", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
+			return
+		}
+		if err := tx.Create(&travelRuleRecord).Error; err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, "ExternalTransfer", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+	}
+
+	if screeningResult.Hit {
+		screeningHold := model.ScreeningHold{
+			TransactionID:  transaction.ID,
+			TransactionTag: transaction.TransactionTag,
+			Address:        requestData.RecipientAddress,
+			AssetSymbol:    transaction.AssetSymbol,
+			Network:        transaction.Network,
+			Value:          transaction.Value,
+			Provider:       screeningResult.Provider,
+			Reason:         screeningResult.Reason,
+			Status:         model.ScreeningHoldStatus.HELD,
+		}
+		if err := tx.Create(&screeningHold).Error; err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, "ExternalTransfer", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		ReturnError(responseWriter, "ExternalTransfer", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
 		return
@@ -277,16 +336,24 @@ func (controller UserAssetController) ProcessTransactions(responseWriter http.Re
 
 	go func() {
 
+		// Withdrawals waiting for float go back in the queue first, as far as the float covers them
+		baseRepository := database.BaseRepository{Database: database.Database{Logger: controller.Logger, Config: controller.Config, DB: controller.Repository.Db()}}
+		tasks.ResumeAwaitingFloatWithdrawals(controller.Cache, controller.Logger, controller.Config, baseRepository)
+		awaitingFloatAssets, err := tasks.GetAwaitingFloatAssets(baseRepository)
+		if err != nil {
+			controller.Logger.Error("Error response from ProcessTransactions job : %+v, while fetching the assets waiting for float", err)
+		}
+
 		// Fetches all PENDING transactions from the transaction queue table for processing
 		var transactionQueue []model.TransactionQueue
-		if err := controller.Repository.FetchByFieldName(&model.TransactionQueue{TransactionStatus: model.TransactionStatus.PENDING}, &transactionQueue); err != nil {
+		if err := controller.Repository.FetchQueuedTransactionsByStatus(model.TransactionStatus.PENDING, &transactionQueue); err != nil {
 			controller.Logger.Error("Error response from ProcessTransactions job : %+v", err)
 			done <- true
 		}
 		processor := &TransactionProccessor{Logger: controller.Logger, Cache: controller.Cache, Config: controller.Config, Repository: controller.Repository}
 
-		// Sort by asset symbol
-		sort.Slice(transactionQueue, func(i, j int) bool {
+		// Sort by asset symbol, keeping the order they were queued in
+		sort.SliceStable(transactionQueue, func(i, j int) bool {
 			return transactionQueue[i].AssetSymbol < transactionQueue[j].AssetSymbol
 		})
 
@@ -313,6 +380,15 @@ func (controller UserAssetController) ProcessTransactions(responseWriter http.Re
 				continue
 			}
 
+			// withdrawals of an asset waiting for float wait behind the earlier ones
+			if tasks.IsBehindAwaitingFloat(awaitingFloatAssets, transaction.AssetSymbol, transaction.Network, transaction.CreatedAt) {
+				if err := processor.updateTransactions(transaction.TransactionId, model.TransactionStatus.AWAITING_FLOAT, model.ChainTransaction{}); err != nil {
+					controller.Logger.Error("Error occured while updating queued transaction %+v to AWAITING_FLOAT : %s", transaction.ID, err)
+				}
+				_ = processor.releaseLock(transaction.ID.String(), lockerServiceResponse.Token)
+				continue
+			}
+
 			// update transaction to processing
 			if err := processor.updateTransactions(transaction.TransactionId, model.TransactionStatus.PROCESSING, model.ChainTransaction{}); err != nil {
 				_ = processor.releaseLock(transaction.ID.String(), lockerServiceResponse.Token)
@@ -423,7 +499,8 @@ func (processor *TransactionProccessor) processSingleTxn(transaction model.Trans
 		switch serviceErr.Code {
 		case errorcode.INSUFFICIENT_FUNDS:
 			_ = processor.ProcessTxnWithInsufficientFloat(transaction.AssetSymbol, transaction.Network, *sendSingleTransactionRequest.Amount)
-			if err := processor.updateTransactions(transaction.TransactionId, model.TransactionStatus.PENDING, model.ChainTransaction{}); err != nil {
+			// parked until the float covers it, see tasks.ResumeAwaitingFloatWithdrawals
+			if err := processor.updateTransactions(transaction.TransactionId, model.TransactionStatus.AWAITING_FLOAT, model.ChainTransaction{}); err != nil {
 				return err
 			}
 			return nil
@@ -532,18 +609,23 @@ func (processor TransactionProccessor) updateTransactions(transactionId uuid.UUI
 		}
 	}()
 
+	// the rows are read in the transaction that updates them
 	transactionQueueDetails := model.TransactionQueue{}
-	if err := processor.Repository.Get(&model.TransactionQueue{TransactionId: transactionId}, &transactionQueueDetails); err != nil {
+	if err := tx.Where(&model.TransactionQueue{TransactionId: transactionId}).First(&transactionQueueDetails).Error; err != nil {
+		tx.Rollback()
 		return err
 	}
 	if err := tx.Model(&transactionQueueDetails).Updates(&model.TransactionQueue{TransactionStatus: status}).Error; err != nil {
+		tx.Rollback()
 		return err
 	}
 	transactionDetails := model.Transaction{}
-	if err := processor.Repository.Get(&model.Transaction{BaseModel: model.BaseModel{ID: transactionId}}, &transactionDetails); err != nil {
+	if err := tx.Where("id = ?", transactionId).First(&transactionDetails).Error; err != nil {
+		tx.Rollback()
 		return err
 	}
 	if err := tx.Model(&transactionDetails).Updates(&model.Transaction{TransactionStatus: status, OnChainTxId: chainTransaction.ID, Network: transactionQueueDetails.Network}).Error; err != nil {
+		tx.Rollback()
 		return err
 	}
 	if err := tx.Commit().Error; err != nil {
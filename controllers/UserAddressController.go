@@ -20,6 +20,7 @@ func (controller UserAssetController) GetAllAssetAddresses(responseWriter http.R
 	var userAsset model.UserAsset
 	var coinType int64
 	var responseData dto.AllAssetAddresses
+	var maintenanceErr error
 	apiResponse := utility.NewResponse()
 	routeParams := mux.Vars(requestReader)
 	assetID, err := uuid.FromString(routeParams["assetId"])
@@ -50,6 +51,9 @@ func (controller UserAssetController) GetAllAssetAddresses(responseWriter http.R
 			}
 			// Check if deposit is ACTIVE for the asset on this network
 			isActive, err := userAddressService.IsDepositActive(userAsset.AssetSymbol, network.Network, controller.Repository)
+			if _, ok := err.(services.MaintenanceError); ok {
+				maintenanceErr = err
+			}
 			if err != nil || !isActive  {
 				controller.Logger.Debug("%s for %s network", errorcode.DEPOSIT_NOT_ACTIVE, network.Network)
 				continue
@@ -66,6 +70,9 @@ func (controller UserAssetController) GetAllAssetAddresses(responseWriter http.R
 	}
 
 	if len(responseData.Addresses) == 0 {
+		if maintenanceErr != nil && returnMaintenanceError(responseWriter, "GetAllAssetAddresses", maintenanceErr, controller.Logger) {
+			return
+		}
 		ReturnError(responseWriter, "GetAllAssetAddresses", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR",
 			fmt.Sprintf("No addresses found for asset, ensure deposit is ACTIVE for asset %s", assetID)), controller.Logger)
 		return
@@ -164,6 +171,9 @@ func (controller UserAssetController) CreateAuxiliaryAddress(responseWriter http
 	}
 
 	if err := controller.VerifyDepositIsSupportedAndPopulateAsset(assetID, network, &userAsset); err != nil {
+		if returnMaintenanceError(responseWriter, "CreateAuxiliaryAddress", err, controller.Logger) {
+			return
+		}
 		ReturnError(responseWriter, "CreateAuxiliaryAddress", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR",
 			fmt.Sprintf("%s, for get asset address with id = %s", errorcode.DEPOSIT_NOT_ACTIVE, assetID)), controller.Logger)
 		return
@@ -6,10 +6,12 @@ import (
 	"net/http"
 	"strconv"
 	"time"
+	"wallet-adapter/database"
 	"wallet-adapter/dto"
 	"wallet-adapter/errorcode"
 	"wallet-adapter/model"
 	"wallet-adapter/services"
+	"wallet-adapter/tasks"
 	"wallet-adapter/utility"
 
 	"github.com/jinzhu/gorm"
@@ -185,6 +187,12 @@ func (controller UserAssetController) GetUserAssetByAddress(responseWriter http.
 
 	if err != nil {
 		if err.Error() == errorcode.SQL_404 {
+			if IsV2Address && userAssetMemo != "" {
+				// memos allocated by the memo allocator carry a check digit, a failed check points to a mistyped memo
+				if memoNetwork, networkErr := services.GetNetworkByAssetAndNetwork(controller.Repository, network, assetSymbol); networkErr == nil && !services.ValidateMemo(memoNetwork.CoinType, userAssetMemo) {
+					controller.Logger.Warning("GetUserAssetByAddress logs : memo %s for address %s fails the %s memo check, it is likely mistyped or allocated before memo check digits", userAssetMemo, address, network)
+				}
+			}
 			ReturnError(responseWriter, "GetUserAssetByAddress", http.StatusNotFound, err, apiResponse.PlainError(err.(utility.AppError).ErrType, fmt.Sprintf("Record not found for address : %s, with asset symbol : %s and memo : %s, additional context : %s", address, assetSymbol, userAssetMemo, err)), controller.Logger)
 			return
 		}
@@ -341,6 +349,51 @@ func (controller UserAssetController) OnChainCreditUserAsset(responseWriter http
 	value := strconv.FormatFloat(requestData.Value, 'g', utility.DigPrecision, 64)
 	previousBalance := assetDetails.AvailableBalance
 	currentAvailableBalance := utility.Add(requestData.Value, assetDetails.AvailableBalance, assetNetworkDetails.NativeDecimals)
+	updatedAsset := model.UserAsset{AvailableBalance: currentAvailableBalance}
+
+	// deposits below the network minimum are recorded but not credited to the available balance
+	isDustDeposit := services.IsDustDeposit(requestData.Value, assetNetworkDetails)
+	if isDustDeposit {
+		controller.Logger.Info("OnChainCreditUserAssets logs : deposit of %s %s on %s to %s is below minimum deposit %v, applying dust policy %s", value, assetDetails.AssetSymbol, assetNetworkDetails.Network, requestData.ChainData.RecipientAddress, assetNetworkDetails.MinimumDeposit, assetNetworkDetails.DustPolicy)
+		currentAvailableBalance = previousBalance
+		updatedAsset = model.UserAsset{}
+		if assetNetworkDetails.DustPolicy == model.DustPolicy.BUCKET {
+			updatedAsset.DustBalance = utility.Add(requestData.Value, assetDetails.DustBalance, assetNetworkDetails.NativeDecimals)
+		}
+	}
+
+	// deposits from addresses that match a sanctions screen are held for review and not credited
+	screeningResult := services.ScreenCounterparty(services.NewScreener(controller.Cache, controller.Logger, controller.Config), controller.Logger, requestData.ChainData.SenderAddress, assetDetails.AssetSymbol, requestData.ChainData.Network)
+	if screeningResult.Hit {
+		controller.Logger.Info("OnChainCreditUserAssets logs : deposit %s from %s held for screening review : %s", requestData.TransactionReference, requestData.ChainData.SenderAddress, screeningResult.Reason)
+		currentAvailableBalance = previousBalance
+		updatedAsset = model.UserAsset{}
+	}
+
+	// deposits to invalidated or retired addresses follow the address crediting rules, a primary address may be rotated by this deposit
+	depositAddress := model.UserAddress{}
+	if err := controller.Repository.GetByFieldName(&model.UserAddress{AssetID: assetDetails.ID, Address: requestData.ChainData.RecipientAddress, Network: requestData.ChainData.Network}, &depositAddress); err != nil && err.Error() != errorcode.SQL_404 {
+		controller.Logger.Error("OnChainCreditUserAssets logs : error fetching deposit address %s : %s", requestData.ChainData.RecipientAddress, err)
+	}
+	isAddressRotationDue := false
+	if depositAddress.ID != uuid.Nil {
+		isAddressRotationDue = services.IsRotationDueAfterDeposit(depositAddress, assetNetworkDetails)
+		if addressResult := services.CheckDepositAddress(depositAddress, assetNetworkDetails); addressResult.Hit && !screeningResult.Hit {
+			controller.Logger.Info("OnChainCreditUserAssets logs : deposit %s held for review : %s", requestData.TransactionReference, addressResult.Reason)
+			screeningResult = addressResult
+			currentAvailableBalance = previousBalance
+			updatedAsset = model.UserAsset{}
+		}
+	}
+
+	// Deposits are credited even when travel rule information is missing, an incomplete record is kept for follow up
+	isTravelRuleRequired, fiatValue, err := services.IsTravelRuleRequired(controller.Cache, controller.Logger, controller.Config, assetDetails.AssetSymbol, decimal.NewFromFloat(requestData.Value))
+	if err != nil {
+		controller.Logger.Error("OnChainCreditUserAssets logs : error valuing deposit %s for travel rule check : %s", requestData.TransactionReference, err)
+	}
+	if isTravelRuleRequired && requestData.TravelRule == nil {
+		controller.Logger.Warning("OnChainCreditUserAssets logs : deposit %s requires travel rule information but none was provided", requestData.TransactionReference)
+	}
 
 	tx := controller.Repository.Db().Begin()
 	defer func() {
@@ -353,7 +406,7 @@ func (controller UserAssetController) OnChainCreditUserAsset(responseWriter http
 		return
 	}
 
-	if err := tx.Model(&assetDetails).Updates(model.UserAsset{AvailableBalance: currentAvailableBalance}).Error; err != nil {
+	if err := tx.Model(&assetDetails).Updates(updatedAsset).Error; err != nil {
 		tx.Rollback()
 		ReturnError(responseWriter, "OnChainCreditUserAssets", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
 		return
@@ -382,6 +435,12 @@ func (controller UserAssetController) OnChainCreditUserAsset(responseWriter http
 	transactionStatus := model.TransactionStatus.PENDING
 	if chainTransaction.Status == true {
 		transactionStatus = model.TransactionStatus.COMPLETED
+		if isDustDeposit {
+			transactionStatus = model.TransactionStatus.DUST
+		}
+		if screeningResult.Hit {
+			transactionStatus = model.TransactionStatus.SCREENING_HOLD
+		}
 	} else {
 		transactionStatus = model.TransactionStatus.REJECTED
 	}
@@ -413,6 +472,53 @@ func (controller UserAssetController) OnChainCreditUserAsset(responseWriter http
 		return
 	}
 
+	if depositAddress.ID != uuid.Nil && chainTransaction.Status {
+		addressUpdate := map[string]interface{}{"deposit_count": gorm.Expr("deposit_count + ?", 1)}
+		if isAddressRotationDue {
+			controller.Logger.Info("OnChainCreditUserAssets logs : address %s reached %d deposits and is rotated", depositAddress.Address, assetNetworkDetails.RotateAfterDeposits)
+			addressUpdate["is_primary_address"] = false
+			addressUpdate["retired_at"] = time.Now()
+		}
+		if err := tx.Model(&model.UserAddress{}).Where("id = ?", depositAddress.ID).Updates(addressUpdate).Error; err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, "OnChainCreditUserAssets", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+	}
+
+	if isTravelRuleRequired || requestData.TravelRule != nil {
+		travelRuleRecord, err := services.BuildTravelRuleRecord(controller.Config, transaction, requestData.TravelRule, fiatValue)
+		if err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, "OnChainCreditUserAssets", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
+			return
+		}
+		if err := tx.Create(&travelRuleRecord).Error; err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, "OnChainCreditUserAssets", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+	}
+
+	if transactionStatus == model.TransactionStatus.SCREENING_HOLD {
+		screeningHold := model.ScreeningHold{
+			TransactionID:  transaction.ID,
+			TransactionTag: transaction.TransactionTag,
+			Address:        requestData.ChainData.SenderAddress,
+			AssetSymbol:    transaction.AssetSymbol,
+			Network:        transaction.Network,
+			Value:          transaction.Value,
+			Provider:       screeningResult.Provider,
+			Reason:         screeningResult.Reason,
+			Status:         model.ScreeningHoldStatus.HELD,
+		}
+		if err := tx.Create(&screeningHold).Error; err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, "OnChainCreditUserAssets", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		ReturnError(responseWriter, "OnChainCreditUserAssets", http.StatusInternalServerError, err, apiResponse.PlainError("INPUT_ERR", utility.GetSQLErr(err)), controller.Logger)
 		return
@@ -693,6 +799,7 @@ func (controller UserAssetController) GetTransaction(responseWriter http.Respons
 
 	transaction.Map(&responseData)
 	controller.populateChainData(transaction, &responseData, apiResponse, responseWriter)
+	controller.populateFloatEstimate(transaction, &responseData)
 	controller.Logger.Info("Outgoing response to GetTransaction request %+v", http.StatusOK)
 	responseWriter.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(responseWriter).Encode(responseData)
@@ -728,6 +835,7 @@ func (controller UserAssetController) GetTransactionsByAssetId(responseWriter ht
 		tx := dto.TransactionResponse{}
 		transaction.Map(&tx)
 		controller.populateChainData(transaction, &tx, apiResponse, responseWriter)
+		controller.populateFloatEstimate(transaction, &tx)
 		responseData.Transactions = append(responseData.Transactions, tx)
 	}
 	for i := 0; i < len(recipientTransactions); i++ {
@@ -735,6 +843,7 @@ func (controller UserAssetController) GetTransactionsByAssetId(responseWriter ht
 		txRecipient := dto.TransactionResponse{}
 		receipientTransaction.Map(&txRecipient)
 		controller.populateChainData(receipientTransaction, &txRecipient, apiResponse, responseWriter)
+		controller.populateFloatEstimate(receipientTransaction, &txRecipient)
 		responseData.Transactions = append(responseData.Transactions, txRecipient)
 	}
 
@@ -767,6 +876,21 @@ func (controller UserAssetController) populateChainData(transaction model.Transa
 
 }
 
+// populateFloatEstimate tells callers of a withdrawal waiting for float when the float is expected to be topped up, if this fails it
+// logs the error and leaves the estimate out
+func (controller UserAssetController) populateFloatEstimate(transaction model.Transaction, txResponse *dto.TransactionResponse) {
+	if transaction.TransactionStatus != model.TransactionStatus.AWAITING_FLOAT {
+		return
+	}
+	baseRepository := database.BaseRepository{Database: database.Database{Logger: controller.Logger, Config: controller.Config, DB: controller.Repository.Db()}}
+	estimate, err := tasks.EstimateFloatTopUp(baseRepository, transaction.AssetSymbol, transaction.Network)
+	if err != nil {
+		controller.Logger.Error("Could not estimate the float top up of %s on %s for transaction %v : %s", transaction.AssetSymbol, transaction.Network, transaction.ID, err)
+		return
+	}
+	txResponse.EstimatedFloatTopUp = estimate
+}
+
 func (controller UserAssetController) verifyTransactionStatus(transaction model.Transaction) (string, error) {
 
 	// Get queued transaction for transactionId
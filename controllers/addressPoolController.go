@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"wallet-adapter/database"
+	"wallet-adapter/services"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+)
+
+// GetAddressPoolDepth ... Reports available and assigned deposit addresses per network pool
+func (controller UserAssetController) GetAddressPoolDepth(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+
+	poolDepth, err := services.GetAddressPoolDepth(controller.Repository)
+	if err != nil {
+		ReturnError(responseWriter, "GetAddressPoolDepth", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetAddressPoolDepth request %+v", poolDepth)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, poolDepth))
+}
+
+// TriggerAddressPoolRefill ... Refills network address pools below their low-water mark
+func (controller UserAssetController) TriggerAddressPoolRefill(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+
+	// Endpoint spins up a go-routine to refill the pools and sends back an acknowledgement to the scheduler
+	done := make(chan bool)
+
+	go func() {
+
+		Database := &database.Database{
+			Logger: controller.Logger,
+			Config: controller.Config,
+			DB:     controller.Repository.Db(),
+		}
+
+		baseRepository := database.BaseRepository{Database: *Database}
+		userAssetRepository := database.UserAssetRepository{BaseRepository: baseRepository}
+		tasks.RefillAddressPools(controller.Cache, controller.Logger, controller.Config, userAssetRepository)
+
+		done <- true
+	}()
+
+	controller.Logger.Info("Outgoing response to TriggerAddressPoolRefill request %+v", utility.SUCCESS)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.PlainSuccess(utility.SUCCESSFUL, utility.SUCCESS))
+
+	<-done
+}
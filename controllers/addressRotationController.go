@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+// RotateAssetAddress ... Retires the primary deposit addresses of an asset on a network and issues new ones
+func (controller UserAssetController) RotateAssetAddress(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	var userAsset model.UserAsset
+	apiResponse := utility.NewResponse()
+	routeParams := mux.Vars(requestReader)
+	network := requestReader.URL.Query().Get("network")
+
+	assetID, err := uuid.FromString(routeParams["assetId"])
+	if err != nil {
+		ReturnError(responseWriter, "RotateAssetAddress", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return
+	}
+	controller.Logger.Info("Incoming request details for RotateAssetAddress : assetId : %s, network : %s", assetID, network)
+
+	if err := controller.Repository.GetAssetsByID(&model.UserAsset{BaseModel: model.BaseModel{ID: assetID}}, &userAsset); err != nil {
+		ReturnError(responseWriter, "RotateAssetAddress", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get asset with id = %s", utility.GetSQLErr(err), assetID)), controller.Logger)
+		return
+	}
+	if network == "" {
+		network = userAsset.DefaultNetwork
+	}
+
+	networkRecord, err := services.GetNetworkByAssetAndNetwork(controller.Repository, network, userAsset.AssetSymbol)
+	if err != nil {
+		ReturnError(responseWriter, "RotateAssetAddress", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get network with assetSymbol = %s and network : %s", utility.GetSQLErr(err), userAsset.AssetSymbol, network)), controller.Logger)
+		return
+	}
+	if !services.IsRotatableNetwork(networkRecord) {
+		ReturnError(responseWriter, "RotateAssetAddress", http.StatusBadRequest, errorcode.ADDRESS_ROTATION_NOT_SUPPORTED, apiResponse.PlainError("INPUT_ERR", errorcode.ADDRESS_ROTATION_NOT_SUPPORTED), controller.Logger)
+		return
+	}
+
+	var primaryAddresses []model.UserAddress
+	if err := controller.Repository.FetchByFieldName(&model.UserAddress{AssetID: assetID, IsPrimaryAddress: true, Network: network}, &primaryAddresses); err != nil {
+		ReturnError(responseWriter, "RotateAssetAddress", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	addressIDs := make([]uuid.UUID, 0, len(primaryAddresses))
+	for _, primaryAddress := range primaryAddresses {
+		addressIDs = append(addressIDs, primaryAddress.ID)
+	}
+	if _, err := controller.Repository.RetireUserAddresses(addressIDs); err != nil {
+		ReturnError(responseWriter, "RotateAssetAddress", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	// the replacement addresses are generated and subscribed on the crypto-adapter as they are issued
+	userAddressService := services.NewService(controller.Cache, controller.Logger, controller.Config)
+	addresses, err := controller.GetAddressesForNetwork(mapNetworkToAssetStruct(networkRecord, userAsset), userAddressService)
+	if err != nil {
+		ReturnError(responseWriter, "RotateAssetAddress", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to RotateAssetAddress request : retired %d addresses, issued %+v", len(addressIDs), addresses)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, addresses))
+}
+
+// InvalidateAddress ... Invalidates a compromised deposit address and cancels its subscription, later deposits to it are held for review
+func (controller UserAssetController) InvalidateAddress(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.InvalidateAddressRequest{}
+	address := mux.Vars(requestReader)["address"]
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for InvalidateAddress : address : %s, %+v", address, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "InvalidateAddress", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	invalidated, err := controller.Repository.InvalidateUserAddresses(address, requestData.Reason)
+	if err != nil {
+		ReturnError(responseWriter, "InvalidateAddress", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	if invalidated == 0 {
+		ReturnError(responseWriter, "InvalidateAddress", http.StatusNotFound, errorcode.RECORD_NOT_FOUND, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("No valid user address found for address = %s", address)), controller.Logger)
+		return
+	}
+
+	// deposits already reported stay held, the crypto-adapter stops reporting new ones once the subscription is cancelled
+	responseData := dto.InvalidateAddressResponse{Address: address, Invalidated: invalidated}
+	userAddressService := services.NewService(controller.Cache, controller.Logger, controller.Config)
+	if err := userAddressService.CancelAddressSubscription(controller.Repository, address); err != nil {
+		controller.Logger.Error("InvalidateAddress logs : could not cancel the subscription of invalidated address %s : %s", address, err)
+	} else {
+		responseData.SubscriptionCancelled = true
+	}
+	controller.Logger.Info("Outgoing response to InvalidateAddress request %+v, invalidated by %s", responseData, requestData.InvalidatedBy)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, responseData))
+}
+
+// TriggerAddressRotation ... Retires primary deposit addresses past their network rotation window
+func (controller UserAssetController) TriggerAddressRotation(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+
+	// Endpoint spins up a go-routine to rotate the addresses and sends back an acknowledgement to the scheduler
+	done := make(chan bool)
+
+	go func() {
+
+		Database := &database.Database{
+			Logger: controller.Logger,
+			Config: controller.Config,
+			DB:     controller.Repository.Db(),
+		}
+
+		baseRepository := database.BaseRepository{Database: *Database}
+		userAssetRepository := database.UserAssetRepository{BaseRepository: baseRepository}
+		tasks.RotateDepositAddresses(controller.Cache, controller.Logger, controller.Config, userAssetRepository)
+
+		done <- true
+	}()
+
+	controller.Logger.Info("Outgoing response to TriggerAddressRotation request %+v", utility.SUCCESS)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.PlainSuccess(utility.SUCCESSFUL, utility.SUCCESS))
+
+	<-done
+}
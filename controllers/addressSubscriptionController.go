@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+)
+
+// GetUnwatchedAddresses ... Reports user addresses without an active crypto-adapter subscription, filtered by subscription status (PENDING or FAILED)
+func (controller UserAssetController) GetUnwatchedAddresses(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	unwatchedAddresses := []dto.UnwatchedAddress{}
+
+	status := requestReader.URL.Query().Get("status")
+	controller.Logger.Info("Incoming request details for GetUnwatchedAddresses : status : %s", status)
+
+	if err := controller.Repository.FetchUnwatchedAddresses(status, &unwatchedAddresses); err != nil {
+		ReturnError(responseWriter, "GetUnwatchedAddresses", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetUnwatchedAddresses request %+v", len(unwatchedAddresses))
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, unwatchedAddresses))
+}
+
+// TriggerSubscriptionReconciliation ... Re-subscribes user addresses whose subscription is pending or failed
+func (controller UserAssetController) TriggerSubscriptionReconciliation(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+
+	// Endpoint spins up a go-routine to reconcile the subscriptions and sends back an acknowledgement to the scheduler
+	done := make(chan bool)
+
+	go func() {
+
+		Database := &database.Database{
+			Logger: controller.Logger,
+			Config: controller.Config,
+			DB:     controller.Repository.Db(),
+		}
+
+		baseRepository := database.BaseRepository{Database: *Database}
+		userAssetRepository := database.UserAssetRepository{BaseRepository: baseRepository}
+		tasks.ReconcileAddressSubscriptions(controller.Cache, controller.Logger, controller.Config, userAssetRepository)
+
+		done <- true
+	}()
+
+	controller.Logger.Info("Outgoing response to TriggerSubscriptionReconciliation request %+v", utility.SUCCESS)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.PlainSuccess(utility.SUCCESSFUL, utility.SUCCESS))
+
+	<-done
+}
@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+)
+
+// GetAssetConfig ... Lists the supported denominations with their networks
+func (controller UserAssetController) GetAssetConfig(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	denominations := []model.Denomination{}
+	networks := []model.Network{}
+
+	if err := controller.Repository.Fetch(&denominations); err != nil {
+		ReturnError(responseWriter, "GetAssetConfig", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	if err := controller.Repository.Fetch(&networks); err != nil {
+		ReturnError(responseWriter, "GetAssetConfig", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	for index := range denominations {
+		for _, network := range networks {
+			if network.AssetSymbol == denominations[index].AssetSymbol {
+				denominations[index].Networks = append(denominations[index].Networks, network)
+			}
+		}
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, denominations))
+}
+
+// GetAssetConfigOverrides ... Lists the fields of an asset set through the admin API, these take precedence over seeded values
+func (controller UserAssetController) GetAssetConfigOverrides(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	overrides := []model.AssetConfigOverride{}
+	assetSymbol := mux.Vars(requestReader)["assetSymbol"]
+
+	if err := controller.Repository.FetchByFieldName(&model.AssetConfigOverride{AssetSymbol: assetSymbol}, &overrides); err != nil {
+		ReturnError(responseWriter, "GetAssetConfigOverrides", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetAssetConfigOverrides request %+v", overrides)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, overrides))
+}
+
+// GetAssetConfigChanges ... Lists the change history of denominations and networks, filtered by assetSymbol and network query params
+func (controller UserAssetController) GetAssetConfigChanges(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	changes := []model.AssetConfigChange{}
+	assetSymbol := requestReader.URL.Query().Get("assetSymbol")
+	network := requestReader.URL.Query().Get("network")
+
+	if err := controller.Repository.FetchAssetConfigChanges(assetSymbol, network, &changes); err != nil {
+		ReturnError(responseWriter, "GetAssetConfigChanges", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, changes))
+}
+
+// CreateDenomination ... Adds a denomination, its fields are kept as overrides so re-seeding does not revert them
+func (controller UserAssetController) CreateDenomination(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.CreateDenominationRequest{}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for CreateDenomination : %+v", requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "CreateDenomination", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+	if err := controller.Repository.GetByFieldName(&model.Denomination{AssetSymbol: requestData.AssetSymbol}, &model.Denomination{}); err == nil {
+		ReturnError(responseWriter, "CreateDenomination", http.StatusConflict, errorcode.ASSET_CONFIG_EXISTS, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for asset symbol = %s", errorcode.ASSET_CONFIG_EXISTS, requestData.AssetSymbol)), controller.Logger)
+		return
+	}
+
+	controller.saveAssetConfig(responseWriter, "CreateDenomination", &model.Denomination{AssetSymbol: requestData.AssetSymbol}, requestData.DenominationConfig,
+		model.AssetConfigChange{EntityType: model.AssetConfigEntity.DENOMINATION, Action: model.AssetConfigAction.CREATE, AssetSymbol: requestData.AssetSymbol,
+			ChangedBy: requestData.ChangedBy, Reason: requestData.Reason})
+}
+
+// UpdateDenomination ... Changes the fields of a denomination
+func (controller UserAssetController) UpdateDenomination(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.UpdateDenominationRequest{}
+	assetSymbol := mux.Vars(requestReader)["assetSymbol"]
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for UpdateDenomination : assetSymbol : %s, %+v", assetSymbol, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "UpdateDenomination", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	denomination := model.Denomination{}
+	if err := controller.Repository.GetByFieldName(&model.Denomination{AssetSymbol: assetSymbol}, &denomination); err != nil {
+		ReturnError(responseWriter, "UpdateDenomination", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get denomination with asset symbol = %s", utility.GetSQLErr(err), assetSymbol)), controller.Logger)
+		return
+	}
+	if requestData.DefaultNetwork != nil {
+		if _, err := services.GetNetworkByAssetAndNetwork(controller.Repository, *requestData.DefaultNetwork, assetSymbol); err != nil {
+			ReturnError(responseWriter, "UpdateDenomination", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get network with assetSymbol = %s and network : %s", utility.GetSQLErr(err), assetSymbol, *requestData.DefaultNetwork)), controller.Logger)
+			return
+		}
+	}
+
+	controller.saveAssetConfig(responseWriter, "UpdateDenomination", &denomination, requestData.DenominationConfig,
+		model.AssetConfigChange{EntityType: model.AssetConfigEntity.DENOMINATION, Action: model.AssetConfigAction.UPDATE, AssetSymbol: assetSymbol,
+			ChangedBy: requestData.ChangedBy, Reason: requestData.Reason})
+}
+
+// DeleteDenomination ... Deletes a denomination without user assets together with its networks and overrides.
+// Denominations still supplied by the rate service are re-seeded on the next deploy
+func (controller UserAssetController) DeleteDenomination(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.AssetConfigAudit{}
+	assetSymbol := mux.Vars(requestReader)["assetSymbol"]
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for DeleteDenomination : assetSymbol : %s, %+v", assetSymbol, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "DeleteDenomination", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	denomination := model.Denomination{}
+	if err := controller.Repository.GetByFieldName(&model.Denomination{AssetSymbol: assetSymbol}, &denomination); err != nil {
+		ReturnError(responseWriter, "DeleteDenomination", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get denomination with asset symbol = %s", utility.GetSQLErr(err), assetSymbol)), controller.Logger)
+		return
+	}
+	if err := controller.Repository.GetByFieldName(&model.UserAsset{DenominationID: denomination.ID}, &model.UserAsset{}); err == nil {
+		ReturnError(responseWriter, "DeleteDenomination", http.StatusConflict, errorcode.ASSET_CONFIG_IN_USE, apiResponse.PlainError("INPUT_ERR", errorcode.ASSET_CONFIG_IN_USE), controller.Logger)
+		return
+	}
+
+	controller.deleteAssetConfig(responseWriter, "DeleteDenomination", model.AssetConfigChange{EntityType: model.AssetConfigEntity.DENOMINATION, Action: model.AssetConfigAction.DELETE,
+		AssetSymbol: assetSymbol, ChangedBy: requestData.ChangedBy, Reason: requestData.Reason})
+}
+
+// CreateNetwork ... Adds a network to a denomination, networks added this way are kept when the denomination is re-seeded
+func (controller UserAssetController) CreateNetwork(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.CreateNetworkRequest{}
+	assetSymbol := mux.Vars(requestReader)["assetSymbol"]
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for CreateNetwork : assetSymbol : %s, %+v", assetSymbol, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "CreateNetwork", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+	if err := controller.Repository.GetByFieldName(&model.Denomination{AssetSymbol: assetSymbol}, &model.Denomination{}); err != nil {
+		ReturnError(responseWriter, "CreateNetwork", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get denomination with asset symbol = %s", utility.GetSQLErr(err), assetSymbol)), controller.Logger)
+		return
+	}
+	if _, err := services.GetNetworkByAssetAndNetwork(controller.Repository, requestData.Network, assetSymbol); err == nil {
+		ReturnError(responseWriter, "CreateNetwork", http.StatusConflict, errorcode.ASSET_CONFIG_EXISTS, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for asset symbol = %s and network = %s", errorcode.ASSET_CONFIG_EXISTS, assetSymbol, requestData.Network)), controller.Logger)
+		return
+	}
+
+	// the network name is kept as an override, it marks the network as added locally for re-seeding
+	networkConfig := struct {
+		Network string `json:"network"`
+		dto.NetworkConfig
+	}{Network: requestData.Network, NetworkConfig: requestData.NetworkConfig}
+	controller.saveAssetConfig(responseWriter, "CreateNetwork", &model.Network{AssetSymbol: assetSymbol}, networkConfig,
+		model.AssetConfigChange{EntityType: model.AssetConfigEntity.NETWORK, Action: model.AssetConfigAction.CREATE, AssetSymbol: assetSymbol, Network: requestData.Network,
+			ChangedBy: requestData.ChangedBy, Reason: requestData.Reason})
+}
+
+// UpdateNetwork ... Changes the fields of a network
+func (controller UserAssetController) UpdateNetwork(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.UpdateNetworkRequest{}
+	routeParams := mux.Vars(requestReader)
+	assetSymbol, network := routeParams["assetSymbol"], routeParams["network"]
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for UpdateNetwork : assetSymbol : %s, network : %s, %+v", assetSymbol, network, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "UpdateNetwork", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	networkRecord, err := services.GetNetworkByAssetAndNetwork(controller.Repository, network, assetSymbol)
+	if err != nil {
+		ReturnError(responseWriter, "UpdateNetwork", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get network with assetSymbol = %s and network : %s", utility.GetSQLErr(err), assetSymbol, network)), controller.Logger)
+		return
+	}
+
+	controller.saveAssetConfig(responseWriter, "UpdateNetwork", &networkRecord, requestData.NetworkConfig,
+		model.AssetConfigChange{EntityType: model.AssetConfigEntity.NETWORK, Action: model.AssetConfigAction.UPDATE, AssetSymbol: assetSymbol, Network: network,
+			ChangedBy: requestData.ChangedBy, Reason: requestData.Reason})
+}
+
+// DeleteNetwork ... Deletes a network with its overrides. Networks still supplied by the rate service are re-seeded on the next deploy,
+// set their deposit and withdraw activity instead to disable them
+func (controller UserAssetController) DeleteNetwork(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.AssetConfigAudit{}
+	routeParams := mux.Vars(requestReader)
+	assetSymbol, network := routeParams["assetSymbol"], routeParams["network"]
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for DeleteNetwork : assetSymbol : %s, network : %s, %+v", assetSymbol, network, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "DeleteNetwork", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+	if _, err := services.GetNetworkByAssetAndNetwork(controller.Repository, network, assetSymbol); err != nil {
+		ReturnError(responseWriter, "DeleteNetwork", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get network with assetSymbol = %s and network : %s", utility.GetSQLErr(err), assetSymbol, network)), controller.Logger)
+		return
+	}
+
+	controller.deleteAssetConfig(responseWriter, "DeleteNetwork", model.AssetConfigChange{EntityType: model.AssetConfigEntity.NETWORK, Action: model.AssetConfigAction.DELETE,
+		AssetSymbol: assetSymbol, Network: network, ChangedBy: requestData.ChangedBy, Reason: requestData.Reason})
+}
+
+func (controller UserAssetController) saveAssetConfig(responseWriter http.ResponseWriter, executingMethod string, record, config interface{}, change model.AssetConfigChange) {
+
+	apiResponse := utility.NewResponse()
+	fields, err := services.GetConfigFields(config)
+	if err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
+		return
+	}
+	if len(fields) == 0 {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, errorcode.ASSET_CONFIG_EMPTY, apiResponse.PlainError("INPUT_ERR", errorcode.ASSET_CONFIG_EMPTY), controller.Logger)
+		return
+	}
+
+	change, err = services.ChangeAssetConfig(controller.Repository, record, fields, change)
+	if err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to %s request %+v, changes : %s", executingMethod, record, change.Changes)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, record))
+}
+
+func (controller UserAssetController) deleteAssetConfig(responseWriter http.ResponseWriter, executingMethod string, change model.AssetConfigChange) {
+
+	apiResponse := utility.NewResponse()
+	if err := controller.Repository.DeleteAssetConfig(&change); err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to %s request %+v", executingMethod, change)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.PlainSuccess(utility.SUCCESSFUL, utility.SUCCESS))
+}
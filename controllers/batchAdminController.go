@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+// GetBatches ... Lists batches, optionally filtered by asset, network and status
+func (controller BatchController) GetBatches(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	batches := []model.BatchRequest{}
+	query := requestReader.URL.Query()
+
+	// a struct condition skips the filters left empty
+	if err := controller.Repository.FetchByFieldName(&model.BatchRequest{AssetSymbol: query.Get("assetSymbol"), Network: query.Get("network"), Status: query.Get("status")}, &batches); err != nil {
+		ReturnError(responseWriter, "GetBatches", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, batches))
+}
+
+// GetBatch ... Returns a batch with its queued transactions and the chain transaction it was sent in
+func (controller BatchController) GetBatch(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	batchDetails := model.BatchDetails{QueuedTransactions: []model.TransactionQueue{}}
+
+	batch, ok := controller.getBatch(responseWriter, requestReader, "GetBatch")
+	if !ok {
+		return
+	}
+	batchDetails.Batch = batch
+	if err := controller.Repository.FetchByFieldName(&model.TransactionQueue{BatchID: batch.ID}, &batchDetails.QueuedTransactions); err != nil {
+		ReturnError(responseWriter, "GetBatch", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	chainTransaction := model.ChainTransaction{}
+	if err := controller.Repository.GetByFieldName(&model.ChainTransaction{BatchID: batch.ID}, &chainTransaction); err != nil {
+		if err.Error() != errorcode.SQL_404 {
+			ReturnError(responseWriter, "GetBatch", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+	} else {
+		batchDetails.ChainTransaction = &chainTransaction
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, batchDetails))
+}
+
+// CloseBatch ... Closes a batch waiting for withdrawals ahead of its batch policy, so it is sent on the next batch processing run
+func (controller BatchController) CloseBatch(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	batchService := services.BatchService{BaseService: services.BaseService{Config: controller.Config, Cache: controller.Cache, Logger: controller.Logger}}
+
+	batch, requestedBy, lockerServiceToken, ok := controller.getLockedBatch(responseWriter, requestReader, "CloseBatch")
+	if !ok {
+		return
+	}
+	defer controller.releaseLock(batch.ID.String(), lockerServiceToken)
+
+	if batch.Status != model.BatchStatus.WAIT_MODE {
+		ReturnError(responseWriter, "CloseBatch", http.StatusBadRequest, errorcode.BATCH_NOT_WAITING, apiResponse.PlainError("INPUT_ERR", errorcode.BATCH_NOT_WAITING), controller.Logger)
+		return
+	}
+	if err := batchService.CloseBatch(controller.Repository, &batch, model.BatchCloseReason.FORCED, requestedBy); err != nil {
+		ReturnError(responseWriter, "CloseBatch", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to CloseBatch request %+v", batch)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, batch))
+}
+
+// UnbatchTransactions ... Moves the withdrawals of a stuck batch back to single processing and terminates the emptied batch.
+// A batch that may have reached the chain keeps its withdrawals
+func (controller BatchController) UnbatchTransactions(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	batchService := services.BatchService{BaseService: services.BaseService{Config: controller.Config, Cache: controller.Cache, Logger: controller.Logger}}
+	processor := &BatchTransactionProcessor{Cache: controller.Cache, Logger: controller.Logger, Config: controller.Config, Repository: controller.Repository}
+
+	batch, requestedBy, lockerServiceToken, ok := controller.getLockedBatch(responseWriter, requestReader, "UnbatchTransactions")
+	if !ok {
+		return
+	}
+	defer controller.releaseLock(batch.ID.String(), lockerServiceToken)
+
+	switch batch.Status {
+	case model.BatchStatus.WAIT_MODE, model.BatchStatus.START_MODE, model.BatchStatus.RETRY_MODE, model.BatchStatus.AWAITING_FLOAT:
+	default:
+		ReturnError(responseWriter, "UnbatchTransactions", http.StatusBadRequest, errorcode.BATCH_NOT_UNBATCHABLE, apiResponse.PlainError("INPUT_ERR", errorcode.BATCH_NOT_UNBATCHABLE), controller.Logger)
+		return
+	}
+	if err := controller.Repository.GetByFieldName(&model.ChainTransaction{BatchID: batch.ID}, &model.ChainTransaction{}); err == nil {
+		ReturnError(responseWriter, "UnbatchTransactions", http.StatusBadRequest, errorcode.BATCH_NOT_UNBATCHABLE, apiResponse.PlainError("INPUT_ERR", errorcode.BATCH_NOT_UNBATCHABLE), controller.Logger)
+		return
+	} else if err.Error() != errorcode.SQL_404 {
+		ReturnError(responseWriter, "UnbatchTransactions", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	if batch.Status == model.BatchStatus.RETRY_MODE {
+		// the batch was sent, only a send the crypto adapter has no record of can be undone
+		txnExist, _, err := services.GetBroadcastedTXNDetailsByRef(batch.ID.String(), batch.AssetSymbol, batch.Network, controller.Cache, controller.Logger, controller.Config)
+		if err != nil {
+			ReturnError(responseWriter, "UnbatchTransactions", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", err.Error()), controller.Logger)
+			return
+		}
+		if txnExist {
+			ReturnError(responseWriter, "UnbatchTransactions", http.StatusBadRequest, errorcode.BATCH_NOT_UNBATCHABLE, apiResponse.PlainError("INPUT_ERR", errorcode.BATCH_NOT_UNBATCHABLE), controller.Logger)
+			return
+		}
+	}
+
+	transactionIds, err := batchService.UnbatchTransactions(controller.Repository, batch, requestedBy)
+	if err != nil {
+		ReturnError(responseWriter, "UnbatchTransactions", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	if err := processor.UpdateBatchedTransactionsStatus(batch, model.ChainTransaction{}, model.BatchStatus.TERMINATED); err != nil {
+		ReturnError(responseWriter, "UnbatchTransactions", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to UnbatchTransactions request, transactions %+v moved out of batch %s", transactionIds, batch.ID)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, transactionIds))
+}
+
+// RetryBatch ... Requeues the withdrawals of a terminated batch as a new batch, sent on the next batch processing run
+func (controller BatchController) RetryBatch(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	batchService := services.BatchService{BaseService: services.BaseService{Config: controller.Config, Cache: controller.Cache, Logger: controller.Logger}}
+	processor := &BatchTransactionProcessor{Cache: controller.Cache, Logger: controller.Logger, Config: controller.Config, Repository: controller.Repository}
+
+	batch, requestedBy, lockerServiceToken, ok := controller.getLockedBatch(responseWriter, requestReader, "RetryBatch")
+	if !ok {
+		return
+	}
+	defer controller.releaseLock(batch.ID.String(), lockerServiceToken)
+
+	if batch.Status != model.BatchStatus.TERMINATED {
+		ReturnError(responseWriter, "RetryBatch", http.StatusBadRequest, errorcode.BATCH_NOT_TERMINATED, apiResponse.PlainError("INPUT_ERR", errorcode.BATCH_NOT_TERMINATED), controller.Logger)
+		return
+	}
+	retryBatch, err := batchService.RequeueBatch(controller.Repository, batch, requestedBy)
+	if err != nil {
+		if err.Error() == errorcode.BATCH_EMPTY {
+			ReturnError(responseWriter, "RetryBatch", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.BATCH_EMPTY), controller.Logger)
+			return
+		}
+		ReturnError(responseWriter, "RetryBatch", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	// the retry batch is recorded with the withdrawals it carries, as a processing run does when it starts a batch
+	if err := processor.UpdateBatchedTransactionsStatus(retryBatch, model.ChainTransaction{}, model.BatchStatus.START_MODE); err != nil {
+		ReturnError(responseWriter, "RetryBatch", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	if err := controller.Repository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: retryBatch.ID}}, &retryBatch); err != nil {
+		ReturnError(responseWriter, "RetryBatch", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to RetryBatch request %+v", retryBatch)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, retryBatch))
+}
+
+func (controller BatchController) getBatch(responseWriter http.ResponseWriter, requestReader *http.Request, executingMethod string) (model.BatchRequest, bool) {
+	apiResponse := utility.NewResponse()
+	batch := model.BatchRequest{}
+
+	batchID, err := uuid.FromString(mux.Vars(requestReader)["batchId"])
+	if err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return batch, false
+	}
+	if err := controller.Repository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: batchID}}, &batch); err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get batch with id = %s", utility.GetSQLErr(err), batchID)), controller.Logger)
+		return batch, false
+	}
+	return batch, true
+}
+
+// getLockedBatch validates a batch action, takes the lock the batch processing run holds while it works on the batch and reads
+// the batch under it. It returns who requested the action, to be recorded on the batch. The caller releases the lock
+func (controller BatchController) getLockedBatch(responseWriter http.ResponseWriter, requestReader *http.Request, executingMethod string) (model.BatchRequest, string, string, bool) {
+	apiResponse := utility.NewResponse()
+	requestData := dto.BatchActionRequest{}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for %s : batchId : %s, %+v", executingMethod, mux.Vars(requestReader)["batchId"], requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return model.BatchRequest{}, "", "", false
+	}
+	batch, ok := controller.getBatch(responseWriter, requestReader, executingMethod)
+	if !ok {
+		return batch, "", "", false
+	}
+	lockerServiceToken, err := controller.obtainLock(batch.ID.String())
+	if err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusConflict, err, apiResponse.PlainError("SYSTEM_ERR", err.Error()), controller.Logger)
+		return batch, "", "", false
+	}
+	// the batch may have moved on while the lock was taken
+	if err := controller.Repository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: batch.ID}}, &batch); err != nil {
+		controller.releaseLock(batch.ID.String(), lockerServiceToken)
+		ReturnError(responseWriter, executingMethod, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return batch, "", "", false
+	}
+	return batch, requestData.RequestedBy, lockerServiceToken, true
+}
@@ -36,6 +36,14 @@ func (controller BatchController) ProcessBatchBTCTransactions(responseWriter htt
 	done := make(chan bool)
 
 	go func() {
+		// Batches waiting for float go back in the queue first, as far as the float covers them
+		baseRepository := database.BaseRepository{Database: database.Database{Logger: controller.Logger, Config: controller.Config, DB: controller.Repository.Db()}}
+		tasks.ResumeAwaitingFloatWithdrawals(controller.Cache, controller.Logger, controller.Config, baseRepository)
+		awaitingFloatAssets, err := tasks.GetAwaitingFloatAssets(baseRepository)
+		if err != nil {
+			controller.Logger.Error("Error response from ProcessBatchBTCTransactions : %+v, while fetching the assets waiting for float", err)
+		}
+
 		// Get all active batches
 		activeBatches, err := batchService.GetAllActiveBatches(controller.Repository, assetSymbol)
 		if err != nil {
@@ -60,6 +68,33 @@ func (controller BatchController) ProcessBatchBTCTransactions(responseWriter htt
 					continue
 				}
 			} else {
+				// A waiting batch is only processed once its batch policy lets this run close it
+				if batch.Status == model.BatchStatus.WAIT_MODE {
+					closeReason, err := batchService.ReviewWaitingBatch(controller.Repository, batch, time.Now(), true)
+					if err != nil || closeReason == "" {
+						_ = controller.releaseLock(batch.ID.String(), lockerServiceToken)
+						continue
+					}
+					if err := batchService.CloseBatch(controller.Repository, &batch, closeReason, ""); err != nil {
+						_ = controller.releaseLock(batch.ID.String(), lockerServiceToken)
+						continue
+					}
+				}
+
+				// batches of an asset waiting for float wait behind the earlier withdrawals, a batch takes the place of its first withdrawal
+				batchQueuedAt, err := controller.getBatchQueuedAt(batch)
+				if err != nil {
+					_ = controller.releaseLock(batch.ID.String(), lockerServiceToken)
+					continue
+				}
+				if tasks.IsBehindAwaitingFloat(awaitingFloatAssets, batch.AssetSymbol, batch.Network, batchQueuedAt) {
+					if err := processor.UpdateBatchedTransactionsStatus(batch, model.ChainTransaction{}, model.BatchStatus.AWAITING_FLOAT); err != nil {
+						controller.Logger.Error("Error response from ProcessBatchBTCTransactions : %+v while updating active batch status to AWAITING_FLOAT", err)
+					}
+					_ = controller.releaseLock(batch.ID.String(), lockerServiceToken)
+					continue
+				}
+
 				if err := processor.UpdateBatchedTransactionsStatus(batch, model.ChainTransaction{}, model.BatchStatus.START_MODE); err != nil {
 					controller.Logger.Error("Error response from ProcessBatchBTCTransactions : %+v while updating active batch status to PROCESSING", err)
 					_ = controller.releaseLock(batch.ID.String(), lockerServiceToken)
@@ -136,7 +171,10 @@ func (processor *BatchTransactionProcessor) processBatch(batch model.BatchReques
 				for _, value := range sendBatchTransactionRequest.Recipients {
 					total += value.Value
 				}
-				if err := processor.ProcessBatchTxnWithInsufficientFloat(batch.AssetSymbol, batch.Network, *big.NewInt(total)); err != nil {
+				_ = processor.ProcessBatchTxnWithInsufficientFloat(batch.AssetSymbol, batch.Network, *big.NewInt(total))
+				// parked until the float covers it, see tasks.ResumeAwaitingFloatWithdrawals
+				if err := processor.UpdateBatchedTransactionsStatus(batch, model.ChainTransaction{}, model.BatchStatus.AWAITING_FLOAT); err != nil {
+					return err
 				}
 				return err
 			}
@@ -232,11 +270,14 @@ func (processor *BatchTransactionProcessor) retryBatchProcessing(batch model.Bat
 	return nil
 }
 
+// UpdateBatchedTransactionsStatus ... Moves a batch to a status, along with the withdrawals the batch carries. Withdrawals of the batch in any
+// other status, such as one held for screening review, were never sent with it and keep their status
 func (processor *BatchTransactionProcessor) UpdateBatchedTransactionsStatus(batch model.BatchRequest, chainTransaction model.ChainTransaction, status string) error {
 
-	// Fetches all transactions for the given BatchID
+	// Fetches the transactions the batch carries for the given BatchID
 	var queuedBatchedTransactions []model.TransactionQueue
-	if err := processor.Repository.FetchByFieldName(&model.TransactionQueue{BatchID: batch.ID, AssetSymbol: batch.AssetSymbol}, &queuedBatchedTransactions); err != nil {
+	if err := processor.Repository.Db().Where("batch_id = ? AND asset_symbol = ? AND transaction_status IN (?)", batch.ID, batch.AssetSymbol,
+		GetBatchedTransactionStatuses(status)).Find(&queuedBatchedTransactions).Error; err != nil {
 		return err
 	}
 
@@ -249,7 +290,7 @@ func (processor *BatchTransactionProcessor) UpdateBatchedTransactionsStatus(batc
 	if err := tx.Error; err != nil {
 		return err
 	}
-	if status == model.BatchStatus.PROCESSING || status == model.BatchStatus.COMPLETED || status == model.BatchStatus.TERMINATED {
+	if status == model.BatchStatus.PROCESSING || status == model.BatchStatus.COMPLETED || status == model.BatchStatus.TERMINATED || status == model.BatchStatus.AWAITING_FLOAT {
 		// Updates all transactions associated with the batch
 		batchedTransactionsIds := []uuid.UUID{}
 		queuedBatchedTransactionsIds := []uuid.UUID{}
@@ -285,6 +326,17 @@ func (processor *BatchTransactionProcessor) UpdateBatchedTransactionsStatus(batc
 
 }
 
+// GetBatchedTransactionStatuses ... Returns the statuses of the withdrawals a batch carries when it moves to a status. A batch is parked
+// for float, and sent, with the withdrawals that were pending, and completes or terminates with those it was sent with
+func GetBatchedTransactionStatuses(status string) []string {
+	switch status {
+	case model.BatchStatus.WAIT_MODE, model.BatchStatus.START_MODE, model.BatchStatus.AWAITING_FLOAT:
+		return []string{model.TransactionStatus.PENDING}
+	default:
+		return []string{model.TransactionStatus.PENDING, model.TransactionStatus.PROCESSING}
+	}
+}
+
 func (processor *BatchTransactionProcessor) ProcessBatchTxnWithInsufficientFloat(assetSymbol, network string, amount big.Int) error {
 
 	DB := database.Database{Logger: processor.Logger, Config: processor.Config, DB: processor.Repository.Db()}
@@ -300,6 +352,21 @@ func (processor *BatchTransactionProcessor) ProcessBatchTxnWithInsufficientFloat
 	return errors.New(fmt.Sprintf("Not enough balance in float for this transaction, sweep operation in progress."))
 }
 
+// getBatchQueuedAt returns when the first withdrawal of a batch was queued
+func (controller BatchController) getBatchQueuedAt(batch model.BatchRequest) (time.Time, error) {
+	queuedTransactions := []model.TransactionQueue{}
+	if err := controller.Repository.FetchByFieldName(&model.TransactionQueue{BatchID: batch.ID}, &queuedTransactions); err != nil {
+		return time.Time{}, err
+	}
+	queuedAt := batch.CreatedAt
+	for i, queuedTransaction := range queuedTransactions {
+		if i == 0 || queuedTransaction.CreatedAt.Before(queuedAt) {
+			queuedAt = queuedTransaction.CreatedAt
+		}
+	}
+	return queuedAt, nil
+}
+
 func (controller BatchController) obtainLock(identifier string) (string, error) {
 	serviceErr := dto.ServicesRequestErr{}
 
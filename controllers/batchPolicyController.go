@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+// GetBatchPolicies ... Lists the batch policies, assets without one close their waiting batch on every scheduled processing run
+func (controller UserAssetController) GetBatchPolicies(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	batchPolicies := []model.BatchPolicy{}
+
+	if err := controller.Repository.Fetch(&batchPolicies); err != nil {
+		ReturnError(responseWriter, "GetBatchPolicies", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, batchPolicies))
+}
+
+// SaveBatchPolicy ... Sets when the waiting batch of an asset on a network is closed, replacing its current policy
+func (controller UserAssetController) SaveBatchPolicy(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.SaveBatchPolicyRequest{}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for SaveBatchPolicy : %+v", requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "SaveBatchPolicy", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+	if requestData.MaxTotalValue.IsNegative() {
+		err := errors.New(errorcode.BATCH_POLICY_VALUE_INVALID)
+		ReturnError(responseWriter, "SaveBatchPolicy", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.BATCH_POLICY_VALUE_INVALID), controller.Logger)
+		return
+	}
+	if requestData.MaxAgeMinutes > 0 && requestData.MinAgeMinutes > requestData.MaxAgeMinutes {
+		err := errors.New(errorcode.BATCH_POLICY_AGE_INVALID)
+		ReturnError(responseWriter, "SaveBatchPolicy", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.BATCH_POLICY_AGE_INVALID), controller.Logger)
+		return
+	}
+	if err := controller.Repository.GetByFieldName(&model.Network{AssetSymbol: requestData.AssetSymbol, Network: requestData.Network}, &model.Network{}); err != nil {
+		ReturnError(responseWriter, "SaveBatchPolicy", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get network with assetSymbol = %s and network : %s", utility.GetSQLErr(err), requestData.AssetSymbol, requestData.Network)), controller.Logger)
+		return
+	}
+
+	batchPolicy := model.BatchPolicy{}
+	// the update is a map so limits can be cleared, zero values of a struct are not assigned
+	update := map[string]interface{}{
+		"max_recipients":  requestData.MaxRecipients,
+		"max_total_value": requestData.MaxTotalValue,
+		"max_age_minutes": requestData.MaxAgeMinutes,
+		"min_age_minutes": requestData.MinAgeMinutes,
+		"updated_by":      requestData.UpdatedBy,
+	}
+	if err := controller.Repository.UpdateOrCreate(model.BatchPolicy{AssetSymbol: requestData.AssetSymbol, Network: requestData.Network}, &batchPolicy, update); err != nil {
+		ReturnError(responseWriter, "SaveBatchPolicy", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to SaveBatchPolicy request %+v", batchPolicy)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, batchPolicy))
+}
+
+// DeleteBatchPolicy ... Removes the batch policy of an asset, its waiting batch goes back to closing on every scheduled processing run
+func (controller UserAssetController) DeleteBatchPolicy(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+
+	policyID, err := uuid.FromString(mux.Vars(requestReader)["policyId"])
+	if err != nil {
+		ReturnError(responseWriter, "DeleteBatchPolicy", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return
+	}
+	controller.Logger.Info("Incoming request details for DeleteBatchPolicy : policyId : %s", policyID)
+
+	batchPolicy := model.BatchPolicy{}
+	if err := controller.Repository.Get(&model.BatchPolicy{BaseModel: model.BaseModel{ID: policyID}}, &batchPolicy); err != nil {
+		ReturnError(responseWriter, "DeleteBatchPolicy", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get batch policy with id = %s", utility.GetSQLErr(err), policyID)), controller.Logger)
+		return
+	}
+	if err := controller.Repository.Delete(&batchPolicy); err != nil {
+		ReturnError(responseWriter, "DeleteBatchPolicy", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to DeleteBatchPolicy request %+v", batchPolicy)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, batchPolicy))
+}
@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+// GetColdWallets ... Lists the registered cold wallets with their transfer settings
+func (controller UserAssetController) GetColdWallets(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	coldWallets := []model.ColdWallet{}
+
+	if err := controller.Repository.Fetch(&coldWallets); err != nil {
+		ReturnError(responseWriter, "GetColdWallets", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, coldWallets))
+}
+
+// CreateColdWallet ... Registers a cold wallet, it receives no funds until someone else verifies it
+func (controller UserAssetController) CreateColdWallet(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.CreateColdWalletRequest{}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for CreateColdWallet : %+v", requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "CreateColdWallet", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+	if err := controller.Repository.GetByFieldName(&model.Network{AssetSymbol: requestData.AssetSymbol, Network: requestData.Network}, &model.Network{}); err != nil {
+		ReturnError(responseWriter, "CreateColdWallet", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get network with assetSymbol = %s and network : %s", utility.GetSQLErr(err), requestData.AssetSymbol, requestData.Network)), controller.Logger)
+		return
+	}
+
+	requiresApproval := true
+	if requestData.RequiresApproval != nil {
+		requiresApproval = *requestData.RequiresApproval
+	}
+	coldWallet := model.ColdWallet{
+		AssetSymbol:       requestData.AssetSymbol,
+		Network:           requestData.Network,
+		Address:           requestData.Address,
+		Memo:              requestData.Memo,
+		Label:             requestData.Label,
+		SurplusPercent:    requestData.SurplusPercent,
+		MaxTransferAmount: requestData.MaxTransferAmount,
+		RequiresApproval:  requiresApproval,
+		ApprovalThreshold: requestData.ApprovalThreshold,
+		CreatedBy:         requestData.CreatedBy,
+	}
+	if err := controller.Repository.Create(&coldWallet); err != nil {
+		ReturnError(responseWriter, "CreateColdWallet", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to CreateColdWallet request %+v", coldWallet)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusCreated)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, coldWallet))
+}
+
+// UpdateColdWallet ... Changes the label and surplus share of a cold wallet
+func (controller UserAssetController) UpdateColdWallet(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.UpdateColdWalletRequest{}
+
+	coldWallet, ok := controller.getColdWallet(responseWriter, requestReader, "UpdateColdWallet")
+	if !ok {
+		return
+	}
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for UpdateColdWallet : walletId : %s, %+v", coldWallet.ID, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "UpdateColdWallet", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	// the update is a map so settings can be set back to zero, zero values of a struct are not assigned
+	update := map[string]interface{}{
+		"label":           requestData.Label,
+		"surplus_percent": requestData.SurplusPercent,
+		"updated_by":      requestData.UpdatedBy,
+	}
+	if err := controller.Repository.Update(&coldWallet, update); err != nil {
+		ReturnError(responseWriter, "UpdateColdWallet", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to UpdateColdWallet request %+v", coldWallet)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, coldWallet))
+}
+
+// UpdateColdWalletLimits ... Changes the transfer cap and approval settings of a cold wallet. They decide how much leaves the float without
+// a second person looking at it, so they are kept apart from the other settings under their own permission
+func (controller UserAssetController) UpdateColdWalletLimits(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.UpdateColdWalletLimitsRequest{}
+
+	coldWallet, ok := controller.getColdWallet(responseWriter, requestReader, "UpdateColdWalletLimits")
+	if !ok {
+		return
+	}
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for UpdateColdWalletLimits : walletId : %s, %+v", coldWallet.ID, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "UpdateColdWalletLimits", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	update := map[string]interface{}{
+		"max_transfer_amount": requestData.MaxTransferAmount,
+		"requires_approval":   requestData.RequiresApproval,
+		"approval_threshold":  requestData.ApprovalThreshold,
+		"updated_by":          requestData.UpdatedBy,
+	}
+	if err := controller.Repository.Update(&coldWallet, update); err != nil {
+		ReturnError(responseWriter, "UpdateColdWalletLimits", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to UpdateColdWalletLimits request %+v", coldWallet)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, coldWallet))
+}
+
+// VerifyColdWallet ... Marks a cold wallet as verified, after which it can receive funds
+func (controller UserAssetController) VerifyColdWallet(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.ReviewRequest{}
+
+	coldWallet, ok := controller.getColdWallet(responseWriter, requestReader, "VerifyColdWallet")
+	if !ok {
+		return
+	}
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for VerifyColdWallet : walletId : %s, %+v", coldWallet.ID, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "VerifyColdWallet", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+	if coldWallet.IsVerified {
+		ReturnError(responseWriter, "VerifyColdWallet", http.StatusBadRequest, errors.New(errorcode.COLD_WALLET_ALREADY_VERIFIED), apiResponse.PlainError("INPUT_ERR", errorcode.COLD_WALLET_ALREADY_VERIFIED), controller.Logger)
+		return
+	}
+	if coldWallet.CreatedBy == requestData.ReviewedBy {
+		ReturnError(responseWriter, "VerifyColdWallet", http.StatusBadRequest, errors.New(errorcode.COLD_WALLET_SELF_VERIFICATION), apiResponse.PlainError("INPUT_ERR", errorcode.COLD_WALLET_SELF_VERIFICATION), controller.Logger)
+		return
+	}
+
+	verifiedAt := time.Now()
+	if err := controller.Repository.Update(&coldWallet, model.ColdWallet{IsVerified: true, VerifiedBy: requestData.ReviewedBy, VerifiedAt: &verifiedAt}); err != nil {
+		ReturnError(responseWriter, "VerifyColdWallet", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to VerifyColdWallet request %+v", coldWallet)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, coldWallet))
+}
+
+// GetColdTransfers ... Lists the transfers of float surplus to cold wallets, filtered by status
+func (controller UserAssetController) GetColdTransfers(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	coldTransfers := []model.ColdTransfer{}
+
+	status := requestReader.URL.Query().Get("status")
+	if err := controller.Repository.FetchByFieldName(&model.ColdTransfer{Status: status}, &coldTransfers); err != nil {
+		ReturnError(responseWriter, "GetColdTransfers", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetColdTransfers request %+v", len(coldTransfers))
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, coldTransfers))
+}
+
+// ApproveColdTransfer ... Sends a cold transfer that was waiting for approval
+func (controller UserAssetController) ApproveColdTransfer(responseWriter http.ResponseWriter, requestReader *http.Request) {
+	controller.reviewColdTransfer(responseWriter, requestReader, "ApproveColdTransfer", true)
+}
+
+// RejectColdTransfer ... Cancels a cold transfer that was waiting for approval, the funds stay in the float
+func (controller UserAssetController) RejectColdTransfer(responseWriter http.ResponseWriter, requestReader *http.Request) {
+	controller.reviewColdTransfer(responseWriter, requestReader, "RejectColdTransfer", false)
+}
+
+func (controller UserAssetController) reviewColdTransfer(responseWriter http.ResponseWriter, requestReader *http.Request, executingMethod string, isApproved bool) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.ReviewRequest{}
+
+	transferID, err := uuid.FromString(mux.Vars(requestReader)["transferId"])
+	if err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return
+	}
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for %s : transferId : %s, %+v", executingMethod, transferID, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	coldTransfer := model.ColdTransfer{}
+	if err := controller.Repository.Get(&model.ColdTransfer{BaseModel: model.BaseModel{ID: transferID}}, &coldTransfer); err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get cold transfer with id = %s", utility.GetSQLErr(err), transferID)), controller.Logger)
+		return
+	}
+
+	// The transfer is claimed before anything is sent, so two reviews of the same transfer cannot both go through
+	reviewedAt := time.Now()
+	status := model.ColdTransferStatus.REJECTED
+	if isApproved {
+		status = model.ColdTransferStatus.APPROVED
+	}
+	claim := controller.Repository.Db().Model(&model.ColdTransfer{}).Where("id = ? AND status = ?", transferID, model.ColdTransferStatus.PENDING_APPROVAL).
+		Updates(map[string]interface{}{"status": status, "reviewed_by": requestData.ReviewedBy, "reviewed_at": reviewedAt})
+	if claim.Error != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusInternalServerError, claim.Error, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(claim.Error)), controller.Logger)
+		return
+	}
+	if claim.RowsAffected != 1 {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, errors.New(errorcode.COLD_TRANSFER_NOT_PENDING), apiResponse.PlainError("INPUT_ERR", errorcode.COLD_TRANSFER_NOT_PENDING), controller.Logger)
+		return
+	}
+	coldTransfer.Status, coldTransfer.ReviewedBy, coldTransfer.ReviewedAt = status, requestData.ReviewedBy, &reviewedAt
+
+	if isApproved {
+		baseRepository := database.BaseRepository{Database: database.Database{Logger: controller.Logger, Config: controller.Config, DB: controller.Repository.Db()}}
+		if err := tasks.PrepareApprovedColdTransfer(controller.Cache, controller.Logger, controller.Config, baseRepository, &coldTransfer); err != nil {
+			if updateErr := controller.Repository.Update(&coldTransfer, &model.ColdTransfer{Status: model.ColdTransferStatus.FAILED, Error: err.Error()}); updateErr != nil {
+				controller.Logger.Error("Could not update cold transfer %s : %s", coldTransfer.Reference, updateErr)
+			}
+			if err.Error() == errorcode.COLD_WALLET_NOT_VERIFIED || err.Error() == errorcode.COLD_TRANSFER_NO_SURPLUS {
+				ReturnError(responseWriter, executingMethod, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", err.Error()), controller.Logger)
+				return
+			}
+			ReturnError(responseWriter, executingMethod, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", err.Error()), controller.Logger)
+			return
+		}
+		if err := tasks.SendColdTransfer(controller.Cache, controller.Logger, controller.Config, baseRepository, &coldTransfer, requestData.ReviewedBy); err != nil {
+			ReturnError(responseWriter, executingMethod, http.StatusInternalServerError, err, apiResponse.PlainError(utility.SVCS_CRYPTOADAPTER_ERR, err.Error()), controller.Logger)
+			return
+		}
+	}
+
+	controller.Logger.Info("Outgoing response to %s request %+v", executingMethod, coldTransfer)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, coldTransfer))
+}
+
+func (controller UserAssetController) getColdWallet(responseWriter http.ResponseWriter, requestReader *http.Request, executingMethod string) (model.ColdWallet, bool) {
+	apiResponse := utility.NewResponse()
+	coldWallet := model.ColdWallet{}
+
+	walletID, err := uuid.FromString(mux.Vars(requestReader)["walletId"])
+	if err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return coldWallet, false
+	}
+	if err := controller.Repository.Get(&model.ColdWallet{BaseModel: model.BaseModel{ID: walletID}}, &coldWallet); err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get cold wallet with id = %s", utility.GetSQLErr(err), walletID)), controller.Logger)
+		return coldWallet, false
+	}
+	return coldWallet, true
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"wallet-adapter/dto"
+	"wallet-adapter/utility"
+)
+
+// GetDustReport ... Returns the value of below-minimum deposits held at each address, optionally filtered by assetSymbol and network
+func (controller UserAssetController) GetDustReport(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	responseData := dto.DustReportResponse{Deposits: []dto.DustDepositSummary{}}
+
+	assetSymbol := requestReader.URL.Query().Get("assetSymbol")
+	network := requestReader.URL.Query().Get("network")
+	controller.Logger.Info("Incoming request details for GetDustReport : symbol : %s, network : %s", assetSymbol, network)
+
+	if err := controller.Repository.FetchDustDepositSummary(assetSymbol, network, &responseData.Deposits); err != nil {
+		ReturnError(responseWriter, "GetDustReport", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetDustReport request %+v", responseData)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(responseData)
+}
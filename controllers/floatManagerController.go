@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
 	"wallet-adapter/tasks"
 	"wallet-adapter/utility"
 )
@@ -38,3 +40,30 @@ func (controller UserAssetController) TriggerFloat(responseWriter http.ResponseW
 
 	<-done
 }
+
+// SimulateFloat ... Replays the float history of an asset against candidate float parameters, nothing is sent or saved
+func (controller UserAssetController) SimulateFloat(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.FloatSimulationRequest{}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for SimulateFloat : %+v", requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "SimulateFloat", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	baseRepository := database.BaseRepository{Database: database.Database{Logger: controller.Logger, Config: controller.Config, DB: controller.Repository.Db()}}
+	report, err := tasks.SimulateFloat(controller.Logger, baseRepository, requestData)
+	if err != nil {
+		ReturnError(responseWriter, "SimulateFloat", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", err.Error()), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to SimulateFloat request %+v", report)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, report))
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+// GetFloatParams ... Lists the float parameters of every asset, with the version in use and whether float management is paused
+func (controller UserAssetController) GetFloatParams(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	floatParams := []model.FloatManagerParam{}
+
+	if err := controller.Repository.Fetch(&floatParams); err != nil {
+		ReturnError(responseWriter, "GetFloatParams", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, floatParams))
+}
+
+// SaveFloatParams ... Sets the float parameters of an asset, or of one of its networks, as a new version
+func (controller UserAssetController) SaveFloatParams(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.SaveFloatParamsRequest{}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for SaveFloatParams : %+v", requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "SaveFloatParams", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+	if requestData.Network != "" {
+		if err := controller.Repository.GetByFieldName(&model.Network{AssetSymbol: requestData.AssetSymbol, Network: requestData.Network}, &model.Network{}); err != nil {
+			ReturnError(responseWriter, "SaveFloatParams", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get network with assetSymbol = %s and network : %s", utility.GetSQLErr(err), requestData.AssetSymbol, requestData.Network)), controller.Logger)
+			return
+		}
+	} else if err := controller.Repository.GetByFieldName(&model.Denomination{AssetSymbol: requestData.AssetSymbol}, &model.Denomination{}); err != nil {
+		ReturnError(responseWriter, "SaveFloatParams", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get denomination with assetSymbol = %s", utility.GetSQLErr(err), requestData.AssetSymbol)), controller.Logger)
+		return
+	}
+
+	floatParam := model.FloatManagerParam{}
+	// a map condition keeps the empty network of asset parameters, a struct condition would skip it
+	if err := controller.Repository.GetByFieldName(map[string]interface{}{"asset_symbol": requestData.AssetSymbol, "network": requestData.Network}, &floatParam); err != nil && err.Error() != errorcode.SQL_404 {
+		ReturnError(responseWriter, "SaveFloatParams", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	floatParam.AssetSymbol, floatParam.Network = requestData.AssetSymbol, requestData.Network
+	floatParam.MinPercentMaxUserBalance, floatParam.MaxPercentMaxUserBalance = requestData.MinPercentMaxUserBalance, requestData.MaxPercentMaxUserBalance
+	floatParam.MinPercentTotalUserBalance = requestData.MinPercentTotalUserBalance
+	floatParam.AveragePercentTotalUserBalance = requestData.AveragePercentTotalUserBalance
+	floatParam.MaxPercentTotalUserBalance = requestData.MaxPercentTotalUserBalance
+	floatParam.PercentMinimumTriggerLevel, floatParam.PercentMaximumTriggerLevel = requestData.PercentMinimumTriggerLevel, requestData.PercentMaximumTriggerLevel
+	floatParam.UpdatedBy = requestData.UpdatedBy
+	floatParam.TargetMode = requestData.TargetMode
+	if floatParam.TargetMode == "" {
+		floatParam.TargetMode = model.FloatTargetMode.STATIC
+	}
+	if err := services.ValidateFloatParams(floatParam); err != nil {
+		ReturnError(responseWriter, "SaveFloatParams", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", err.Error()), controller.Logger)
+		return
+	}
+
+	controller.saveFloatParams(responseWriter, "SaveFloatParams", floatParam)
+}
+
+// PauseFloat ... Stops the float manager from topping up or draining the float of an asset
+func (controller UserAssetController) PauseFloat(responseWriter http.ResponseWriter, requestReader *http.Request) {
+	controller.setFloatPaused(responseWriter, requestReader, "PauseFloat", true)
+}
+
+// ResumeFloat ... Lets the float manager manage the float of an asset again
+func (controller UserAssetController) ResumeFloat(responseWriter http.ResponseWriter, requestReader *http.Request) {
+	controller.setFloatPaused(responseWriter, requestReader, "ResumeFloat", false)
+}
+
+// GetFloatParamVersions ... Lists the versions of float parameters, latest first
+func (controller UserAssetController) GetFloatParamVersions(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	versions := []model.FloatManagerParamVersion{}
+
+	paramID, err := uuid.FromString(mux.Vars(requestReader)["paramId"])
+	if err != nil {
+		ReturnError(responseWriter, "GetFloatParamVersions", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return
+	}
+	if err := controller.Repository.FetchFloatParamVersions(paramID, &versions); err != nil {
+		ReturnError(responseWriter, "GetFloatParamVersions", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, versions))
+}
+
+func (controller UserAssetController) setFloatPaused(responseWriter http.ResponseWriter, requestReader *http.Request, executingMethod string, isPaused bool) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.FloatPauseRequest{}
+
+	paramID, err := uuid.FromString(mux.Vars(requestReader)["paramId"])
+	if err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return
+	}
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for %s : paramId : %s, %+v", executingMethod, paramID, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	floatParam := model.FloatManagerParam{}
+	if err := controller.Repository.Get(&model.FloatManagerParam{BaseModel: model.BaseModel{ID: paramID}}, &floatParam); err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get float params with id = %s", utility.GetSQLErr(err), paramID)), controller.Logger)
+		return
+	}
+	if floatParam.IsPaused == isPaused {
+		// nothing changes, so no version is added
+		responseWriter.Header().Set("Content-Type", "application/json")
+		responseWriter.WriteHeader(http.StatusOK)
+		json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, floatParam))
+		return
+	}
+	floatParam.IsPaused, floatParam.UpdatedBy = isPaused, requestData.UpdatedBy
+
+	controller.saveFloatParams(responseWriter, executingMethod, floatParam)
+}
+
+func (controller UserAssetController) saveFloatParams(responseWriter http.ResponseWriter, executingMethod string, floatParam model.FloatManagerParam) {
+	apiResponse := utility.NewResponse()
+
+	if err := services.SaveFloatParams(controller.Repository, &floatParam); err != nil {
+		ReturnError(responseWriter, executingMethod, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to %s request %+v", executingMethod, floatParam)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, floatParam))
+}
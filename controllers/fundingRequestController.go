@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"wallet-adapter/database"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+)
+
+// GetFundingRequests ... Lists the requests to the cold wallet users to top up the float, latest first, filtered by asset, network and status
+func (controller UserAssetController) GetFundingRequests(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	fundingRequests := []model.FundingRequest{}
+
+	query := requestReader.URL.Query()
+	statuses := []string{}
+	if status := query.Get("status"); status != "" {
+		statuses = append(statuses, status)
+	}
+	if err := controller.Repository.FetchFundingRequests(query.Get("assetSymbol"), query.Get("network"), statuses, &fundingRequests); err != nil {
+		ReturnError(responseWriter, "GetFundingRequests", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetFundingRequests request %+v", len(fundingRequests))
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, fundingRequests))
+}
+
+// fundingRequestAckPage is shown when the acknowledgement link of a funding email is followed. Opening the link changes nothing,
+// so link scanners in mail clients cannot acknowledge a request, the request is acknowledged when the form is posted
+var fundingRequestAckPage = template.Must(template.New("fundingRequestAck").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Funding request {{.Request.ID}}</title></head>
+<body>
+<h3>Float top up of {{.Request.Amount}} {{.Request.AssetSymbol}} on {{.Request.Network}}</h3>
+<p>Float address: {{.Request.FloatAddress}}</p>
+{{if .Acknowledged}}<p>Acknowledged by {{.Request.AcknowledgedBy}}.</p>
+{{else if eq .Request.Status "OPEN"}}<form method="post">
+<input type="hidden" name="by" value="{{.By}}">
+<button type="submit">Acknowledge funding request</button>
+</form>
+{{else}}<p>This funding request is {{.Request.Status}}.</p>
+{{end}}</body>
+</html>
+`))
+
+type fundingRequestAckView struct {
+	Request      model.FundingRequest
+	By           string
+	Acknowledged bool
+}
+
+// ShowFundingRequestAcknowledgement ... Shows the funding request of the link in the funding email with a form to acknowledge it,
+// the token in the link stands in for authentication
+func (controller UserAssetController) ShowFundingRequestAcknowledgement(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	baseRepository := database.BaseRepository{Database: database.Database{Logger: controller.Logger, Config: controller.Config, DB: controller.Repository.Db()}}
+	fundingRequest, err := tasks.GetFundingRequestByAckToken(baseRepository, mux.Vars(requestReader)["token"])
+	if err != nil {
+		if err.Error() == errorcode.SQL_404 {
+			ReturnError(responseWriter, "ShowFundingRequestAcknowledgement", http.StatusNotFound, err, apiResponse.PlainError("INPUT_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+		ReturnError(responseWriter, "ShowFundingRequestAcknowledgement", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to ShowFundingRequestAcknowledgement request %+v", fundingRequest.ID)
+	writeFundingRequestAckPage(responseWriter, fundingRequestAckView{Request: fundingRequest, By: requestReader.URL.Query().Get("by"), Acknowledged: fundingRequest.Status == model.FundingRequestStatus.ACKNOWLEDGED}, controller.Logger)
+}
+
+// AcknowledgeFundingRequest ... Acknowledges a funding request from the form on the acknowledgement page, the token in the link stands in for authentication
+func (controller UserAssetController) AcknowledgeFundingRequest(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	acknowledgedBy := requestReader.FormValue("by")
+	if acknowledgedBy == "" {
+		acknowledgedBy = controller.Config.ColdWalletEmail
+	}
+
+	baseRepository := database.BaseRepository{Database: database.Database{Logger: controller.Logger, Config: controller.Config, DB: controller.Repository.Db()}}
+	fundingRequest, err := tasks.AcknowledgeFundingRequest(baseRepository, mux.Vars(requestReader)["token"], acknowledgedBy)
+	if err != nil {
+		switch err.Error() {
+		case errorcode.SQL_404:
+			ReturnError(responseWriter, "AcknowledgeFundingRequest", http.StatusNotFound, err, apiResponse.PlainError("INPUT_ERR", utility.GetSQLErr(err)), controller.Logger)
+		case errorcode.FUNDING_REQUEST_CLOSED:
+			ReturnError(responseWriter, "AcknowledgeFundingRequest", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", err.Error()), controller.Logger)
+		default:
+			ReturnError(responseWriter, "AcknowledgeFundingRequest", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		}
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to AcknowledgeFundingRequest request %+v", fundingRequest.ID)
+	writeFundingRequestAckPage(responseWriter, fundingRequestAckView{Request: fundingRequest, Acknowledged: true}, controller.Logger)
+}
+
+func writeFundingRequestAckPage(responseWriter http.ResponseWriter, view fundingRequestAckView, logger *utility.Logger) {
+	responseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
+	responseWriter.WriteHeader(http.StatusOK)
+	if err := fundingRequestAckPage.Execute(responseWriter, view); err != nil {
+		logger.Error("Funding request acknowledgement page could not be written : %s", err)
+	}
+}
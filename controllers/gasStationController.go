@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+)
+
+// GetFeeWallets ... Lists the wallets funding sweep fees, one per network
+func (controller UserAssetController) GetFeeWallets(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	feeWallets := []model.FeeWallet{}
+
+	if err := controller.Repository.Fetch(&feeWallets); err != nil {
+		ReturnError(responseWriter, "GetFeeWallets", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, feeWallets))
+}
+
+// SaveFeeWallet ... Sets the wallet funding sweep fees on a network, replacing the current one
+func (controller UserAssetController) SaveFeeWallet(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.SaveFeeWalletRequest{}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for SaveFeeWallet : %+v", requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "SaveFeeWallet", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+	network := model.Network{}
+	if err := controller.Repository.GetByFieldName(&model.Network{Network: requestData.Network}, &network); err != nil {
+		ReturnError(responseWriter, "SaveFeeWallet", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get network : %s", utility.GetSQLErr(err), requestData.Network)), controller.Logger)
+		return
+	}
+
+	feeWallet := model.FeeWallet{}
+	// the update is a map so a wallet can be enabled again, zero values of a struct are not assigned
+	update := map[string]interface{}{"native_asset": network.NativeAsset, "address": requestData.Address, "is_disabled": requestData.IsDisabled}
+	if err := controller.Repository.UpdateOrCreate(model.FeeWallet{Network: requestData.Network}, &feeWallet, update); err != nil {
+		ReturnError(responseWriter, "SaveFeeWallet", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to SaveFeeWallet request %+v", feeWallet)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, feeWallet))
+}
+
+// GetGasFundings ... Lists the sweep fee fundings sent to deposit addresses, filtered by status, address and network
+func (controller UserAssetController) GetGasFundings(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	fundings := []model.GasFunding{}
+
+	query := requestReader.URL.Query()
+	statuses := []string{}
+	if status := query.Get("status"); status != "" {
+		statuses = append(statuses, status)
+	}
+	controller.Logger.Info("Incoming request details for GetGasFundings : status : %s, address : %s, network : %s", query.Get("status"), query.Get("address"), query.Get("network"))
+
+	if err := controller.Repository.FetchGasFundings(query.Get("address"), query.Get("network"), statuses, &fundings); err != nil {
+		ReturnError(responseWriter, "GetGasFundings", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetGasFundings request %+v", len(fundings))
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, fundings))
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+// GetMaintenanceStatus ... Lists the maintenance windows in progress and scheduled, this endpoint is public
+func (controller UserAssetController) GetMaintenanceStatus(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+
+	status, err := services.GetMaintenanceStatus(controller.Repository)
+	if err != nil {
+		ReturnError(responseWriter, "GetMaintenanceStatus", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, status))
+}
+
+// CreateMaintenanceWindow ... Schedules a maintenance window pausing an operation on a network
+func (controller UserAssetController) CreateMaintenanceWindow(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.CreateMaintenanceWindowRequest{}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for CreateMaintenanceWindow : %+v", requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "CreateMaintenanceWindow", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+	if err := controller.Repository.GetByFieldName(&model.Network{AssetSymbol: requestData.AssetSymbol, Network: requestData.Network}, &model.Network{}); err != nil {
+		ReturnError(responseWriter, "CreateMaintenanceWindow", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get network with assetSymbol = %s and network : %s", utility.GetSQLErr(err), requestData.AssetSymbol, requestData.Network)), controller.Logger)
+		return
+	}
+
+	window := model.MaintenanceWindow{
+		AssetSymbol: requestData.AssetSymbol,
+		Network:     requestData.Network,
+		Scope:       requestData.Scope,
+		StartsAt:    requestData.StartsAt,
+		EndsAt:      requestData.EndsAt,
+		Message:     requestData.Message,
+		CreatedBy:   requestData.CreatedBy,
+	}
+	if err := controller.Repository.Create(&window); err != nil {
+		ReturnError(responseWriter, "CreateMaintenanceWindow", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to CreateMaintenanceWindow request %+v", window)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusCreated)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, window))
+}
+
+// CancelMaintenanceWindow ... Ends a maintenance window early, or cancels it before it starts
+func (controller UserAssetController) CancelMaintenanceWindow(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.CancelMaintenanceWindowRequest{}
+
+	windowID, err := uuid.FromString(mux.Vars(requestReader)["windowId"])
+	if err != nil {
+		ReturnError(responseWriter, "CancelMaintenanceWindow", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return
+	}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for CancelMaintenanceWindow : windowId : %s, %+v", windowID, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "CancelMaintenanceWindow", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	window := model.MaintenanceWindow{}
+	if err := controller.Repository.Get(&model.MaintenanceWindow{BaseModel: model.BaseModel{ID: windowID}}, &window); err != nil {
+		ReturnError(responseWriter, "CancelMaintenanceWindow", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get maintenance window with id = %s", utility.GetSQLErr(err), windowID)), controller.Logger)
+		return
+	}
+	now := time.Now()
+	if window.CancelledAt != nil || !window.EndsAt.After(now) {
+		ReturnError(responseWriter, "CancelMaintenanceWindow", http.StatusBadRequest, errorcode.MAINTENANCE_WINDOW_ENDED, apiResponse.PlainError("INPUT_ERR", errorcode.MAINTENANCE_WINDOW_ENDED), controller.Logger)
+		return
+	}
+
+	if err := controller.Repository.Update(&window, model.MaintenanceWindow{CancelledBy: requestData.CancelledBy, CancelledAt: &now}); err != nil {
+		ReturnError(responseWriter, "CancelMaintenanceWindow", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to CancelMaintenanceWindow request %+v", window)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, window))
+}
+
+// returnMaintenanceError responds with the maintenance window pausing an operation, clients can retry once it ends
+func returnMaintenanceError(responseWriter http.ResponseWriter, executingMethod string, err error, logger *utility.Logger) bool {
+	maintenanceErr, ok := err.(services.MaintenanceError)
+	if !ok {
+		return false
+	}
+	apiResponse := utility.NewResponse()
+	retryAfter := math.Ceil(time.Until(maintenanceErr.Window.EndsAt).Seconds())
+	responseWriter.Header().Set("Retry-After", strconv.FormatFloat(math.Max(retryAfter, 0), 'f', 0, 64))
+	ReturnError(responseWriter, executingMethod, http.StatusServiceUnavailable, err,
+		apiResponse.Error(errorcode.NETWORK_UNDER_MAINTENANCE_CODE, maintenanceErr.Error(), services.MaintenanceWindowToStatus(maintenanceErr.Window)), logger)
+	return true
+}
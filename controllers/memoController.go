@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"wallet-adapter/dto"
+	"wallet-adapter/utility"
+)
+
+// GetDuplicateMemos ... Reports memos assigned to more than one user
+func (controller UserAssetController) GetDuplicateMemos(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	duplicateMemos := []dto.DuplicateMemo{}
+
+	if err := controller.Repository.FetchDuplicateMemos(&duplicateMemos); err != nil {
+		ReturnError(responseWriter, "GetDuplicateMemos", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetDuplicateMemos request %+v", duplicateMemos)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, duplicateMemos))
+}
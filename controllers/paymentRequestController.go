@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+	"wallet-adapter/utility/qrcode"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultQRCodeScale = 8
+	maxQRCodeScale     = 40
+)
+
+// GetPaymentRequest ... Returns the deposit address of an asset with its payment URI, amount and label are optional query params
+func (controller UserAssetController) GetPaymentRequest(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	paymentRequest, ok := controller.buildPaymentRequest(responseWriter, requestReader, "GetPaymentRequest")
+	if !ok {
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetPaymentRequest request %+v", paymentRequest)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(paymentRequest)
+}
+
+// GetPaymentRequestQRCode ... Renders the payment URI of an asset deposit address as a PNG (default) or SVG QR code
+func (controller UserAssetController) GetPaymentRequestQRCode(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	format := requestReader.URL.Query().Get("format")
+	if format == "" {
+		format = "png"
+	}
+	if format != "png" && format != "svg" {
+		ReturnError(responseWriter, "GetPaymentRequestQRCode", http.StatusBadRequest, errorcode.INPUT_ERR, apiResponse.PlainError("INPUT_ERR", "format must be png or svg"), controller.Logger)
+		return
+	}
+	scale := defaultQRCodeScale
+	if size := requestReader.URL.Query().Get("scale"); size != "" {
+		var err error
+		if scale, err = strconv.Atoi(size); err != nil || scale < 1 || scale > maxQRCodeScale {
+			ReturnError(responseWriter, "GetPaymentRequestQRCode", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("scale must be between 1 and %d", maxQRCodeScale)), controller.Logger)
+			return
+		}
+	}
+
+	paymentRequest, ok := controller.buildPaymentRequest(responseWriter, requestReader, "GetPaymentRequestQRCode")
+	if !ok {
+		return
+	}
+
+	code, err := qrcode.Encode(paymentRequest.URI)
+	if err != nil {
+		ReturnError(responseWriter, "GetPaymentRequestQRCode", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", err.Error()), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetPaymentRequestQRCode request %s, format : %s", paymentRequest.URI, format)
+	if format == "svg" {
+		responseWriter.Header().Set("Content-Type", "image/svg+xml")
+		responseWriter.WriteHeader(http.StatusOK)
+		_, _ = responseWriter.Write([]byte(code.SVG(scale)))
+		return
+	}
+	image, err := code.PNG(scale)
+	if err != nil {
+		ReturnError(responseWriter, "GetPaymentRequestQRCode", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
+		return
+	}
+	responseWriter.Header().Set("Content-Type", "image/png")
+	responseWriter.WriteHeader(http.StatusOK)
+	_, _ = responseWriter.Write(image)
+}
+
+func (controller UserAssetController) buildPaymentRequest(responseWriter http.ResponseWriter, requestReader *http.Request, funcName string) (dto.PaymentRequest, bool) {
+
+	var userAsset model.UserAsset
+	apiResponse := utility.NewResponse()
+	routeParams := mux.Vars(requestReader)
+	query := requestReader.URL.Query()
+	network := query.Get("network")
+	addressType := query.Get("addressType")
+
+	assetID, err := uuid.FromString(routeParams["assetId"])
+	if err != nil {
+		ReturnError(responseWriter, funcName, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return dto.PaymentRequest{}, false
+	}
+	controller.Logger.Info("Incoming request details for %s : assetId : %s, network : %s, addressType : %s", funcName, assetID, network, addressType)
+
+	if err := controller.VerifyDepositIsSupportedAndPopulateAsset(assetID, network, &userAsset); err != nil {
+		if returnMaintenanceError(responseWriter, funcName, err, controller.Logger) {
+			return dto.PaymentRequest{}, false
+		}
+		ReturnError(responseWriter, funcName, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR",
+			fmt.Sprintf("%s, for get asset address with id = %s", errorcode.DEPOSIT_NOT_ACTIVE, assetID)), controller.Logger)
+		return dto.PaymentRequest{}, false
+	}
+	if network == "" {
+		network = userAsset.DefaultNetwork
+	}
+
+	networkRecord, err := services.GetNetworkByAssetAndNetwork(controller.Repository, network, userAsset.AssetSymbol)
+	if err != nil {
+		ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
+		return dto.PaymentRequest{}, false
+	}
+
+	userAddressService := services.NewService(controller.Cache, controller.Logger, controller.Config)
+	addresses, err := controller.GetAddressesForNetwork(mapNetworkToAssetStruct(networkRecord, userAsset), userAddressService)
+	if err != nil || len(addresses) == 0 {
+		ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
+		return dto.PaymentRequest{}, false
+	}
+	address := addresses[0]
+	if addressType != "" {
+		found := false
+		for _, candidate := range addresses {
+			if candidate.Type == addressType {
+				address, found = candidate, true
+				break
+			}
+		}
+		if !found {
+			ReturnError(responseWriter, funcName, http.StatusBadRequest, errorcode.INPUT_ERR, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("No %s address for asset %s on %s", addressType, assetID, network)), controller.Logger)
+			return dto.PaymentRequest{}, false
+		}
+	}
+
+	uri, err := services.BuildPaymentURI(networkRecord, address, query.Get("amount"), query.Get("label"))
+	if err != nil {
+		ReturnError(responseWriter, funcName, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", err.Error()), controller.Logger)
+		return dto.PaymentRequest{}, false
+	}
+
+	return dto.PaymentRequest{
+		Address: address.Address,
+		Network: network,
+		Memo:    address.Memo,
+		Type:    address.Type,
+		URI:     uri,
+	}, true
+}
@@ -0,0 +1,279 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+	"github.com/shopspring/decimal"
+)
+
+// GetScreeningHolds ... Lists transactions held after a sanctions screening hit, filtered by status (defaults to HELD)
+func (controller UserAssetController) GetScreeningHolds(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	screeningHolds := []model.ScreeningHold{}
+
+	status := requestReader.URL.Query().Get("status")
+	if status == "" {
+		status = model.ScreeningHoldStatus.HELD
+	}
+	controller.Logger.Info("Incoming request details for GetScreeningHolds : status : %s", status)
+
+	if err := controller.Repository.FetchByFieldName(&model.ScreeningHold{Status: status}, &screeningHolds); err != nil {
+		ReturnError(responseWriter, "GetScreeningHolds", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetScreeningHolds request %+v", len(screeningHolds))
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, screeningHolds))
+}
+
+// ReleaseScreeningHold ... Releases a held transaction, deposits are credited and withdrawals are queued for processing
+func (controller UserAssetController) ReleaseScreeningHold(responseWriter http.ResponseWriter, requestReader *http.Request) {
+	controller.reviewScreeningHold(responseWriter, requestReader, "ReleaseScreeningHold", model.ScreeningHoldStatus.RELEASED)
+}
+
+// RejectScreeningHold ... Rejects a held transaction, the transaction is marked REJECTED and never processed
+func (controller UserAssetController) RejectScreeningHold(responseWriter http.ResponseWriter, requestReader *http.Request) {
+	controller.reviewScreeningHold(responseWriter, requestReader, "RejectScreeningHold", model.ScreeningHoldStatus.REJECTED)
+}
+
+func (controller UserAssetController) reviewScreeningHold(responseWriter http.ResponseWriter, requestReader *http.Request, funcName, reviewStatus string) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.ReviewScreeningHoldRequest{}
+
+	routeParams := mux.Vars(requestReader)
+	holdID, err := uuid.FromString(routeParams["holdId"])
+	if err != nil {
+		ReturnError(responseWriter, funcName, http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return
+	}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for %s : holdId : %s, %+v", funcName, holdID, requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, funcName, http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+
+	screeningHold := model.ScreeningHold{}
+	if err := controller.Repository.Get(&model.ScreeningHold{BaseModel: model.BaseModel{ID: holdID}}, &screeningHold); err != nil {
+		ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get screeningHold with id = %s", utility.GetSQLErr(err), holdID)), controller.Logger)
+		return
+	}
+	if screeningHold.Status != model.ScreeningHoldStatus.HELD {
+		ReturnError(responseWriter, funcName, http.StatusBadRequest, errorcode.SCREENING_HOLD_NOT_PENDING, apiResponse.PlainError("INPUT_ERR", errorcode.SCREENING_HOLD_NOT_PENDING), controller.Logger)
+		return
+	}
+
+	transaction := model.Transaction{}
+	if err := controller.Repository.Get(&model.Transaction{BaseModel: model.BaseModel{ID: screeningHold.TransactionID}}, &transaction); err != nil {
+		ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get transaction with id = %s", utility.GetSQLErr(err), screeningHold.TransactionID)), controller.Logger)
+		return
+	}
+	assetNetworkDetails, err := services.GetNetworkByAssetAndNetwork(controller.Repository, transaction.Network, transaction.AssetSymbol)
+	if err != nil {
+		ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get assetNetworkDetails with assetSymbol = %s and network : %s", utility.GetSQLErr(err), transaction.AssetSymbol, transaction.Network)), controller.Logger)
+		return
+	}
+	value, err := decimal.NewFromString(transaction.Value)
+	if err != nil {
+		ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
+		return
+	}
+	value = value.Round(int32(assetNetworkDetails.NativeDecimals))
+
+	tx := controller.Repository.Db().Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+	if err := tx.Error; err != nil {
+		ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
+		return
+	}
+
+	// the hold is claimed first, so of two reviews of the same hold only one goes through
+	reviewedAt := time.Now()
+	claim := tx.Model(&model.ScreeningHold{}).Where("id = ? AND status = ?", holdID, model.ScreeningHoldStatus.HELD).
+		Updates(map[string]interface{}{"status": reviewStatus, "reviewed_by": requestData.ReviewedBy, "review_note": requestData.Note, "reviewed_at": reviewedAt})
+	if err := claim.Error; err != nil {
+		tx.Rollback()
+		ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+	if claim.RowsAffected != 1 {
+		tx.Rollback()
+		ReturnError(responseWriter, funcName, http.StatusBadRequest, errorcode.SCREENING_HOLD_NOT_PENDING, apiResponse.PlainError("INPUT_ERR", errorcode.SCREENING_HOLD_NOT_PENDING), controller.Logger)
+		return
+	}
+
+	transactionUpdate := model.Transaction{TransactionStatus: model.TransactionStatus.REJECTED, TransactionEndDate: time.Now()}
+	switch {
+	case reviewStatus == model.ScreeningHoldStatus.RELEASED && transaction.TransactionTag == model.TransactionTag.WITHDRAW:
+		// a released withdrawal goes out on its own, the batch it may have been put in could have been sent without it
+		if err := unbatchTransactionInTx(tx, transaction.ID); err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+		transactionUpdate = model.Transaction{TransactionStatus: model.TransactionStatus.PENDING}
+	case reviewStatus == model.ScreeningHoldStatus.RELEASED && transaction.TransactionTag == model.TransactionTag.DEPOSIT:
+		// A released deposit is credited to the user asset at release time, unless it is below the network minimum deposit
+		// in which case the dust policy of the network applies as it does to deposits that were never held
+		if depositValue, _ := value.Float64(); services.IsDustDeposit(depositValue, assetNetworkDetails) {
+			availableBalance, err := recordDustDepositInTx(tx, transaction.RecipientID, value, assetNetworkDetails.DustPolicy)
+			if err != nil {
+				tx.Rollback()
+				ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+				return
+			}
+			controller.Logger.Info("%s logs : released deposit %s of %s is below minimum deposit %v, applying dust policy %s", funcName, transaction.TransactionReference, value, assetNetworkDetails.MinimumDeposit, assetNetworkDetails.DustPolicy)
+			transactionUpdate = model.Transaction{
+				TransactionStatus:  model.TransactionStatus.DUST,
+				PreviousBalance:    availableBalance,
+				AvailableBalance:   availableBalance,
+				TransactionEndDate: time.Now(),
+			}
+			break
+		}
+		previousBalance, availableBalance, err := creditUserAssetInTx(tx, transaction.RecipientID, value)
+		if err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+		transactionUpdate = model.Transaction{
+			TransactionStatus:  model.TransactionStatus.COMPLETED,
+			PreviousBalance:    previousBalance,
+			AvailableBalance:   availableBalance,
+			TransactionEndDate: time.Now(),
+		}
+	case transaction.TransactionTag == model.TransactionTag.WITHDRAW:
+		// the user was debited before the withdrawal was held, a rejected withdrawal gives the value back
+		if err := controller.reverseRejectedWithdrawal(tx, transaction, value); err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+	}
+
+	if err := tx.Model(&transaction).Updates(transactionUpdate).Error; err != nil {
+		tx.Rollback()
+		ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	if transaction.TransactionTag == model.TransactionTag.WITHDRAW {
+		if err := tx.Model(&model.TransactionQueue{}).Where("transaction_id = ?", transaction.ID).Updates(model.TransactionQueue{TransactionStatus: transactionUpdate.TransactionStatus}).Error; err != nil {
+			tx.Rollback()
+			ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		ReturnError(responseWriter, funcName, http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseData := dto.TransactionReceipt{
+		AssetID:              transaction.RecipientID,
+		Value:                transaction.Value,
+		TransactionReference: transaction.TransactionReference,
+		PaymentReference:     transaction.PaymentReference,
+		TransactionStatus:    transactionUpdate.TransactionStatus,
+	}
+
+	controller.Logger.Info("Outgoing response to %s request %+v", funcName, responseData)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(responseData)
+}
+
+// reverseRejectedWithdrawal credits the value of a rejected withdrawal back to the user asset it was debited from, records the credit
+// and takes the withdrawal out of its batch, if any, so the batch is never sent with it
+func (controller UserAssetController) reverseRejectedWithdrawal(tx *gorm.DB, transaction model.Transaction, value decimal.Decimal) error {
+	previousBalance, availableBalance, err := creditUserAssetInTx(tx, transaction.RecipientID, value)
+	if err != nil {
+		return err
+	}
+	reversal := model.Transaction{
+		InitiatorID:          transaction.InitiatorID,
+		RecipientID:          transaction.RecipientID,
+		TransactionReference: "REVERSAL-" + transaction.TransactionReference,
+		PaymentReference:     "REVERSAL-" + transaction.PaymentReference,
+		DebitReference:       transaction.DebitReference,
+		Memo:                 "SCREENING_REJECTED",
+		TransactionType:      model.TransactionType.OFFCHAIN,
+		TransactionStatus:    model.TransactionStatus.COMPLETED,
+		TransactionTag:       model.TransactionTag.CREDIT,
+		Value:                value.String(),
+		PreviousBalance:      previousBalance,
+		AvailableBalance:     availableBalance,
+		ProcessingType:       model.ProcessingType.SINGLE,
+		TransactionStartDate: time.Now(),
+		TransactionEndDate:   time.Now(),
+		AssetSymbol:          transaction.AssetSymbol,
+		Network:              transaction.Network,
+	}
+	if err := tx.Create(&reversal).Error; err != nil {
+		return err
+	}
+	return unbatchTransactionInTx(tx, transaction.ID)
+}
+
+// unbatchTransactionInTx takes a withdrawal out of its batch, if any
+func unbatchTransactionInTx(tx *gorm.DB, transactionID uuid.UUID) error {
+	if err := tx.Model(&model.TransactionQueue{}).Where("transaction_id = ?", transactionID).Updates(map[string]interface{}{"batch_id": uuid.Nil}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&model.Transaction{}).Where("id = ?", transactionID).Updates(map[string]interface{}{"batch_id": uuid.Nil}).Error
+}
+
+// recordDustDepositInTx leaves the available balance of a user asset as it is for a dust deposit, the BUCKET policy adds the value
+// to the dust balance. It returns the available balance
+func recordDustDepositInTx(tx *gorm.DB, assetID uuid.UUID, value decimal.Decimal, dustPolicy string) (string, error) {
+	if dustPolicy == model.DustPolicy.BUCKET {
+		if err := tx.Model(&model.UserAsset{}).Where("id = ?", assetID).Update("dust_balance", gorm.Expr("dust_balance + ?", value.String())).Error; err != nil {
+			return "", err
+		}
+	}
+	assetDetails := model.UserAsset{}
+	if err := tx.Where("id = ?", assetID).First(&assetDetails).Error; err != nil {
+		return "", err
+	}
+	return assetDetails.AvailableBalance, nil
+}
+
+// creditUserAssetInTx adds the value to the balance of a user asset in the database, so concurrent credits are not lost, and returns the
+// balance before and after
+func creditUserAssetInTx(tx *gorm.DB, assetID uuid.UUID, value decimal.Decimal) (string, string, error) {
+	if err := tx.Model(&model.UserAsset{}).Where("id = ?", assetID).Update("available_balance", gorm.Expr("available_balance + ?", value.String())).Error; err != nil {
+		return "", "", err
+	}
+	assetDetails := model.UserAsset{}
+	if err := tx.Where("id = ?", assetID).First(&assetDetails).Error; err != nil {
+		return "", "", err
+	}
+	availableBalance, err := decimal.NewFromString(assetDetails.AvailableBalance)
+	if err != nil {
+		return "", "", err
+	}
+	return availableBalance.Sub(value).String(), availableBalance.String(), nil
+}
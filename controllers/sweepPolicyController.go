@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+// GetSweepPolicies ... Lists the sweep policies, assets without one fill the float to its target and send the rest to the brokerage
+func (controller UserAssetController) GetSweepPolicies(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	sweepPolicies := []model.SweepPolicy{}
+
+	if err := controller.Repository.Fetch(&sweepPolicies); err != nil {
+		ReturnError(responseWriter, "GetSweepPolicies", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, sweepPolicies))
+}
+
+// SaveSweepPolicy ... Sets how the sweeps of an asset on a network are split, replacing its current policy
+func (controller UserAssetController) SaveSweepPolicy(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	requestData := dto.SaveSweepPolicyRequest{}
+
+	json.NewDecoder(requestReader.Body).Decode(&requestData)
+	controller.Logger.Info("Incoming request details for SaveSweepPolicy : %+v", requestData)
+
+	if validationErr := ValidateRequest(controller.Validator, requestData, controller.Logger); len(validationErr) > 0 {
+		ReturnError(responseWriter, "SaveSweepPolicy", http.StatusBadRequest, validationErr, apiResponse.Error("INPUT_ERR", errorcode.INPUT_ERR, validationErr), controller.Logger)
+		return
+	}
+	sendsToCold := requestData.Type == model.SweepPolicyType.FLOAT_THEN_COLD || requestData.Type == model.SweepPolicyType.ALL_TO_COLD
+	if sendsToCold && requestData.ColdAddress == "" {
+		err := errors.New(errorcode.SWEEP_POLICY_COLD_ADDRESS_REQUIRED)
+		ReturnError(responseWriter, "SaveSweepPolicy", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.SWEEP_POLICY_COLD_ADDRESS_REQUIRED), controller.Logger)
+		return
+	}
+	if requestData.ColdAddress != "" {
+		// sweeps only go to cold wallets in the registry that have been verified
+		coldWallet := model.ColdWallet{}
+		if err := controller.Repository.GetByFieldName(&model.ColdWallet{AssetSymbol: requestData.AssetSymbol, Network: requestData.Network, Address: requestData.ColdAddress, IsVerified: true}, &coldWallet); err != nil {
+			ReturnError(responseWriter, "SaveSweepPolicy", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.SWEEP_POLICY_COLD_ADDRESS_REQUIRED), controller.Logger)
+			return
+		}
+		if requestData.ColdMemo == "" {
+			requestData.ColdMemo = coldWallet.Memo
+		}
+	}
+	if err := controller.Repository.GetByFieldName(&model.Network{AssetSymbol: requestData.AssetSymbol, Network: requestData.Network}, &model.Network{}); err != nil {
+		ReturnError(responseWriter, "SaveSweepPolicy", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get network with assetSymbol = %s and network : %s", utility.GetSQLErr(err), requestData.AssetSymbol, requestData.Network)), controller.Logger)
+		return
+	}
+
+	sweepPolicy := model.SweepPolicy{}
+	// the update is a map so fields can be cleared, zero values of a struct are not assigned
+	update := map[string]interface{}{
+		"type":          requestData.Type,
+		"float_percent": requestData.FloatPercent,
+		"cold_address":  requestData.ColdAddress,
+		"cold_memo":     requestData.ColdMemo,
+		"updated_by":    requestData.UpdatedBy,
+	}
+	if err := controller.Repository.UpdateOrCreate(model.SweepPolicy{AssetSymbol: requestData.AssetSymbol, Network: requestData.Network}, &sweepPolicy, update); err != nil {
+		ReturnError(responseWriter, "SaveSweepPolicy", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to SaveSweepPolicy request %+v", sweepPolicy)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, sweepPolicy))
+}
+
+// DeleteSweepPolicy ... Removes the sweep policy of an asset, its sweeps go back to filling the float and sending the rest to the brokerage
+func (controller UserAssetController) DeleteSweepPolicy(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+
+	policyID, err := uuid.FromString(mux.Vars(requestReader)["policyId"])
+	if err != nil {
+		ReturnError(responseWriter, "DeleteSweepPolicy", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return
+	}
+	controller.Logger.Info("Incoming request details for DeleteSweepPolicy : policyId : %s", policyID)
+
+	sweepPolicy := model.SweepPolicy{}
+	if err := controller.Repository.Get(&model.SweepPolicy{BaseModel: model.BaseModel{ID: policyID}}, &sweepPolicy); err != nil {
+		ReturnError(responseWriter, "DeleteSweepPolicy", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get sweep policy with id = %s", utility.GetSQLErr(err), policyID)), controller.Logger)
+		return
+	}
+	if err := controller.Repository.Delete(&sweepPolicy); err != nil {
+		ReturnError(responseWriter, "DeleteSweepPolicy", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to DeleteSweepPolicy request %+v", sweepPolicy)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, sweepPolicy))
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"wallet-adapter/database"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultSweepRunLimit = 50
+	maxSweepRunLimit     = 500
+)
+
+// GetSweepRuns ... Lists the latest sweep runs with the number of addresses swept, skipped and failed
+func (controller UserAssetController) GetSweepRuns(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	sweepRuns := []model.SweepRun{}
+
+	limit := defaultSweepRunLimit
+	if size := requestReader.URL.Query().Get("limit"); size != "" {
+		var err error
+		if limit, err = strconv.Atoi(size); err != nil || limit < 1 || limit > maxSweepRunLimit {
+			ReturnError(responseWriter, "GetSweepRuns", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("limit must be between 1 and %d", maxSweepRunLimit)), controller.Logger)
+			return
+		}
+	}
+
+	if err := controller.Repository.FetchSweepRuns(limit, &sweepRuns); err != nil {
+		ReturnError(responseWriter, "GetSweepRuns", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, sweepRuns))
+}
+
+// GetSweepItems ... Lists what a sweep run did for each address, filtered by status and address
+func (controller UserAssetController) GetSweepItems(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	sweepItems := []model.SweepItem{}
+
+	sweepRunID, err := uuid.FromString(mux.Vars(requestReader)["runId"])
+	if err != nil {
+		ReturnError(responseWriter, "GetSweepItems", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", errorcode.UUID_CAST_ERR), controller.Logger)
+		return
+	}
+	status := requestReader.URL.Query().Get("status")
+	address := requestReader.URL.Query().Get("address")
+	controller.Logger.Info("Incoming request details for GetSweepItems : runId : %s, status : %s, address : %s", sweepRunID, status, address)
+
+	if err := controller.Repository.Get(&model.SweepRun{BaseModel: model.BaseModel{ID: sweepRunID}}, &model.SweepRun{}); err != nil {
+		ReturnError(responseWriter, "GetSweepItems", http.StatusBadRequest, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get sweep run with id = %s", utility.GetSQLErr(err), sweepRunID)), controller.Logger)
+		return
+	}
+	if err := controller.Repository.FetchSweepItems(sweepRunID, status, address, &sweepItems); err != nil {
+		ReturnError(responseWriter, "GetSweepItems", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetSweepItems request %+v", len(sweepItems))
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, sweepItems))
+}
+
+// GetSweepPlan ... Dry run of the sweep job, shows what it would sweep, where to and what it would skip without sending anything
+func (controller UserAssetController) GetSweepPlan(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+
+	baseRepository := database.BaseRepository{Database: database.Database{Logger: controller.Logger, Config: controller.Config, DB: controller.Repository.Db()}}
+	sweepPlan, err := tasks.PlanSweep(controller.Cache, controller.Logger, controller.Config, baseRepository)
+	if err != nil {
+		ReturnError(responseWriter, "GetSweepPlan", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", utility.GetSQLErr(err)), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetSweepPlan request %+v", len(sweepPlan.Items))
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(apiResponse.Successful(utility.SUCCESSFUL, utility.SUCCESS, sweepPlan))
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+)
+
+// GetTravelRuleRecord ... Exports the travel rule record of a transaction as an IVMS101 payload
+func (controller UserAssetController) GetTravelRuleRecord(responseWriter http.ResponseWriter, requestReader *http.Request) {
+
+	apiResponse := utility.NewResponse()
+	routeParams := mux.Vars(requestReader)
+	transactionRef := routeParams["reference"]
+	controller.Logger.Info("Incoming request details for GetTravelRuleRecord : transactionReference : %s", transactionRef)
+
+	transaction := model.Transaction{}
+	if err := controller.Repository.GetByFieldName(&model.Transaction{TransactionReference: transactionRef}, &transaction); err != nil {
+		ReturnError(responseWriter, "GetTravelRuleRecord", http.StatusInternalServerError, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get transaction with transactionReference = %s", utility.GetSQLErr(err), transactionRef)), controller.Logger)
+		return
+	}
+
+	travelRuleRecord := model.TravelRuleRecord{}
+	if err := controller.Repository.GetByFieldName(&model.TravelRuleRecord{TransactionID: transaction.ID}, &travelRuleRecord); err != nil {
+		ReturnError(responseWriter, "GetTravelRuleRecord", http.StatusInternalServerError, err, apiResponse.PlainError("INPUT_ERR", fmt.Sprintf("%s, for get travel rule record with transactionReference = %s", utility.GetSQLErr(err), transactionRef)), controller.Logger)
+		return
+	}
+
+	travelRule, err := services.DecryptTravelRuleRecord(controller.Config, travelRuleRecord)
+	if err != nil {
+		ReturnError(responseWriter, "GetTravelRuleRecord", http.StatusInternalServerError, err, apiResponse.PlainError("SYSTEM_ERR", errorcode.SYSTEM_ERR), controller.Logger)
+		return
+	}
+
+	controller.Logger.Info("Outgoing response to GetTravelRuleRecord request for transactionReference : %s, status : %s", transactionRef, travelRuleRecord.Status)
+	responseWriter.Header().Set("Content-Type", "application/json")
+	responseWriter.WriteHeader(http.StatusOK)
+	json.NewEncoder(responseWriter).Encode(services.ToIVMS101(travelRule))
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+)
+
+func main() {
+	fmt.Println("Starting FundingRequests follow up")
+
+	config := Config.Data{}
+	config.Init("")
+	if !config.EnableFloatManager {
+		log.Println("Float manager is disabled... exiting")
+		return
+	}
+
+	logger := utility.NewLogger()
+
+	Database := &database.Database{
+		Logger: logger,
+		Config: config,
+	}
+	Database.LoadDBInstance()
+	defer Database.CloseDBInstance()
+
+	purgeInterval := config.PurgeCacheInterval * time.Second
+	cacheDuration := config.ExpireCacheDuration * time.Second
+	authCache := utility.InitializeCache(cacheDuration, purgeInterval)
+	baseRepository := database.BaseRepository{Database: *Database}
+
+	tasks.FollowUpFundingRequests(authCache, logger, config, baseRepository)
+	tasks.ResumeAwaitingFloatWithdrawals(authCache, logger, config, baseRepository)
+}
@@ -1,7 +1,14 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 	Config "wallet-adapter/config"
 	"wallet-adapter/database"
@@ -10,6 +17,8 @@ import (
 )
 
 func main() {
+	dryRun := flag.Bool("dry-run", false, "print what the sweep would do without acquiring the lock or sending anything")
+	flag.Parse()
 	fmt.Println("Starting Sweep Job")
 
 	config := Config.Data{}
@@ -29,6 +38,28 @@ func main() {
 	//authCache, logger, config, baseRepository
 	authCache := utility.InitializeCache(cacheDuration, purgeInterval)
 	baseRepository := database.BaseRepository{Database: *Database}
-	tasks.SweepTransactions(authCache, logger, config, baseRepository)
+	if *dryRun {
+		sweepPlan, err := tasks.PlanSweep(authCache, logger, config, baseRepository)
+		if err != nil {
+			log.Fatalf("Sweep plan could not be computed : %s", err)
+		}
+		output, err := json.MarshalIndent(sweepPlan, "", "  ")
+		if err != nil {
+			log.Fatalf("Sweep plan could not be printed : %s", err)
+		}
+		fmt.Println(string(output))
+		return
+	}
+	// on shutdown the sweeps in flight are completed and recorded, the remaining addresses are left for the next run
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		logger.Info("Stopping Sweep Job, waiting for sweeps in flight")
+		cancel()
+	}()
+	tasks.SweepTransactionsWithContext(ctx, authCache, logger, config, baseRepository)
 
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 	"wallet-adapter/errorcode"
 	"wallet-adapter/model"
 	"wallet-adapter/utility"
@@ -22,9 +23,39 @@ type IUserAssetRepository interface {
 	BulkUpdate(ids interface{}, model interface{}, update interface{}) error
 	GetAssetByAddressSymbolAndNetwork(address, assetSymbol, network string, model interface{}) error
 	GetAssetBySymbolMemoAddressAndNetwork(assetSymbol, memo, address, network string, model interface{}) error
+	FetchDustDepositSummary(assetSymbol, network string, model interface{}) error
+	ClaimPooledAddress(assetSymbol, network, addressType string, assetID uuid.UUID, pooledAddress *model.PooledAddress) error
+	ReleasePooledAddress(pooledAddressID uuid.UUID) error
+	FetchAddressPoolDepth(model interface{}) error
+	AllocateDerivationIndex(accountKey string) (int64, error)
+	FetchAddressesDueForRotation(assetSymbol, network string, createdBefore time.Time, model interface{}) error
+	RetireUserAddresses(addressIDs []uuid.UUID) (int64, error)
+	InvalidateUserAddresses(address, reason string) (int64, error)
+	FetchUnwatchedAddresses(subscriptionStatus string, model interface{}) error
+	UpdateAddressSubscriptionStatus(addressIDs []uuid.UUID, subscriptionStatus string, subscribedAt *time.Time) error
+	FetchDuplicateMemos(model interface{}) error
+	SaveAssetConfig(record interface{}, overrides []model.AssetConfigOverride, change *model.AssetConfigChange) error
+	DeleteAssetConfig(change *model.AssetConfigChange) error
+	FetchAssetConfigChanges(assetSymbol, network string, changes interface{}) error
+	FetchActiveMaintenanceWindows(assetSymbol, network, scope string, at time.Time, windows interface{}) error
+	FetchMaintenanceWindows(endsAfter time.Time, windows interface{}) error
+	FetchSweepRuns(limit int, runs interface{}) error
+	FetchSweepItems(sweepRunID uuid.UUID, status, address string, items interface{}) error
+	FetchGasFundings(address, network string, statuses []string, fundings interface{}) error
+	FetchFloatHistory(assetSymbol, network string, from, to time.Time, snapshots interface{}) error
+	FetchTransactionsBetween(assetSymbol, network string, tags []string, from, to time.Time, transactions interface{}) error
+	SaveFloatParams(param *model.FloatManagerParam) error
+	FetchFloatParamVersions(paramID uuid.UUID, versions interface{}) error
+	FetchFundingRequests(assetSymbol, network string, statuses []string, requests interface{}) error
+	FetchQueuedTransactionsByStatus(status string, queuedTransactions interface{}) error
 	Db() *gorm.DB
 }
 
+const (
+	poolClaimAttempts       = 5
+	indexAllocationAttempts = 10
+)
+
 // UserAssetRepository ...
 type UserAssetRepository struct {
 	BaseRepository
@@ -209,3 +240,495 @@ func (repo *UserAssetRepository) GetAssetBySymbolMemoAddressAndNetwork(assetSymb
 func (repo *UserAssetRepository) Db() *gorm.DB {
 	return repo.DB
 }
+
+// FetchDustDepositSummary ... Aggregates deposits held back as dust by asset, network and receiving address
+func (repo *UserAssetRepository) FetchDustDepositSummary(assetSymbol, network string, model interface{}) error {
+	query := repo.DB.Table("transactions").
+		Select("transactions.asset_symbol, transactions.network, chain_transactions.recipient_address as address, count(transactions.id) as deposit_count, sum(transactions.value) as total_value").
+		Joins("INNER JOIN chain_transactions ON chain_transactions.id = transactions.on_chain_tx_id").
+		Where("transactions.transaction_tag = ? AND transactions.transaction_status = ?", "DEPOSIT", "DUST")
+	if assetSymbol != "" {
+		query = query.Where("transactions.asset_symbol = ?", assetSymbol)
+	}
+	if network != "" {
+		query = query.Where("transactions.network = ?", network)
+	}
+	if err := query.Group("transactions.asset_symbol, transactions.network, chain_transactions.recipient_address").
+		Order("total_value desc").Scan(model).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchDustDepositSummary %s", err)
+		return utility.AppError{
+			ErrType: "INPUT_ERR",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// ClaimPooledAddress ... Assigns the oldest available pooled address for an asset and network, of the address type when one is given, to a user asset.
+// The status guard on the update makes the claim atomic across concurrent requests and instances, a lost race retries with the next candidate
+func (repo *UserAssetRepository) ClaimPooledAddress(assetSymbol, network, addressType string, assetID uuid.UUID, pooledAddress *model.PooledAddress) error {
+	for attempt := 0; attempt < poolClaimAttempts; attempt++ {
+		candidate := model.PooledAddress{}
+		query := repo.DB.Where("asset_symbol = ? AND network = ? AND status = ?", assetSymbol, network, model.PooledAddressStatus.AVAILABLE)
+		if addressType != "" {
+			query = query.Where("address_type = ?", addressType)
+		}
+		if err := query.Order("created_at asc").First(&candidate).Error; err != nil {
+			if gorm.IsRecordNotFoundError(err) {
+				return utility.AppError{
+					ErrType: errorcode.RECORD_NOT_FOUND,
+					Err:     err,
+				}
+			}
+			repo.Logger.Error("Error with repository ClaimPooledAddress %s", err)
+			return utility.AppError{
+				ErrType: errorcode.SERVER_ERR,
+				Err:     err,
+			}
+		}
+
+		assignedAt := time.Now()
+		result := repo.DB.Model(&model.PooledAddress{}).Where("id = ? AND status = ?", candidate.ID, model.PooledAddressStatus.AVAILABLE).
+			Updates(map[string]interface{}{"status": model.PooledAddressStatus.ASSIGNED, "asset_id": assetID, "assigned_at": assignedAt})
+		if result.Error != nil {
+			repo.Logger.Error("Error with repository ClaimPooledAddress %s", result.Error)
+			return utility.AppError{
+				ErrType: errorcode.SERVER_ERR,
+				Err:     result.Error,
+			}
+		}
+		if result.RowsAffected == 1 {
+			candidate.Status = model.PooledAddressStatus.ASSIGNED
+			candidate.AssetID = &assetID
+			candidate.AssignedAt = &assignedAt
+			*pooledAddress = candidate
+			return nil
+		}
+	}
+	return utility.AppError{
+		ErrType: errorcode.RECORD_NOT_FOUND,
+		Err:     gorm.ErrRecordNotFound,
+	}
+}
+
+// ReleasePooledAddress ... Returns an assigned pooled address to the pool, used when the user address it was claimed for could not be saved
+func (repo *UserAssetRepository) ReleasePooledAddress(pooledAddressID uuid.UUID) error {
+	if err := repo.DB.Model(&model.PooledAddress{}).Where("id = ? AND status = ?", pooledAddressID, model.PooledAddressStatus.ASSIGNED).
+		Updates(map[string]interface{}{"status": model.PooledAddressStatus.AVAILABLE, "asset_id": nil, "assigned_at": nil}).Error; err != nil {
+		repo.Logger.Error("Error with repository ReleasePooledAddress %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchAddressPoolDepth ... Counts pooled addresses by asset, network and status
+func (repo *UserAssetRepository) FetchAddressPoolDepth(model interface{}) error {
+	if err := repo.DB.Table("pooled_addresses").
+		Select("asset_symbol, network, status, count(id) as count").
+		Group("asset_symbol, network, status").Scan(model).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchAddressPoolDepth %s", err)
+		return utility.AppError{
+			ErrType: "INPUT_ERR",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// AllocateDerivationIndex ... Reserves the next external chain index of an account xpub.
+// The compare-and-set on next_index guarantees an index is handed out once even with concurrent requests
+func (repo *UserAssetRepository) AllocateDerivationIndex(accountKey string) (int64, error) {
+	accountIndex := model.HDAccountIndex{}
+	if err := repo.DB.Where(model.HDAccountIndex{AccountKey: accountKey}).FirstOrCreate(&accountIndex).Error; err != nil {
+		repo.Logger.Error("Error with repository AllocateDerivationIndex %s", err)
+		return 0, utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+
+	for attempt := 0; attempt < indexAllocationAttempts; attempt++ {
+		result := repo.DB.Model(&model.HDAccountIndex{}).Where("id = ? AND next_index = ?", accountIndex.ID, accountIndex.NextIndex).
+			Update("next_index", accountIndex.NextIndex+1)
+		if result.Error != nil {
+			repo.Logger.Error("Error with repository AllocateDerivationIndex %s", result.Error)
+			return 0, utility.AppError{
+				ErrType: errorcode.SERVER_ERR,
+				Err:     result.Error,
+			}
+		}
+		if result.RowsAffected == 1 {
+			return accountIndex.NextIndex, nil
+		}
+		if err := repo.DB.Where("id = ?", accountIndex.ID).First(&accountIndex).Error; err != nil {
+			repo.Logger.Error("Error with repository AllocateDerivationIndex %s", err)
+			return 0, utility.AppError{
+				ErrType: errorcode.SERVER_ERR,
+				Err:     err,
+			}
+		}
+	}
+	return 0, utility.AppError{
+		ErrType: errorcode.SERVER_ERR,
+		Err:     errors.New("could not reserve a derivation index, too many concurrent allocations"),
+	}
+}
+
+// FetchAddressesDueForRotation ... Fetches valid primary addresses of an asset on a network issued before the given time
+func (repo *UserAssetRepository) FetchAddressesDueForRotation(assetSymbol, network string, createdBefore time.Time, model interface{}) error {
+	if err := repo.DB.Select("user_addresses.*").
+		Joins("INNER JOIN user_assets ON user_assets.id = user_addresses.asset_id").
+		Joins("INNER JOIN denominations ON denominations.id = user_assets.denomination_id").
+		Where("denominations.asset_symbol = ? AND user_addresses.network = ? AND user_addresses.is_primary_address = ? AND user_addresses.is_valid = ? AND user_addresses.created_at < ?",
+			assetSymbol, network, true, true, createdBefore).
+		Find(model).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchAddressesDueForRotation %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// RetireUserAddresses ... Demotes primary addresses so a replacement is issued on the next address request.
+// Retired addresses stay subscribed and keep receiving deposits
+func (repo *UserAssetRepository) RetireUserAddresses(addressIDs []uuid.UUID) (int64, error) {
+	if len(addressIDs) == 0 {
+		return 0, nil
+	}
+	result := repo.DB.Model(&model.UserAddress{}).Where("id IN (?) AND is_primary_address = ?", addressIDs, true).
+		Updates(map[string]interface{}{"is_primary_address": false, "retired_at": time.Now()})
+	if result.Error != nil {
+		repo.Logger.Error("Error with repository RetireUserAddresses %s", result.Error)
+		return 0, utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     result.Error,
+		}
+	}
+	return result.RowsAffected, nil
+}
+
+// InvalidateUserAddresses ... Marks every user address record of a compromised address invalid and retires it
+func (repo *UserAssetRepository) InvalidateUserAddresses(address, reason string) (int64, error) {
+	invalidatedAt := time.Now()
+	result := repo.DB.Model(&model.UserAddress{}).Where("address = ? AND is_valid = ?", address, true).
+		Updates(map[string]interface{}{"is_valid": false, "is_primary_address": false, "retired_at": gorm.Expr("COALESCE(retired_at, ?)", invalidatedAt), "invalidated_at": invalidatedAt, "invalidation_reason": reason})
+	if result.Error != nil {
+		repo.Logger.Error("Error with repository InvalidateUserAddresses %s", result.Error)
+		return 0, utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     result.Error,
+		}
+	}
+	return result.RowsAffected, nil
+}
+
+// FetchUnwatchedAddresses ... Fetches user addresses without an active crypto-adapter subscription, optionally filtered by subscription status.
+// Shared memo addresses are subscribed by the shared address service and cancelled subscriptions of invalidated addresses are not included
+func (repo *UserAssetRepository) FetchUnwatchedAddresses(subscriptionStatus string, unwatchedAddresses interface{}) error {
+	query := repo.DB.Table("user_addresses").
+		Select("user_addresses.id, user_addresses.address, user_addresses.network, user_addresses.subscription_status, user_addresses.created_at, denominations.asset_symbol, networks.coin_type").
+		Joins("INNER JOIN user_assets ON user_assets.id = user_addresses.asset_id").
+		Joins("INNER JOIN denominations ON denominations.id = user_assets.denomination_id").
+		Joins("INNER JOIN networks ON networks.asset_symbol = denominations.asset_symbol AND networks.network = user_addresses.network").
+		Where("user_addresses.address IS NOT NULL AND user_addresses.address <> ''")
+	if subscriptionStatus != "" {
+		query = query.Where("user_addresses.subscription_status = ?", subscriptionStatus)
+	} else {
+		query = query.Where("user_addresses.subscription_status NOT IN (?)", []string{model.SubscriptionStatus.ACTIVE, model.SubscriptionStatus.CANCELLED})
+	}
+	if err := query.Order("user_addresses.created_at asc").Scan(unwatchedAddresses).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchUnwatchedAddresses %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// UpdateAddressSubscriptionStatus ... Records the outcome of subscribing user addresses on the crypto-adapter
+func (repo *UserAssetRepository) UpdateAddressSubscriptionStatus(addressIDs []uuid.UUID, subscriptionStatus string, subscribedAt *time.Time) error {
+	if len(addressIDs) == 0 {
+		return nil
+	}
+	if err := repo.DB.Model(&model.UserAddress{}).Where("id IN (?)", addressIDs).
+		Updates(map[string]interface{}{"subscription_status": subscriptionStatus, "subscribed_at": subscribedAt}).Error; err != nil {
+		repo.Logger.Error("Error with repository UpdateAddressSubscriptionStatus %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchDuplicateMemos ... Fetches memos assigned to more than one user, deposits to these memos cannot be attributed
+func (repo *UserAssetRepository) FetchDuplicateMemos(model interface{}) error {
+	if err := repo.DB.Raw(`
+		SELECT ua.memo, COUNT(DISTINCT a.user_id) AS user_count FROM user_addresses ua
+		INNER JOIN user_assets a ON a.id = ua.asset_id
+		WHERE ua.memo IS NOT NULL AND ua.memo <> ''
+		GROUP BY ua.memo HAVING COUNT(DISTINCT a.user_id) > 1`).Scan(model).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchDuplicateMemos %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// SaveAssetConfig ... Saves a denomination or network changed through the admin API with its overridden fields and change history
+func (repo *UserAssetRepository) SaveAssetConfig(record interface{}, overrides []model.AssetConfigOverride, change *model.AssetConfigChange) error {
+	if err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(record).Error; err != nil {
+			return err
+		}
+		for _, override := range overrides {
+			if err := tx.Where(model.AssetConfigOverride{AssetSymbol: override.AssetSymbol, Network: override.Network, Field: override.Field}).
+				Assign(model.AssetConfigOverride{Value: override.Value, UpdatedBy: override.UpdatedBy}).FirstOrCreate(&override).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(change).Error
+	}); err != nil {
+		repo.Logger.Error("Error with repository SaveAssetConfig %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// DeleteAssetConfig ... Deletes a network, or a denomination with its networks when the change has no network, together with their overridden fields
+func (repo *UserAssetRepository) DeleteAssetConfig(change *model.AssetConfigChange) error {
+	if err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		networks := tx.Where("asset_symbol = ?", change.AssetSymbol)
+		overrides := tx.Where("asset_symbol = ?", change.AssetSymbol)
+		if change.Network != "" {
+			networks = networks.Where("network = ?", change.Network)
+			overrides = overrides.Where("network = ?", change.Network)
+		} else if err := tx.Where("asset_symbol = ?", change.AssetSymbol).Delete(model.Denomination{}).Error; err != nil {
+			return err
+		}
+		if err := networks.Delete(model.Network{}).Error; err != nil {
+			return err
+		}
+		if err := overrides.Delete(model.AssetConfigOverride{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(change).Error
+	}); err != nil {
+		repo.Logger.Error("Error with repository DeleteAssetConfig %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchAssetConfigChanges ... Fetches the admin change history of denominations and networks, latest first
+func (repo *UserAssetRepository) FetchAssetConfigChanges(assetSymbol, network string, changes interface{}) error {
+	query := repo.DB.Order("created_at desc")
+	if assetSymbol != "" {
+		query = query.Where("asset_symbol = ?", assetSymbol)
+	}
+	if network != "" {
+		query = query.Where("network = ?", network)
+	}
+	if err := query.Find(changes).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchAssetConfigChanges %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchActiveMaintenanceWindows ... Fetches the maintenance windows pausing an operation on an asset network at a time, latest ending first
+func (repo *UserAssetRepository) FetchActiveMaintenanceWindows(assetSymbol, network, scope string, at time.Time, windows interface{}) error {
+	if err := repo.DB.Where("network = ? AND scope = ? AND (asset_symbol = '' OR asset_symbol = ?) AND starts_at <= ? AND ends_at > ? AND cancelled_at IS NULL",
+		network, scope, assetSymbol, at, at).Order("ends_at desc").Find(windows).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchActiveMaintenanceWindows %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchMaintenanceWindows ... Fetches the maintenance windows that are not cancelled and end after a time, earliest starting first
+func (repo *UserAssetRepository) FetchMaintenanceWindows(endsAfter time.Time, windows interface{}) error {
+	if err := repo.DB.Where("ends_at > ? AND cancelled_at IS NULL", endsAfter).Order("starts_at asc").Find(windows).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchMaintenanceWindows %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchSweepRuns ... Fetches the latest sweep runs, latest first
+func (repo *UserAssetRepository) FetchSweepRuns(limit int, runs interface{}) error {
+	if err := repo.DB.Order("started_at desc").Limit(limit).Find(runs).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchSweepRuns %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchSweepItems ... Fetches the per address outcomes of a sweep run, optionally filtered by status and address
+func (repo *UserAssetRepository) FetchSweepItems(sweepRunID uuid.UUID, status, address string, items interface{}) error {
+	query := repo.DB.Where("sweep_run_id = ?", sweepRunID).Order("created_at asc")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if address != "" {
+		query = query.Where("address = ?", address)
+	}
+	if err := query.Find(items).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchSweepItems %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchGasFundings ... Fetches sweep fee fundings in any of the statuses, oldest first, optionally filtered by address and network
+func (repo *UserAssetRepository) FetchGasFundings(address, network string, statuses []string, fundings interface{}) error {
+	query := repo.DB.Order("created_at asc")
+	if address != "" {
+		query = query.Where("address = ?", address)
+	}
+	if network != "" {
+		query = query.Where("network = ?", network)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN (?)", statuses)
+	}
+	if err := query.Find(fundings).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchGasFundings %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// CountUnsweptDeposits ... Counts the deposits of an asset to an address that are not swept yet, confirmed or not
+func (repo *UserAssetRepository) CountUnsweptDeposits(address, assetSymbol string, count *int) error {
+	if err := repo.DB.Table("transactions").Joins("INNER JOIN chain_transactions ON chain_transactions.id = transactions.on_chain_tx_id").
+		Where("chain_transactions.recipient_address = ? AND transactions.asset_symbol = ? AND transactions.transaction_tag = ? AND transactions.swept_status = ?",
+			address, assetSymbol, "DEPOSIT", false).Count(count).Error; err != nil {
+		repo.Logger.Error("Error with repository CountUnsweptDeposits %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchFloatHistory ... Fetches the variables recorded by the float manager runs of an asset between two dates, oldest first
+func (repo *UserAssetRepository) FetchFloatHistory(assetSymbol, network string, from, to time.Time, snapshots interface{}) error {
+	if err := repo.DB.Where("asset_symbol = ? AND network = ? AND created_at >= ? AND created_at <= ?", assetSymbol, network, from, to).Order("created_at asc").Find(snapshots).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchFloatHistory %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchTransactionsBetween ... Fetches the transactions of an asset with any of the tags created between two dates, oldest first
+func (repo *UserAssetRepository) FetchTransactionsBetween(assetSymbol, network string, tags []string, from, to time.Time, transactions interface{}) error {
+	if err := repo.DB.Where("asset_symbol = ? AND network = ? AND transaction_tag IN (?) AND created_at > ? AND created_at <= ?", assetSymbol, network, tags, from, to).Order("created_at asc").Find(transactions).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchTransactionsBetween %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// SaveFloatParams ... Saves float parameters together with the record of their version
+func (repo *UserAssetRepository) SaveFloatParams(param *model.FloatManagerParam) error {
+	if err := repo.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(param).Error; err != nil {
+			return err
+		}
+		version := param.VersionOf()
+		return tx.Create(&version).Error
+	}); err != nil {
+		repo.Logger.Error("Error with repository SaveFloatParams %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchFloatParamVersions ... Fetches the versions of float parameters, latest first
+func (repo *UserAssetRepository) FetchFloatParamVersions(paramID uuid.UUID, versions interface{}) error {
+	if err := repo.DB.Where("float_manager_param_id = ?", paramID).Order("version desc").Find(versions).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchFloatParamVersions %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchFundingRequests ... Fetches funding requests in any of the statuses, latest first, optionally filtered by asset and network
+func (repo *UserAssetRepository) FetchFundingRequests(assetSymbol, network string, statuses []string, requests interface{}) error {
+	query := repo.DB.Order("created_at desc")
+	if assetSymbol != "" {
+		query = query.Where("asset_symbol = ?", assetSymbol)
+	}
+	if network != "" {
+		query = query.Where("network = ?", network)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN (?)", statuses)
+	}
+	if err := query.Find(requests).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchFundingRequests %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// FetchQueuedTransactionsByStatus ... Fetches the queued transactions in a status, oldest first
+func (repo *UserAssetRepository) FetchQueuedTransactionsByStatus(status string, queuedTransactions interface{}) error {
+	if err := repo.DB.Where("transaction_status = ?", status).Order("created_at asc").Find(queuedTransactions).Error; err != nil {
+		repo.Logger.Error("Error with repository FetchQueuedTransactionsByStatus %s", err)
+		return utility.AppError{
+			ErrType: errorcode.SERVER_ERR,
+			Err:     err,
+		}
+	}
+	return nil
+}
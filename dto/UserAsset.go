@@ -36,6 +36,7 @@ type ChainData struct {
 	TransactionHash  string `json:"transactionHash" validate:"required"`
 	TransactionFee   string `json:"transactionFee" validate:"required"`
 	RecipientAddress string `json:"recipientAddress"`
+	SenderAddress    string `json:"senderAddress"`
 	Network string `json:"network"`
 	BlockHeight      int64  `json:"blockHeight"`
 }
@@ -43,6 +44,7 @@ type ChainData struct {
 type OnChainCreditUserAssetRequest struct {
 	CreditUserAssetRequest
 	ChainData ChainData `json:"chainData" validate:"required"`
+	TravelRule *TravelRuleData `json:"travelRule,omitempty"`
 }
 
 // CreditUserAssetRequest ... Model definition for credit user asset request
@@ -75,3 +77,17 @@ type AllAssetAddresses struct {
 	DefaultAddressType string         `json:"defaultAddressType"`
 	DefaultNetwork string         `json:"defaultNetwork"`
 }
+
+// DustDepositSummary ... Aggregated value of dust deposits held at an address
+type DustDepositSummary struct {
+	AssetSymbol  string  `json:"assetSymbol"`
+	Network      string  `json:"network"`
+	Address      string  `json:"address"`
+	DepositCount int64   `json:"depositCount"`
+	TotalValue   float64 `json:"totalValue"`
+}
+
+// DustReportResponse ... Model definition for dust report response
+type DustReportResponse struct {
+	Deposits []DustDepositSummary `json:"deposits"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+// AddressPoolStatusCount ... Number of pooled addresses in a status for an asset network
+type AddressPoolStatusCount struct {
+	AssetSymbol string `json:"assetSymbol"`
+	Network     string `json:"network"`
+	Status      string `json:"status"`
+	Count       int64  `json:"count"`
+}
+
+// AddressPoolDepth ... Pool depth metrics for an asset network
+type AddressPoolDepth struct {
+	AssetSymbol       string `json:"assetSymbol"`
+	Network           string `json:"network"`
+	Available         int64  `json:"available"`
+	Assigned          int64  `json:"assigned"`
+	LowWaterMark      int64  `json:"lowWaterMark"`
+	BelowLowWaterMark bool   `json:"belowLowWaterMark"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// InvalidateAddressRequest ... Model definition for invalidating a compromised deposit address
+type InvalidateAddressRequest struct {
+	InvalidatedBy string `json:"invalidatedBy" validate:"required"`
+	Reason        string `json:"reason" validate:"required,max=255"`
+}
+
+// InvalidateAddressResponse ... Number of user address records invalidated for an address and whether its crypto-adapter subscription was cancelled
+type InvalidateAddressResponse struct {
+	Address               string `json:"address"`
+	Invalidated           int64  `json:"invalidated"`
+	SubscriptionCancelled bool   `json:"subscriptionCancelled"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// UnwatchedAddress ... A user address without an active crypto-adapter subscription
+type UnwatchedAddress struct {
+	ID                 uuid.UUID `json:"id"`
+	Address            string    `json:"address"`
+	AssetSymbol        string    `json:"assetSymbol"`
+	Network            string    `json:"network"`
+	CoinType           int64     `json:"coinType"`
+	SubscriptionStatus string    `json:"subscriptionStatus"`
+	CreatedAt          time.Time `json:"createdAt"`
+}
+
+// SubscriptionReconciliation ... Outcome of a subscription reconciler run
+type SubscriptionReconciliation struct {
+	Attempted  int `json:"attempted"`
+	Subscribed int `json:"subscribed"`
+	Failed     int `json:"failed"`
+}
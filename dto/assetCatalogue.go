@@ -0,0 +1,24 @@
+package dto
+
+// AssetCatalogue ... Local copy of the rate service denominations and TrustWallet coins, used to seed supported assets without the remote sources
+type AssetCatalogue struct {
+	Version         int                 `json:"version"`
+	Denominations   []AssetDenomination `json:"denominations"`
+	TWDenominations []TWDenomination    `json:"twDenominations"`
+}
+
+// AssetSeedChange ... Change seeding makes to a denomination, or to a network when network is set
+type AssetSeedChange struct {
+	AssetSymbol string                            `json:"assetSymbol"`
+	Network     string                            `json:"network,omitempty"`
+	Action      string                            `json:"action"`
+	Changes     map[string]AssetConfigFieldChange `json:"changes,omitempty"`
+}
+
+// AssetSeedReport ... Changes seeding would make to the supported assets, with the source of the seeded assets
+type AssetSeedReport struct {
+	Mode             string            `json:"mode"`
+	Source           string            `json:"source"`
+	CatalogueVersion int               `json:"catalogueVersion,omitempty"`
+	Changes          []AssetSeedChange `json:"changes"`
+}
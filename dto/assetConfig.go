@@ -0,0 +1,71 @@
+package dto
+
+import "encoding/json"
+
+// DenominationConfig ... Denomination fields that can be set through the admin API, omitted fields are left unchanged
+type DenominationConfig struct {
+	Name             *string `json:"name,omitempty" validate:"omitempty,max=100"`
+	DefaultNetwork   *string `json:"defaultNetwork,omitempty" validate:"omitempty,max=150"`
+	TradeActivity    *string `json:"tradeActivity,omitempty" validate:"omitempty,alpha,max=36"`
+	TransferActivity *string `json:"transferActivity,omitempty" validate:"omitempty,alpha,max=36"`
+}
+
+// NetworkConfig ... Network fields that can be set through the admin API, omitted fields are left unchanged
+type NetworkConfig struct {
+	NativeAsset          *string  `json:"nativeAsset,omitempty" validate:"omitempty,max=36"`
+	CoinType             *int64   `json:"coinType,omitempty" validate:"omitempty,gte=0"`
+	RequiresMemo         *bool    `json:"requiresMemo,omitempty"`
+	NativeDecimals       *int     `json:"nativeDecimals,omitempty" validate:"omitempty,gte=0,lte=36"`
+	ChainDenomId         *string  `json:"chainDenomId,omitempty" validate:"omitempty,max=150"`
+	AddressProvider      *string  `json:"addressProvider,omitempty" validate:"omitempty,oneof=Bundle Binance Xpub"`
+	IsBatchable          *bool    `json:"isBatchable,omitempty"`
+	IsMultiAddresses     *bool    `json:"isMultiAddresses,omitempty"`
+	IsToken              *bool    `json:"isToken,omitempty"`
+	MinimumSweepable     *float64 `json:"minimumSweepable,omitempty" validate:"omitempty,gte=0"`
+	SweepFee             *int64   `json:"sweepFee,omitempty" validate:"omitempty,gte=0"`
+	MinimumDeposit       *float64 `json:"minimumDeposit,omitempty" validate:"omitempty,gte=0"`
+	DustPolicy           *string  `json:"dustPolicy,omitempty" validate:"omitempty,oneof=RECORD BUCKET"`
+	RotateAfterDeposits  *int64   `json:"rotateAfterDeposits,omitempty" validate:"omitempty,gte=0"`
+	RotationWindowHours  *int64   `json:"rotationWindowHours,omitempty" validate:"omitempty,gte=0"`
+	RetiredAddressPolicy *string  `json:"retiredAddressPolicy,omitempty" validate:"omitempty,oneof=CREDIT HOLD"`
+	DepositActivity      *string  `json:"depositActivity,omitempty" validate:"omitempty,alpha,max=36"`
+	WithdrawActivity     *string  `json:"withdrawActivity,omitempty" validate:"omitempty,alpha,max=36"`
+}
+
+// AssetConfigAudit ... Operator details recorded in the change history of every admin change
+type AssetConfigAudit struct {
+	ChangedBy string `json:"changedBy" validate:"required,max=150"`
+	Reason    string `json:"reason" validate:"max=255"`
+}
+
+// CreateDenominationRequest ... Model definition for adding a denomination through the admin API
+type CreateDenominationRequest struct {
+	AssetSymbol string `json:"assetSymbol" validate:"required,alphanum,max=36"`
+	DenominationConfig
+	AssetConfigAudit
+}
+
+// UpdateDenominationRequest ... Model definition for changing a denomination through the admin API
+type UpdateDenominationRequest struct {
+	DenominationConfig
+	AssetConfigAudit
+}
+
+// CreateNetworkRequest ... Model definition for adding a network to a denomination through the admin API
+type CreateNetworkRequest struct {
+	Network string `json:"network" validate:"required,max=150"`
+	NetworkConfig
+	AssetConfigAudit
+}
+
+// UpdateNetworkRequest ... Model definition for changing a network through the admin API
+type UpdateNetworkRequest struct {
+	NetworkConfig
+	AssetConfigAudit
+}
+
+// AssetConfigFieldChange ... Value of a field before and after an admin change
+type AssetConfigFieldChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+// BatchActionRequest ... Model definition for acting on a batch from the batch administration endpoints
+type BatchActionRequest struct {
+	RequestedBy string `json:"requestedBy" validate:"required,max=150"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+// SaveBatchPolicyRequest ... Model definition for setting when the waiting batch of an asset on a network is closed
+type SaveBatchPolicyRequest struct {
+	AssetSymbol   string          `json:"assetSymbol" validate:"required,max=36"`
+	Network       string          `json:"network" validate:"required,max=150"`
+	MaxRecipients int64           `json:"maxRecipients" validate:"min=0"`
+	MaxTotalValue decimal.Decimal `json:"maxTotalValue"`
+	MaxAgeMinutes int64           `json:"maxAgeMinutes" validate:"min=0"`
+	MinAgeMinutes int64           `json:"minAgeMinutes" validate:"min=0"`
+	UpdatedBy     string          `json:"updatedBy" validate:"required,max=150"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+// CreateColdWalletRequest ... Model definition for registering a cold wallet, it receives funds once it is verified.
+// Approval is required for transfers above the threshold unless requiresApproval is false
+type CreateColdWalletRequest struct {
+	AssetSymbol       string  `json:"assetSymbol" validate:"required,max=36"`
+	Network           string  `json:"network" validate:"required,max=150"`
+	Address           string  `json:"address" validate:"required,max=150"`
+	Memo              string  `json:"memo" validate:"max=150"`
+	Label             string  `json:"label" validate:"required,max=150"`
+	SurplusPercent    int64   `json:"surplusPercent" validate:"min=0,max=100"`
+	MaxTransferAmount float64 `json:"maxTransferAmount" validate:"min=0"`
+	RequiresApproval  *bool   `json:"requiresApproval"`
+	ApprovalThreshold float64 `json:"approvalThreshold" validate:"min=0"`
+	CreatedBy         string  `json:"createdBy" validate:"required,max=150"`
+}
+
+// UpdateColdWalletRequest ... Model definition for changing the label and surplus share of a cold wallet, its address cannot be changed
+type UpdateColdWalletRequest struct {
+	Label          string `json:"label" validate:"required,max=150"`
+	SurplusPercent int64  `json:"surplusPercent" validate:"min=0,max=100"`
+	UpdatedBy      string `json:"updatedBy" validate:"required,max=150"`
+}
+
+// UpdateColdWalletLimitsRequest ... Model definition for changing the transfer cap and approval settings of a cold wallet, these
+// are changed under their own permission
+type UpdateColdWalletLimitsRequest struct {
+	MaxTransferAmount float64 `json:"maxTransferAmount" validate:"min=0"`
+	RequiresApproval  bool    `json:"requiresApproval"`
+	ApprovalThreshold float64 `json:"approvalThreshold" validate:"min=0"`
+	UpdatedBy         string  `json:"updatedBy" validate:"required,max=150"`
+}
+
+// ReviewRequest ... Model definition for verifying a cold wallet, or approving or rejecting a cold transfer
+type ReviewRequest struct {
+	ReviewedBy string `json:"reviewedBy" validate:"required,max=150"`
+}
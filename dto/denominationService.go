@@ -25,6 +25,7 @@ type AssetDenomination struct {
 	Symbol           string `json:"symbol"`
 	NativeAsset  string    `json:"nativeAsset"`
 	NativeDecimal             int            `json:"nativeDecimals,omitempty"`
+	ChainDenomId     string `json:"chainDenomId,omitempty"`
 	CoinType         int64  `json:"coinType"`
 	TokenType        string `json:"tokenType"`
 	RequiresMemo     bool   `json:"requiresMemo"`
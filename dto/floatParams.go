@@ -0,0 +1,22 @@
+package dto
+
+// SaveFloatParamsRequest ... Model definition for setting the float parameters of an asset, the network is left empty to set
+// the parameters used by networks of the asset without their own
+type SaveFloatParamsRequest struct {
+	AssetSymbol                    string  `json:"assetSymbol" validate:"required,max=36"`
+	Network                        string  `json:"network" validate:"max=150"`
+	MinPercentMaxUserBalance       float64 `json:"minPercentMaxUserBalance" validate:"min=0"`
+	MaxPercentMaxUserBalance       float64 `json:"maxPercentMaxUserBalance" validate:"min=0"`
+	MinPercentTotalUserBalance     float64 `json:"minPercentTotalUserBalance" validate:"min=0"`
+	AveragePercentTotalUserBalance float64 `json:"averagePercentTotalUserBalance" validate:"min=0"`
+	MaxPercentTotalUserBalance     float64 `json:"maxPercentTotalUserBalance" validate:"min=0"`
+	PercentMinimumTriggerLevel     float64 `json:"percentMinimumTriggerLevel"`
+	PercentMaximumTriggerLevel     float64 `json:"percentMaximumTriggerLevel"`
+	TargetMode                     string  `json:"targetMode" validate:"omitempty,oneof=STATIC FORECAST"`
+	UpdatedBy                      string  `json:"updatedBy" validate:"required,max=150"`
+}
+
+// FloatPauseRequest ... Model definition for pausing or resuming float management of an asset
+type FloatPauseRequest struct {
+	UpdatedBy string `json:"updatedBy" validate:"required,max=150"`
+}
@@ -0,0 +1,53 @@
+package dto
+
+import "time"
+
+// FloatSimulationParams ... A candidate set of float manager parameters to replay the float history against
+type FloatSimulationParams struct {
+	Label                          string  `json:"label" validate:"required,max=150"`
+	MinPercentMaxUserBalance       float64 `json:"minPercentMaxUserBalance" validate:"min=0"`
+	MaxPercentMaxUserBalance       float64 `json:"maxPercentMaxUserBalance" validate:"min=0"`
+	MinPercentTotalUserBalance     float64 `json:"minPercentTotalUserBalance" validate:"min=0"`
+	AveragePercentTotalUserBalance float64 `json:"averagePercentTotalUserBalance" validate:"min=0"`
+	MaxPercentTotalUserBalance     float64 `json:"maxPercentTotalUserBalance" validate:"min=0"`
+	PercentMinimumTriggerLevel     float64 `json:"percentMinimumTriggerLevel" validate:"min=0"`
+	PercentMaximumTriggerLevel     float64 `json:"percentMaximumTriggerLevel" validate:"min=0"`
+}
+
+// FloatSimulationRequest ... Model definition for replaying the float history of an asset, the current parameters are always
+// replayed alongside the candidates
+type FloatSimulationRequest struct {
+	AssetSymbol string                  `json:"assetSymbol" validate:"required,max=36"`
+	Network     string                  `json:"network" validate:"required,max=150"`
+	From        time.Time               `json:"from" validate:"required"`
+	To          time.Time               `json:"to" validate:"required"`
+	ParamSets   []FloatSimulationParams `json:"paramSets" validate:"dive"`
+}
+
+// FloatSimulationReport ... How the float of an asset would have fared under each parameter set
+type FloatSimulationReport struct {
+	AssetSymbol     string                  `json:"assetSymbol"`
+	Network         string                  `json:"network"`
+	From            time.Time               `json:"from"`
+	To              time.Time               `json:"to"`
+	Runs            int                     `json:"runs"`
+	DepositCount    int                     `json:"depositCount"`
+	WithdrawalCount int                     `json:"withdrawalCount"`
+	StartingBalance float64                 `json:"startingBalance"`
+	MaxUserBalance  float64                 `json:"maxUserBalance"`
+	Results         []FloatSimulationResult `json:"results"`
+}
+
+// FloatSimulationResult ... The outcome of replaying the float history with one parameter set, amounts are in decimal units
+type FloatSimulationResult struct {
+	Params                FloatSimulationParams `json:"params"`
+	DryRuns               int                   `json:"dryRuns"`
+	Shortfall             float64               `json:"shortfall"`
+	TopUpEmails           int                   `json:"topUpEmails"`
+	TopUpAmount           float64               `json:"topUpAmount"`
+	SurplusTransfers      int                   `json:"surplusTransfers"`
+	SurplusTransferAmount float64               `json:"surplusTransferAmount"`
+	AverageIdleSurplus    float64               `json:"averageIdleSurplus"`
+	MaxIdleSurplus        float64               `json:"maxIdleSurplus"`
+	EndingBalance         float64               `json:"endingBalance"`
+}
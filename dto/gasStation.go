@@ -0,0 +1,8 @@
+package dto
+
+// SaveFeeWalletRequest ... Model definition for setting the wallet funding sweep fees on a network
+type SaveFeeWalletRequest struct {
+	Network    string `json:"network" validate:"required,max=150"`
+	Address    string `json:"address" validate:"required,max=150"`
+	IsDisabled bool   `json:"isDisabled"`
+}
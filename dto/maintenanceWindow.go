@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+// CreateMaintenanceWindowRequest ... Model definition for scheduling a maintenance window, an empty asset symbol covers every asset on the network
+type CreateMaintenanceWindowRequest struct {
+	AssetSymbol string    `json:"assetSymbol" validate:"max=36"`
+	Network     string    `json:"network" validate:"required,max=150"`
+	Scope       string    `json:"scope" validate:"required,oneof=DEPOSIT WITHDRAW SWEEP FLOAT"`
+	StartsAt    time.Time `json:"startsAt" validate:"required"`
+	EndsAt      time.Time `json:"endsAt" validate:"required,gtfield=StartsAt"`
+	Message     string    `json:"message" validate:"required,max=255"`
+	CreatedBy   string    `json:"createdBy" validate:"required,max=150"`
+}
+
+// CancelMaintenanceWindowRequest ... Model definition for ending a maintenance window early
+type CancelMaintenanceWindowRequest struct {
+	CancelledBy string `json:"cancelledBy" validate:"required,max=150"`
+}
+
+// MaintenanceWindowStatus ... Public details of a maintenance window
+type MaintenanceWindowStatus struct {
+	AssetSymbol string    `json:"assetSymbol,omitempty"`
+	Network     string    `json:"network"`
+	Scope       string    `json:"scope"`
+	StartsAt    time.Time `json:"startsAt"`
+	EndsAt      time.Time `json:"endsAt"`
+	Message     string    `json:"message"`
+}
+
+// MaintenanceStatus ... Maintenance windows in progress and scheduled
+type MaintenanceStatus struct {
+	Active   []MaintenanceWindowStatus `json:"active"`
+	Upcoming []MaintenanceWindowStatus `json:"upcoming"`
+}
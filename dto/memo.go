@@ -0,0 +1,7 @@
+package dto
+
+// DuplicateMemo ... A memo assigned to more than one user
+type DuplicateMemo struct {
+	Memo      string `json:"memo"`
+	UserCount int64  `json:"userCount"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// PaymentRequest ... Deposit address with its standard payment URI
+type PaymentRequest struct {
+	Address string `json:"address"`
+	Network string `json:"network"`
+	Memo    string `json:"memo,omitempty"`
+	Type    string `json:"type,omitempty"`
+	URI     string `json:"uri"`
+}
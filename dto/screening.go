@@ -0,0 +1,35 @@
+package dto
+
+// ScreenAddressRequest ... Request definition for screen address, external screening provider
+type ScreenAddressRequest struct {
+	Address     string `json:"address"`
+	AssetSymbol string `json:"assetSymbol"`
+	Network     string `json:"network"`
+}
+
+// ScreenAddressResponse ... Model definition for screen address successful response, external screening provider
+type ScreenAddressResponse struct {
+	Address      string `json:"address"`
+	IsSanctioned bool   `json:"isSanctioned"`
+	Reason       string `json:"reason"`
+}
+
+// ScreeningResult ... Outcome of screening a counterparty address
+type ScreeningResult struct {
+	Hit      bool
+	Provider string
+	Reason   string
+}
+
+// SanctionedAddress ... An entry in a locally loaded sanctions list
+type SanctionedAddress struct {
+	Address string `json:"address"`
+	Network string `json:"network"`
+	Reason  string `json:"reason"`
+}
+
+// ReviewScreeningHoldRequest ... Model definition for release or reject screening hold request
+type ReviewScreeningHoldRequest struct {
+	ReviewedBy string `json:"reviewedBy" validate:"required"`
+	Note       string `json:"note"`
+}
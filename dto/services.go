@@ -2,6 +2,7 @@ package dto
 
 import (
 	"math/big"
+	"time"
 
 	uuid "github.com/satori/go.uuid"
 )
@@ -130,6 +131,40 @@ type OnchainBalanceResponse struct {
 	Decimals    int    `json:"decimals"`
 }
 
+// AddressDepositsRequest ... Request definition for the deposits received by an address since a time, crypto-adapter service
+type AddressDepositsRequest struct {
+	AssetSymbol string    `json:"assetSymbol"`
+	Network     string    `json:"network"`
+	Address     string    `json:"address"`
+	From        time.Time `json:"from"`
+}
+
+// AddressDeposit ... A deposit received by an address, the value is in the smallest unit of the asset
+type AddressDeposit struct {
+	TransactionHash string    `json:"transactionHash"`
+	SenderAddress   string    `json:"senderAddress"`
+	Value           string    `json:"value"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// AddressDepositsResponse ... Model definition for get address deposits successful response, crypto-adapter service
+type AddressDepositsResponse struct {
+	Deposits []AddressDeposit `json:"deposits"`
+}
+
+// NetworkFeeRequest ... Request definition for the estimated fee of sending a token, crypto-adapter service
+type NetworkFeeRequest struct {
+	AssetSymbol string `json:"assetSymbol"`
+	Network     string `json:"network"`
+}
+
+// NetworkFeeResponse ... Model definition for the estimated fee successful response, in the smallest unit of the native asset, crypto-adapter service
+type NetworkFeeResponse struct {
+	Fee         string `json:"fee"`
+	AssetSymbol string `json:"assetSymbol"`
+	Decimals    int    `json:"decimals"`
+}
+
 type WitdrawToHotWalletRequest struct {
 	WithdrawOrderId    string `json:"withdrawOrderId"`
 	Network            string `json:"network"`
@@ -0,0 +1,36 @@
+package dto
+
+import "time"
+
+// SweepPlan ... What a sweep run would do with the current deposits, minimums and float parameters
+type SweepPlan struct {
+	GeneratedAt    time.Time       `json:"generatedAt"`
+	CandidateCount int             `json:"candidateCount"`
+	Items          []SweepPlanItem `json:"items"`
+}
+
+// SweepPlanItem ... What a sweep run would do for an address, or a batch of addresses
+type SweepPlanItem struct {
+	Address          string   `json:"address,omitempty"`
+	Origins          []string `json:"origins,omitempty"`
+	AssetSymbol      string   `json:"assetSymbol"`
+	Network          string   `json:"network"`
+	IsBatch          bool     `json:"isBatch"`
+	TransactionCount int      `json:"transactionCount"`
+	Amount           string   `json:"amount"`
+	WillSweep        bool     `json:"willSweep"`
+	SkipReason       string   `json:"skipReason,omitempty"`
+	AddressProvider  string   `json:"addressProvider,omitempty"`
+	FloatAddress     string   `json:"floatAddress,omitempty"`
+	FloatPercent     int64    `json:"floatPercent"`
+	BrokerageAddress string   `json:"brokerageAddress,omitempty"`
+	BrokeragePercent int64    `json:"brokeragePercent"`
+	ColdAddress      string   `json:"coldAddress,omitempty"`
+	ColdPercent      int64    `json:"coldPercent"`
+	SweepPolicy      string   `json:"sweepPolicy,omitempty"`
+	Memo             string   `json:"memo,omitempty"`
+	FeeAsset         string   `json:"feeAsset,omitempty"`
+	SweepFee         int64    `json:"sweepFee"`
+	FundsSweepFee    bool     `json:"fundsSweepFee"`
+	Error            string   `json:"error,omitempty"`
+}
@@ -0,0 +1,12 @@
+package dto
+
+// SaveSweepPolicyRequest ... Model definition for setting the sweep policy of an asset on a network
+type SaveSweepPolicyRequest struct {
+	AssetSymbol  string `json:"assetSymbol" validate:"required,max=36"`
+	Network      string `json:"network" validate:"required,max=150"`
+	Type         string `json:"type" validate:"required,oneof=FLOAT_THEN_BROKERAGE FIXED_PERCENTAGES FLOAT_THEN_COLD ALL_TO_COLD"`
+	FloatPercent int64  `json:"floatPercent" validate:"min=0,max=100"`
+	ColdAddress  string `json:"coldAddress" validate:"max=150"`
+	ColdMemo     string `json:"coldMemo" validate:"max=150"`
+	UpdatedBy    string `json:"updatedBy" validate:"required,max=150"`
+}
@@ -24,6 +24,7 @@ type TransactionResponse struct {
 	UpdatedDate          time.Time  `json:"updatedDate,omitempty"`
 	TransactionTag       string     `json:"transactionTag,omitempty"`
 	ChainData            *ChainData `json:"chainData"`
+	EstimatedFloatTopUp  *time.Time `json:"estimatedFloatTopUp,omitempty"`
 }
 
 type TransactionListResponse struct {
@@ -36,6 +37,7 @@ type ExternalTransferRequest struct {
 	DebitReference       string  `json:"debitReference,omitempty" validate:"required"`
 	Network       string  `json:"network,omitempty"`
 	TransactionReference string  `json:"transactionReference,omitempty" validate:"required"`
+	TravelRule           *TravelRuleData `json:"travelRule,omitempty"`
 }
 
 type ExternalTransferResponse struct {
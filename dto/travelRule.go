@@ -0,0 +1,111 @@
+package dto
+
+// TravelRuleParty ... Identifying information for an originator or beneficiary, LegalName is used for legal persons
+type TravelRuleParty struct {
+	FirstName              string `json:"firstName"`
+	LastName               string `json:"lastName" validate:"required_without=LegalName"`
+	LegalName              string `json:"legalName"`
+	AccountNumber          string `json:"accountNumber"`
+	AddressLine            string `json:"addressLine"`
+	Country                string `json:"country"`
+	NationalIdentifier     string `json:"nationalIdentifier"`
+	NationalIdentifierType string `json:"nationalIdentifierType"`
+	CountryOfIssue         string `json:"countryOfIssue"`
+	DateOfBirth            string `json:"dateOfBirth"`
+	PlaceOfBirth           string `json:"placeOfBirth"`
+	CountryOfResidence     string `json:"countryOfResidence"`
+	VaspName               string `json:"vaspName"`
+	VaspLEI                string `json:"vaspLEI"`
+}
+
+// TravelRuleData ... Originator and beneficiary information carried on a transfer
+type TravelRuleData struct {
+	Originator  TravelRuleParty `json:"originator" validate:"required"`
+	Beneficiary TravelRuleParty `json:"beneficiary" validate:"required"`
+}
+
+// AssetRateResponse ... Model definition for get asset rate successful response, rate service
+type AssetRateResponse struct {
+	Base  string `json:"base"`
+	Quote string `json:"quote"`
+	Rate  string `json:"rate"`
+}
+
+// IVMS101Payload ... IVMS101 identity payload for a transfer
+type IVMS101Payload struct {
+	Originator      IVMS101Originator  `json:"originator"`
+	Beneficiary     IVMS101Beneficiary `json:"beneficiary"`
+	OriginatingVASP IVMS101VASP        `json:"originatingVASP"`
+	BeneficiaryVASP IVMS101VASP        `json:"beneficiaryVASP"`
+}
+
+type IVMS101Originator struct {
+	OriginatorPersons []IVMS101Person `json:"originatorPersons"`
+	AccountNumber     []string        `json:"accountNumber"`
+}
+
+type IVMS101Beneficiary struct {
+	BeneficiaryPersons []IVMS101Person `json:"beneficiaryPersons"`
+	AccountNumber      []string        `json:"accountNumber"`
+}
+
+type IVMS101Person struct {
+	NaturalPerson *IVMS101NaturalPerson `json:"naturalPerson,omitempty"`
+	LegalPerson   *IVMS101LegalPerson   `json:"legalPerson,omitempty"`
+}
+
+type IVMS101NaturalPerson struct {
+	Name                   IVMS101NaturalPersonName       `json:"name"`
+	GeographicAddress      []IVMS101Address               `json:"geographicAddress,omitempty"`
+	NationalIdentification *IVMS101NationalIdentification `json:"nationalIdentification,omitempty"`
+	DateAndPlaceOfBirth    *IVMS101DateAndPlaceOfBirth    `json:"dateAndPlaceOfBirth,omitempty"`
+	CountryOfResidence     string                         `json:"countryOfResidence,omitempty"`
+}
+
+type IVMS101NaturalPersonName struct {
+	NameIdentifier []IVMS101NaturalPersonNameIdentifier `json:"nameIdentifier"`
+}
+
+type IVMS101NaturalPersonNameIdentifier struct {
+	PrimaryIdentifier   string `json:"primaryIdentifier"`
+	SecondaryIdentifier string `json:"secondaryIdentifier,omitempty"`
+	NameIdentifierType  string `json:"nameIdentifierType"`
+}
+
+type IVMS101LegalPerson struct {
+	Name                   IVMS101LegalPersonName         `json:"name"`
+	GeographicAddress      []IVMS101Address               `json:"geographicAddress,omitempty"`
+	NationalIdentification *IVMS101NationalIdentification `json:"nationalIdentification,omitempty"`
+	CountryOfRegistration  string                         `json:"countryOfRegistration,omitempty"`
+}
+
+type IVMS101LegalPersonName struct {
+	NameIdentifier []IVMS101LegalPersonNameIdentifier `json:"nameIdentifier"`
+}
+
+type IVMS101LegalPersonNameIdentifier struct {
+	LegalPersonName               string `json:"legalPersonName"`
+	LegalPersonNameIdentifierType string `json:"legalPersonNameIdentifierType"`
+}
+
+type IVMS101Address struct {
+	AddressType string   `json:"addressType"`
+	AddressLine []string `json:"addressLine,omitempty"`
+	Country     string   `json:"country"`
+}
+
+type IVMS101NationalIdentification struct {
+	NationalIdentifier     string `json:"nationalIdentifier"`
+	NationalIdentifierType string `json:"nationalIdentifierType"`
+	CountryOfIssue         string `json:"countryOfIssue,omitempty"`
+}
+
+type IVMS101DateAndPlaceOfBirth struct {
+	DateOfBirth  string `json:"dateOfBirth"`
+	PlaceOfBirth string `json:"placeOfBirth"`
+}
+
+type IVMS101VASP struct {
+	OriginatingVASP *IVMS101Person `json:"originatingVASP,omitempty"`
+	BeneficiaryVASP *IVMS101Person `json:"beneficiaryVASP,omitempty"`
+}
@@ -32,4 +32,34 @@ const (
 	SERVER_ERR                          = "SERVER_ERR"
 	MULTIPLE_ADDRESS_ERROR = "Multiple addresses is not supported for specified asset"
 	MULTIPLE_ADDRESS_ERROR_CODE = "MULTIPLE_ADDRESS_NOT_SUPPORTED"
+	SCREENING_HOLD_NOT_PENDING          = "Screening hold has already been reviewed"
+	SCREENING_UNAVAILABLE               = "Address screening could not be completed"
+	TRAVEL_RULE_REQUIRED                = "Originator and beneficiary information is required for transfers of this value"
+	TRAVEL_RULE_REQUIRED_CODE           = "TRAVEL_RULE_REQUIRED"
+	TRAVEL_RULE_VALUATION_ERR           = "Transfer value could not be determined for travel rule check"
+	ADDRESS_ROTATION_NOT_SUPPORTED      = "Address rotation is not supported for networks with shared memo addresses"
+	ADDRESS_ALREADY_ISSUED              = "Key management returned an address already issued to the asset"
+	MEMO_ALLOCATION_FAILED              = "A unique memo could not be allocated"
+	ASSET_CONFIG_EXISTS                 = "Asset configuration already exists"
+	ASSET_CONFIG_IN_USE                 = "Denomination has user assets and cannot be deleted"
+	ASSET_CONFIG_EMPTY                  = "No configuration field was supplied"
+	NETWORK_UNDER_MAINTENANCE_CODE      = "NETWORK_UNDER_MAINTENANCE"
+	MAINTENANCE_WINDOW_ENDED            = "Maintenance window has already ended or been cancelled"
+	SWEEP_POLICY_COLD_ADDRESS_REQUIRED  = "A verified cold wallet is required for sweep policies sending funds to cold storage"
+	COLD_WALLET_ALREADY_VERIFIED        = "Cold wallet has already been verified"
+	COLD_WALLET_SELF_VERIFICATION       = "A cold wallet must be verified by someone other than who registered it"
+	COLD_TRANSFER_NOT_PENDING           = "Cold transfer is not waiting for approval"
+	COLD_WALLET_NOT_VERIFIED            = "Cold wallet is no longer verified to receive funds"
+	COLD_TRANSFER_NO_SURPLUS            = "Float has no surplus left to send to the cold wallet"
+	FLOAT_SIMULATION_NO_HISTORY         = "No float manager runs were recorded for the asset in the simulated period"
+	FLOAT_PARAMS_RANGE_INVALID          = "Float percentages must be ordered, minimum < average <= maximum of the total user balance and minimum < maximum of the maximum user balance"
+	FLOAT_PARAMS_TRIGGER_INVALID        = "Float trigger levels must be between 0 and 1"
+	FUNDING_REQUEST_CLOSED              = "Funding request has already been fulfilled or has expired"
+	BATCH_POLICY_AGE_INVALID            = "Minimum batch age must not be above the maximum batch age"
+	BATCH_POLICY_VALUE_INVALID          = "Maximum total value of a batch must not be negative"
+	BATCH_NOT_WAITING                   = "Only a batch waiting for withdrawals can be closed"
+	BATCH_NOT_TERMINATED                = "Only a terminated batch can be retried"
+	BATCH_NOT_UNBATCHABLE               = "Transactions can only be moved out of a batch that has not been sent"
+	BATCH_EMPTY                         = "Batch has no transactions"
+	BATCH_NOT_JOINABLE                  = "No waiting batch could be found for the withdrawal"
 )
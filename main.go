@@ -13,6 +13,7 @@ import (
 
 	"log"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -42,6 +43,15 @@ func main() {
 	cacheDuration := config.ExpireCacheDuration * time.Second
 	authCache := utility.InitializeCache(cacheDuration, purgeInterval)
 
+	if len(os.Args) > 1 && os.Args[1] == seedAssetsCommand {
+		seedAssets(os.Args[2:], config, logger, Database.DB, authCache)
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == simulateFloatCommand {
+		simulateFloat(os.Args[2:], config, logger, *Database)
+		return
+	}
+
 	services.SeedSupportedAssets(Database.DB, logger, config, authCache)
 	if err := services.InitHotWallet(authCache, Database.DB, logger, config); err != nil {
 		logger.Error("Error with InitHotWallet %s", err)
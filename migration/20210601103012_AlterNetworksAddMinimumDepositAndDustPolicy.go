@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210601103012, Down20210601103012)
+}
+
+func Up20210601103012(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec("ALTER TABLE networks ADD minimum_deposit decimal(64,18) NOT NULL DEFAULT 0 AFTER `minimum_sweepable`;")
+	if err != nil {
+		return err
+	}
+	_, err1 := tx.Exec("ALTER TABLE networks ADD dust_policy varchar(36) NOT NULL DEFAULT 'RECORD';")
+	if err1 != nil {
+		return err1
+	}
+	_, err2 := tx.Exec("ALTER TABLE user_assets ADD dust_balance decimal(64,18) NOT NULL DEFAULT 0;")
+	if err2 != nil {
+		return err2
+	}
+	return nil
+}
+
+func Down20210601103012(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE networks DROP COLUMN minimum_deposit;")
+	if err != nil {
+		return err
+	}
+	_, err1 := tx.Exec("ALTER TABLE networks DROP COLUMN dust_policy;")
+	if err1 != nil {
+		return err1
+	}
+	_, err2 := tx.Exec("ALTER TABLE user_assets DROP COLUMN dust_balance;")
+	if err2 != nil {
+		return err2
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210607091544, Down20210607091544)
+}
+
+func Up20210607091544(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS screening_holds (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		transaction_id varchar(36) NOT NULL,
+		transaction_tag varchar(36) NOT NULL,
+		address varchar(150) NOT NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NULL,
+		value decimal(64,18) NOT NULL,
+		provider varchar(36) NULL,
+		reason varchar(255) NULL,
+		status varchar(36) NOT NULL DEFAULT 'HELD',
+		reviewed_by varchar(150) NULL,
+		review_note varchar(255) NULL,
+		reviewed_at timestamp NULL,
+
+		PRIMARY KEY (id),
+		CONSTRAINT uix_screening_holds_transaction_id UNIQUE (transaction_id),
+		INDEX screening_address (address),
+		INDEX screening_status (status)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210607091544(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("DROP TABLE IF EXISTS screening_holds;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
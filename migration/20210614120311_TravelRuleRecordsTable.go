@@ -0,0 +1,46 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210614120311, Down20210614120311)
+}
+
+func Up20210614120311(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS travel_rule_records (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		transaction_id varchar(36) NOT NULL,
+		transaction_tag varchar(36) NOT NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NULL,
+		value decimal(64,18) NOT NULL,
+		fiat_currency varchar(10) NULL,
+		fiat_value decimal(64,18) NULL,
+		status varchar(36) NOT NULL DEFAULT 'COMPLETE',
+		encrypted_payload text NULL,
+
+		PRIMARY KEY (id),
+		CONSTRAINT uix_travel_rule_records_transaction_id UNIQUE (transaction_id)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210614120311(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("DROP TABLE IF EXISTS travel_rule_records;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210621083027, Down20210621083027)
+}
+
+func Up20210621083027(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS pooled_addresses (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		pool_identity varchar(36) NOT NULL,
+		address varchar(150) NOT NULL,
+		address_type varchar(50) NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NOT NULL,
+		coin_type bigint NULL,
+		status varchar(36) NOT NULL DEFAULT 'AVAILABLE',
+		asset_id varchar(36) NULL,
+		assigned_at timestamp NULL,
+
+		PRIMARY KEY (id),
+		CONSTRAINT uix_pooled_addresses_address UNIQUE (address),
+		INDEX pooled_address_asset_network (asset_symbol, network, status)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210621083027(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("DROP TABLE IF EXISTS pooled_addresses;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
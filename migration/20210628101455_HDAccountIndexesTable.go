@@ -0,0 +1,47 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210628101455, Down20210628101455)
+}
+
+func Up20210628101455(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS hd_account_indices (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		account_key varchar(150) NOT NULL,
+		next_index bigint NOT NULL DEFAULT 0,
+
+		PRIMARY KEY (id),
+		CONSTRAINT uix_hd_account_indices_account_key UNIQUE (account_key)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE user_addresses ADD COLUMN derivation_index bigint NULL;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210628101455(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE user_addresses DROP COLUMN derivation_index;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DROP TABLE IF EXISTS hd_account_indices;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
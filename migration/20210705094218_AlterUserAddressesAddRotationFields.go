@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210705094218, Down20210705094218)
+}
+
+func Up20210705094218(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec("ALTER TABLE networks ADD rotate_after_deposits bigint NOT NULL DEFAULT 0, ADD rotation_window_hours bigint NOT NULL DEFAULT 0, ADD retired_address_policy varchar(36) NOT NULL DEFAULT 'CREDIT';")
+	if err != nil {
+		return err
+	}
+	_, err1 := tx.Exec("ALTER TABLE user_addresses ADD deposit_count int NOT NULL DEFAULT 0, ADD retired_at timestamp NULL, ADD invalidated_at timestamp NULL, ADD invalidation_reason varchar(255) NULL;")
+	if err1 != nil {
+		return err1
+	}
+	return nil
+}
+
+func Down20210705094218(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE networks DROP COLUMN rotate_after_deposits, DROP COLUMN rotation_window_hours, DROP COLUMN retired_address_policy;")
+	if err != nil {
+		return err
+	}
+	_, err1 := tx.Exec("ALTER TABLE user_addresses DROP COLUMN deposit_count, DROP COLUMN retired_at, DROP COLUMN invalidated_at, DROP COLUMN invalidation_reason;")
+	if err1 != nil {
+		return err1
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210712085536, Down20210712085536)
+}
+
+func Up20210712085536(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	// existing addresses start as PENDING so the reconciler confirms their watchers once
+	_, err := tx.Exec("ALTER TABLE user_addresses ADD subscription_status varchar(36) NOT NULL DEFAULT 'PENDING', ADD subscribed_at timestamp NULL, ADD INDEX idx_user_addresses_subscription_status (subscription_status);")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210712085536(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE user_addresses DROP INDEX idx_user_addresses_subscription_status, DROP COLUMN subscription_status, DROP COLUMN subscribed_at;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package migration
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210719103348, Down20210719103348)
+}
+
+func Up20210719103348(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec("ALTER TABLE user_memos MODIFY memo varchar(100) NOT NULL, ADD network varchar(36) NOT NULL DEFAULT '' AFTER memo, ADD created_at timestamp NULL;")
+	if err != nil {
+		return err
+	}
+	_, err1 := tx.Exec("ALTER TABLE user_addresses MODIFY memo varchar(100);")
+	if err1 != nil {
+		return err1
+	}
+
+	// memos shared by more than one user cannot be resolved to a deposit owner, they are reported for manual review
+	rows, err2 := tx.Query(`SELECT ua.memo, COUNT(DISTINCT a.user_id) FROM user_addresses ua
+		INNER JOIN user_assets a ON a.id = ua.asset_id
+		WHERE ua.memo IS NOT NULL AND ua.memo <> ''
+		GROUP BY ua.memo HAVING COUNT(DISTINCT a.user_id) > 1;`)
+	if err2 != nil {
+		return err2
+	}
+	defer rows.Close()
+	duplicates := 0
+	for rows.Next() {
+		var memo string
+		var userCount int
+		if err := rows.Scan(&memo, &userCount); err != nil {
+			return err
+		}
+		duplicates++
+		log.Printf("user_memos migration : memo %s is assigned to %d users", memo, userCount)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	log.Printf("user_memos migration : %d duplicate memos found, see GET /memos/duplicates", duplicates)
+	return nil
+}
+
+func Down20210719103348(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE user_memos DROP COLUMN network, DROP COLUMN created_at;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210726091207, Down20210726091207)
+}
+
+func Up20210726091207(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS asset_config_overrides (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NOT NULL DEFAULT '',
+		field varchar(100) NOT NULL,
+		value varchar(255) NOT NULL,
+		updated_by varchar(150) NULL,
+
+		PRIMARY KEY (id),
+		CONSTRAINT uix_asset_config_overrides_field UNIQUE (asset_symbol, network, field)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	_, err1 := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS asset_config_changes (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		entity_type varchar(36) NOT NULL,
+		action varchar(36) NOT NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NOT NULL DEFAULT '',
+		changes text NULL,
+		changed_by varchar(150) NOT NULL,
+		reason varchar(255) NULL,
+
+		PRIMARY KEY (id),
+		INDEX asset_config_change_asset (asset_symbol)
+		);
+		`)
+	if err1 != nil {
+		return err1
+	}
+	return nil
+}
+
+func Down20210726091207(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("DROP TABLE IF EXISTS asset_config_overrides, asset_config_changes;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
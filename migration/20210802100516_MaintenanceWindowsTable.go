@@ -0,0 +1,47 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210802100516, Down20210802100516)
+}
+
+func Up20210802100516(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS maintenance_windows (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		asset_symbol varchar(36) NOT NULL DEFAULT '',
+		network varchar(150) NOT NULL,
+		scope varchar(36) NOT NULL,
+		starts_at timestamp NOT NULL,
+		ends_at timestamp NOT NULL,
+		message varchar(255) NULL,
+		created_by varchar(150) NOT NULL,
+		cancelled_by varchar(150) NULL,
+		cancelled_at timestamp NULL,
+
+		PRIMARY KEY (id),
+		INDEX maintenance_window_network (network),
+		INDEX maintenance_window_ends_at (ends_at)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210802100516(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("DROP TABLE IF EXISTS maintenance_windows;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
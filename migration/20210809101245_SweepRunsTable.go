@@ -0,0 +1,82 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210809101245, Down20210809101245)
+}
+
+func Up20210809101245(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS sweep_runs (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		status varchar(36) NOT NULL DEFAULT 'RUNNING',
+		started_at timestamp NOT NULL,
+		finished_at timestamp NULL,
+		candidate_count int NOT NULL DEFAULT 0,
+		swept_count int NOT NULL DEFAULT 0,
+		skipped_count int NOT NULL DEFAULT 0,
+		failed_count int NOT NULL DEFAULT 0,
+		error text NULL,
+
+		PRIMARY KEY (id),
+		INDEX sweep_run_started_at (started_at)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS sweep_items (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		sweep_run_id varchar(36) NOT NULL,
+		address varchar(150) NULL,
+		origins text NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NULL,
+		is_batch boolean NOT NULL DEFAULT false,
+		transaction_count int NOT NULL DEFAULT 0,
+		amount decimal(64,18) NOT NULL DEFAULT 0,
+		float_address varchar(150) NULL,
+		float_percent bigint NOT NULL DEFAULT 0,
+		brokerage_address varchar(150) NULL,
+		brokerage_percent bigint NOT NULL DEFAULT 0,
+		memo varchar(150) NULL,
+		status varchar(36) NOT NULL,
+		skip_reason varchar(36) NULL,
+		reference varchar(150) NULL,
+		transaction_hash varchar(150) NULL,
+		error text NULL,
+
+		PRIMARY KEY (id),
+		INDEX sweep_item_run (sweep_run_id),
+		INDEX sweep_item_address (address),
+		INDEX sweep_item_status (status)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210809101245(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("DROP TABLE IF EXISTS sweep_items;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DROP TABLE IF EXISTS sweep_runs;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210816093021, Down20210816093021)
+}
+
+func Up20210816093021(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS fee_wallets (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		network varchar(150) NOT NULL,
+		native_asset varchar(36) NOT NULL,
+		address varchar(150) NOT NULL,
+		is_disabled boolean NOT NULL DEFAULT false,
+
+		PRIMARY KEY (id),
+		UNIQUE INDEX fee_wallet_network (network)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS gas_fundings (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		address varchar(150) NOT NULL,
+		network varchar(150) NOT NULL,
+		native_asset varchar(36) NOT NULL,
+		asset_symbol varchar(36) NOT NULL,
+		fee_wallet_address varchar(150) NOT NULL,
+		amount bigint NOT NULL DEFAULT 0,
+		reference varchar(150) NOT NULL,
+		transaction_hash varchar(150) NULL,
+		status varchar(36) NOT NULL,
+		confirmed_at timestamp NULL,
+		recovery_reference varchar(150) NULL,
+		recovery_transaction_hash varchar(150) NULL,
+
+		PRIMARY KEY (id),
+		UNIQUE INDEX gas_funding_reference (reference),
+		INDEX gas_funding_address (address),
+		INDEX gas_funding_status (status)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210816093021(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("DROP TABLE IF EXISTS gas_fundings;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DROP TABLE IF EXISTS fee_wallets;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210823101714, Down20210823101714)
+}
+
+func Up20210823101714(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS sweep_policies (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NOT NULL,
+		type varchar(36) NOT NULL,
+		float_percent bigint NOT NULL DEFAULT 0,
+		cold_address varchar(150) NULL,
+		cold_memo varchar(150) NULL,
+		updated_by varchar(150) NOT NULL,
+
+		PRIMARY KEY (id),
+		UNIQUE INDEX sweep_policy_asset_network (asset_symbol, network)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE sweep_items ADD cold_address varchar(150) NULL, ADD cold_percent bigint NOT NULL DEFAULT 0, ADD sweep_policy varchar(36) NULL;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210823101714(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE sweep_items DROP COLUMN cold_address, DROP COLUMN cold_percent, DROP COLUMN sweep_policy;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DROP TABLE IF EXISTS sweep_policies;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
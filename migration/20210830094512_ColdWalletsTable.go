@@ -0,0 +1,83 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210830094512, Down20210830094512)
+}
+
+func Up20210830094512(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS cold_wallets (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NOT NULL,
+		address varchar(150) NOT NULL,
+		memo varchar(150) NULL,
+		label varchar(150) NOT NULL,
+		is_verified boolean NOT NULL DEFAULT false,
+		verified_by varchar(150) NULL,
+		verified_at timestamp NULL,
+		surplus_percent bigint NOT NULL DEFAULT 0,
+		max_transfer_amount decimal(64,18) NOT NULL DEFAULT 0,
+		requires_approval boolean NOT NULL DEFAULT false,
+		approval_threshold decimal(64,18) NOT NULL DEFAULT 0,
+		created_by varchar(150) NOT NULL,
+
+		PRIMARY KEY (id),
+		UNIQUE INDEX cold_wallet_address (asset_symbol, network, address)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS cold_transfers (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		cold_wallet_id varchar(36) NOT NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NOT NULL,
+		from_address varchar(150) NOT NULL,
+		to_address varchar(150) NOT NULL,
+		memo varchar(150) NULL,
+		amount decimal(64,18) NOT NULL,
+		base_amount varchar(78) NOT NULL,
+		reference varchar(150) NOT NULL,
+		transaction_hash varchar(150) NULL,
+		status varchar(36) NOT NULL,
+		reviewed_by varchar(150) NULL,
+		reviewed_at timestamp NULL,
+		error text NULL,
+
+		PRIMARY KEY (id),
+		UNIQUE INDEX cold_transfer_reference (reference),
+		INDEX cold_transfer_wallet (cold_wallet_id),
+		INDEX cold_transfer_status (status)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210830094512(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("DROP TABLE IF EXISTS cold_transfers;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DROP TABLE IF EXISTS cold_wallets;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
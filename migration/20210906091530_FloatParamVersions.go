@@ -0,0 +1,76 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210906091530, Down20210906091530)
+}
+
+func Up20210906091530(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec("ALTER TABLE float_manager_params ADD version bigint NOT NULL DEFAULT 1, ADD is_paused tinyint(1) NOT NULL DEFAULT 0, ADD updated_by varchar(150) NULL;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS float_manager_param_versions (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		float_manager_param_id varchar(36) NOT NULL,
+		version bigint NOT NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NOT NULL,
+		min_percent_max_user_balance decimal(64,2) NOT NULL,
+		max_percent_max_user_balance decimal(64,2) NOT NULL,
+		min_percent_total_user_balance decimal(64,2) NOT NULL,
+		average_percent_total_user_balance decimal(64,2) NOT NULL,
+		max_percent_total_user_balance decimal(64,2) NOT NULL,
+		percent_minimum_trigger_level decimal(64,2) NOT NULL,
+		percent_maximum_trigger_level decimal(64,2) NOT NULL,
+		is_paused tinyint(1) NOT NULL DEFAULT 0,
+		updated_by varchar(150) NULL,
+
+		PRIMARY KEY (id),
+		UNIQUE INDEX float_param_version (float_manager_param_id, version)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	// the parameters in use become their first version
+	_, err = tx.Exec(`INSERT INTO float_manager_param_versions (id, created_at, updated_at, float_manager_param_id, version, asset_symbol, network,
+		min_percent_max_user_balance, max_percent_max_user_balance, min_percent_total_user_balance, average_percent_total_user_balance,
+		max_percent_total_user_balance, percent_minimum_trigger_level, percent_maximum_trigger_level, is_paused)
+		SELECT UUID(), NOW(), NOW(), id, version, asset_symbol, network, min_percent_max_user_balance, max_percent_max_user_balance,
+		min_percent_total_user_balance, average_percent_total_user_balance, max_percent_total_user_balance, percent_minimum_trigger_level,
+		percent_maximum_trigger_level, is_paused FROM float_manager_params;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE float_manager_variables ADD float_param_version bigint NULL;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210906091530(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE float_manager_variables DROP COLUMN float_param_version;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DROP TABLE IF EXISTS float_manager_param_versions;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE float_manager_params DROP COLUMN version, DROP COLUMN is_paused, DROP COLUMN updated_by;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
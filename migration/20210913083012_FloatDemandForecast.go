@@ -0,0 +1,46 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210913083012, Down20210913083012)
+}
+
+func Up20210913083012(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec("ALTER TABLE float_manager_params ADD target_mode varchar(36) NOT NULL DEFAULT 'STATIC';")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE float_manager_param_versions ADD target_mode varchar(36) NOT NULL DEFAULT 'STATIC';")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`ALTER TABLE float_manager_variables ADD target_mode varchar(36) NULL, ADD forecast_hourly_outflow decimal(64,18) NULL,
+		ADD forecast_seasonality decimal(64,18) NULL, ADD forecast_pending_withdrawals decimal(64,18) NULL, ADD forecast_target decimal(64,18) NULL;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210913083012(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec(`ALTER TABLE float_manager_variables DROP COLUMN target_mode, DROP COLUMN forecast_hourly_outflow,
+		DROP COLUMN forecast_seasonality, DROP COLUMN forecast_pending_withdrawals, DROP COLUMN forecast_target;`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE float_manager_param_versions DROP COLUMN target_mode;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE float_manager_params DROP COLUMN target_mode;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210920101502, Down20210920101502)
+}
+
+func Up20210920101502(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS funding_requests (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NOT NULL,
+		float_address varchar(150) NOT NULL,
+		amount decimal(64,18) NOT NULL,
+		base_amount varchar(78) NOT NULL,
+		float_balance varchar(78) NOT NULL,
+		status varchar(36) NOT NULL,
+		ack_token varchar(64) NOT NULL,
+		acknowledged_by varchar(150) NULL,
+		acknowledged_at timestamp NULL,
+		escalated_at timestamp NULL,
+		fulfilled_at timestamp NULL,
+		fulfilled_amount varchar(78) NULL,
+		expires_at timestamp NOT NULL,
+
+		PRIMARY KEY (id),
+		UNIQUE INDEX funding_request_ack_token (ack_token),
+		INDEX funding_request_asset_status (asset_symbol, network, status)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210920101502(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("DROP TABLE IF EXISTS funding_requests;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
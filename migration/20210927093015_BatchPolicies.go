@@ -0,0 +1,52 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20210927093015, Down20210927093015)
+}
+
+func Up20210927093015(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS batch_policies (
+		id varchar(36) NOT NULL,
+		created_at timestamp NULL,
+		updated_at timestamp NULL,
+		asset_symbol varchar(36) NOT NULL,
+		network varchar(150) NOT NULL,
+		max_recipients bigint NOT NULL DEFAULT 0,
+		max_total_value decimal(64,18) NOT NULL DEFAULT 0,
+		max_age_minutes bigint NOT NULL DEFAULT 0,
+		min_age_minutes bigint NOT NULL DEFAULT 0,
+		updated_by varchar(150) NOT NULL,
+
+		PRIMARY KEY (id),
+		UNIQUE INDEX batch_policy_asset_network (asset_symbol, network)
+		);
+		`)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("ALTER TABLE batch_requests ADD closed_at datetime NULL, ADD close_reason varchar(36) NULL;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20210927093015(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE batch_requests DROP COLUMN closed_at, DROP COLUMN close_reason;")
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DROP TABLE IF EXISTS batch_policies;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
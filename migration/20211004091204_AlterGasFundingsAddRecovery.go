@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20211004091204, Down20211004091204)
+}
+
+func Up20211004091204(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec("ALTER TABLE gas_fundings ADD sweep_reference varchar(150) NULL, ADD recovered_amount bigint NOT NULL DEFAULT 0;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20211004091204(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE gas_fundings DROP COLUMN sweep_reference, DROP COLUMN recovered_amount;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
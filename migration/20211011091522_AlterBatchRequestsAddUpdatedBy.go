@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20211011091522, Down20211011091522)
+}
+
+func Up20211011091522(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec("ALTER TABLE batch_requests ADD updated_by varchar(150) NULL;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20211011091522(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE batch_requests DROP COLUMN updated_by;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
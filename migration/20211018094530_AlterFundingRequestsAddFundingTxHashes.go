@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20211018094530, Down20211018094530)
+}
+
+func Up20211018094530(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec("ALTER TABLE funding_requests ADD funding_tx_hashes text NULL;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20211018094530(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE funding_requests DROP COLUMN funding_tx_hashes;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20211018101245, Down20211018101245)
+}
+
+func Up20211018101245(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec("ALTER TABLE user_addresses ADD key_identity varchar(36) NULL;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20211018101245(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE user_addresses DROP COLUMN key_identity;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
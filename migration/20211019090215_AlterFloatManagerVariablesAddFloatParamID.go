@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"database/sql"
+	"github.com/pressly/goose"
+)
+
+func init() {
+	goose.AddMigration(Up20211019090215, Down20211019090215)
+}
+
+func Up20211019090215(tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec("ALTER TABLE float_manager_variables ADD float_param_id varchar(36) NULL;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func Down20211019090215(tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec("ALTER TABLE float_manager_variables DROP COLUMN float_param_id;")
+	if err != nil {
+		return err
+	}
+	return nil
+}
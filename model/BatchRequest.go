@@ -6,7 +6,7 @@ import (
 
 // BTHStatus ...
 type BTHStatus struct {
-	WAIT_MODE, RETRY_MODE, PROCESSING, COMPLETED, TERMINATED, START_MODE string
+	WAIT_MODE, RETRY_MODE, PROCESSING, COMPLETED, TERMINATED, START_MODE, AWAITING_FLOAT string
 }
 
 var (
@@ -18,6 +18,23 @@ var (
 		PROCESSING: "ONGOING",
 		COMPLETED:  "COMPLETED",
 		TERMINATED: "TERMINATED",
+		AWAITING_FLOAT: "AWAITING_FLOAT",
+	}
+)
+
+// BTHCloseReason ...
+type BTHCloseReason struct {
+	MAX_RECIPIENTS, MAX_VALUE, MAX_AGE, SCHEDULED, FORCED string
+}
+
+var (
+	// BatchCloseReason ... Why a batch stopped taking withdrawals
+	BatchCloseReason = BTHCloseReason{
+		MAX_RECIPIENTS: "MAX_RECIPIENTS",
+		MAX_VALUE:      "MAX_VALUE",
+		MAX_AGE:        "MAX_AGE",
+		SCHEDULED:      "SCHEDULED",
+		FORCED:         "FORCED",
 	}
 )
 
@@ -30,5 +47,15 @@ type BatchRequest struct {
 	DateOfProcessing *time.Time    `json:"date_of_processing,omitempty"`
 	DateCompleted    *time.Time    `json:"date_completed,omitempty"`
 	NoOfRecords      int           `json:"no_of_records,omitempty"`
+	ClosedAt         *time.Time    `json:"closed_at,omitempty"`
+	CloseReason      string        `gorm:"type:VARCHAR(36)" json:"close_reason,omitempty"`
+	UpdatedBy        string        `gorm:"type:VARCHAR(150)" json:"updated_by,omitempty"`
 	Transactions     []Transaction `json:"transaction_requests,omitempty"`
 }
+
+// BatchDetails ... A batch with its queued transactions and the chain transaction it was sent in, if any
+type BatchDetails struct {
+	Batch              BatchRequest       `json:"batch"`
+	QueuedTransactions []TransactionQueue `json:"queued_transactions"`
+	ChainTransaction   *ChainTransaction  `json:"chain_transaction,omitempty"`
+}
@@ -1,5 +1,25 @@
 package model
 
+// DepositDustPolicy ...
+type DepositDustPolicy struct{ RECORD, BUCKET string }
+
+var (
+	DustPolicy = DepositDustPolicy{
+		RECORD: "RECORD",
+		BUCKET: "BUCKET",
+	}
+)
+
+// DepositRetiredAddressPolicy ...
+type DepositRetiredAddressPolicy struct{ CREDIT, HOLD string }
+
+var (
+	RetiredAddressPolicy = DepositRetiredAddressPolicy{
+		CREDIT: "CREDIT",
+		HOLD:   "HOLD",
+	}
+)
+
 type Network struct {
 	BaseModel
 	NativeAsset                string         `json:"nativeAsset,omitempty"`
@@ -15,6 +35,11 @@ type Network struct {
 	IsToken             *bool          `gorm:"is_token default:0" json:"isToken"`
 	MinimumSweepable    float64         `json:"minimumSweepable"`
 	SweepFee            int64           `json:"sweepFee"`
+	MinimumDeposit      float64         `json:"minimumDeposit"`
+	DustPolicy          string          `gorm:"default:'RECORD'" json:"dustPolicy"`
+	RotateAfterDeposits int64           `json:"rotateAfterDeposits"`
+	RotationWindowHours int64           `json:"rotationWindowHours"`
+	RetiredAddressPolicy string         `gorm:"default:'CREDIT'" json:"retiredAddressPolicy"`
 	DepositActivity     string         `json:"depositActivity"`
 	WithdrawActivity    string         `json:"withdrawActivity"`
 }
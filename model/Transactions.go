@@ -17,7 +17,7 @@ type ProcessType struct{ SINGLE, BATCH string }
 type TxnTag struct{ CREDIT, DEBIT, TRANSFER, DEPOSIT, WITHDRAW string }
 
 // TxnStatus ...
-type TxnStatus struct{ PENDING, PROCESSING, COMPLETED, TERMINATED, REJECTED string }
+type TxnStatus struct{ PENDING, PROCESSING, COMPLETED, TERMINATED, REJECTED, DUST, SCREENING_HOLD, AWAITING_FLOAT string }
 
 var (
 	TransactionType = TxnType{
@@ -30,6 +30,9 @@ var (
 		COMPLETED:  "COMPLETED",
 		TERMINATED: "TERMINATED",
 		REJECTED:   "REJECTED",
+		DUST:       "DUST",
+		SCREENING_HOLD: "SCREENING_HOLD",
+		AWAITING_FLOAT: "AWAITING_FLOAT",
 	}
 
 	TransactionTag = TxnTag{
@@ -6,12 +6,22 @@ import (
 )
 
 // AddrProvider ...
-type AddrProvider struct{ BUNDLE, BINANCE string }
+type AddrProvider struct{ BUNDLE, BINANCE, XPUB string }
+
+// AddrSubscriptionStatus ...
+type AddrSubscriptionStatus struct{ PENDING, ACTIVE, FAILED, CANCELLED string }
 
 var (
 	AddressProvider = AddrProvider{
 		BUNDLE: "Bundle",
 		BINANCE:  "Binance",
+		XPUB:    "Xpub",
+	}
+	SubscriptionStatus = AddrSubscriptionStatus{
+		PENDING: "PENDING",
+		ACTIVE:  "ACTIVE",
+		FAILED:  "FAILED",
+		CANCELLED: "CANCELLED",
 	}
 )
 
@@ -22,11 +32,19 @@ type UserAddress struct {
 	Address     string    `gorm:"VARCHAR(100);" json:"address"`
 	AddressType string    `gorm:"VARCHAR(50);" json:"addressType"`
 	V2Address   string    `gorm:"VARCHAR(255);" json:"v2Address"`
-	Memo        string    `gorm:"VARCHAR(15);" json:"memo"`
+	Memo        string    `gorm:"VARCHAR(100);" json:"memo"`
 	AddressProvider string `gorm:"VARCHAR(150) NOT NULL Default='Bundle';" json:"address_provider"`
 	Network string    `json:"network"`
 	IsPrimaryAddress bool `json:"is_primary_address"`
 	SweepCount int `json:"sweep_count"`
 	NextSweepTime *time.Time `json:"next_sweep_count"`
 	IsValid     bool      `gorm:"default:1" json:"is_valid"`
+	DerivationIndex *int64 `json:"derivation_index,omitempty"`
+	KeyIdentity *uuid.UUID `gorm:"type:VARCHAR(36)" json:"key_identity,omitempty"`
+	DepositCount int `json:"deposit_count"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+	InvalidatedAt *time.Time `json:"invalidated_at,omitempty"`
+	InvalidationReason string `gorm:"VARCHAR(255);" json:"invalidation_reason,omitempty"`
+	SubscriptionStatus string `gorm:"default:'PENDING'" json:"subscription_status"`
+	SubscribedAt *time.Time `json:"subscribed_at,omitempty"`
 }
@@ -0,0 +1,42 @@
+package model
+
+// ConfigEntity ...
+type ConfigEntity struct{ DENOMINATION, NETWORK string }
+
+// ConfigAction ...
+type ConfigAction struct{ CREATE, UPDATE, DELETE string }
+
+var (
+	AssetConfigEntity = ConfigEntity{
+		DENOMINATION: "DENOMINATION",
+		NETWORK:      "NETWORK",
+	}
+	AssetConfigAction = ConfigAction{
+		CREATE: "CREATE",
+		UPDATE: "UPDATE",
+		DELETE: "DELETE",
+	}
+)
+
+// AssetConfigOverride ... Denomination or network field set through the admin API, re-applied over the seeded value on every re-seed.
+// Denomination fields are stored with an empty network
+type AssetConfigOverride struct {
+	BaseModel
+	AssetSymbol string `gorm:"type:VARCHAR(36);not null;unique_index:uix_asset_config_overrides_field" json:"assetSymbol"`
+	Network     string `gorm:"type:VARCHAR(150);not null;default:'';unique_index:uix_asset_config_overrides_field" json:"network"`
+	Field       string `gorm:"type:VARCHAR(100);not null;unique_index:uix_asset_config_overrides_field" json:"field"`
+	Value       string `gorm:"type:VARCHAR(255);not null" json:"value"`
+	UpdatedBy   string `gorm:"type:VARCHAR(150)" json:"updatedBy"`
+}
+
+// AssetConfigChange ... History of changes made to denominations and networks through the admin API
+type AssetConfigChange struct {
+	BaseModel
+	EntityType  string `gorm:"type:VARCHAR(36);not null" json:"entityType"`
+	Action      string `gorm:"type:VARCHAR(36);not null" json:"action"`
+	AssetSymbol string `gorm:"type:VARCHAR(36);not null;index:asset_config_change_asset" json:"assetSymbol"`
+	Network     string `gorm:"type:VARCHAR(150);not null;default:''" json:"network"`
+	Changes     string `gorm:"type:TEXT" json:"changes"`
+	ChangedBy   string `gorm:"type:VARCHAR(150);not null" json:"changedBy"`
+	Reason      string `gorm:"type:VARCHAR(255)" json:"reason,omitempty"`
+}
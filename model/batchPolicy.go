@@ -0,0 +1,17 @@
+package model
+
+import "github.com/shopspring/decimal"
+
+// BatchPolicy ... When the waiting batch of an asset on a network is closed for processing. A batch closes once it has the maximum
+// recipients or total value, or reaches its maximum age, and a scheduled processing run only closes it past its minimum age.
+// Zero limits are not applied, assets without a policy close their batch on every scheduled run
+type BatchPolicy struct {
+	BaseModel
+	AssetSymbol   string          `gorm:"type:VARCHAR(36);not null;unique_index:batch_policy_asset_network" json:"assetSymbol"`
+	Network       string          `gorm:"type:VARCHAR(150);not null;unique_index:batch_policy_asset_network" json:"network"`
+	MaxRecipients int64           `gorm:"not null;default:0" json:"maxRecipients"`
+	MaxTotalValue decimal.Decimal `gorm:"type:decimal(64,18);not null;default:0" json:"maxTotalValue"`
+	MaxAgeMinutes int64           `gorm:"not null;default:0" json:"maxAgeMinutes"`
+	MinAgeMinutes int64           `gorm:"not null;default:0" json:"minAgeMinutes"`
+	UpdatedBy     string          `gorm:"type:VARCHAR(150);not null" json:"updatedBy"`
+}
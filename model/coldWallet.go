@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// ColdTransferStatuses ...
+type ColdTransferStatuses struct{ PENDING_APPROVAL, APPROVED, SENT, REJECTED, FAILED string }
+
+var (
+	ColdTransferStatus = ColdTransferStatuses{
+		PENDING_APPROVAL: "PENDING_APPROVAL",
+		APPROVED:         "APPROVED",
+		SENT:             "SENT",
+		REJECTED:         "REJECTED",
+		FAILED:           "FAILED",
+	}
+)
+
+// ColdWallet ... Cold storage address of an asset on a network, only verified wallets receive funds.
+// SurplusPercent is the share of the float surplus sent to it, what is left goes to the brokerage
+type ColdWallet struct {
+	BaseModel
+	AssetSymbol       string     `gorm:"type:VARCHAR(36);not null;unique_index:cold_wallet_address" json:"assetSymbol"`
+	Network           string     `gorm:"type:VARCHAR(150);not null;unique_index:cold_wallet_address" json:"network"`
+	Address           string     `gorm:"type:VARCHAR(150);not null;unique_index:cold_wallet_address" json:"address"`
+	Memo              string     `gorm:"type:VARCHAR(150)" json:"memo,omitempty"`
+	Label             string     `gorm:"type:VARCHAR(150);not null" json:"label"`
+	IsVerified        bool       `gorm:"not null;default:false" json:"isVerified"`
+	VerifiedBy        string     `gorm:"type:VARCHAR(150)" json:"verifiedBy,omitempty"`
+	VerifiedAt        *time.Time `json:"verifiedAt,omitempty"`
+	SurplusPercent    int64      `gorm:"not null;default:0" json:"surplusPercent"`
+	MaxTransferAmount float64    `gorm:"type:decimal(64,18);not null;default:0" json:"maxTransferAmount"`
+	RequiresApproval  bool       `gorm:"not null;default:false" json:"requiresApproval"`
+	ApprovalThreshold float64    `gorm:"type:decimal(64,18);not null;default:0" json:"approvalThreshold"`
+	CreatedBy         string     `gorm:"type:VARCHAR(150);not null" json:"createdBy"`
+	UpdatedBy         string     `gorm:"type:VARCHAR(150)" json:"updatedBy,omitempty"`
+}
+
+// ColdTransfer ... Float surplus sent, or waiting for approval to be sent, to a cold wallet
+type ColdTransfer struct {
+	BaseModel
+	ColdWalletID    uuid.UUID  `gorm:"type:VARCHAR(36);not null;index:cold_transfer_wallet" json:"coldWalletId"`
+	AssetSymbol     string     `gorm:"type:VARCHAR(36);not null" json:"assetSymbol"`
+	Network         string     `gorm:"type:VARCHAR(150);not null" json:"network"`
+	FromAddress     string     `gorm:"type:VARCHAR(150);not null" json:"fromAddress"`
+	ToAddress       string     `gorm:"type:VARCHAR(150);not null" json:"toAddress"`
+	Memo            string     `gorm:"type:VARCHAR(150)" json:"memo,omitempty"`
+	Amount          string     `gorm:"type:decimal(64,18);not null" json:"amount"`
+	BaseAmount      string     `gorm:"type:VARCHAR(78);not null" json:"baseAmount"`
+	Reference       string     `gorm:"type:VARCHAR(150);not null;unique_index:cold_transfer_reference" json:"reference"`
+	TransactionHash string     `gorm:"type:VARCHAR(150)" json:"transactionHash,omitempty"`
+	Status          string     `gorm:"type:VARCHAR(36);not null;index:cold_transfer_status" json:"status"`
+	ReviewedBy      string     `gorm:"type:VARCHAR(150)" json:"reviewedBy,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewedAt,omitempty"`
+	Error           string     `gorm:"type:TEXT" json:"error,omitempty"`
+}
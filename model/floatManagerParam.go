@@ -1,5 +1,19 @@
 package model
 
+import uuid "github.com/satori/go.uuid"
+
+// FloatTargetModes ...
+type FloatTargetModes struct{ STATIC, FORECAST string }
+
+var (
+	// FloatTargetMode ... How the float manager sets the float range, STATIC uses percentages of user balances and FORECAST
+	// raises the minimum to the withdrawals expected before the next runs
+	FloatTargetMode = FloatTargetModes{
+		STATIC:   "STATIC",
+		FORECAST: "FORECAST",
+	}
+)
+
 // FloatManagerParam...
 type FloatManagerParam struct {
 	BaseModel
@@ -12,4 +26,48 @@ type FloatManagerParam struct {
 	PercentMaximumTriggerLevel     float64
 	AssetSymbol                    string
 	Network                    string
+	Version                        int64  `gorm:"not null;default:1"`
+	IsPaused                       bool   `gorm:"not null;default:false"`
+	UpdatedBy                      string `gorm:"type:VARCHAR(150)"`
+	TargetMode                     string `gorm:"type:VARCHAR(36);not null;default:'STATIC'"`
+}
+
+// FloatManagerParamVersion ... A version of the float parameters of an asset, kept so float manager runs can be traced to the
+// parameters they used
+type FloatManagerParamVersion struct {
+	BaseModel
+	FloatManagerParamID            uuid.UUID `gorm:"type:VARCHAR(36);not null;unique_index:float_param_version"`
+	Version                        int64     `gorm:"not null;unique_index:float_param_version"`
+	AssetSymbol                    string    `gorm:"type:VARCHAR(36);not null"`
+	Network                        string    `gorm:"type:VARCHAR(150);not null"`
+	MinPercentMaxUserBalance       float64
+	MaxPercentMaxUserBalance       float64
+	MinPercentTotalUserBalance     float64
+	AveragePercentTotalUserBalance float64
+	MaxPercentTotalUserBalance     float64
+	PercentMinimumTriggerLevel     float64
+	PercentMaximumTriggerLevel     float64
+	IsPaused                       bool
+	UpdatedBy                      string    `gorm:"type:VARCHAR(150)"`
+	TargetMode                     string    `gorm:"type:VARCHAR(36)"`
+}
+
+// VersionOf ... Returns the float parameters as a version record
+func (param FloatManagerParam) VersionOf() FloatManagerParamVersion {
+	return FloatManagerParamVersion{
+		FloatManagerParamID:            param.ID,
+		Version:                        param.Version,
+		AssetSymbol:                    param.AssetSymbol,
+		Network:                        param.Network,
+		MinPercentMaxUserBalance:       param.MinPercentMaxUserBalance,
+		MaxPercentMaxUserBalance:       param.MaxPercentMaxUserBalance,
+		MinPercentTotalUserBalance:     param.MinPercentTotalUserBalance,
+		AveragePercentTotalUserBalance: param.AveragePercentTotalUserBalance,
+		MaxPercentTotalUserBalance:     param.MaxPercentTotalUserBalance,
+		PercentMinimumTriggerLevel:     param.PercentMinimumTriggerLevel,
+		PercentMaximumTriggerLevel:     param.PercentMaximumTriggerLevel,
+		IsPaused:                       param.IsPaused,
+		UpdatedBy:                      param.UpdatedBy,
+		TargetMode:                     param.TargetMode,
+	}
 }
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
 
 type FloatManager struct {
 	BaseModel
@@ -18,6 +22,15 @@ type FloatManager struct {
 	Surplus               float64
 	Action                string
 	LastRunTime           time.Time
+	// the parameters the run used, a network without its own parameters uses those of the asset
+	FloatParamID          *uuid.UUID `gorm:"type:VARCHAR(36)"`
+	FloatParamVersion     int64
+	TargetMode            string
+	// the forecast of FORECAST runs, in native units
+	ForecastHourlyOutflow      float64
+	ForecastSeasonality        float64
+	ForecastPendingWithdrawals float64
+	ForecastTarget             float64
 }
 
 func (float FloatManager) TableName() string {
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// FundingRequestStatuses ...
+type FundingRequestStatuses struct{ OPEN, ACKNOWLEDGED, FULFILLED, EXPIRED string }
+
+var (
+	FundingRequestStatus = FundingRequestStatuses{
+		OPEN:         "OPEN",
+		ACKNOWLEDGED: "ACKNOWLEDGED",
+		FULFILLED:    "FULFILLED",
+		EXPIRED:      "EXPIRED",
+	}
+)
+
+// FundingRequest ... A request to the cold wallet users to top up the float of an asset, the float manager opens at most one at a time per asset.
+// It is fulfilled once deposits from a cold wallet or treasury address to the float, since it was opened, add up to the requested amount
+type FundingRequest struct {
+	BaseModel
+	AssetSymbol     string     `gorm:"type:VARCHAR(36);not null;index:funding_request_asset_status" json:"assetSymbol"`
+	Network         string     `gorm:"type:VARCHAR(150);not null;index:funding_request_asset_status" json:"network"`
+	FloatAddress    string     `gorm:"type:VARCHAR(150);not null" json:"floatAddress"`
+	Amount          string     `gorm:"type:decimal(64,18);not null" json:"amount"`
+	BaseAmount      string     `gorm:"type:VARCHAR(78);not null" json:"baseAmount"`
+	FloatBalance    string     `gorm:"type:VARCHAR(78);not null" json:"floatBalance"`
+	Status          string     `gorm:"type:VARCHAR(36);not null;index:funding_request_asset_status" json:"status"`
+	AckToken        string     `gorm:"type:VARCHAR(64);not null;unique_index" json:"-"`
+	AcknowledgedBy  string     `gorm:"type:VARCHAR(150)" json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt  *time.Time `json:"acknowledgedAt,omitempty"`
+	EscalatedAt     *time.Time `json:"escalatedAt,omitempty"`
+	FulfilledAt     *time.Time `json:"fulfilledAt,omitempty"`
+	FulfilledAmount string     `gorm:"type:VARCHAR(78)" json:"fulfilledAmount,omitempty"`
+	FundingTxHashes string     `gorm:"type:TEXT" json:"fundingTxHashes,omitempty"`
+	ExpiresAt       time.Time  `gorm:"not null" json:"expiresAt"`
+}
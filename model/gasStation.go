@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// GasFundingStatuses ...
+type GasFundingStatuses struct{ PENDING, CONFIRMED, SPENT, RECOVERED, FAILED string }
+
+var (
+	GasFundingStatus = GasFundingStatuses{
+		PENDING:   "PENDING",
+		CONFIRMED: "CONFIRMED",
+		SPENT:     "SPENT",
+		RECOVERED: "RECOVERED",
+		FAILED:    "FAILED",
+	}
+)
+
+// FeeWallet ... Wallet holding the native coin of a network, used to fund the fee of token sweeps from deposit addresses.
+// It is kept apart from the float hot wallet so funding sweeps never draws on user withdrawals
+type FeeWallet struct {
+	BaseModel
+	Network     string `gorm:"type:VARCHAR(150);not null;unique_index:fee_wallet_network" json:"network"`
+	NativeAsset string `gorm:"type:VARCHAR(36);not null" json:"nativeAsset"`
+	Address     string `gorm:"type:VARCHAR(150);not null" json:"address"`
+	IsDisabled  bool   `gorm:"not null;default:false" json:"isDisabled"`
+}
+
+// GasFunding ... Native coin sent from the fee wallet to a deposit address so a token deposit on it can be swept
+type GasFunding struct {
+	BaseModel
+	Address                 string     `gorm:"type:VARCHAR(150);not null;index:gas_funding_address" json:"address"`
+	Network                 string     `gorm:"type:VARCHAR(150);not null" json:"network"`
+	NativeAsset             string     `gorm:"type:VARCHAR(36);not null" json:"nativeAsset"`
+	AssetSymbol             string     `gorm:"type:VARCHAR(36);not null" json:"assetSymbol"`
+	FeeWalletAddress        string     `gorm:"type:VARCHAR(150);not null" json:"feeWalletAddress"`
+	Amount                  int64      `gorm:"not null;default:0" json:"amount"`
+	Reference               string     `gorm:"type:VARCHAR(150);not null;unique_index:gas_funding_reference" json:"reference"`
+	TransactionHash         string     `gorm:"type:VARCHAR(150)" json:"transactionHash,omitempty"`
+	Status                  string     `gorm:"type:VARCHAR(36);not null;index:gas_funding_status" json:"status"`
+	ConfirmedAt             *time.Time `json:"confirmedAt,omitempty"`
+	SweepReference          string     `gorm:"type:VARCHAR(150)" json:"sweepReference,omitempty"`
+	RecoveryReference       string     `gorm:"type:VARCHAR(150)" json:"recoveryReference,omitempty"`
+	RecoveredAmount         int64      `gorm:"not null;default:0" json:"recoveredAmount"`
+	RecoveryTransactionHash string     `gorm:"type:VARCHAR(150)" json:"recoveryTransactionHash,omitempty"`
+}
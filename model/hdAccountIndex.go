@@ -0,0 +1,8 @@
+package model
+
+// HDAccountIndex ... Next unused external chain index of a configured account xpub
+type HDAccountIndex struct {
+	BaseModel
+	AccountKey string `gorm:"type:VARCHAR(150);not null;unique_index" json:"accountKey"`
+	NextIndex  int64  `gorm:"not null;default:0" json:"nextIndex"`
+}
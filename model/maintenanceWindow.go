@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// MaintenanceScopes ...
+type MaintenanceScopes struct{ DEPOSIT, WITHDRAW, SWEEP, FLOAT string }
+
+var (
+	MaintenanceScope = MaintenanceScopes{
+		DEPOSIT:  "DEPOSIT",
+		WITHDRAW: "WITHDRAW",
+		SWEEP:    "SWEEP",
+		FLOAT:    "FLOAT",
+	}
+)
+
+// MaintenanceWindow ... Period during which an operation is paused on a network, for every asset on the network when asset symbol is empty
+type MaintenanceWindow struct {
+	BaseModel
+	AssetSymbol string     `gorm:"type:VARCHAR(36);not null;default:''" json:"assetSymbol"`
+	Network     string     `gorm:"type:VARCHAR(150);not null;index:maintenance_window_network" json:"network"`
+	Scope       string     `gorm:"type:VARCHAR(36);not null" json:"scope"`
+	StartsAt    time.Time  `gorm:"not null" json:"startsAt"`
+	EndsAt      time.Time  `gorm:"not null;index:maintenance_window_ends_at" json:"endsAt"`
+	Message     string     `gorm:"type:VARCHAR(255)" json:"message"`
+	CreatedBy   string     `gorm:"type:VARCHAR(150);not null" json:"createdBy"`
+	CancelledBy string     `gorm:"type:VARCHAR(150)" json:"cancelledBy,omitempty"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty"`
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// PoolStatus ...
+type PoolStatus struct{ AVAILABLE, ASSIGNED string }
+
+var (
+	PooledAddressStatus = PoolStatus{
+		AVAILABLE: "AVAILABLE",
+		ASSIGNED:  "ASSIGNED",
+	}
+)
+
+// PooledAddress ... Deposit address generated and subscribed ahead of time, handed to a user asset on first request
+type PooledAddress struct {
+	BaseModel
+	PoolIdentity uuid.UUID  `gorm:"type:VARCHAR(36);not null" json:"poolIdentity"`
+	Address      string     `gorm:"type:VARCHAR(150);not null;unique_index" json:"address"`
+	AddressType  string     `gorm:"type:VARCHAR(50)" json:"addressType"`
+	AssetSymbol  string     `gorm:"type:VARCHAR(36);not null;index:pooled_address_asset_network" json:"assetSymbol"`
+	Network      string     `gorm:"type:VARCHAR(150);not null;index:pooled_address_asset_network" json:"network"`
+	CoinType     int64      `json:"coinType"`
+	Status       string     `gorm:"type:VARCHAR(36);not null;default:'AVAILABLE'" json:"status"`
+	AssetID      *uuid.UUID `gorm:"type:VARCHAR(36)" json:"assetId,omitempty"`
+	AssignedAt   *time.Time `json:"assignedAt,omitempty"`
+}
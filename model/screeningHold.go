@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// HoldStatus ...
+type HoldStatus struct{ HELD, RELEASED, REJECTED string }
+
+var (
+	ScreeningHoldStatus = HoldStatus{
+		HELD:     "HELD",
+		RELEASED: "RELEASED",
+		REJECTED: "REJECTED",
+	}
+)
+
+// ScreeningHold ... Record of a transaction held after its counterparty address matched a sanctions screen
+type ScreeningHold struct {
+	BaseModel
+	TransactionID  uuid.UUID  `gorm:"type:VARCHAR(36);not null;unique_index" json:"transactionId"`
+	TransactionTag string     `gorm:"type:VARCHAR(36);not null" json:"transactionTag"`
+	Address        string     `gorm:"type:VARCHAR(150);not null;index:screening_address" json:"address"`
+	AssetSymbol    string     `gorm:"type:VARCHAR(36);not null" json:"assetSymbol"`
+	Network        string     `gorm:"type:VARCHAR(150)" json:"network"`
+	Value          string     `gorm:"type:decimal(64,18);not null" json:"value"`
+	Provider       string     `gorm:"type:VARCHAR(36)" json:"provider"`
+	Reason         string     `gorm:"type:VARCHAR(255)" json:"reason"`
+	Status         string     `gorm:"type:VARCHAR(36);not null;default:'HELD';index:screening_status" json:"status"`
+	ReviewedBy     string     `gorm:"type:VARCHAR(150)" json:"reviewedBy,omitempty"`
+	ReviewNote     string     `gorm:"type:VARCHAR(255)" json:"reviewNote,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewedAt,omitempty"`
+}
@@ -0,0 +1,26 @@
+package model
+
+// SweepPolicyTypes ...
+type SweepPolicyTypes struct{ FLOAT_THEN_BROKERAGE, FIXED_PERCENTAGES, FLOAT_THEN_COLD, ALL_TO_COLD string }
+
+var (
+	SweepPolicyType = SweepPolicyTypes{
+		FLOAT_THEN_BROKERAGE: "FLOAT_THEN_BROKERAGE",
+		FIXED_PERCENTAGES:    "FIXED_PERCENTAGES",
+		FLOAT_THEN_COLD:      "FLOAT_THEN_COLD",
+		ALL_TO_COLD:          "ALL_TO_COLD",
+	}
+)
+
+// SweepPolicy ... How the sweeps of an asset on a network are split between the float and the brokerage or cold storage,
+// assets without a policy fill the float to its target and send the rest to the brokerage
+type SweepPolicy struct {
+	BaseModel
+	AssetSymbol  string `gorm:"type:VARCHAR(36);not null;unique_index:sweep_policy_asset_network" json:"assetSymbol"`
+	Network      string `gorm:"type:VARCHAR(150);not null;unique_index:sweep_policy_asset_network" json:"network"`
+	Type         string `gorm:"type:VARCHAR(36);not null" json:"type"`
+	FloatPercent int64  `gorm:"not null;default:0" json:"floatPercent"`
+	ColdAddress  string `gorm:"type:VARCHAR(150)" json:"coldAddress,omitempty"`
+	ColdMemo     string `gorm:"type:VARCHAR(150)" json:"coldMemo,omitempty"`
+	UpdatedBy    string `gorm:"type:VARCHAR(150);not null" json:"updatedBy"`
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// SweepRunStatuses ...
+type SweepRunStatuses struct{ RUNNING, COMPLETED, FAILED, CANCELLED string }
+
+// SweepItemStatuses ...
+type SweepItemStatuses struct{ SWEPT, SKIPPED, FAILED string }
+
+// SweepSkipReasons ...
+type SweepSkipReasons struct{ BELOW_MINIMUM, TRX_DAILY_LIMIT, INSUFFICIENT_FUNDS, AWAITING_GAS string }
+
+var (
+	SweepRunStatus = SweepRunStatuses{
+		RUNNING:   "RUNNING",
+		COMPLETED: "COMPLETED",
+		FAILED:    "FAILED",
+		CANCELLED: "CANCELLED",
+	}
+	SweepItemStatus = SweepItemStatuses{
+		SWEPT:   "SWEPT",
+		SKIPPED: "SKIPPED",
+		FAILED:  "FAILED",
+	}
+	SweepSkipReason = SweepSkipReasons{
+		BELOW_MINIMUM:      "BELOW_MINIMUM",
+		TRX_DAILY_LIMIT:    "TRX_DAILY_LIMIT",
+		INSUFFICIENT_FUNDS: "INSUFFICIENT_FUNDS",
+		AWAITING_GAS:       "AWAITING_GAS",
+	}
+)
+
+// SweepRun ... Record of one execution of the sweep job
+type SweepRun struct {
+	BaseModel
+	Status         string     `gorm:"type:VARCHAR(36);not null;default:'RUNNING'" json:"status"`
+	StartedAt      time.Time  `gorm:"not null;index:sweep_run_started_at" json:"startedAt"`
+	FinishedAt     *time.Time `json:"finishedAt,omitempty"`
+	CandidateCount int        `gorm:"not null;default:0" json:"candidateCount"`
+	SweptCount     int        `gorm:"not null;default:0" json:"sweptCount"`
+	SkippedCount   int        `gorm:"not null;default:0" json:"skippedCount"`
+	FailedCount    int        `gorm:"not null;default:0" json:"failedCount"`
+	Error          string     `gorm:"type:TEXT" json:"error,omitempty"`
+}
+
+// SweepItem ... Outcome of sweeping one address, or one batch of addresses, during a sweep run
+type SweepItem struct {
+	BaseModel
+	SweepRunID       uuid.UUID `gorm:"type:VARCHAR(36);not null;index:sweep_item_run" json:"sweepRunId"`
+	Address          string    `gorm:"type:VARCHAR(150);index:sweep_item_address" json:"address"`
+	Origins          string    `gorm:"type:TEXT" json:"origins,omitempty"`
+	AssetSymbol      string    `gorm:"type:VARCHAR(36);not null" json:"assetSymbol"`
+	Network          string    `gorm:"type:VARCHAR(150)" json:"network"`
+	IsBatch          bool      `gorm:"not null;default:false" json:"isBatch"`
+	TransactionCount int       `gorm:"not null;default:0" json:"transactionCount"`
+	Amount           string    `gorm:"type:decimal(64,18);not null;default:0" json:"amount"`
+	FloatAddress     string    `gorm:"type:VARCHAR(150)" json:"floatAddress,omitempty"`
+	FloatPercent     int64     `gorm:"not null;default:0" json:"floatPercent"`
+	BrokerageAddress string    `gorm:"type:VARCHAR(150)" json:"brokerageAddress,omitempty"`
+	BrokeragePercent int64     `gorm:"not null;default:0" json:"brokeragePercent"`
+	ColdAddress      string    `gorm:"type:VARCHAR(150)" json:"coldAddress,omitempty"`
+	ColdPercent      int64     `gorm:"not null;default:0" json:"coldPercent"`
+	SweepPolicy      string    `gorm:"type:VARCHAR(36)" json:"sweepPolicy,omitempty"`
+	Memo             string    `gorm:"type:VARCHAR(150)" json:"memo,omitempty"`
+	Status           string    `gorm:"type:VARCHAR(36);not null;index:sweep_item_status" json:"status"`
+	SkipReason       string    `gorm:"type:VARCHAR(36)" json:"skipReason,omitempty"`
+	Reference        string    `gorm:"type:VARCHAR(150)" json:"reference,omitempty"`
+	TransactionHash  string    `gorm:"type:VARCHAR(150)" json:"transactionHash,omitempty"`
+	Error            string    `gorm:"type:TEXT" json:"error,omitempty"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	uuid "github.com/satori/go.uuid"
+)
+
+// TravelRuleStatus ...
+type TravelRuleStatus struct{ COMPLETE, INCOMPLETE string }
+
+var (
+	TravelRuleRecordStatus = TravelRuleStatus{
+		COMPLETE:   "COMPLETE",
+		INCOMPLETE: "INCOMPLETE",
+	}
+)
+
+// TravelRuleRecord ... Originator and beneficiary information for a transfer, the party details are stored encrypted
+type TravelRuleRecord struct {
+	BaseModel
+	TransactionID    uuid.UUID `gorm:"type:VARCHAR(36);not null;unique_index" json:"transactionId"`
+	TransactionTag   string    `gorm:"type:VARCHAR(36);not null" json:"transactionTag"`
+	AssetSymbol      string    `gorm:"type:VARCHAR(36);not null" json:"assetSymbol"`
+	Network          string    `gorm:"type:VARCHAR(150)" json:"network"`
+	Value            string    `gorm:"type:decimal(64,18);not null" json:"value"`
+	FiatCurrency     string    `gorm:"type:VARCHAR(10)" json:"fiatCurrency"`
+	FiatValue        string    `gorm:"type:decimal(64,18)" json:"fiatValue"`
+	Status           string    `gorm:"type:VARCHAR(36);not null;default:'COMPLETE'" json:"status"`
+	EncryptedPayload string    `gorm:"type:TEXT" json:"-"`
+}
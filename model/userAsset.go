@@ -13,6 +13,7 @@ type UserAsset struct {
 	UserID           uuid.UUID `gorm:"type:VARCHAR(36);not null" json:"user_id"`
 	DenominationID   uuid.UUID `gorm:"type:VARCHAR(36);not null" json:"-"`
 	AvailableBalance string    `gorm:"type:decimal(64,18) CHECK(available_balance >= 0);not null;" json:"available_balance"`
+	DustBalance      string    `gorm:"type:decimal(64,18);not null;default:0" json:"dust_balance"`
 	AssetSymbol      string    `gorm:"-" json:"asset_symbol,omitempty"`
 	DefaultNetwork         string     `gorm:"-" json:"defaultNetwork,omitempty"`
 }
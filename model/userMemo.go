@@ -1,12 +1,16 @@
 package model
 
 import (
+	"time"
+
 	uuid "github.com/satori/go.uuid"
 )
 
 // UserMemo ... User unique memo
 type UserMemo struct {
 	UserID uuid.UUID `gorm:"type:VARCHAR(36);not null" json:"user_id"`
-	Memo   string    `gorm:"type:VARCHAR(100);not null" json:"memo,omitempty"`
+	Memo   string    `gorm:"type:VARCHAR(100);not null;unique_index:uix_user_memos_memo" json:"memo,omitempty"`
+	Network string   `gorm:"type:VARCHAR(36);not null;default:''" json:"network"`
 	IsPrimaryAddress bool `json:"is_primary_address"`
+	CreatedAt time.Time `json:"created_at"`
 }
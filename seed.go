@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	Config "wallet-adapter/config"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/jinzhu/gorm"
+)
+
+const seedAssetsCommand = "seed-assets"
+
+// seedAssets runs supported asset seeding on its own and prints the seeding report :
+// service seed-assets [-mode remote|local|merge] [-dry-run]
+func seedAssets(args []string, config Config.Data, logger *utility.Logger, DB *gorm.DB, cache *utility.MemoryCache) {
+	flags := flag.NewFlagSet(seedAssetsCommand, flag.ExitOnError)
+	mode := flags.String("mode", services.GetAssetSeedingMode(config), "source of the seeded assets : remote, local or merge")
+	dryRun := flags.Bool("dry-run", false, "print the changes seeding would make without applying them")
+	if err := flags.Parse(args); err != nil {
+		log.Fatalf("Invalid %s arguments : %s", seedAssetsCommand, err)
+	}
+	config.AssetSeedingMode = *mode
+
+	assets, report, err := services.PlanAssetSeeding(DB, logger, config, cache)
+	if err != nil {
+		log.Fatalf("Supported assets could not be seeded : %s", err)
+	}
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatalf("Seeding report could not be printed : %s", err)
+	}
+	fmt.Println(string(output))
+
+	if *dryRun {
+		return
+	}
+	services.SaveSupportedAssets(DB, logger, assets)
+}
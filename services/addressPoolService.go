@@ -0,0 +1,165 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+)
+
+// GetAddressPoolLowWaterMark ... Returns the number of available pooled addresses below which a network pool is refilled, zero disables pooling
+func GetAddressPoolLowWaterMark(assetSymbol, network string) int64 {
+	return viper.GetInt64(fmt.Sprintf("ADDRESSPOOLLOWWATERMARK.%s_%s", assetSymbol, network))
+}
+
+// GetAddressPoolRefillSize ... Returns the number of addresses generated per refill, defaults to the low-water mark
+func GetAddressPoolRefillSize(assetSymbol, network string) int64 {
+	refillSize := viper.GetInt64(fmt.Sprintf("ADDRESSPOOLREFILLSIZE.%s_%s", assetSymbol, network))
+	if refillSize <= 0 {
+		return GetAddressPoolLowWaterMark(assetSymbol, network)
+	}
+	return refillSize
+}
+
+// IsPoolableNetwork ... Pooling applies to networks without memos whose addresses are derived by key-management
+func IsPoolableNetwork(network model.Network) bool {
+	if network.AddressProvider != model.AddressProvider.BUNDLE || network.RequiresMemo {
+		return false
+	}
+	return GetAddressPoolLowWaterMark(network.AssetSymbol, network.Network) > 0
+}
+
+// RefillAddressPool ... Pre-generates and subscribes addresses for a network when its available depth is below the low-water mark.
+// Each pool identity is derived once so key-management never returns a previously pooled address, multi address networks pool
+// the address of every type derived under the identity and count each of them towards the depth
+func (service BaseService) RefillAddressPool(repository database.IUserAssetRepository, network model.Network, availableDepth int64) (int64, error) {
+	lowWaterMark := GetAddressPoolLowWaterMark(network.AssetSymbol, network.Network)
+	if availableDepth >= lowWaterMark {
+		return 0, nil
+	}
+
+	var generated int64
+	refillSize := GetAddressPoolRefillSize(network.AssetSymbol, network.Network)
+	for generated < refillSize {
+		poolIdentity := uuid.NewV4()
+		addressResponse, err := service.GenerateAllAddresses(poolIdentity, network.AssetSymbol, network.CoinType, "", network.Network)
+		if err != nil {
+			return generated, err
+		}
+		if len(addressResponse) == 0 {
+			return generated, errors.New(errorcode.SYSTEM_ERR)
+		}
+		if network.IsMultiAddresses == nil || !*network.IsMultiAddresses {
+			addressResponse = addressResponse[:1]
+		}
+		for _, address := range addressResponse {
+			pooledAddress := model.PooledAddress{
+				PoolIdentity: poolIdentity,
+				Address:      address.Data,
+				AddressType:  address.Type,
+				AssetSymbol:  network.AssetSymbol,
+				Network:      network.Network,
+				CoinType:     network.CoinType,
+				Status:       model.PooledAddressStatus.AVAILABLE,
+			}
+			if err := repository.Create(&pooledAddress); err != nil {
+				service.Logger.Error("Error response from address pool refill, could not save pooled address : %s ", err)
+				return generated, errors.New(utility.GetSQLErr(err))
+			}
+			generated++
+		}
+	}
+	return generated, nil
+}
+
+// AssignPooledAddress ... Hands an available pooled address, of the address type when one is given, to a user asset,
+// returns an empty address when the pool is disabled or drained
+func AssignPooledAddress(repository database.IUserAssetRepository, logger *utility.Logger, networkAsset dto.NetworkAsset, addressType string) (model.PooledAddress, error) {
+	pooledAddress := model.PooledAddress{}
+	if GetAddressPoolLowWaterMark(networkAsset.AssetSymbol, networkAsset.Network) <= 0 {
+		return pooledAddress, nil
+	}
+	if err := repository.ClaimPooledAddress(networkAsset.AssetSymbol, networkAsset.Network, addressType, networkAsset.AssetID, &pooledAddress); err != nil {
+		if appErr, ok := err.(utility.AppError); ok && appErr.ErrType == errorcode.RECORD_NOT_FOUND {
+			logger.Info("Address pool for assetSymbol : %s, network : %s and address type : %s is drained, falling back to on-demand generation", networkAsset.AssetSymbol, networkAsset.Network, addressType)
+			return model.PooledAddress{}, nil
+		}
+		return model.PooledAddress{}, err
+	}
+	return pooledAddress, nil
+}
+
+// AssignPooledAddresses ... Hands a pooled address of each address type to a user asset, returns no address unless every type could be
+// assigned so the asset does not mix pooled and generated addresses
+func AssignPooledAddresses(repository database.IUserAssetRepository, logger *utility.Logger, networkAsset dto.NetworkAsset, addressTypes []string) ([]model.PooledAddress, error) {
+	pooledAddresses := []model.PooledAddress{}
+	for _, addressType := range addressTypes {
+		pooledAddress, err := AssignPooledAddress(repository, logger, networkAsset, addressType)
+		if err != nil || pooledAddress.Address == "" {
+			ReleasePooledAddresses(repository, logger, pooledAddresses)
+			return []model.PooledAddress{}, err
+		}
+		pooledAddresses = append(pooledAddresses, pooledAddress)
+	}
+	return pooledAddresses, nil
+}
+
+// ReleasePooledAddresses ... Returns assigned pooled addresses to the pool when the user addresses they were claimed for were not saved
+func ReleasePooledAddresses(repository database.IUserAssetRepository, logger *utility.Logger, pooledAddresses []model.PooledAddress) {
+	for _, pooledAddress := range pooledAddresses {
+		if err := repository.ReleasePooledAddress(pooledAddress.ID); err != nil {
+			logger.Error("Error response from address pool, could not return pooled address %s to the pool : %s ", pooledAddress.Address, err)
+		}
+	}
+}
+
+// GetAddressPoolDepth ... Reports available and assigned pool depth per network against the configured low-water mark
+func GetAddressPoolDepth(repository database.IUserAssetRepository) ([]dto.AddressPoolDepth, error) {
+	networks := []model.Network{}
+	if err := repository.Fetch(&networks); err != nil {
+		return nil, err
+	}
+	statusCounts := []dto.AddressPoolStatusCount{}
+	if err := repository.FetchAddressPoolDepth(&statusCounts); err != nil {
+		return nil, err
+	}
+
+	// pools configured but not yet filled are reported with zero depth
+	for _, network := range networks {
+		if IsPoolableNetwork(network) {
+			statusCounts = append(statusCounts, dto.AddressPoolStatusCount{AssetSymbol: network.AssetSymbol, Network: network.Network})
+		}
+	}
+
+	poolDepth := []dto.AddressPoolDepth{}
+	poolIndex := map[string]int{}
+	for _, statusCount := range statusCounts {
+		key := statusCount.AssetSymbol + utility.SEPERATOR + statusCount.Network
+		index, ok := poolIndex[key]
+		if !ok {
+			poolDepth = append(poolDepth, dto.AddressPoolDepth{
+				AssetSymbol:  statusCount.AssetSymbol,
+				Network:      statusCount.Network,
+				LowWaterMark: GetAddressPoolLowWaterMark(statusCount.AssetSymbol, statusCount.Network),
+			})
+			index = len(poolDepth) - 1
+			poolIndex[key] = index
+		}
+		switch statusCount.Status {
+		case model.PooledAddressStatus.AVAILABLE:
+			poolDepth[index].Available = statusCount.Count
+		case model.PooledAddressStatus.ASSIGNED:
+			poolDepth[index].Assigned = statusCount.Count
+		}
+	}
+	for index := range poolDepth {
+		poolDepth[index].BelowLowWaterMark = poolDepth[index].Available < poolDepth[index].LowWaterMark
+	}
+	return poolDepth, nil
+}
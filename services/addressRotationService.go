@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+)
+
+// GetRetiredAddressPolicy ... Returns how deposits to rotated addresses of an asset on a network are handled, defaults to crediting them
+func GetRetiredAddressPolicy(assetSymbol, network string) string {
+	if strings.EqualFold(viper.GetString(fmt.Sprintf("RETIREDADDRESSPOLICY.%s_%s", assetSymbol, network)), model.RetiredAddressPolicy.HOLD) {
+		return model.RetiredAddressPolicy.HOLD
+	}
+	return model.RetiredAddressPolicy.CREDIT
+}
+
+// IsRotatableNetwork ... Memo networks share one address across users and Binance addresses are fixed per user, neither can be rotated
+func IsRotatableNetwork(network model.Network) bool {
+	return !network.RequiresMemo && network.AddressProvider != model.AddressProvider.BINANCE
+}
+
+// IsRotationDueAfterDeposit ... Checks if a primary address reaches the network deposit limit with the deposit being credited
+func IsRotationDueAfterDeposit(userAddress model.UserAddress, network model.Network) bool {
+	if !IsRotatableNetwork(network) || network.RotateAfterDeposits <= 0 {
+		return false
+	}
+	return userAddress.IsPrimaryAddress && userAddress.IsValid && int64(userAddress.DepositCount+1) >= network.RotateAfterDeposits
+}
+
+// CheckDepositAddress ... Applies the crediting rules for deposits to addresses that are no longer primary.
+// Deposits to invalidated addresses are always held, deposits to rotated addresses follow the network retired address policy
+func CheckDepositAddress(userAddress model.UserAddress, network model.Network) dto.ScreeningResult {
+	if !userAddress.IsValid {
+		return dto.ScreeningResult{Hit: true, Provider: utility.ADDRESS_HOLD_INVALIDATED,
+			Reason: fmt.Sprintf("deposit to invalidated address %s : %s", userAddress.Address, userAddress.InvalidationReason)}
+	}
+	if userAddress.RetiredAt != nil && network.RetiredAddressPolicy == model.RetiredAddressPolicy.HOLD {
+		return dto.ScreeningResult{Hit: true, Provider: utility.ADDRESS_HOLD_RETIRED,
+			Reason: fmt.Sprintf("deposit to address %s retired on %s", userAddress.Address, userAddress.RetiredAt.Format(time.RFC3339))}
+	}
+	return dto.ScreeningResult{}
+}
+
+// GetKeyIdentity ... Returns the identity key-management derives a new address of an asset on a network under, and the addresses already issued to it.
+// Key-management returns the same address for the same identity, so only the first address of an asset is derived under the user,
+// addresses replacing rotated or invalidated ones are derived under a fresh identity that is stored with the address
+func GetKeyIdentity(repository database.IUserAssetRepository, networkAsset dto.NetworkAsset) (uuid.UUID, map[string]bool, error) {
+	var issuedAddresses []model.UserAddress
+	if err := repository.FetchByFieldName(&model.UserAddress{AssetID: networkAsset.AssetID, Network: networkAsset.Network}, &issuedAddresses); err != nil {
+		return uuid.Nil, nil, err
+	}
+	issued := make(map[string]bool, len(issuedAddresses))
+	for _, issuedAddress := range issuedAddresses {
+		if issuedAddress.Address != "" {
+			issued[issuedAddress.Address] = true
+		}
+	}
+	if len(issued) == 0 {
+		return networkAsset.UserID, issued, nil
+	}
+	return uuid.NewV4(), issued, nil
+}
+
+// CheckAddressesNotIssued ... Rejects derived addresses that were already issued to the asset, a retired or invalidated address must not come back
+func CheckAddressesNotIssued(issued map[string]bool, addresses ...string) error {
+	for _, address := range addresses {
+		if issued[address] {
+			return errors.New(errorcode.ADDRESS_ALREADY_ISSUED)
+		}
+	}
+	return nil
+}
+
+// CancelAddressSubscription ... Cancels the crypto-adapter subscription of an invalidated address and records it cancelled,
+// a failed cancellation leaves the subscription in place and deposits to the address keep being held
+func (service BaseService) CancelAddressSubscription(repository database.IUserAssetRepository, address string) error {
+	var userAddresses []model.UserAddress
+	if err := repository.FetchByFieldName(&model.UserAddress{Address: address}, &userAddresses); err != nil {
+		return err
+	}
+	requestData := dto.SubscriptionRequestV2{Subscriptions: make(map[string][]string)}
+	addressIDs := make([]uuid.UUID, 0, len(userAddresses))
+	for _, userAddress := range userAddresses {
+		userAsset := model.UserAsset{}
+		if err := repository.GetAssetsByID(&model.UserAsset{BaseModel: model.BaseModel{ID: userAddress.AssetID}}, &userAsset); err != nil {
+			return err
+		}
+		network, err := GetNetworkByAssetAndNetwork(repository, userAddress.Network, userAsset.AssetSymbol)
+		if err != nil {
+			return err
+		}
+		coinType := strconv.FormatInt(network.CoinType, 10)
+		if len(requestData.Subscriptions[coinType]) == 0 {
+			requestData.Subscriptions[coinType] = []string{address}
+		}
+		addressIDs = append(addressIDs, userAddress.ID)
+	}
+	if len(addressIDs) == 0 {
+		return nil
+	}
+	if err := UnsubscribeAddressV2(service.Cache, service.Logger, service.Config, requestData, &dto.SubscriptionResponse{}, &dto.ServicesRequestErr{}); err != nil {
+		return err
+	}
+	return repository.UpdateAddressSubscriptionStatus(addressIDs, model.SubscriptionStatus.CANCELLED, nil)
+}
+
+// RotateExpiredAddresses ... Retires primary addresses of a network that have been issued for longer than the network rotation window
+func RotateExpiredAddresses(repository database.IUserAssetRepository, network model.Network) (int64, error) {
+	if !IsRotatableNetwork(network) || network.RotationWindowHours <= 0 {
+		return 0, nil
+	}
+	var userAddresses []model.UserAddress
+	issuedBefore := time.Now().Add(-time.Duration(network.RotationWindowHours) * time.Hour)
+	if err := repository.FetchAddressesDueForRotation(network.AssetSymbol, network.Network, issuedBefore, &userAddresses); err != nil {
+		return 0, err
+	}
+	addressIDs := make([]uuid.UUID, 0, len(userAddresses))
+	for _, userAddress := range userAddresses {
+		addressIDs = append(addressIDs, userAddress.ID)
+	}
+	return repository.RetireUserAddresses(addressIDs)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"strconv"
+	"time"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+const defaultSubscriptionBatchSize = 100
+
+// subscribeUserAddresses subscribes newly issued user addresses. A failed subscription does not fail address issuance,
+// the addresses are stored as FAILED and re-subscribed by the subscription reconciler
+func (service BaseService) subscribeUserAddresses(addressArray []string, coinType int64) (string, *time.Time) {
+	if err := service.subscribeAddress(dto.ServicesRequestErr{}, addressArray, coinType); err != nil {
+		service.Logger.Error("%s : addresses %+v are left for the subscription reconciler : %s", utility.COULD_NOT_SUBSCRIBE_ADDRESS, addressArray, err)
+		return model.SubscriptionStatus.FAILED, nil
+	}
+	subscribedAt := time.Now()
+	return model.SubscriptionStatus.ACTIVE, &subscribedAt
+}
+
+// GetSubscriptionBatchSize ... Returns the number of addresses sent per reconciler subscription request
+func (service BaseService) GetSubscriptionBatchSize() int {
+	if service.Config.SubscriptionBatchSize <= 0 {
+		return defaultSubscriptionBatchSize
+	}
+	return service.Config.SubscriptionBatchSize
+}
+
+// ReconcileAddressSubscriptions ... Re-subscribes user addresses that are pending or failed subscription in bulk,
+// each batch is recorded ACTIVE or FAILED according to the crypto-adapter response
+func (service BaseService) ReconcileAddressSubscriptions(repository database.IUserAssetRepository) (dto.SubscriptionReconciliation, error) {
+	reconciliation := dto.SubscriptionReconciliation{}
+	var unwatchedAddresses []dto.UnwatchedAddress
+	if err := repository.FetchUnwatchedAddresses("", &unwatchedAddresses); err != nil {
+		return reconciliation, err
+	}
+
+	batchSize := service.GetSubscriptionBatchSize()
+	for start := 0; start < len(unwatchedAddresses); start += batchSize {
+		end := start + batchSize
+		if end > len(unwatchedAddresses) {
+			end = len(unwatchedAddresses)
+		}
+		batch := unwatchedAddresses[start:end]
+
+		requestData := dto.SubscriptionRequestV2{Subscriptions: make(map[string][]string)}
+		addressIDs := make([]uuid.UUID, 0, len(batch))
+		for _, unwatchedAddress := range batch {
+			coinType := strconv.FormatInt(unwatchedAddress.CoinType, 10)
+			requestData.Subscriptions[coinType] = append(requestData.Subscriptions[coinType], unwatchedAddress.Address)
+			addressIDs = append(addressIDs, unwatchedAddress.ID)
+		}
+		reconciliation.Attempted += len(batch)
+
+		subscriptionStatus := model.SubscriptionStatus.ACTIVE
+		subscribedAt := time.Now()
+		subscribedAtRef := &subscribedAt
+		if err := SubscribeAddressV2(service.Cache, service.Logger, service.Config, requestData, &dto.SubscriptionResponse{}, &dto.ServicesRequestErr{}); err != nil {
+			service.Logger.Error("Subscription reconciler could not subscribe %d addresses : %s", len(batch), err)
+			subscriptionStatus = model.SubscriptionStatus.FAILED
+			subscribedAtRef = nil
+			reconciliation.Failed += len(batch)
+		} else {
+			reconciliation.Subscribed += len(batch)
+		}
+
+		if err := repository.UpdateAddressSubscriptionStatus(addressIDs, subscriptionStatus, subscribedAtRef); err != nil {
+			return reconciliation, err
+		}
+	}
+	return reconciliation, nil
+}
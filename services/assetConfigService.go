@@ -0,0 +1,77 @@
+package services
+
+import (
+	"encoding/json"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+)
+
+// GetConfigFields ... Returns the fields set on an admin config request keyed by their json name
+func GetConfigFields(config interface{}) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return fields, err
+	}
+	err = json.Unmarshal(encoded, &fields)
+	return fields, err
+}
+
+// ApplyConfigOverrides ... Sets the fields overridden through the admin API on a seeded network, or on the denomination when network is empty
+func ApplyConfigOverrides(record interface{}, assetSymbol, network string, overrides []model.AssetConfigOverride) error {
+	fields := map[string]json.RawMessage{}
+	for _, override := range overrides {
+		if override.AssetSymbol == assetSymbol && override.Network == network {
+			fields[override.Field] = json.RawMessage(override.Value)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, record)
+}
+
+// ChangeAssetConfig ... Sets the given fields on a denomination or network, the fields are kept as overrides so re-seeding does not revert them
+// and the previous values are recorded in the change history
+func ChangeAssetConfig(repository database.IUserAssetRepository, record interface{}, fields map[string]json.RawMessage, change model.AssetConfigChange) (model.AssetConfigChange, error) {
+	current, err := GetConfigFields(record)
+	if err != nil {
+		return change, err
+	}
+
+	fieldChanges := map[string]dto.AssetConfigFieldChange{}
+	overrides := make([]model.AssetConfigOverride, 0, len(fields))
+	for field, value := range fields {
+		overrides = append(overrides, model.AssetConfigOverride{AssetSymbol: change.AssetSymbol, Network: change.Network, Field: field, Value: string(value), UpdatedBy: change.ChangedBy})
+		previous, ok := current[field]
+		if !ok {
+			previous = json.RawMessage("null")
+		}
+		if string(previous) != string(value) {
+			fieldChanges[field] = dto.AssetConfigFieldChange{From: previous, To: value}
+		}
+	}
+
+	encodedFields, err := json.Marshal(fields)
+	if err != nil {
+		return change, err
+	}
+	if err := json.Unmarshal(encodedFields, record); err != nil {
+		return change, err
+	}
+	encodedChanges, err := json.Marshal(fieldChanges)
+	if err != nil {
+		return change, err
+	}
+	change.Changes = string(encodedChanges)
+
+	if err := repository.SaveAssetConfig(record, overrides, &change); err != nil {
+		return change, err
+	}
+	return change, nil
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	Config "wallet-adapter/config"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+
+	"github.com/jinzhu/gorm"
+)
+
+const defaultAssetCataloguePath = "config/asset-catalogue.json"
+
+// SeedingMode ...
+type SeedingMode struct{ REMOTE, LOCAL, MERGE string }
+
+var (
+	AssetSeedingMode = SeedingMode{
+		REMOTE: "remote",
+		LOCAL:  "local",
+		MERGE:  "merge",
+	}
+)
+
+// GetAssetSeedingMode ... Returns the configured source of seeded assets, defaults to the remote sources merged with the local catalogue
+func GetAssetSeedingMode(config Config.Data) string {
+	mode := strings.ToLower(config.AssetSeedingMode)
+	if mode == AssetSeedingMode.REMOTE || mode == AssetSeedingMode.LOCAL {
+		return mode
+	}
+	return AssetSeedingMode.MERGE
+}
+
+// LoadAssetCatalogue ... Reads the local asset catalogue
+func LoadAssetCatalogue(config Config.Data) (dto.AssetCatalogue, error) {
+	catalogue := dto.AssetCatalogue{}
+	cataloguePath := config.AssetCataloguePath
+	if cataloguePath == "" {
+		cataloguePath = defaultAssetCataloguePath
+	}
+	content, err := ioutil.ReadFile(cataloguePath)
+	if err != nil {
+		return catalogue, err
+	}
+	err = json.Unmarshal(content, &catalogue)
+	return catalogue, err
+}
+
+// LoadSupportedAssets ... Loads the assets to seed for the configured seeding mode. Remote and merge modes fall back to the local catalogue
+// when the rate service or TrustWallet cannot be reached, the report records which source the assets came from
+func LoadSupportedAssets(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data) ([]model.Denomination, dto.AssetSeedReport, error) {
+	mode := GetAssetSeedingMode(config)
+	report := dto.AssetSeedReport{Mode: mode, Source: mode}
+
+	if mode == AssetSeedingMode.LOCAL {
+		catalogue, err := LoadAssetCatalogue(config)
+		if err != nil {
+			return nil, report, err
+		}
+		report.CatalogueVersion = catalogue.Version
+		return normalizeAsset(catalogue.Denominations, catalogue.TWDenominations), report, nil
+	}
+
+	denominationService := NewService(cache, logger, config)
+	assetDenominations, remoteErr := denominationService.GetAssetDenominations()
+	TWDenominations := []dto.TWDenomination{}
+	if remoteErr == nil {
+		TWDenominations, remoteErr = denominationService.GetTWDenominations()
+	}
+	if remoteErr == nil && mode == AssetSeedingMode.REMOTE {
+		return normalizeAsset(assetDenominations.Denominations, TWDenominations), report, nil
+	}
+
+	catalogue, catalogueErr := LoadAssetCatalogue(config)
+	if remoteErr != nil {
+		if catalogueErr != nil {
+			return nil, report, fmt.Errorf("remote sources : %s, local catalogue : %s", remoteErr, catalogueErr)
+		}
+		logger.Error("Supported assets could not be fetched, seeding from local catalogue version %d, err : %s", catalogue.Version, remoteErr)
+		report.Source = AssetSeedingMode.LOCAL
+		report.CatalogueVersion = catalogue.Version
+		return normalizeAsset(catalogue.Denominations, catalogue.TWDenominations), report, nil
+	}
+	if catalogueErr != nil {
+		logger.Error("Local asset catalogue could not be loaded, seeding from remote sources only, err : %s", catalogueErr)
+		report.Source = AssetSeedingMode.REMOTE
+		return normalizeAsset(assetDenominations.Denominations, TWDenominations), report, nil
+	}
+
+	// remote entries take precedence, the catalogue adds the assets and coins the remote sources do not list
+	report.CatalogueVersion = catalogue.Version
+	denominations := assetDenominations.Denominations
+	for _, denomination := range catalogue.Denominations {
+		if !containsDenomination(denominations, denomination.Symbol) {
+			denominations = append(denominations, denomination)
+		}
+	}
+	for _, coin := range catalogue.TWDenominations {
+		if getMainCoinAssetSymbol(coin.CoinId, TWDenominations) == "" {
+			TWDenominations = append(TWDenominations, coin)
+		}
+	}
+	return normalizeAsset(denominations, TWDenominations), report, nil
+}
+
+// PlanAssetSeeding ... Loads the assets to seed and reports the changes seeding them would make, without applying them
+func PlanAssetSeeding(DB *gorm.DB, logger *utility.Logger, config Config.Data, cache *utility.MemoryCache) ([]model.Denomination, dto.AssetSeedReport, error) {
+	assets, report, err := LoadSupportedAssets(cache, logger, config)
+	if err != nil {
+		return assets, report, err
+	}
+	report.Changes, err = DiffSupportedAssets(DB, assets)
+	return assets, report, err
+}
+
+// DiffSupportedAssets ... Compares the assets to seed, with the admin overrides applied, against the stored denominations and networks
+func DiffSupportedAssets(DB *gorm.DB, assets []model.Denomination) ([]dto.AssetSeedChange, error) {
+	changes := []dto.AssetSeedChange{}
+	for _, asset := range assets {
+		denomination, networks, err := resolveSeededAsset(DB, asset)
+		if err != nil {
+			return changes, err
+		}
+
+		existing := model.Denomination{}
+		if err := DB.Where("asset_symbol = ?", asset.AssetSymbol).First(&existing).Error; err != nil {
+			if !gorm.IsRecordNotFoundError(err) {
+				return changes, err
+			}
+			changes = append(changes, dto.AssetSeedChange{AssetSymbol: asset.AssetSymbol, Action: model.AssetConfigAction.CREATE})
+		} else if fieldChanges := diffSeededFields(existing, denomination); len(fieldChanges) > 0 {
+			changes = append(changes, dto.AssetSeedChange{AssetSymbol: asset.AssetSymbol, Action: model.AssetConfigAction.UPDATE, Changes: fieldChanges})
+		}
+
+		var existingNetworks []model.Network
+		if err := DB.Where("asset_symbol = ?", asset.AssetSymbol).Find(&existingNetworks).Error; err != nil {
+			return changes, err
+		}
+		storedNetworks, staleNetworks := splitStoredNetworks(existingNetworks, networks)
+		for _, network := range networks {
+			storedNetwork, ok := storedNetworks[network.Network]
+			if !ok {
+				changes = append(changes, dto.AssetSeedChange{AssetSymbol: asset.AssetSymbol, Network: network.Network, Action: model.AssetConfigAction.CREATE})
+				continue
+			}
+			if fieldChanges := diffSeededFields(storedNetwork, network); len(fieldChanges) > 0 {
+				changes = append(changes, dto.AssetSeedChange{AssetSymbol: asset.AssetSymbol, Network: network.Network, Action: model.AssetConfigAction.UPDATE, Changes: fieldChanges})
+			}
+		}
+		for _, staleNetwork := range staleNetworks {
+			changes = append(changes, dto.AssetSeedChange{AssetSymbol: asset.AssetSymbol, Network: staleNetwork.Network, Action: model.AssetConfigAction.DELETE})
+		}
+	}
+	return changes, nil
+}
+
+// resolveSeededAsset applies the admin overrides to a seeded denomination and its networks, and adds the networks created through the admin API
+func resolveSeededAsset(DB *gorm.DB, asset model.Denomination) (model.Denomination, []model.Network, error) {
+	var overrides []model.AssetConfigOverride
+	if err := DB.Where("asset_symbol = ?", asset.AssetSymbol).Find(&overrides).Error; err != nil {
+		return asset, nil, err
+	}
+
+	networks := asset.Networks
+	asset.Networks = nil
+	if err := ApplyConfigOverrides(&asset, asset.AssetSymbol, "", overrides); err != nil {
+		return asset, nil, err
+	}
+
+	seeded := map[string]bool{}
+	for _, network := range networks {
+		seeded[network.Network] = true
+	}
+	for _, override := range overrides {
+		if override.Network != "" && override.Field == "network" && !seeded[override.Network] {
+			seeded[override.Network] = true
+			networks = append(networks, model.Network{AssetSymbol: asset.AssetSymbol, Network: override.Network})
+		}
+	}
+
+	resolvedNetworks := make([]model.Network, 0, len(networks))
+	for _, network := range networks {
+		if err := ApplyConfigOverrides(&network, asset.AssetSymbol, network.Network, overrides); err != nil {
+			return asset, nil, err
+		}
+		// unset flags are stored as false, the column default
+		if network.IsBatchable == nil {
+			network.IsBatchable = new(bool)
+		}
+		if network.IsMultiAddresses == nil {
+			network.IsMultiAddresses = new(bool)
+		}
+		if network.IsToken == nil {
+			network.IsToken = new(bool)
+		}
+		resolvedNetworks = append(resolvedNetworks, network)
+	}
+	return asset, resolvedNetworks, nil
+}
+
+// splitStoredNetworks keys the stored networks of an asset by network, duplicate rows and networks the source no longer lists are returned as stale
+func splitStoredNetworks(existingNetworks, networks []model.Network) (map[string]model.Network, []model.Network) {
+	seeded := map[string]bool{}
+	for _, network := range networks {
+		seeded[network.Network] = true
+	}
+	storedNetworks := map[string]model.Network{}
+	staleNetworks := []model.Network{}
+	for _, existingNetwork := range existingNetworks {
+		if _, ok := storedNetworks[existingNetwork.Network]; ok || !seeded[existingNetwork.Network] {
+			staleNetworks = append(staleNetworks, existingNetwork)
+			continue
+		}
+		storedNetworks[existingNetwork.Network] = existingNetwork
+	}
+	return storedNetworks, staleNetworks
+}
+
+// diffSeededFields returns the fields that differ between a stored record and its seeded value, record keys and timestamps are ignored
+func diffSeededFields(stored, seeded interface{}) map[string]dto.AssetConfigFieldChange {
+	fieldChanges := map[string]dto.AssetConfigFieldChange{}
+	storedFields, err := GetConfigFields(stored)
+	if err != nil {
+		return fieldChanges
+	}
+	seededFields, err := GetConfigFields(seeded)
+	if err != nil {
+		return fieldChanges
+	}
+	for field := range seededFields {
+		if _, ok := storedFields[field]; !ok {
+			storedFields[field] = json.RawMessage("null")
+		}
+	}
+	for field, storedValue := range storedFields {
+		if field == "id" || field == "created_at" || field == "updated_at" || field == "networks" {
+			continue
+		}
+		seededValue, ok := seededFields[field]
+		if !ok {
+			seededValue = json.RawMessage("null")
+		}
+		if string(storedValue) != string(seededValue) {
+			fieldChanges[field] = dto.AssetConfigFieldChange{From: storedValue, To: seededValue}
+		}
+	}
+	return fieldChanges
+}
+
+func containsDenomination(denominations []dto.AssetDenomination, symbol string) bool {
+	for _, denomination := range denominations {
+		if denomination.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
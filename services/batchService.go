@@ -1,18 +1,27 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"time"
 	"wallet-adapter/database"
+	"wallet-adapter/dto"
 	"wallet-adapter/errorcode"
 	"wallet-adapter/model"
+	"wallet-adapter/utility"
 
+	"github.com/jinzhu/gorm"
 	uuid "github.com/satori/go.uuid"
+	"github.com/shopspring/decimal"
 )
 
 type BatchService struct {
 	BaseService
 }
 
-func (service BatchService) GetWaitingBatchId(repository database.IBatchRepository, assetSymbol, network string) (uuid.UUID, error) {
+// GetWaitingBatchId ... Returns the batch waiting for withdrawals of an asset on a network, opening one if there is none. A waiting
+// batch the withdrawal value would take past the maximum recipients or total value of its batch policy is closed and a new one opened
+func (service BatchService) GetWaitingBatchId(repository database.IBatchRepository, assetSymbol, network string, value decimal.Decimal) (uuid.UUID, error) {
 
 	var currentBatch model.BatchRequest
 	if err := repository.GetByFieldName(&model.BatchRequest{Status: model.BatchStatus.WAIT_MODE, AssetSymbol: assetSymbol, Network: network}, &currentBatch); err != nil {
@@ -20,18 +29,287 @@ func (service BatchService) GetWaitingBatchId(repository database.IBatchReposito
 			service.Logger.Error("Error response from batch service : ", err)
 			return uuid.UUID{}, err
 		}
-		// Create new batch entry
-		currentBatch.AssetSymbol = assetSymbol
-		currentBatch.Network = network
-		if err := repository.Create(&currentBatch); err != nil {
-			service.Logger.Error("Error response from batch service : ", err)
-			return uuid.UUID{}, err
+		return service.openBatch(repository, assetSymbol, network)
+	}
+
+	batchPolicy, err := service.GetBatchPolicy(repository, assetSymbol, network)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	recipients, totalValue, err := service.GetBatchTotals(repository, currentBatch.ID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	closeReason := ""
+	if recipients > 0 && batchPolicy.MaxRecipients > 0 && int64(recipients) >= batchPolicy.MaxRecipients {
+		closeReason = model.BatchCloseReason.MAX_RECIPIENTS
+	} else if recipients > 0 && batchPolicy.MaxTotalValue.IsPositive() && totalValue.Add(value).GreaterThan(batchPolicy.MaxTotalValue) {
+		closeReason = model.BatchCloseReason.MAX_VALUE
+	}
+	if closeReason == "" {
+		return currentBatch.ID, nil
+	}
+	// a batch the processing run closed since it was read is left as it is, a new one opens in its place
+	if err := service.CloseBatch(repository, &currentBatch, closeReason, ""); err != nil && err.Error() != errorcode.BATCH_NOT_WAITING {
+		return uuid.UUID{}, err
+	}
+	return service.openBatch(repository, assetSymbol, network)
+}
+
+// JoinWaitingBatch ... Returns the waiting batch a withdrawal joins and a release func to call once the withdrawal is queued in it.
+// Withdrawals of an asset on a network join one at a time under its batching lock, so they keep to the batch policy and open a single
+// waiting batch between them. The batch lock the processing run and batcher hold to close a batch is also taken, so the batch cannot
+// close and be sent before the withdrawal is queued in it
+func (service BatchService) JoinWaitingBatch(repository database.IBatchRepository, assetSymbol, network string, value decimal.Decimal) (uuid.UUID, func(), error) {
+	batchingLockToken, err := service.acquireLock(BatchingLockID(assetSymbol, network), utility.BATCHING_LOCK_WAIT_MILLISECONDS)
+	if err != nil {
+		return uuid.UUID{}, nil, err
+	}
+	releaseBatching := func() { service.releaseLock(BatchingLockID(assetSymbol, network), batchingLockToken) }
+
+	// a batch closed by the processing run between reading it and taking its lock is passed over, the next read opens a new one
+	for attempt := 0; attempt < 3; attempt++ {
+		batchID, err := service.GetWaitingBatchId(repository, assetSymbol, network, value)
+		if err != nil {
+			releaseBatching()
+			return uuid.UUID{}, nil, err
+		}
+		batchLockToken, err := service.acquireLock(batchID.String(), utility.BATCHING_LOCK_WAIT_MILLISECONDS)
+		if err != nil {
+			releaseBatching()
+			return uuid.UUID{}, nil, err
+		}
+		batch := model.BatchRequest{}
+		if err := repository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: batchID}}, &batch); err != nil {
+			service.releaseLock(batchID.String(), batchLockToken)
+			releaseBatching()
+			return uuid.UUID{}, nil, err
 		}
+		if batch.Status == model.BatchStatus.WAIT_MODE {
+			return batchID, func() {
+				service.releaseLock(batchID.String(), batchLockToken)
+				releaseBatching()
+			}, nil
+		}
+		service.releaseLock(batchID.String(), batchLockToken)
+	}
+	releaseBatching()
+	return uuid.UUID{}, nil, errors.New(errorcode.BATCH_NOT_JOINABLE)
+}
+
+// OpenWaitingBatch ... Opens a waiting batch for an asset on a network if it has none, under the batching lock withdrawals join
+// batches with
+func (service BatchService) OpenWaitingBatch(repository database.IBatchRepository, assetSymbol, network string) error {
+	batchingLockToken, err := service.acquireLock(BatchingLockID(assetSymbol, network), utility.BATCHING_LOCK_WAIT_MILLISECONDS)
+	if err != nil {
+		return err
 	}
+	defer service.releaseLock(BatchingLockID(assetSymbol, network), batchingLockToken)
+	_, err = service.GetWaitingBatchId(repository, assetSymbol, network, decimal.Zero)
+	return err
+}
+
+// BatchingLockID ... Returns the identifier of the lock withdrawals of an asset on a network join its waiting batch under
+func BatchingLockID(assetSymbol, network string) string {
+	return fmt.Sprintf("batching%s%s%s%s", utility.SEPERATOR, assetSymbol, utility.SEPERATOR, network)
+}
+
+func (service BatchService) acquireLock(identifier string, timeout int64) (string, error) {
+	lockerServiceRequest := dto.LockerServiceRequest{
+		Identifier:   fmt.Sprintf("%s%s", service.Config.LockerPrefix, identifier),
+		ExpiresAfter: utility.SIX_HUNDRED_MILLISECONDS,
+		Timeout:      timeout,
+	}
+	lockerServiceResponse := dto.LockerServiceResponse{}
+	if err := AcquireLock(service.Cache, service.Logger, service.Config, lockerServiceRequest, &lockerServiceResponse, &dto.ServicesRequestErr{}); err != nil {
+		service.Logger.Error("Error response from batch service : %+v while taking lock %s", err, identifier)
+		return "", err
+	}
+	return lockerServiceResponse.Token, nil
+}
 
+func (service BatchService) releaseLock(identifier, lockerServiceToken string) {
+	lockReleaseRequest := dto.LockReleaseRequest{
+		Identifier: fmt.Sprintf("%s%s", service.Config.LockerPrefix, identifier),
+		Token:      lockerServiceToken,
+	}
+	if err := ReleaseLock(service.Cache, service.Logger, service.Config, lockReleaseRequest, &dto.ServicesRequestSuccess{}, &dto.ServicesRequestErr{}); err != nil {
+		service.Logger.Error("Error response from batch service : %+v while releasing lock %s", err, identifier)
+	}
+}
+
+func (service BatchService) openBatch(repository database.IBatchRepository, assetSymbol, network string) (uuid.UUID, error) {
+	currentBatch := model.BatchRequest{AssetSymbol: assetSymbol, Network: network}
+	if err := repository.Create(&currentBatch); err != nil {
+		service.Logger.Error("Error response from batch service : ", err)
+		return uuid.UUID{}, err
+	}
 	return currentBatch.ID, nil
 }
 
+// GetBatchPolicy ... Returns the batch policy of an asset on a network, assets without one get a policy without limits
+func (service BatchService) GetBatchPolicy(repository database.IBatchRepository, assetSymbol, network string) (model.BatchPolicy, error) {
+	batchPolicy := model.BatchPolicy{}
+	if err := repository.GetByFieldName(&model.BatchPolicy{AssetSymbol: assetSymbol, Network: network}, &batchPolicy); err != nil && err.Error() != errorcode.SQL_404 {
+		service.Logger.Error("Error response from batch service : %+v while getting the batch policy of %s on %s", err, assetSymbol, network)
+		return model.BatchPolicy{}, err
+	}
+	return batchPolicy, nil
+}
+
+// GetBatchTotals ... Returns the number of pending withdrawals in a waiting batch and their total value in asset units. Withdrawals
+// in any other status, such as those held for screening, are not sent with the batch and do not count toward its limits
+func (service BatchService) GetBatchTotals(repository database.IBatchRepository, batchID uuid.UUID) (int, decimal.Decimal, error) {
+	transactions := []model.Transaction{}
+	if err := repository.FetchByFieldName(&model.Transaction{BatchID: batchID, TransactionStatus: model.TransactionStatus.PENDING}, &transactions); err != nil && err.Error() != errorcode.SQL_404 {
+		return 0, decimal.Zero, err
+	}
+	totalValue := decimal.Zero
+	for _, transaction := range transactions {
+		value, err := decimal.NewFromString(transaction.Value)
+		if err != nil {
+			return 0, decimal.Zero, err
+		}
+		totalValue = totalValue.Add(value)
+	}
+	return len(transactions), totalValue, nil
+}
+
+// GetBatchCloseReason ... Returns why a waiting batch should close, or an empty reason while it stays open. Full batches close at once,
+// a batch closes when it reaches its maximum age, and a scheduled processing run closes a batch past its minimum age. Empty batches stay open
+func GetBatchCloseReason(batchPolicy model.BatchPolicy, openedAt time.Time, recipients int, totalValue decimal.Decimal, now time.Time, isScheduledRun bool) string {
+	if recipients == 0 {
+		return ""
+	}
+	if batchPolicy.MaxRecipients > 0 && int64(recipients) >= batchPolicy.MaxRecipients {
+		return model.BatchCloseReason.MAX_RECIPIENTS
+	}
+	if batchPolicy.MaxTotalValue.IsPositive() && totalValue.GreaterThanOrEqual(batchPolicy.MaxTotalValue) {
+		return model.BatchCloseReason.MAX_VALUE
+	}
+	age := now.Sub(openedAt)
+	if batchPolicy.MaxAgeMinutes > 0 && age >= time.Duration(batchPolicy.MaxAgeMinutes)*time.Minute {
+		return model.BatchCloseReason.MAX_AGE
+	}
+	if isScheduledRun && age >= time.Duration(batchPolicy.MinAgeMinutes)*time.Minute {
+		return model.BatchCloseReason.SCHEDULED
+	}
+	return ""
+}
+
+// ReviewWaitingBatch ... Returns why a waiting batch should close under the batch policy of its asset, or an empty reason while it stays open
+func (service BatchService) ReviewWaitingBatch(repository database.IBatchRepository, batch model.BatchRequest, now time.Time, isScheduledRun bool) (string, error) {
+	batchPolicy, err := service.GetBatchPolicy(repository, batch.AssetSymbol, batch.Network)
+	if err != nil {
+		return "", err
+	}
+	recipients, totalValue, err := service.GetBatchTotals(repository, batch.ID)
+	if err != nil {
+		return "", err
+	}
+	return GetBatchCloseReason(batchPolicy, batch.CreatedAt, recipients, totalValue, now, isScheduledRun), nil
+}
+
+// CloseBatch ... Stops a waiting batch from taking withdrawals and sets it to be processed, recording why it closed. closedBy is set when
+// an admin closed the batch ahead of its batch policy. A batch no longer waiting is left as it is and BATCH_NOT_WAITING returned
+func (service BatchService) CloseBatch(repository database.IBatchRepository, batch *model.BatchRequest, closeReason, closedBy string) error {
+	closedAt := time.Now()
+	update := map[string]interface{}{"status": model.BatchStatus.START_MODE, "closed_at": closedAt, "close_reason": closeReason}
+	if closedBy != "" {
+		update["updated_by"] = closedBy
+	}
+	claim := repository.Db().Model(&model.BatchRequest{}).Where("id = ? AND status = ?", batch.ID, model.BatchStatus.WAIT_MODE).Updates(update)
+	if err := claim.Error; err != nil {
+		service.Logger.Error("Error response from batch service : %+v while closing batch %s", err, batch.ID)
+		return err
+	}
+	if claim.RowsAffected != 1 {
+		service.Logger.Info("Batch %s of %s on %s was not closed, it is no longer waiting for withdrawals", batch.ID, batch.AssetSymbol, batch.Network)
+		return errors.New(errorcode.BATCH_NOT_WAITING)
+	}
+	batch.Status, batch.ClosedAt, batch.CloseReason = model.BatchStatus.START_MODE, &closedAt, closeReason
+	if closedBy != "" {
+		batch.UpdatedBy = closedBy
+	}
+	service.Logger.Info("Batch %s of %s on %s closed : %s", batch.ID, batch.AssetSymbol, batch.Network, closeReason)
+	return nil
+}
+
+// UnbatchTransactions ... Moves the withdrawals of a batch that was never sent back to single processing, keeping their status, so
+// pending ones go out with the next transaction processing run and those waiting for float resume on their own. It returns the moved
+// transaction ids and records who moved them on the batch
+func (service BatchService) UnbatchTransactions(repository database.IBatchRepository, batch model.BatchRequest, requestedBy string) ([]uuid.UUID, error) {
+	queuedTransactions := []model.TransactionQueue{}
+	if err := repository.FetchByFieldName(&model.TransactionQueue{BatchID: batch.ID}, &queuedTransactions); err != nil {
+		return nil, err
+	}
+	transactionIds := []uuid.UUID{}
+	for _, queuedTransaction := range queuedTransactions {
+		transactionIds = append(transactionIds, queuedTransaction.TransactionId)
+	}
+
+	if err := repository.Db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.TransactionQueue{}).Where("batch_id = ?", batch.ID).Updates(map[string]interface{}{"batch_id": uuid.Nil}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Transaction{}).Where("id IN (?)", transactionIds).Updates(map[string]interface{}{"batch_id": uuid.Nil}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.BatchRequest{}).Where("id = ?", batch.ID).Updates(map[string]interface{}{"updated_by": requestedBy}).Error
+	}); err != nil {
+		service.Logger.Error("Error response from batch service : %+v while moving the transactions out of batch %s", err, batch.ID)
+		return nil, err
+	}
+	service.Logger.Info("Transactions %+v moved out of batch %s of %s on %s by %s", transactionIds, batch.ID, batch.AssetSymbol, batch.Network, requestedBy)
+	return transactionIds, nil
+}
+
+// RequeueBatch ... Puts the terminated withdrawals of a terminated batch back in the queue as pending under a new batch, closed and ready
+// to be processed. Withdrawals in any other status, such as those held for screening, stay where they are. The batch id is the reference
+// the batch was sent with, so the withdrawals cannot be sent again under the terminated batch
+func (service BatchService) RequeueBatch(repository database.IBatchRepository, batch model.BatchRequest, requestedBy string) (model.BatchRequest, error) {
+	queuedTransactions := []model.TransactionQueue{}
+	if err := repository.FetchByFieldName(&model.TransactionQueue{BatchID: batch.ID, TransactionStatus: model.TransactionStatus.TERMINATED}, &queuedTransactions); err != nil {
+		return model.BatchRequest{}, err
+	}
+	if len(queuedTransactions) == 0 {
+		return model.BatchRequest{}, errors.New(errorcode.BATCH_EMPTY)
+	}
+	queueIds, transactionIds := []uuid.UUID{}, []uuid.UUID{}
+	for _, queuedTransaction := range queuedTransactions {
+		queueIds = append(queueIds, queuedTransaction.ID)
+		transactionIds = append(transactionIds, queuedTransaction.TransactionId)
+	}
+
+	closedAt := time.Now()
+	retryBatch := model.BatchRequest{
+		AssetSymbol: batch.AssetSymbol,
+		Network:     batch.Network,
+		Status:      model.BatchStatus.START_MODE,
+		ClosedAt:    &closedAt,
+		CloseReason: model.BatchCloseReason.FORCED,
+		UpdatedBy:   requestedBy,
+	}
+	if err := repository.Db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&retryBatch).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{"batch_id": retryBatch.ID, "transaction_status": model.TransactionStatus.PENDING}
+		if err := tx.Model(&model.TransactionQueue{}).Where("id IN (?) AND transaction_status = ?", queueIds, model.TransactionStatus.TERMINATED).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Transaction{}).Where("id IN (?) AND transaction_status = ?", transactionIds, model.TransactionStatus.TERMINATED).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.BatchRequest{}).Where("id = ?", batch.ID).Updates(map[string]interface{}{"updated_by": requestedBy}).Error
+	}); err != nil {
+		service.Logger.Error("Error response from batch service : %+v while requeuing batch %s", err, batch.ID)
+		return model.BatchRequest{}, err
+	}
+	service.Logger.Info("Transactions of terminated batch %s of %s on %s requeued as batch %s by %s", batch.ID, batch.AssetSymbol, batch.Network, retryBatch.ID, requestedBy)
+	return retryBatch, nil
+}
+
 func (service BatchService) GetAllActiveBatches(repository database.IBatchRepository, assetSymbol string) ([]model.BatchRequest, error) {
 
 	var activeBatches []model.BatchRequest
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"time"
 	Config "wallet-adapter/config"
 	"wallet-adapter/dto"
 	"wallet-adapter/utility"
@@ -26,6 +28,24 @@ func SubscribeAddressV2(cache *utility.MemoryCache, logger *utility.Logger, conf
 	return nil
 }
 
+// UnsubscribeAddressV2 ... Cancels the crypto adapter subscription of addresses, deposits to them are no longer reported
+func UnsubscribeAddressV2(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, requestData dto.SubscriptionRequestV2, responseData *dto.SubscriptionResponse, serviceErr interface{}) error {
+	metaData := utility.GetRequestMetaData("unsubscribeAddressV2", config)
+	APIClient := NewClient(nil, logger, config, fmt.Sprintf("%s%s", metaData.Endpoint, metaData.Action))
+	APIRequest, err := APIClient.NewRequest(metaData.Type, "", requestData)
+	if err != nil {
+		return err
+	}
+	_, err = APIClient.Do(APIRequest, responseData)
+	if err != nil {
+		if errUnmarshal := json.Unmarshal([]byte(err.Error()), serviceErr); errUnmarshal != nil {
+			return err
+		}
+		return err
+	}
+	return nil
+}
+
 // TransactionStatus ... Calls crypto adapter with transaction hash to confirm transaction status on-chain
 func TransactionStatus(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, requestData dto.TransactionStatusRequest, responseData *dto.TransactionStatusResponse, serviceErr interface{}) error {
 
@@ -91,6 +111,64 @@ func GetOnchainBalance(cache *utility.MemoryCache, logger *utility.Logger, confi
 	return nil
 }
 
+// GetAddressDeposits ... Calls crypto adapter with asset symbol and address to return the deposits the address received on-chain since a time
+func GetAddressDeposits(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, requestData dto.AddressDepositsRequest, responseData *dto.AddressDepositsResponse, serviceErr interface{}) error {
+
+	authToken, err := GetAuthToken(cache, logger, config)
+	if err != nil {
+		return err
+	}
+	metaData := utility.GetRequestMetaData("getAddressDeposits", config)
+
+	APIClient := NewClient(nil, logger, config, fmt.Sprintf("%s%s?address=%s&assetSymbol=%s&network=%s&from=%s", metaData.Endpoint, metaData.Action, requestData.Address, requestData.AssetSymbol, requestData.Network, url.QueryEscape(requestData.From.Format(time.RFC3339))))
+	APIRequest, err := APIClient.NewRequest(metaData.Type, "", nil)
+	if err != nil {
+		return err
+	}
+	APIClient.AddHeader(APIRequest, map[string]string{
+		"x-auth-token": authToken,
+	})
+	_, err = APIClient.Do(APIRequest, responseData)
+	if err != nil {
+		logger.Error("An error occured when trying to get address deposits: ", err)
+		if errUnmarshal := json.Unmarshal([]byte(err.Error()), serviceErr); errUnmarshal != nil {
+			return err
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetNetworkFee ... Gets the estimated fee, in the native asset, of sending an asset on a network
+func GetNetworkFee(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, requestData dto.NetworkFeeRequest, responseData *dto.NetworkFeeResponse, serviceErr interface{}) error {
+
+	authToken, err := GetAuthToken(cache, logger, config)
+	if err != nil {
+		return err
+	}
+	metaData := utility.GetRequestMetaData("getNetworkFee", config)
+
+	APIClient := NewClient(nil, logger, config, fmt.Sprintf("%s%s?assetSymbol=%s&network=%s", metaData.Endpoint, metaData.Action, requestData.AssetSymbol, requestData.Network))
+	APIRequest, err := APIClient.NewRequest(metaData.Type, "", nil)
+	if err != nil {
+		return err
+	}
+	APIClient.AddHeader(APIRequest, map[string]string{
+		"x-auth-token": authToken,
+	})
+	_, err = APIClient.Do(APIRequest, responseData)
+	if err != nil {
+		logger.Error("An error occured when trying to get network fee: ", err)
+		if errUnmarshal := json.Unmarshal([]byte(err.Error()), serviceErr); errUnmarshal != nil {
+			return err
+		}
+		return err
+	}
+
+	return nil
+}
+
 // GetBroadcastedTXNStatusByRef ...
 func GetBroadcastedTXNStatusByRef(transactionRef, assetSymbol string, cache *utility.MemoryCache, logger *utility.Logger, config Config.Data) bool {
 	serviceErr := dto.ServicesRequestErr{}
@@ -1,9 +1,13 @@
 package services
 
 import (
-	"github.com/jinzhu/gorm"
+	"errors"
+	"wallet-adapter/database"
+	"wallet-adapter/errorcode"
 	"wallet-adapter/model"
 	"wallet-adapter/utility"
+
+	"github.com/jinzhu/gorm"
 )
 
 func InitFloatParams(DB *gorm.DB, logger *utility.Logger) error {
@@ -27,7 +31,48 @@ func InitFloatParams(DB *gorm.DB, logger *utility.Logger) error {
 		}
 		if err := DB.Where(model.FloatManagerParam{AssetSymbol: asset.AssetSymbol, Network: asset.Network}).FirstOrCreate(&floatParam).Error; err != nil {
 			logger.Error("Error with creating float params for asset %s : %s", asset.AssetSymbol, err)
+			continue
 		}
+		version := floatParam.VersionOf()
+		if err := DB.Where(model.FloatManagerParamVersion{FloatManagerParamID: floatParam.ID, Version: floatParam.Version}).FirstOrCreate(&version).Error; err != nil {
+			logger.Error("Error with recording float params version for asset %s : %s", asset.AssetSymbol, err)
+		}
+	}
+	return nil
+}
+
+// GetFloatParams ... Returns the float parameters of an asset on a network, networks without their own parameters use
+// the parameters set for the asset, saved without a network
+func GetFloatParams(repository database.IUserAssetRepository, assetSymbol, network string) (model.FloatManagerParam, error) {
+	floatParam := model.FloatManagerParam{}
+	err := repository.GetByFieldName(&model.FloatManagerParam{AssetSymbol: assetSymbol, Network: network}, &floatParam)
+	if err == nil || err.Error() != errorcode.SQL_404 || network == "" {
+		return floatParam, err
+	}
+	// a struct condition skips the empty network, so the asset parameters are looked up with a map
+	err = repository.GetByFieldName(map[string]interface{}{"asset_symbol": assetSymbol, "network": ""}, &floatParam)
+	return floatParam, err
+}
+
+// ValidateFloatParams ... Checks the float range percentages are ordered and the trigger levels are fractions of the range,
+// the average may be the maximum as the parameters seeded for each float are
+func ValidateFloatParams(floatParam model.FloatManagerParam) error {
+	if floatParam.MinPercentTotalUserBalance >= floatParam.AveragePercentTotalUserBalance || floatParam.AveragePercentTotalUserBalance > floatParam.MaxPercentTotalUserBalance ||
+		floatParam.MinPercentMaxUserBalance >= floatParam.MaxPercentMaxUserBalance {
+		return errors.New(errorcode.FLOAT_PARAMS_RANGE_INVALID)
+	}
+	if floatParam.PercentMinimumTriggerLevel < 0 || floatParam.PercentMinimumTriggerLevel > 1 || floatParam.PercentMaximumTriggerLevel < 0 || floatParam.PercentMaximumTriggerLevel > 1 {
+		return errors.New(errorcode.FLOAT_PARAMS_TRIGGER_INVALID)
+	}
+	return nil
+}
+
+// SaveFloatParams ... Saves float parameters as their next version, float manager runs record the version they used
+func SaveFloatParams(repository database.IUserAssetRepository, floatParam *model.FloatManagerParam) error {
+	floatParam.Version++
+	if err := repository.SaveFloatParams(floatParam); err != nil {
+		floatParam.Version--
+		return err
 	}
 	return nil
 }
@@ -64,6 +64,24 @@ func GenerateAddressWithoutSub(cache *utility.MemoryCache, logger *utility.Logge
 
 // GenerateAllAddresses ...
 func (service BaseService) GenerateAllAddresses(userID uuid.UUID, symbol string, coinType int64, addressType, network string) ([]dto.AllAddressResponse, error) {
+	addresses, err := service.GenerateAllAddressesWithoutSub(userID, symbol, addressType, network)
+	if err != nil {
+		return []dto.AllAddressResponse{}, err
+	}
+	addressArray := []string{}
+	for _, item := range addresses {
+		addressArray = append(addressArray, item.Data)
+	}
+
+	if err := service.subscribeAddress(dto.ServicesRequestErr{}, addressArray, coinType); err != nil {
+		return []dto.AllAddressResponse{}, err
+	}
+
+	return addresses, nil
+}
+
+// GenerateAllAddressesWithoutSub ... Generates the addresses of every address type for a user asset, callers subscribe them
+func (service BaseService) GenerateAllAddressesWithoutSub(userID uuid.UUID, symbol string, addressType, network string) ([]dto.AllAddressResponse, error) {
 	var APIClient *Client
 	var serviceErr dto.ServicesRequestErr
 	authToken, err := GetAuthToken(service.Cache, service.Logger, service.Config)
@@ -96,14 +114,6 @@ func (service BaseService) GenerateAllAddresses(userID uuid.UUID, symbol string,
 		}
 		return []dto.AllAddressResponse{}, errors.New(serviceErr.Message)
 	}
-	addressArray := []string{}
-	for _, item := range responseData.Addresses {
-		addressArray = append(addressArray, item.Data)
-	}
-
-	if err := service.subscribeAddress(serviceErr, addressArray, coinType); err != nil {
-		return []dto.AllAddressResponse{}, err
-	}
 
 	return responseData.Addresses, nil
 }
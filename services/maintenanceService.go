@@ -0,0 +1,73 @@
+package services
+
+import (
+	"fmt"
+	"time"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+)
+
+// MaintenanceError ... Returned when an operation is paused on a network by a maintenance window
+type MaintenanceError struct {
+	Window model.MaintenanceWindow
+}
+
+func (e MaintenanceError) Error() string {
+	return fmt.Sprintf("%s on network %s is paused for maintenance until %s : %s", e.Window.Scope, e.Window.Network, e.Window.EndsAt.UTC().Format(time.RFC3339), e.Window.Message)
+}
+
+// GetActiveMaintenanceWindow ... Returns the maintenance window pausing an operation on an asset network, nil when the operation is not paused.
+// When windows overlap the one ending last is returned
+func GetActiveMaintenanceWindow(repository database.IUserAssetRepository, assetSymbol, network, scope string) (*model.MaintenanceWindow, error) {
+	var windows []model.MaintenanceWindow
+	if err := repository.FetchActiveMaintenanceWindows(assetSymbol, network, scope, time.Now(), &windows); err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, nil
+	}
+	return &windows[0], nil
+}
+
+// CheckMaintenance ... Returns a MaintenanceError when an operation is paused on an asset network
+func CheckMaintenance(repository database.IUserAssetRepository, assetSymbol, network, scope string) error {
+	window, err := GetActiveMaintenanceWindow(repository, assetSymbol, network, scope)
+	if err != nil {
+		return err
+	}
+	if window != nil {
+		return MaintenanceError{Window: *window}
+	}
+	return nil
+}
+
+// GetMaintenanceStatus ... Lists the maintenance windows in progress and those scheduled to start
+func GetMaintenanceStatus(repository database.IUserAssetRepository) (dto.MaintenanceStatus, error) {
+	status := dto.MaintenanceStatus{Active: []dto.MaintenanceWindowStatus{}, Upcoming: []dto.MaintenanceWindowStatus{}}
+	var windows []model.MaintenanceWindow
+	now := time.Now()
+	if err := repository.FetchMaintenanceWindows(now, &windows); err != nil {
+		return status, err
+	}
+	for _, window := range windows {
+		if window.StartsAt.After(now) {
+			status.Upcoming = append(status.Upcoming, MaintenanceWindowToStatus(window))
+			continue
+		}
+		status.Active = append(status.Active, MaintenanceWindowToStatus(window))
+	}
+	return status, nil
+}
+
+// MaintenanceWindowToStatus ... Maps a maintenance window to its public details
+func MaintenanceWindowToStatus(window model.MaintenanceWindow) dto.MaintenanceWindowStatus {
+	return dto.MaintenanceWindowStatus{
+		AssetSymbol: window.AssetSymbol,
+		Network:     window.Network,
+		Scope:       window.Scope,
+		StartsAt:    window.StartsAt,
+		EndsAt:      window.EndsAt,
+		Message:     window.Message,
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+
+	"github.com/jinzhu/gorm"
+)
+
+const memoAllocationAttempts = 10
+
+// MemoRule ... Length and charset rules of the memos allocated on a network.
+// Numeric memos are a payload followed by a Damm check digit, text memos are Crockford base32 followed by a mod 37 check character
+type MemoRule struct {
+	Numeric    bool
+	MinPayload int64
+	MaxPayload int64
+	TextLength int
+}
+
+var (
+	// XRP destination tags are uint32, the largest payload keeps the tag with its check digit below 2^32
+	xrpMemoRule = MemoRule{Numeric: true, MinPayload: 100000000, MaxPayload: 429496728}
+	// XLM id memos are uint64
+	xlmMemoRule = MemoRule{Numeric: true, MinPayload: 100000000000, MaxPayload: 999999999999}
+	// BNB memos are free text
+	bnbMemoRule = MemoRule{TextLength: 9}
+
+	memoRules = map[int64]MemoRule{
+		144: xrpMemoRule,
+		148: xlmMemoRule,
+		714: bnbMemoRule,
+	}
+)
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+const crockfordCheckAlphabet = crockfordAlphabet + "*~$=U"
+
+var dammTable = [10][10]int{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// GetMemoRule ... Returns the memo rule for a network coin type, networks without a rule use XRP tags which every memo network accepts
+func GetMemoRule(coinType int64) MemoRule {
+	if rule, ok := memoRules[coinType]; ok {
+		return rule
+	}
+	return xrpMemoRule
+}
+
+// GenerateMemoCandidate ... Generates a random memo following the network memo rule
+func GenerateMemoCandidate(rule MemoRule) (string, error) {
+	if rule.Numeric {
+		payload, err := randomInt(rule.MaxPayload - rule.MinPayload + 1)
+		if err != nil {
+			return "", err
+		}
+		digits := strconv.FormatInt(payload+rule.MinPayload, 10)
+		return digits + strconv.Itoa(dammCheckDigit(digits)), nil
+	}
+
+	// payloads whose check value falls on a symbol are skipped so text memos stay alphanumeric
+	for {
+		payload := make([]byte, rule.TextLength)
+		for index := range payload {
+			symbol, err := randomInt(int64(len(crockfordAlphabet)))
+			if err != nil {
+				return "", err
+			}
+			payload[index] = crockfordAlphabet[symbol]
+		}
+		check := crockfordCheckValue(string(payload))
+		if check < len(crockfordAlphabet) || crockfordCheckAlphabet[check] == 'U' {
+			return string(payload) + string(crockfordCheckAlphabet[check]), nil
+		}
+	}
+}
+
+// ValidateMemo ... Checks a memo against the network rule and its check digit, a failed check usually means a mistyped memo
+func ValidateMemo(coinType int64, memo string) bool {
+	rule := GetMemoRule(coinType)
+	if rule.Numeric {
+		value, err := strconv.ParseInt(memo, 10, 64)
+		if err != nil || memo[0] == '0' {
+			return false
+		}
+		payload := value / 10
+		return payload >= rule.MinPayload && payload <= rule.MaxPayload && dammCheckDigit(memo) == 0
+	}
+
+	memo = strings.ToUpper(memo)
+	if len(memo) != rule.TextLength+1 {
+		return false
+	}
+	for _, symbol := range memo[:rule.TextLength] {
+		if !strings.ContainsRune(crockfordAlphabet, symbol) {
+			return false
+		}
+	}
+	return crockfordCheckAlphabet[crockfordCheckValue(memo[:rule.TextLength])] == memo[rule.TextLength]
+}
+
+// AllocateMemo ... Allocates a unique memo for a user on a memo network. The primary memo of a user on a network is reused,
+// users with a memo from before per-network allocation keep it on every network
+func AllocateMemo(repository database.IUserAssetRepository, networkAsset dto.NetworkAsset, isPrimaryAddress bool) (string, error) {
+	if isPrimaryAddress {
+		for _, network := range []string{networkAsset.Network, ""} {
+			userMemo := model.UserMemo{}
+			err := repository.Db().Where("user_id = ? AND is_primary_address = ? AND network = ?", networkAsset.UserID, true, network).First(&userMemo).Error
+			if err == nil {
+				return userMemo.Memo, nil
+			}
+			if !gorm.IsRecordNotFoundError(err) {
+				return "", err
+			}
+		}
+	}
+
+	rule := GetMemoRule(networkAsset.CoinType)
+	for attempt := 0; attempt < memoAllocationAttempts; attempt++ {
+		memo, err := GenerateMemoCandidate(rule)
+		if err != nil {
+			return "", err
+		}
+		userMemo := model.UserMemo{UserID: networkAsset.UserID, Memo: memo, Network: networkAsset.Network, IsPrimaryAddress: isPrimaryAddress}
+		if err := repository.Create(&userMemo); err != nil {
+			// the unique constraint on memo rejects collisions, any other failure is returned
+			if existErr := repository.GetByFieldName(&model.UserMemo{Memo: memo}, &model.UserMemo{}); existErr != nil {
+				return "", err
+			}
+			continue
+		}
+		return memo, nil
+	}
+	return "", errors.New(errorcode.MEMO_ALLOCATION_FAILED)
+}
+
+func dammCheckDigit(digits string) int {
+	interim := 0
+	for _, digit := range digits {
+		interim = dammTable[interim][digit-'0']
+	}
+	return interim
+}
+
+func crockfordCheckValue(payload string) int {
+	value := 0
+	for _, symbol := range payload {
+		value = (value*len(crockfordAlphabet) + strings.IndexRune(crockfordAlphabet, symbol)) % len(crockfordCheckAlphabet)
+	}
+	return value
+}
+
+func randomInt(max int64) (int64, error) {
+	value, err := rand.Int(rand.Reader, big.NewInt(max))
+	if err != nil {
+		return 0, err
+	}
+	return value.Int64(), nil
+}
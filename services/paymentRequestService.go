@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// BIP21 schemes by SLIP-44 coin type
+	bip21Schemes = map[int64]string{
+		0:   "bitcoin",
+		2:   "litecoin",
+		3:   "dogecoin",
+		5:   "dash",
+		145: "bitcoincash",
+	}
+	// EIP-681 chain ids by SLIP-44 coin type
+	eip681ChainIDs = map[int64]int64{
+		60:       1,
+		20000714: 56,
+	}
+)
+
+// uriParam is an ordered query parameter, URI parameters are written in a stable order
+type uriParam struct {
+	key, value string
+}
+
+// BuildPaymentURI ... Builds the standard payment URI of a deposit address from the network metadata, amount and label are optional
+func BuildPaymentURI(network model.Network, address dto.AssetAddress, amount, label string) (string, error) {
+	var amountValue decimal.Decimal
+	if amount != "" {
+		var err error
+		if amountValue, err = decimal.NewFromString(amount); err != nil || !amountValue.IsPositive() {
+			return "", errors.New("amount must be a positive decimal")
+		}
+	}
+
+	if scheme, ok := bip21Schemes[network.CoinType]; ok {
+		params := []uriParam{{"amount", amount}, {"label", label}}
+		// cashaddr addresses already carry the scheme as their prefix
+		return fmt.Sprintf("%s:%s%s", scheme, strings.TrimPrefix(address.Address, scheme+":"), encodeURIParams(params)), nil
+	}
+
+	if chainID, ok := eip681ChainIDs[network.CoinType]; ok {
+		baseUnits := ""
+		if amount != "" {
+			baseUnits = amountValue.Shift(int32(network.NativeDecimals)).Truncate(0).String()
+		}
+		if network.IsToken != nil && *network.IsToken {
+			if network.ChainDenomId == "" {
+				return "", fmt.Errorf("no contract address stored for %s on %s", network.AssetSymbol, network.Network)
+			}
+			params := []uriParam{{"address", address.Address}, {"uint256", baseUnits}}
+			return fmt.Sprintf("ethereum:%s@%d/transfer%s", network.ChainDenomId, chainID, encodeURIParams(params)), nil
+		}
+		return fmt.Sprintf("ethereum:%s@%d%s", address.Address, chainID, encodeURIParams([]uriParam{{"value", baseUnits}})), nil
+	}
+
+	switch network.CoinType {
+	case 714:
+		params := []uriParam{{"memo", address.Memo}, {"amount", amount}, {"label", label}}
+		return fmt.Sprintf("bnb:%s%s", address.Address, encodeURIParams(params)), nil
+	case 144:
+		params := []uriParam{{"dt", address.Memo}, {"amount", amount}, {"label", label}}
+		return fmt.Sprintf("ripple:%s%s", address.Address, encodeURIParams(params)), nil
+	case 148:
+		params := []uriParam{{"destination", address.Address}, {"amount", amount}}
+		if address.Memo != "" {
+			params = append(params, uriParam{"memo", address.Memo}, uriParam{"memo_type", "MEMO_ID"})
+		}
+		params = append(params, uriParam{"msg", label})
+		return "web+stellar:pay" + encodeURIParams(params), nil
+	}
+
+	params := []uriParam{{"memo", address.Memo}, {"amount", amount}, {"label", label}}
+	return fmt.Sprintf("%s:%s%s", strings.ToLower(network.NativeAsset), address.Address, encodeURIParams(params)), nil
+}
+
+// encodeURIParams percent-encodes non empty parameters, spaces become %20 as BIP21 requires
+func encodeURIParams(params []uriParam) string {
+	var encoded []string
+	for _, param := range params {
+		if param.value == "" {
+			continue
+		}
+		encoded = append(encoded, param.key+"="+strings.Replace(url.QueryEscape(param.value), "+", "%20", -1))
+	}
+	if len(encoded) == 0 {
+		return ""
+	}
+	return "?" + strings.Join(encoded, "&")
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/utility"
+)
+
+// Screener ... Checks counterparty addresses against a sanctions source before funds move
+type Screener interface {
+	Screen(address, assetSymbol, network string) (dto.ScreeningResult, error)
+}
+
+var (
+	localScreeners     = map[string]*LocalListScreener{}
+	localScreenersLock sync.Mutex
+)
+
+// NewScreener ... Returns the screener for the configured provider, screening is skipped when no provider is set
+func NewScreener(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data) Screener {
+	switch strings.ToLower(config.ScreeningProvider) {
+	case utility.SCREENING_PROVIDER_LOCAL:
+		localScreenersLock.Lock()
+		defer localScreenersLock.Unlock()
+		screener, ok := localScreeners[config.SanctionsListPath]
+		if !ok {
+			screener = &LocalListScreener{Logger: logger, FilePath: config.SanctionsListPath}
+			localScreeners[config.SanctionsListPath] = screener
+		}
+		return screener
+	case utility.SCREENING_PROVIDER_HTTP:
+		return &HTTPScreener{Cache: cache, Logger: logger, Config: config}
+	default:
+		return noopScreener{}
+	}
+}
+
+type noopScreener struct{}
+
+func (noopScreener) Screen(address, assetSymbol, network string) (dto.ScreeningResult, error) {
+	return dto.ScreeningResult{}, nil
+}
+
+// LocalListScreener ... Screens addresses against a CSV or JSON sanctions list on disk, the list is reloaded whenever the file changes
+type LocalListScreener struct {
+	Logger   *utility.Logger
+	FilePath string
+
+	mutex   sync.RWMutex
+	modTime time.Time
+	entries map[string]dto.SanctionedAddress
+}
+
+// Screen ...
+func (screener *LocalListScreener) Screen(address, assetSymbol, network string) (dto.ScreeningResult, error) {
+	if err := screener.reloadIfChanged(); err != nil {
+		return dto.ScreeningResult{}, err
+	}
+
+	screener.mutex.RLock()
+	defer screener.mutex.RUnlock()
+	entry, ok := screener.entries[strings.ToLower(strings.TrimSpace(address))]
+	if !ok || (entry.Network != "" && !strings.EqualFold(entry.Network, network)) {
+		return dto.ScreeningResult{Provider: utility.SCREENING_PROVIDER_LOCAL}, nil
+	}
+	reason := entry.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("Address %s is on the sanctions list", address)
+	}
+	return dto.ScreeningResult{Hit: true, Provider: utility.SCREENING_PROVIDER_LOCAL, Reason: reason}, nil
+}
+
+func (screener *LocalListScreener) reloadIfChanged() error {
+	fileInfo, err := os.Stat(screener.FilePath)
+	if err != nil {
+		return err
+	}
+
+	screener.mutex.RLock()
+	isCurrent := screener.entries != nil && fileInfo.ModTime().Equal(screener.modTime)
+	screener.mutex.RUnlock()
+	if isCurrent {
+		return nil
+	}
+
+	entries, err := LoadSanctionsList(screener.FilePath)
+	if err != nil {
+		return err
+	}
+
+	screener.mutex.Lock()
+	screener.entries = entries
+	screener.modTime = fileInfo.ModTime()
+	screener.mutex.Unlock()
+	screener.Logger.Info("Sanctions list loaded from %s with %d entries", screener.FilePath, len(entries))
+	return nil
+}
+
+// LoadSanctionsList ... Reads a sanctions list from a JSON array or a CSV file with address, network and reason columns
+func LoadSanctionsList(filePath string) (map[string]dto.SanctionedAddress, error) {
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	sanctionedAddresses := []dto.SanctionedAddress{}
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		if err := json.Unmarshal(content, &sanctionedAddresses); err != nil {
+			return nil, err
+		}
+	} else {
+		reader := csv.NewReader(strings.NewReader(string(content)))
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			if len(record) == 0 || strings.EqualFold(strings.TrimSpace(record[0]), "address") {
+				continue
+			}
+			sanctionedAddress := dto.SanctionedAddress{Address: record[0]}
+			if len(record) > 1 {
+				sanctionedAddress.Network = strings.TrimSpace(record[1])
+			}
+			if len(record) > 2 {
+				sanctionedAddress.Reason = strings.TrimSpace(record[2])
+			}
+			sanctionedAddresses = append(sanctionedAddresses, sanctionedAddress)
+		}
+	}
+
+	entries := make(map[string]dto.SanctionedAddress, len(sanctionedAddresses))
+	for _, sanctionedAddress := range sanctionedAddresses {
+		address := strings.ToLower(strings.TrimSpace(sanctionedAddress.Address))
+		if address == "" {
+			continue
+		}
+		entries[address] = sanctionedAddress
+	}
+	return entries, nil
+}
+
+// HTTPScreener ... Screens addresses with an external screening provider
+type HTTPScreener struct {
+	Cache  *utility.MemoryCache
+	Logger *utility.Logger
+	Config Config.Data
+}
+
+// Screen ...
+func (screener *HTTPScreener) Screen(address, assetSymbol, network string) (dto.ScreeningResult, error) {
+	requestData := dto.ScreenAddressRequest{Address: address, AssetSymbol: assetSymbol, Network: network}
+	responseData := dto.ScreenAddressResponse{}
+	metaData := utility.GetRequestMetaData("screenAddress", screener.Config)
+
+	APIClient := NewClient(nil, screener.Logger, screener.Config, fmt.Sprintf("%s%s", metaData.Endpoint, metaData.Action))
+	APIRequest, err := APIClient.NewRequest(metaData.Type, "", requestData)
+	if err != nil {
+		return dto.ScreeningResult{}, err
+	}
+	APIClient.AddHeader(APIRequest, map[string]string{
+		"x-api-key": screener.Config.ScreeningServiceKey,
+	})
+	if _, err := APIClient.Do(APIRequest, &responseData); err != nil {
+		screener.Logger.Error("An error occured while calling screening provider %+v", err)
+		return dto.ScreeningResult{}, err
+	}
+
+	return dto.ScreeningResult{Hit: responseData.IsSanctioned, Provider: utility.SCREENING_PROVIDER_HTTP, Reason: responseData.Reason}, nil
+}
+
+// ScreenCounterparty ... Screens an address and holds the transaction when there is a hit, screening errors also hold the transaction
+func ScreenCounterparty(screener Screener, logger *utility.Logger, address, assetSymbol, network string) dto.ScreeningResult {
+	if address == "" {
+		return dto.ScreeningResult{}
+	}
+	result, err := screener.Screen(address, assetSymbol, network)
+	if err != nil {
+		logger.Error("Error screening address %s for %s on %s : %s", address, assetSymbol, network, err)
+		return dto.ScreeningResult{Hit: true, Provider: result.Provider, Reason: fmt.Sprintf("%s : %s", errorcode.SCREENING_UNAVAILABLE, err)}
+	}
+	return result
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	Config "wallet-adapter/config"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+
+	"github.com/shopspring/decimal"
+	"github.com/spf13/viper"
+)
+
+const defaultTravelRuleFiatCurrency = "USD"
+
+// GetTravelRuleThreshold ... Returns the fiat value above which travel rule information is required for an asset, zero disables the check
+func GetTravelRuleThreshold(assetSymbol string) float64 {
+	return viper.GetFloat64(fmt.Sprintf("TRAVELRULETHRESHOLD.%s", assetSymbol))
+}
+
+// GetTravelRuleFiatCurrency ...
+func GetTravelRuleFiatCurrency(config Config.Data) string {
+	if config.TravelRuleFiatCurrency == "" {
+		return defaultTravelRuleFiatCurrency
+	}
+	return strings.ToUpper(config.TravelRuleFiatCurrency)
+}
+
+// GetAssetRate ... Fetches the fiat rate of an asset from rate service, rates are cached for the cache expiry duration
+func GetAssetRate(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, assetSymbol, currency string) (decimal.Decimal, error) {
+	cacheKey := fmt.Sprintf("rate-%s-%s", assetSymbol, currency)
+	if cachedRate, ok := cache.Get(cacheKey).(decimal.Decimal); ok {
+		return cachedRate, nil
+	}
+
+	responseData := dto.AssetRateResponse{}
+	serviceErr := dto.ServicesRequestErr{}
+	metaData := utility.GetRequestMetaData("getAssetRate", config)
+
+	APIClient := NewClient(nil, logger, config, fmt.Sprintf("%s%s?base=%s&quote=%s", metaData.Endpoint, metaData.Action, assetSymbol, currency))
+	APIRequest, err := APIClient.NewRequest(metaData.Type, "", nil)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if _, err = APIClient.Do(APIRequest, &responseData); err != nil {
+		if errUnmarshal := json.Unmarshal([]byte(err.Error()), &serviceErr); errUnmarshal != nil || serviceErr.Message == "" {
+			return decimal.Zero, err
+		}
+		return decimal.Zero, errors.New(serviceErr.Message)
+	}
+
+	rate, err := decimal.NewFromString(responseData.Rate)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	cache.Set(cacheKey, rate, true)
+	return rate, nil
+}
+
+// IsTravelRuleRequired ... Values a transfer in fiat and checks it against the asset travel rule threshold
+func IsTravelRuleRequired(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, assetSymbol string, value decimal.Decimal) (bool, decimal.Decimal, error) {
+	threshold := GetTravelRuleThreshold(assetSymbol)
+	if threshold <= 0 {
+		return false, decimal.Zero, nil
+	}
+
+	rate, err := GetAssetRate(cache, logger, config, assetSymbol, GetTravelRuleFiatCurrency(config))
+	if err != nil {
+		return false, decimal.Zero, err
+	}
+	fiatValue := value.Mul(rate)
+	return fiatValue.GreaterThanOrEqual(decimal.NewFromFloat(threshold)), fiatValue, nil
+}
+
+// BuildTravelRuleRecord ... Creates the travel rule record for a transaction, the party details are encrypted before storage
+func BuildTravelRuleRecord(config Config.Data, transaction model.Transaction, travelRule *dto.TravelRuleData, fiatValue decimal.Decimal) (model.TravelRuleRecord, error) {
+	record := model.TravelRuleRecord{
+		TransactionID:  transaction.ID,
+		TransactionTag: transaction.TransactionTag,
+		AssetSymbol:    transaction.AssetSymbol,
+		Network:        transaction.Network,
+		Value:          transaction.Value,
+		FiatCurrency:   GetTravelRuleFiatCurrency(config),
+		FiatValue:      fiatValue.String(),
+		Status:         model.TravelRuleRecordStatus.INCOMPLETE,
+	}
+	if travelRule == nil {
+		return record, nil
+	}
+
+	// this service is the originating VASP on withdrawals and the beneficiary VASP on deposits
+	ownParty := &travelRule.Beneficiary
+	if transaction.TransactionTag == model.TransactionTag.WITHDRAW {
+		ownParty = &travelRule.Originator
+	}
+	if ownParty.VaspName == "" {
+		ownParty.VaspName = config.VaspName
+		ownParty.VaspLEI = config.VaspLEI
+	}
+
+	payload, err := json.Marshal(travelRule)
+	if err != nil {
+		return record, err
+	}
+	encryptedPayload, err := utility.Encrypt(payload, config.TravelRuleEncryptionKey)
+	if err != nil {
+		return record, err
+	}
+	record.EncryptedPayload = encryptedPayload
+	record.Status = model.TravelRuleRecordStatus.COMPLETE
+	return record, nil
+}
+
+// DecryptTravelRuleRecord ...
+func DecryptTravelRuleRecord(config Config.Data, record model.TravelRuleRecord) (dto.TravelRuleData, error) {
+	travelRule := dto.TravelRuleData{}
+	if record.EncryptedPayload == "" {
+		return travelRule, nil
+	}
+	payload, err := utility.Decrypt(record.EncryptedPayload, config.TravelRuleEncryptionKey)
+	if err != nil {
+		return travelRule, err
+	}
+	err = json.Unmarshal(payload, &travelRule)
+	return travelRule, err
+}
+
+// ToIVMS101 ... Maps travel rule data to the IVMS101 identity payload
+func ToIVMS101(travelRule dto.TravelRuleData) dto.IVMS101Payload {
+	return dto.IVMS101Payload{
+		Originator: dto.IVMS101Originator{
+			OriginatorPersons: []dto.IVMS101Person{toIVMS101Person(travelRule.Originator)},
+			AccountNumber:     accountNumbers(travelRule.Originator),
+		},
+		Beneficiary: dto.IVMS101Beneficiary{
+			BeneficiaryPersons: []dto.IVMS101Person{toIVMS101Person(travelRule.Beneficiary)},
+			AccountNumber:      accountNumbers(travelRule.Beneficiary),
+		},
+		OriginatingVASP: dto.IVMS101VASP{OriginatingVASP: toIVMS101VASP(travelRule.Originator)},
+		BeneficiaryVASP: dto.IVMS101VASP{BeneficiaryVASP: toIVMS101VASP(travelRule.Beneficiary)},
+	}
+}
+
+func toIVMS101Person(party dto.TravelRuleParty) dto.IVMS101Person {
+	var geographicAddress []dto.IVMS101Address
+	if party.AddressLine != "" || party.Country != "" {
+		geographicAddress = []dto.IVMS101Address{{AddressType: "GEOG", AddressLine: []string{party.AddressLine}, Country: party.Country}}
+	}
+	var nationalIdentification *dto.IVMS101NationalIdentification
+	if party.NationalIdentifier != "" {
+		nationalIdentification = &dto.IVMS101NationalIdentification{
+			NationalIdentifier:     party.NationalIdentifier,
+			NationalIdentifierType: party.NationalIdentifierType,
+			CountryOfIssue:         party.CountryOfIssue,
+		}
+	}
+
+	if party.LegalName != "" && party.LastName == "" {
+		return dto.IVMS101Person{LegalPerson: &dto.IVMS101LegalPerson{
+			Name: dto.IVMS101LegalPersonName{NameIdentifier: []dto.IVMS101LegalPersonNameIdentifier{
+				{LegalPersonName: party.LegalName, LegalPersonNameIdentifierType: "LEGL"},
+			}},
+			GeographicAddress:      geographicAddress,
+			NationalIdentification: nationalIdentification,
+			CountryOfRegistration:  party.Country,
+		}}
+	}
+
+	naturalPerson := &dto.IVMS101NaturalPerson{
+		Name: dto.IVMS101NaturalPersonName{NameIdentifier: []dto.IVMS101NaturalPersonNameIdentifier{
+			{PrimaryIdentifier: party.LastName, SecondaryIdentifier: party.FirstName, NameIdentifierType: "LEGL"},
+		}},
+		GeographicAddress:      geographicAddress,
+		NationalIdentification: nationalIdentification,
+		CountryOfResidence:     party.CountryOfResidence,
+	}
+	if party.DateOfBirth != "" {
+		naturalPerson.DateAndPlaceOfBirth = &dto.IVMS101DateAndPlaceOfBirth{DateOfBirth: party.DateOfBirth, PlaceOfBirth: party.PlaceOfBirth}
+	}
+	return dto.IVMS101Person{NaturalPerson: naturalPerson}
+}
+
+func toIVMS101VASP(party dto.TravelRuleParty) *dto.IVMS101Person {
+	if party.VaspName == "" {
+		return nil
+	}
+	legalPerson := &dto.IVMS101LegalPerson{
+		Name: dto.IVMS101LegalPersonName{NameIdentifier: []dto.IVMS101LegalPersonNameIdentifier{
+			{LegalPersonName: party.VaspName, LegalPersonNameIdentifierType: "LEGL"},
+		}},
+	}
+	if party.VaspLEI != "" {
+		legalPerson.NationalIdentification = &dto.IVMS101NationalIdentification{NationalIdentifier: party.VaspLEI, NationalIdentifierType: "LEIX"}
+	}
+	return &dto.IVMS101Person{LegalPerson: legalPerson}
+}
+
+func accountNumbers(party dto.TravelRuleParty) []string {
+	if party.AccountNumber == "" {
+		return []string{}
+	}
+	return []string{party.AccountNumber}
+}
@@ -2,9 +2,9 @@ package services
 
 import (
 	"errors"
+	"github.com/jinzhu/gorm"
 	uuid "github.com/satori/go.uuid"
 	"github.com/trustwallet/blockatlas/pkg/logger"
-	"strconv"
 	Config "wallet-adapter/config"
 	"wallet-adapter/database"
 	"wallet-adapter/dto"
@@ -21,7 +21,7 @@ func (service BaseService) GenerateV2AddressWithMemo(repository database.IUserAs
 		return errors.New(errorcode.SYSTEM_ERR)
 	}
 	addressWithMemo.Address = v2Address
-	addressWithMemo.Memo, err = GenerateMemo(repository, networkAsset.UserID, isPrimaryAddress)
+	addressWithMemo.Memo, err = AllocateMemo(repository, networkAsset, isPrimaryAddress)
 	if err != nil {
 		return err
 	}
@@ -56,6 +56,7 @@ func GetBinanceProvidedAddressforAsset(repository database.IUserAssetRepository,
 func GenerateV1Address(repository database.IUserAssetRepository, logger *utility.Logger, cache *utility.MemoryCache,
 	config Config.Data, networkAsset dto.NetworkAsset, userAddress model.UserAddress, isPrimaryAddress bool) (string, error) {
 	service := BaseService{Config: config, Cache: cache, Logger: logger}
+	var pooledAddresses []model.PooledAddress
 
 	if networkAsset.AddressProvider == model.AddressProvider.BINANCE {
 		if !isPrimaryAddress {
@@ -65,10 +66,7 @@ func GenerateV1Address(repository database.IUserAssetRepository, logger *utility
 		if err != nil {
 			return "", err
 		}
-		addressArray := []string{addressResponse.Address}
-		if err := service.subscribeAddress(dto.ServicesRequestErr{}, addressArray, networkAsset.CoinType); err != nil {
-			return "", err
-		}
+		userAddress.SubscriptionStatus, userAddress.SubscribedAt = service.subscribeUserAddresses([]string{addressResponse.Address}, networkAsset.CoinType)
 		userAddress.Address = addressResponse.Address
 		userAddress.AddressProvider = model.AddressProvider.BINANCE
 		userAddress.AssetID = networkAsset.AssetID
@@ -76,13 +74,52 @@ func GenerateV1Address(repository database.IUserAssetRepository, logger *utility
 		userAddress.Network = networkAsset.Network
 		userAddress.AddressType = networkAsset.Network
 
+	} else if networkAsset.AddressProvider == model.AddressProvider.XPUB {
+		address, derivationIndex, err := DeriveXpubAddress(repository, networkAsset, "")
+		if err != nil {
+			logger.Error("Error response from userAddress service, could not derive xpub address : %s ", err)
+			return "", err
+		}
+		userAddress.SubscriptionStatus, userAddress.SubscribedAt = service.subscribeUserAddresses([]string{address}, networkAsset.CoinType)
+		userAddress.Address = address
+		userAddress.DerivationIndex = &derivationIndex
+		userAddress.AddressProvider = model.AddressProvider.XPUB
+		userAddress.AssetID = networkAsset.AssetID
+		userAddress.IsPrimaryAddress = isPrimaryAddress
+		userAddress.Network = networkAsset.Network
+		userAddress.AddressType = networkAsset.Network
+
 	} else {
-		addressResponse, err := service.GenerateAllAddresses(networkAsset.UserID, networkAsset.AssetSymbol, networkAsset.CoinType, "", networkAsset.Network)
+		// pooled addresses are already subscribed, on-demand generation is the fallback when the pool is disabled or drained
+		pooledAddress, err := AssignPooledAddress(repository, logger, networkAsset, "")
 		if err != nil {
 			return "", err
 		}
-		userAddress.Address = addressResponse[0].Data
-		userAddress.AddressType = addressResponse[0].Type
+		if pooledAddress.Address != "" {
+			pooledAddresses = append(pooledAddresses, pooledAddress)
+			userAddress.Address = pooledAddress.Address
+			userAddress.KeyIdentity = &pooledAddress.PoolIdentity
+			userAddress.SubscriptionStatus = model.SubscriptionStatus.ACTIVE
+			userAddress.SubscribedAt = &pooledAddress.CreatedAt
+		} else {
+			keyIdentity, issued, err := GetKeyIdentity(repository, networkAsset)
+			if err != nil {
+				return "", err
+			}
+			addressResponse, err := service.GenerateAllAddressesWithoutSub(keyIdentity, networkAsset.AssetSymbol, "", networkAsset.Network)
+			if err != nil {
+				return "", err
+			}
+			if err := CheckAddressesNotIssued(issued, addressResponse[0].Data); err != nil {
+				logger.Error("Error response from userAddress service, address %s derived for asset %s was already issued to it", addressResponse[0].Data, networkAsset.AssetID)
+				return "", err
+			}
+			userAddress.Address = addressResponse[0].Data
+			if keyIdentity != networkAsset.UserID {
+				userAddress.KeyIdentity = &keyIdentity
+			}
+			userAddress.SubscriptionStatus, userAddress.SubscribedAt = service.subscribeUserAddresses([]string{userAddress.Address}, networkAsset.CoinType)
+		}
 		userAddress.AddressProvider = model.AddressProvider.BUNDLE
 		userAddress.AssetID = networkAsset.AssetID
 		userAddress.IsPrimaryAddress = isPrimaryAddress
@@ -92,6 +129,7 @@ func GenerateV1Address(repository database.IUserAssetRepository, logger *utility
 
 	if err := repository.Create(&userAddress); err != nil {
 		logger.Error("Error response from userAddress service, could not generate user address : %s ", err)
+		ReleasePooledAddresses(repository, logger, pooledAddresses)
 		return "", errors.New(utility.GetSQLErr(err))
 	}
 	return userAddress.Address, nil
@@ -125,28 +163,6 @@ func (service BaseService) GetV2AddressWithMemo(repository database.IUserAssetRe
 	return dto.AssetAddress{Address: userAddress.V2Address, Memo: userAddress.Memo, Network: networkAsset.Network, Type: networkAsset.Network}, nil
 }
 
-func GenerateMemo(repository database.IUserAssetRepository, userId uuid.UUID, isPrimaryAddress bool) (string, error) {
-	// Memo lookup on the db
-	memo := strconv.Itoa(utility.RandNo(100000000, 999999999))
-	userMemo := model.UserMemo{
-		UserID: userId,
-		Memo:   memo,
-		IsPrimaryAddress: isPrimaryAddress,
-	}
-
-	if !isPrimaryAddress {
-		if err := repository.Create(&userMemo); err != nil {
-			return "", err
-		}
-		return userMemo.Memo, nil
-	}
-	if err := repository.FindOrCreate(&model.UserMemo{UserID: userId, IsPrimaryAddress: isPrimaryAddress}, &userMemo); err != nil {
-		return "", err
-	}
-	// Generates a 9 digit memo
-	return userMemo.Memo, nil
-}
-
 func CheckV2Address(repository database.IUserAssetRepository, address string) (bool, error) {
 	sharedAddress := model.SharedAddress{}
 
@@ -230,13 +246,48 @@ func (service BaseService) GetMultipleAddresses(repository database.IUserAssetRe
 
 func (service BaseService) GenerateAndCreateAssetMultipleAddresses(repository database.IUserAssetRepository, networkAsset dto.NetworkAsset, addressType string, isPrimaryAddress bool, network string) ([]dto.AllAddressResponse, error) {
 
-	responseAddresses, err := service.GenerateAllAddresses(networkAsset.UserID, networkAsset.AssetSymbol, networkAsset.CoinType, addressType, network)
+	if networkAsset.AddressProvider == model.AddressProvider.XPUB {
+		return service.deriveAndCreateXpubMultipleAddresses(repository, networkAsset, addressType, isPrimaryAddress, network)
+	}
+
+	addressTypes := utility.AddressTypesPerAsset[networkAsset.CoinType]
+	if addressType != "" {
+		addressTypes = []string{addressType}
+	}
+	// pooled addresses are already subscribed, on-demand generation is the fallback when the pool is disabled or drained
+	pooledAddresses, err := AssignPooledAddresses(repository, service.Logger, networkAsset, addressTypes)
+	if err != nil {
+		return []dto.AllAddressResponse{}, err
+	}
+	if len(pooledAddresses) > 0 {
+		return service.createPooledMultipleAddresses(repository, networkAsset, pooledAddresses, isPrimaryAddress, network)
+	}
+
+	keyIdentity, issued, err := GetKeyIdentity(repository, networkAsset)
+	if err != nil {
+		return []dto.AllAddressResponse{}, err
+	}
+	responseAddresses, err := service.GenerateAllAddressesWithoutSub(keyIdentity, networkAsset.AssetSymbol, addressType, network)
 	if err != nil {
 		return []dto.AllAddressResponse{}, err
 	}
+	addressArray := []string{}
+	for _, address := range responseAddresses {
+		addressArray = append(addressArray, address.Data)
+	}
+	if err := CheckAddressesNotIssued(issued, addressArray...); err != nil {
+		service.Logger.Error("Error response from userAddress service, addresses %+v derived for asset %s were already issued to it", addressArray, networkAsset.AssetID)
+		return []dto.AllAddressResponse{}, err
+	}
+	var keyIdentityRef *uuid.UUID
+	if keyIdentity != networkAsset.UserID {
+		keyIdentityRef = &keyIdentity
+	}
+	subscriptionStatus, subscribedAt := service.subscribeUserAddresses(addressArray, networkAsset.CoinType)
 	for _, address := range responseAddresses {
 		if err := repository.Create(&model.UserAddress{Address: address.Data, AddressType: address.Type, AssetID: networkAsset.AssetID,
-			AddressProvider: model.AddressProvider.BUNDLE, IsPrimaryAddress : isPrimaryAddress, Network: networkAsset.Network}); err != nil {
+			AddressProvider: model.AddressProvider.BUNDLE, IsPrimaryAddress : isPrimaryAddress, Network: networkAsset.Network, KeyIdentity: keyIdentityRef,
+			SubscriptionStatus: subscriptionStatus, SubscribedAt: subscribedAt}); err != nil {
 			service.Logger.Error("Error response from userAddress service, could not save user BTC addresses : %s ", err)
 			return []dto.AllAddressResponse{}, errors.New(utility.GetSQLErr(err))
 		}
@@ -245,6 +296,69 @@ func (service BaseService) GenerateAndCreateAssetMultipleAddresses(repository da
 	return responseAddresses, nil
 }
 
+// createPooledMultipleAddresses saves the pooled addresses assigned to an asset, every pooled address goes back to the pool if one cannot be saved
+func (service BaseService) createPooledMultipleAddresses(repository database.IUserAssetRepository, networkAsset dto.NetworkAsset, pooledAddresses []model.PooledAddress, isPrimaryAddress bool, network string) ([]dto.AllAddressResponse, error) {
+	userAddresses := []model.UserAddress{}
+	responseAddresses := []dto.AllAddressResponse{}
+	for index := range pooledAddresses {
+		pooledAddress := pooledAddresses[index]
+		userAddresses = append(userAddresses, model.UserAddress{Address: pooledAddress.Address, AddressType: pooledAddress.AddressType, AssetID: networkAsset.AssetID,
+			AddressProvider: model.AddressProvider.BUNDLE, IsPrimaryAddress: isPrimaryAddress, Network: networkAsset.Network, KeyIdentity: &pooledAddresses[index].PoolIdentity,
+			SubscriptionStatus: model.SubscriptionStatus.ACTIVE, SubscribedAt: &pooledAddresses[index].CreatedAt})
+		responseAddresses = append(responseAddresses, dto.AllAddressResponse{Type: pooledAddress.AddressType, Network: network, Data: pooledAddress.Address})
+	}
+
+	err := repository.Db().Transaction(func(tx *gorm.DB) error {
+		for index := range userAddresses {
+			if err := tx.Create(&userAddresses[index]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		service.Logger.Error("Error response from userAddress service, could not save pooled user addresses : %s ", err)
+		ReleasePooledAddresses(repository, service.Logger, pooledAddresses)
+		return []dto.AllAddressResponse{}, errors.New(utility.GetSQLErr(err))
+	}
+	return responseAddresses, nil
+}
+
+// deriveAndCreateXpubMultipleAddresses derives one address per address type, each type has its own account xpub
+func (service BaseService) deriveAndCreateXpubMultipleAddresses(repository database.IUserAssetRepository, networkAsset dto.NetworkAsset, addressType string, isPrimaryAddress bool, network string) ([]dto.AllAddressResponse, error) {
+	addressTypes := utility.AddressTypesPerAsset[networkAsset.CoinType]
+	if addressType != "" {
+		addressTypes = []string{addressType}
+	}
+
+	responseAddresses := []dto.AllAddressResponse{}
+	userAddresses := []model.UserAddress{}
+	addressArray := []string{}
+	for _, addressType := range addressTypes {
+		address, derivationIndex, err := DeriveXpubAddress(repository, networkAsset, addressType)
+		if err != nil {
+			service.Logger.Error("Error response from userAddress service, could not derive xpub address : %s ", err)
+			return []dto.AllAddressResponse{}, err
+		}
+		responseAddresses = append(responseAddresses, dto.AllAddressResponse{Type: addressType, Network: network, Data: address})
+		userAddresses = append(userAddresses, model.UserAddress{Address: address, AddressType: addressType, AssetID: networkAsset.AssetID,
+			AddressProvider: model.AddressProvider.XPUB, IsPrimaryAddress: isPrimaryAddress, Network: networkAsset.Network, DerivationIndex: &derivationIndex})
+		addressArray = append(addressArray, address)
+	}
+
+	subscriptionStatus, subscribedAt := service.subscribeUserAddresses(addressArray, networkAsset.CoinType)
+	for index := range userAddresses {
+		userAddresses[index].SubscriptionStatus = subscriptionStatus
+		userAddresses[index].SubscribedAt = subscribedAt
+		if err := repository.Create(&userAddresses[index]); err != nil {
+			service.Logger.Error("Error response from userAddress service, could not save user xpub addresses : %s ", err)
+			return []dto.AllAddressResponse{}, errors.New(utility.GetSQLErr(err))
+		}
+	}
+
+	return responseAddresses, nil
+}
+
 func (service BaseService) CreateAuxiliaryAddressWithMemo(repository database.IUserAssetRepository, networkAsset dto.NetworkAsset) (dto.AssetAddress, error) {
 	var userAddress model.UserAddress
 	var assetAddress dto.AssetAddress
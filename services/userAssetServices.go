@@ -32,39 +32,60 @@ var (
 	}
 )
 
+// SeedSupportedAssets ... Seeds the supported assets from the configured sources. When no source can be loaded the stored assets are kept
+// so an outage of the rate service or TrustWallet does not block start up
 func SeedSupportedAssets(DB *gorm.DB, logger *utility.Logger, config Config.Data, cache *utility.MemoryCache) {
-
-	// Get assets from rate service
-	denominationService := NewService(cache, logger, config)
-	assetDenominations, err := denominationService.GetAssetDenominations()
-	if err != nil {
-		logger.Fatal("Supported assets could not be seeded, err : %s", err)
-	}
-
-	TWDenominations, err := denominationService.GetTWDenominations()
+	assets, report, err := PlanAssetSeeding(DB, logger, config, cache)
 	if err != nil {
-		logger.Fatal("Supported assets could not be seeded, err : %s", err)
+		logger.Error("Supported assets could not be seeded, stored assets are kept, err : %s", err)
+		return
 	}
+	logger.Info("Supported assets seeding report : %+v", report)
+	SaveSupportedAssets(DB, logger, assets)
+}
 
-	assets := normalizeAsset(assetDenominations.Denominations, TWDenominations)
-
+// SaveSupportedAssets ... Saves the seeded denominations and networks. Fields set through the admin API are re-applied over the seeded values
+// and networks added through the admin API are kept. Stored records keep their IDs, networks the source no longer lists are removed
+func SaveSupportedAssets(DB *gorm.DB, logger *utility.Logger, assets []model.Denomination) {
 	for _, asset := range assets {
 		err := DB.Transaction(func(tx *gorm.DB) error {
 
 			// return any error will rollback
-			if err := DB.Where(model.Denomination{AssetSymbol: asset.AssetSymbol}).Assign(asset).FirstOrCreate(&asset).Error; err != nil {
-				logger.Error("Error with creating asset record %s : %s", asset.AssetSymbol, err)
+			denomination, networks, err := resolveSeededAsset(tx, asset)
+			if err != nil {
+				logger.Error("Error with applying config overrides for asset symbol : %s, error : %s", asset.AssetSymbol, err)
 				return err
 			}
 
-			if err := DB.Where("asset_symbol =? ", asset.AssetSymbol ).Delete(model.Network{}).Error; err != nil {
-				logger.Error("Error with deleting network records for asset symbol : %s, error : %s", asset.AssetSymbol, err)
+			existing := model.Denomination{}
+			if err := tx.Where("asset_symbol = ?", asset.AssetSymbol).First(&existing).Error; err == nil {
+				denomination.ID, denomination.CreatedAt = existing.ID, existing.CreatedAt
+			} else if !gorm.IsRecordNotFoundError(err) {
+				logger.Error("Error with fetching asset record %s : %s", asset.AssetSymbol, err)
+				return err
+			}
+			if err := tx.Save(&denomination).Error; err != nil {
+				logger.Error("Error with creating asset record %s : %s", asset.AssetSymbol, err)
 				return err
 			}
 
-			for _, network := range asset.Networks {
-				if err := DB.Where(model.Network{AssetSymbol: asset.AssetSymbol, Network: network.Network}).Assign(network).FirstOrCreate(&network).Error; err != nil {
-					logger.Error("Error with creating asset record %s : %s", asset.AssetSymbol, err)
+			var existingNetworks []model.Network
+			if err := tx.Where("asset_symbol = ?", asset.AssetSymbol).Find(&existingNetworks).Error; err != nil {
+				logger.Error("Error with fetching network records for asset symbol : %s, error : %s", asset.AssetSymbol, err)
+				return err
+			}
+			storedNetworks, staleNetworks := splitStoredNetworks(existingNetworks, networks)
+			for _, network := range networks {
+				network.ID, network.CreatedAt = storedNetworks[network.Network].ID, storedNetworks[network.Network].CreatedAt
+				if err := tx.Save(&network).Error; err != nil {
+					logger.Error("Error with creating network record %s, network : %s : %s", asset.AssetSymbol, network.Network, err)
+					return err
+				}
+			}
+			for _, staleNetwork := range staleNetworks {
+				if err := tx.Delete(&staleNetwork).Error; err != nil {
+					logger.Error("Error with deleting network record for asset symbol : %s, network : %s, error : %s", asset.AssetSymbol, staleNetwork.Network, err)
+					return err
 				}
 			}
 
@@ -81,9 +102,9 @@ func SeedSupportedAssets(DB *gorm.DB, logger *utility.Logger, config Config.Data
 
 func normalizeAsset(denominations []dto.AssetDenomination, TWDenominations []dto.TWDenomination) []model.Denomination {
 	normalizedAssets := []model.Denomination{}
-	normalizedNetworks := []model.Network{}
 
 	for _, denom := range denominations {
+		normalizedNetworks := []model.Network{}
 		for _, network := range denom.AdditionalNetworks {
 			if network.Network != "" {
 				normalizedNetwork := normalizeNetwork(denom.Symbol, network)
@@ -116,11 +137,17 @@ func normalizeDefaultNetwork(denom dto.AssetDenomination, nativeSymbol string) m
 		RequiresMemo:     denom.RequiresMemo,
 		NativeDecimals:   denom.NativeDecimal,
 		NativeAsset :		nativeSymbol,
+		ChainDenomId:     denom.ChainDenomId,
 		IsToken:          &isToken,
 		SweepFee:         sweepFee[denom.CoinType],
 		DepositActivity:  denom.DepositActivity,
 		WithdrawActivity: denom.WithdrawActivity,
 		MinimumSweepable: viper.GetFloat64(fmt.Sprintf("MINIMUMSWEEP.%s_%s", denom.Symbol, denom.Network)),
+		MinimumDeposit:   viper.GetFloat64(fmt.Sprintf("MINIMUMDEPOSIT.%s_%s", denom.Symbol, denom.Network)),
+		DustPolicy:       GetDustPolicy(denom.Symbol, denom.Network),
+		RotateAfterDeposits:  viper.GetInt64(fmt.Sprintf("ADDRESSROTATIONDEPOSITS.%s_%s", denom.Symbol, denom.Network)),
+		RotationWindowHours:  viper.GetInt64(fmt.Sprintf("ADDRESSROTATIONHOURS.%s_%s", denom.Symbol, denom.Network)),
+		RetiredAddressPolicy: GetRetiredAddressPolicy(denom.Symbol, denom.Network),
 		IsBatchable:      isBatchable[denom.CoinType],
 		IsMultiAddresses: IsMultiAddresses[denom.CoinType],
 		AddressProvider:  addressProvider,
@@ -141,11 +168,17 @@ func normalizeNetwork(assetSymbol string, network dto.AdditionalNetwork) model.N
 		RequiresMemo:        network.RequiresMemo,
 		NativeDecimals :     network.NativeDecimal,
 		NativeAsset :		network.NativeAsset,
+		ChainDenomId:        network.ChainDenomId,
 		IsToken:             &isToken,
 		SweepFee:            sweepFee[network.CoinType],
 		DepositActivity:     network.DepositActivity,
 		WithdrawActivity:    network.WithdrawActivity,
 		MinimumSweepable:    viper.GetFloat64(fmt.Sprintf("MINIMUMSWEEP.%s_%s", network.NativeAsset, network.Network)),
+		MinimumDeposit:      viper.GetFloat64(fmt.Sprintf("MINIMUMDEPOSIT.%s_%s", assetSymbol, network.Network)),
+		DustPolicy:          GetDustPolicy(assetSymbol, network.Network),
+		RotateAfterDeposits:  viper.GetInt64(fmt.Sprintf("ADDRESSROTATIONDEPOSITS.%s_%s", assetSymbol, network.Network)),
+		RotationWindowHours:  viper.GetInt64(fmt.Sprintf("ADDRESSROTATIONHOURS.%s_%s", assetSymbol, network.Network)),
+		RetiredAddressPolicy: GetRetiredAddressPolicy(assetSymbol, network.Network),
 		IsBatchable:         isBatchable[network.CoinType],
 		IsMultiAddresses:    IsMultiAddresses[network.CoinType],
 		AddressProvider:     addressProvider,
@@ -154,6 +187,19 @@ func normalizeNetwork(assetSymbol string, network dto.AdditionalNetwork) model.N
 	return additionalNetwork
 }
 
+// GetDustPolicy ... Returns the configured dust policy for an asset on a network, defaults to recording dust deposits without crediting them
+func GetDustPolicy(assetSymbol, network string) string {
+	if strings.EqualFold(viper.GetString(fmt.Sprintf("DUSTPOLICY.%s_%s", assetSymbol, network)), model.DustPolicy.BUCKET) {
+		return model.DustPolicy.BUCKET
+	}
+	return model.DustPolicy.RECORD
+}
+
+// IsDustDeposit ... Checks if a deposit value falls below the minimum deposit set for the network
+func IsDustDeposit(value float64, networkAsset model.Network) bool {
+	return networkAsset.MinimumDeposit > 0 && value < networkAsset.MinimumDeposit
+}
+
 func GetDynamicDenominationValues(tokenType string, coinType int64) (bool, string) {
 	isToken := false
 	addressProvider := model.AddressProvider.BUNDLE
@@ -192,6 +238,11 @@ func (service BaseService) IsWithdrawalActive(assetSymbol, network string, repos
 		return false, nil
 	}
 
+	// a maintenance window in progress pauses the operation with its expected end
+	if err := CheckMaintenance(repository, assetSymbol, network, model.MaintenanceScope.WITHDRAW); err != nil {
+		return false, err
+	}
+
 	return true, nil
 }
 
@@ -210,6 +261,11 @@ func (service BaseService) IsDepositActive(assetSymbol, network string, reposito
 		return false, nil
 	}
 
+	// a maintenance window in progress pauses the operation with its expected end
+	if err := CheckMaintenance(repository, assetSymbol, network, model.MaintenanceScope.DEPOSIT); err != nil {
+		return false, err
+	}
+
 	return true, nil
 }
 
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/utility"
+	"wallet-adapter/utility/hdwallet"
+
+	"github.com/spf13/viper"
+)
+
+// external chain of a BIP44 style account, change addresses are never handed out for deposits
+const externalChain = 0
+
+var (
+	// bitcoin style chains by SLIP-44 coin type
+	xpubChainParams = map[int64]hdwallet.ChainParams{
+		0:   {PubKeyHashPrefix: 0x00, ScriptHashPrefix: 0x05, Bech32HRP: "bc"},
+		2:   {PubKeyHashPrefix: 0x30, ScriptHashPrefix: 0x32, Bech32HRP: "ltc"},
+		145: {PubKeyHashPrefix: 0x00, ScriptHashPrefix: 0x05, CashAddrPrefix: "bitcoincash"},
+	}
+	// account based chains by SLIP-44 coin type
+	xpubAccountFormats = map[int64]hdwallet.AddressFormat{
+		60:       hdwallet.Ethereum,
+		20000714: hdwallet.Ethereum,
+		195:      hdwallet.Tron,
+	}
+)
+
+// GetAccountXpub ... Returns the account xpub configured for an asset network, multi address networks configure one per address type
+func GetAccountXpub(assetSymbol, network, addressType string) string {
+	if addressType != "" {
+		return viper.GetString(fmt.Sprintf("HDXPUB.%s_%s_%s", assetSymbol, network, strings.ToUpper(addressType)))
+	}
+	return viper.GetString(fmt.Sprintf("HDXPUB.%s_%s", assetSymbol, network))
+}
+
+// GetXpubAddressFormat ... Resolves the address encoding of a network, bitcoin style chains follow the address type or the configured BIP purpose (44, 49 or 84)
+func GetXpubAddressFormat(networkAsset dto.NetworkAsset, addressType string) (hdwallet.AddressFormat, hdwallet.ChainParams, error) {
+	if format, ok := xpubAccountFormats[networkAsset.CoinType]; ok {
+		return format, hdwallet.ChainParams{}, nil
+	}
+	params, ok := xpubChainParams[networkAsset.CoinType]
+	if !ok {
+		return "", params, fmt.Errorf("xpub derivation is not supported for coin type %d", networkAsset.CoinType)
+	}
+
+	switch addressType {
+	case utility.ADDRESS_TYPE_SEGWIT:
+		return hdwallet.P2WPKH, params, nil
+	case utility.ADDRESS_TYPE_QADDRESS:
+		return hdwallet.CashAddr, params, nil
+	case utility.ADDRESS_TYPE_LEGACY:
+		return hdwallet.P2PKH, params, nil
+	}
+
+	switch viper.GetInt64(fmt.Sprintf("HDPURPOSE.%s_%s", networkAsset.AssetSymbol, networkAsset.Network)) {
+	case 84:
+		return hdwallet.P2WPKH, params, nil
+	case 49:
+		return hdwallet.P2SHP2WPKH, params, nil
+	}
+	if params.CashAddrPrefix != "" {
+		return hdwallet.CashAddr, params, nil
+	}
+	return hdwallet.P2PKH, params, nil
+}
+
+// DeriveXpubAddress ... Derives the next unused deposit address of the account xpub, returns the address and its index on the external chain
+func DeriveXpubAddress(repository database.IUserAssetRepository, networkAsset dto.NetworkAsset, addressType string) (string, int64, error) {
+	accountXpub := GetAccountXpub(networkAsset.AssetSymbol, networkAsset.Network, addressType)
+	if accountXpub == "" {
+		return "", 0, fmt.Errorf("no account xpub configured for assetSymbol : %s, network : %s and addressType : %s", networkAsset.AssetSymbol, networkAsset.Network, addressType)
+	}
+	accountKey, err := hdwallet.ParseExtendedKey(accountXpub)
+	if err != nil {
+		return "", 0, err
+	}
+	format, params, err := GetXpubAddressFormat(networkAsset, addressType)
+	if err != nil {
+		return "", 0, err
+	}
+
+	for {
+		// indexes are reserved per xpub so assets sharing an account never reuse an index
+		index, err := repository.AllocateDerivationIndex(accountXpub)
+		if err != nil {
+			return "", 0, err
+		}
+		if index >= int64(hdwallet.HardenedKeyStart) {
+			return "", 0, errors.New("account xpub has exhausted its non-hardened indexes")
+		}
+		childKey, err := accountKey.Derive(externalChain, uint32(index))
+		if err == hdwallet.ErrInvalidChild {
+			continue
+		}
+		if err != nil {
+			return "", 0, err
+		}
+		address, err := hdwallet.EncodeAddress(childKey, format, params)
+		if err != nil {
+			return "", 0, err
+		}
+		return address, index, nil
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+)
+
+const simulateFloatCommand = "simulate-float"
+
+// simulateFloat replays the float history of an asset against the current float parameters and the candidates in a JSON file,
+// then prints the simulation report :
+// service simulate-float -asset BTC -network BTC [-from 2021-08-01] [-to 2021-08-31] [-params candidates.json]
+func simulateFloat(args []string, config Config.Data, logger *utility.Logger, Database database.Database) {
+	flags := flag.NewFlagSet(simulateFloatCommand, flag.ExitOnError)
+	assetSymbol := flags.String("asset", "", "symbol of the simulated float asset")
+	network := flags.String("network", "", "network of the simulated float asset")
+	from := flags.String("from", time.Now().AddDate(0, -1, 0).Format("2006-01-02"), "first day of the replayed history")
+	to := flags.String("to", time.Now().Format("2006-01-02"), "last day of the replayed history")
+	paramsFile := flags.String("params", "", "JSON file with the list of candidate float parameter sets")
+	if err := flags.Parse(args); err != nil {
+		log.Fatalf("Invalid %s arguments : %s", simulateFloatCommand, err)
+	}
+	if *assetSymbol == "" || *network == "" {
+		log.Fatalf("%s needs -asset and -network", simulateFloatCommand)
+	}
+
+	request := dto.FloatSimulationRequest{AssetSymbol: *assetSymbol, Network: *network}
+	var err error
+	if request.From, err = time.Parse("2006-01-02", *from); err != nil {
+		log.Fatalf("Invalid -from date : %s", err)
+	}
+	if request.To, err = time.Parse("2006-01-02", *to); err != nil {
+		log.Fatalf("Invalid -to date : %s", err)
+	}
+	// the last day is replayed in full
+	request.To = request.To.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	if *paramsFile != "" {
+		content, err := ioutil.ReadFile(*paramsFile)
+		if err != nil {
+			log.Fatalf("Candidate float parameters could not be read : %s", err)
+		}
+		if err := json.Unmarshal(content, &request.ParamSets); err != nil {
+			log.Fatalf("Candidate float parameters could not be parsed : %s", err)
+		}
+	}
+
+	report, err := tasks.SimulateFloat(logger, database.BaseRepository{Database: Database}, request)
+	if err != nil {
+		log.Fatalf("Float simulation failed : %s", err)
+	}
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatalf("Float simulation report could not be printed : %s", err)
+	}
+	fmt.Println(string(output))
+}
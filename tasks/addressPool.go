@@ -0,0 +1,55 @@
+package tasks
+
+import (
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/robfig/cron/v3"
+)
+
+// RefillAddressPools ... Tops up every configured network address pool that has fallen below its low-water mark
+func RefillAddressPools(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, userAssetRepository database.UserAssetRepository) {
+	logger.Info("Address pool refill begins")
+
+	var networks []model.Network
+	if err := userAssetRepository.Fetch(&networks); err != nil {
+		logger.Error("Error response from address pool refill : could not fetch networks %+v", err)
+		return
+	}
+	poolDepth, err := services.GetAddressPoolDepth(&userAssetRepository)
+	if err != nil {
+		logger.Error("Error response from address pool refill : could not fetch pool depth %+v", err)
+		return
+	}
+	availableDepth := map[string]int64{}
+	for _, pool := range poolDepth {
+		availableDepth[pool.AssetSymbol+utility.SEPERATOR+pool.Network] = pool.Available
+		logger.Info("Address pool depth for assetSymbol : %s and network : %s : available %d, assigned %d, low-water mark %d", pool.AssetSymbol, pool.Network, pool.Available, pool.Assigned, pool.LowWaterMark)
+	}
+
+	service := services.BaseService{Config: config, Cache: cache, Logger: logger}
+	for _, network := range networks {
+		if !services.IsPoolableNetwork(network) {
+			continue
+		}
+		generated, err := service.RefillAddressPool(&userAssetRepository, network, availableDepth[network.AssetSymbol+utility.SEPERATOR+network.Network])
+		if err != nil {
+			logger.Error("Error refilling address pool for assetSymbol : %s and network : %s after %d addresses : %+v", network.AssetSymbol, network.Network, generated, err)
+			continue
+		}
+		if generated > 0 {
+			logger.Info("Address pool for assetSymbol : %s and network : %s refilled with %d addresses", network.AssetSymbol, network.Network, generated)
+		}
+	}
+
+	logger.Info("Address pool refill ends")
+}
+
+func ExecuteAddressPoolCronJob(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, userAssetRepository database.UserAssetRepository) {
+	c := cron.New()
+	c.AddFunc(config.AddressPoolCronInterval, func() { RefillAddressPools(cache, logger, config, userAssetRepository) })
+	c.Start()
+}
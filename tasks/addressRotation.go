@@ -0,0 +1,42 @@
+package tasks
+
+import (
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/robfig/cron/v3"
+)
+
+// RotateDepositAddresses ... Retires primary deposit addresses that have outlived their network rotation window,
+// replacements are issued and subscribed on the next address request
+func RotateDepositAddresses(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, userAssetRepository database.UserAssetRepository) {
+	logger.Info("Address rotation begins")
+
+	var networks []model.Network
+	if err := userAssetRepository.Fetch(&networks); err != nil {
+		logger.Error("Error response from address rotation : could not fetch networks %+v", err)
+		return
+	}
+
+	for _, network := range networks {
+		retired, err := services.RotateExpiredAddresses(&userAssetRepository, network)
+		if err != nil {
+			logger.Error("Error rotating addresses for assetSymbol : %s and network : %s : %+v", network.AssetSymbol, network.Network, err)
+			continue
+		}
+		if retired > 0 {
+			logger.Info("Address rotation for assetSymbol : %s and network : %s retired %d addresses older than %d hours", network.AssetSymbol, network.Network, retired, network.RotationWindowHours)
+		}
+	}
+
+	logger.Info("Address rotation ends")
+}
+
+func ExecuteAddressRotationCronJob(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, userAssetRepository database.UserAssetRepository) {
+	c := cron.New()
+	c.AddFunc(config.AddressRotationCronInterval, func() { RotateDepositAddresses(cache, logger, config, userAssetRepository) })
+	c.Start()
+}
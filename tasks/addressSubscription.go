@@ -0,0 +1,30 @@
+package tasks
+
+import (
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ReconcileAddressSubscriptions ... Re-subscribes user addresses whose crypto-adapter subscription is pending or failed
+func ReconcileAddressSubscriptions(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, userAssetRepository database.UserAssetRepository) {
+	logger.Info("Address subscription reconciliation begins")
+
+	service := services.BaseService{Config: config, Cache: cache, Logger: logger}
+	reconciliation, err := service.ReconcileAddressSubscriptions(&userAssetRepository)
+	if err != nil {
+		logger.Error("Error response from address subscription reconciliation : %+v", err)
+		return
+	}
+
+	logger.Info("Address subscription reconciliation ends : attempted %d, subscribed %d, failed %d", reconciliation.Attempted, reconciliation.Subscribed, reconciliation.Failed)
+}
+
+func ExecuteSubscriptionReconcilerCronJob(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, userAssetRepository database.UserAssetRepository) {
+	c := cron.New()
+	c.AddFunc(config.SubscriptionReconcilerCronInterval, func() { ReconcileAddressSubscriptions(cache, logger, config, userAssetRepository) })
+	c.Start()
+}
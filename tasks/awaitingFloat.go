@@ -0,0 +1,202 @@
+package tasks
+
+import (
+	"math/big"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ParkedWithdrawal ... A single withdrawal, or a batch of withdrawals, waiting for the float of its asset to cover it
+type ParkedWithdrawal struct {
+	AssetSymbol    string
+	Network        string
+	BatchID        uuid.UUID
+	QueueIDs       []uuid.UUID
+	TransactionIDs []uuid.UUID
+	Amount         *big.Int
+	QueuedAt       time.Time
+}
+
+// GroupParkedWithdrawals ... Groups queued withdrawals of one asset into single withdrawals and batches, in the order they were
+// queued. A batch takes the place of its first queued withdrawal
+func GroupParkedWithdrawals(queuedTransactions []model.TransactionQueue) []ParkedWithdrawal {
+	parked := []ParkedWithdrawal{}
+	batchIndexes := map[uuid.UUID]int{}
+	for _, queuedTransaction := range queuedTransactions {
+		if index, ok := batchIndexes[queuedTransaction.BatchID]; ok && queuedTransaction.BatchID != uuid.Nil {
+			parked[index].QueueIDs = append(parked[index].QueueIDs, queuedTransaction.ID)
+			parked[index].TransactionIDs = append(parked[index].TransactionIDs, queuedTransaction.TransactionId)
+			parked[index].Amount.Add(parked[index].Amount, queuedTransaction.Value.BigInt())
+			continue
+		}
+		if queuedTransaction.BatchID != uuid.Nil {
+			batchIndexes[queuedTransaction.BatchID] = len(parked)
+		}
+		parked = append(parked, ParkedWithdrawal{
+			AssetSymbol:    queuedTransaction.AssetSymbol,
+			Network:        queuedTransaction.Network,
+			BatchID:        queuedTransaction.BatchID,
+			QueueIDs:       []uuid.UUID{queuedTransaction.ID},
+			TransactionIDs: []uuid.UUID{queuedTransaction.TransactionId},
+			Amount:         queuedTransaction.Value.BigInt(),
+			QueuedAt:       queuedTransaction.CreatedAt,
+		})
+	}
+	return parked
+}
+
+// ResumableWithdrawals ... Returns the parked withdrawals the float balance covers, first in first out. It stops at the first
+// withdrawal the balance falls short of, so later and smaller withdrawals cannot jump the queue
+func ResumableWithdrawals(floatBalance *big.Int, parked []ParkedWithdrawal) []ParkedWithdrawal {
+	available := new(big.Int).Set(floatBalance)
+	resumable := []ParkedWithdrawal{}
+	for _, withdrawal := range parked {
+		if available.Cmp(withdrawal.Amount) < 0 {
+			break
+		}
+		available.Sub(available, withdrawal.Amount)
+		resumable = append(resumable, withdrawal)
+	}
+	return resumable
+}
+
+// GetAwaitingFloatAssets ... Returns the asset and network pairs, joined by the cache separator, that have withdrawals waiting for float,
+// with when the oldest of them was queued
+func GetAwaitingFloatAssets(repository database.BaseRepository) (map[string]time.Time, error) {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	queuedTransactions := []model.TransactionQueue{}
+	if err := userAssetRepository.FetchQueuedTransactionsByStatus(model.TransactionStatus.AWAITING_FLOAT, &queuedTransactions); err != nil {
+		return nil, err
+	}
+	awaiting := map[string]time.Time{}
+	for _, queuedTransaction := range queuedTransactions {
+		key := queuedTransaction.AssetSymbol + utility.SEPERATOR + queuedTransaction.Network
+		if _, ok := awaiting[key]; !ok {
+			awaiting[key] = queuedTransaction.CreatedAt
+		}
+	}
+	return awaiting, nil
+}
+
+// IsBehindAwaitingFloat ... Tells whether a withdrawal queued at the time has to wait behind withdrawals of its asset parked for want of float.
+// Withdrawals queued before the oldest parked one, such as those just resumed, keep their place
+func IsBehindAwaitingFloat(awaitingFloatAssets map[string]time.Time, assetSymbol, network string, queuedAt time.Time) bool {
+	oldestParkedAt, ok := awaitingFloatAssets[assetSymbol+utility.SEPERATOR+network]
+	return ok && !queuedAt.Before(oldestParkedAt)
+}
+
+// ResumeAwaitingFloatWithdrawals ... Puts withdrawals parked for want of float back in the queue, per asset and first in first out,
+// as far as the on-chain balance of the hot wallet covers them. Single withdrawals go back to PENDING and batches to START_MODE,
+// to be sent on the next processing run
+func ResumeAwaitingFloatWithdrawals(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository) {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	queuedTransactions := []model.TransactionQueue{}
+	if err := userAssetRepository.FetchQueuedTransactionsByStatus(model.TransactionStatus.AWAITING_FLOAT, &queuedTransactions); err != nil {
+		logger.Error("Awaiting float : could not get the withdrawals waiting for float : %s", err)
+		return
+	}
+
+	assets := []string{}
+	queuedByAsset := map[string][]model.TransactionQueue{}
+	for _, queuedTransaction := range queuedTransactions {
+		key := queuedTransaction.AssetSymbol + utility.SEPERATOR + queuedTransaction.Network
+		if _, ok := queuedByAsset[key]; !ok {
+			assets = append(assets, key)
+		}
+		queuedByAsset[key] = append(queuedByAsset[key], queuedTransaction)
+	}
+
+	for _, key := range assets {
+		parked := GroupParkedWithdrawals(queuedByAsset[key])
+		assetSymbol, network := parked[0].AssetSymbol, parked[0].Network
+
+		floatAccount := model.HotWalletAsset{}
+		if err := repository.GetByFieldName(&model.HotWalletAsset{AssetSymbol: assetSymbol, Network: network}, &floatAccount); err != nil {
+			logger.Error("Awaiting float : could not get the hot wallet of %s on %s : %s", assetSymbol, network, err)
+			continue
+		}
+		onchainBalanceResponse := dto.OnchainBalanceResponse{}
+		if err := services.GetOnchainBalance(cache, logger, config, dto.OnchainBalanceRequest{AssetSymbol: assetSymbol, Network: network, Address: floatAccount.Address},
+			&onchainBalanceResponse, dto.ServicesRequestErr{}); err != nil {
+			logger.Error("Awaiting float : could not get the float balance of %s on %s : %s", assetSymbol, network, err)
+			continue
+		}
+		balance, ok := new(big.Float).SetString(onchainBalanceResponse.Balance)
+		if !ok {
+			logger.Error("Awaiting float : invalid float balance %s for %s on %s", onchainBalanceResponse.Balance, assetSymbol, network)
+			continue
+		}
+		floatBalance, _ := balance.Int(nil)
+
+		resumable := ResumableWithdrawals(floatBalance, parked)
+		for _, withdrawal := range resumable {
+			if err := resumeParkedWithdrawal(repository, withdrawal); err != nil {
+				logger.Error("Awaiting float : could not resume the withdrawals %+v of %s on %s : %s", withdrawal.TransactionIDs, assetSymbol, network, err)
+				break
+			}
+		}
+		logger.Info("Awaiting float : resumed %d of %d parked withdrawals of %s on %s, float balance %s", len(resumable), len(parked), assetSymbol, network, floatBalance)
+	}
+}
+
+// resumeParkedWithdrawal puts a parked withdrawal back to PENDING. Only withdrawals still waiting for float are resumed, a withdrawal of
+// the batch in any other status, such as one held for screening review, keeps its status and is not sent with the batch
+func resumeParkedWithdrawal(repository database.BaseRepository, withdrawal ParkedWithdrawal) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.TransactionQueue{}).Where("id IN (?) AND transaction_status = ?", withdrawal.QueueIDs, model.TransactionStatus.AWAITING_FLOAT).
+			Updates(model.TransactionQueue{TransactionStatus: model.TransactionStatus.PENDING}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Transaction{}).Where("id IN (?) AND transaction_status = ?", withdrawal.TransactionIDs, model.TransactionStatus.AWAITING_FLOAT).
+			Updates(model.Transaction{TransactionStatus: model.TransactionStatus.PENDING}).Error; err != nil {
+			return err
+		}
+		if withdrawal.BatchID != uuid.Nil {
+			return tx.Model(&model.BatchRequest{BaseModel: model.BaseModel{ID: withdrawal.BatchID}}).Updates(model.BatchRequest{Status: model.BatchStatus.START_MODE}).Error
+		}
+		return nil
+	})
+}
+
+// EstimateFloatTopUp ... Estimates when the float of an asset will be topped up from its open funding request, as the time the
+// request was opened plus the average time past requests of the asset took to be fulfilled. Without fulfilled requests to go by,
+// or once that time has passed, the request's expiry is the estimate. It returns nil when no funding request is open
+func EstimateFloatTopUp(repository database.BaseRepository, assetSymbol, network string) (*time.Time, error) {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	openRequests := []model.FundingRequest{}
+	if err := userAssetRepository.FetchFundingRequests(assetSymbol, network, []string{model.FundingRequestStatus.OPEN, model.FundingRequestStatus.ACKNOWLEDGED}, &openRequests); err != nil {
+		return nil, err
+	}
+	if len(openRequests) == 0 {
+		return nil, nil
+	}
+	fulfilledRequests := []model.FundingRequest{}
+	if err := userAssetRepository.FetchFundingRequests(assetSymbol, network, []string{model.FundingRequestStatus.FULFILLED}, &fulfilledRequests); err != nil {
+		return nil, err
+	}
+
+	openRequest := openRequests[0]
+	estimate := openRequest.ExpiresAt
+	var total time.Duration
+	count := 0
+	for _, fulfilledRequest := range fulfilledRequests {
+		if fulfilledRequest.FulfilledAt != nil {
+			total += fulfilledRequest.FulfilledAt.Sub(fulfilledRequest.CreatedAt)
+			count++
+		}
+	}
+	if count > 0 {
+		if average := openRequest.CreatedAt.Add(total / time.Duration(count)); average.After(time.Now()) {
+			estimate = average
+		}
+	}
+	return &estimate, nil
+}
@@ -0,0 +1,81 @@
+package tasks
+
+import (
+	"fmt"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/robfig/cron/v3"
+)
+
+// CloseDueBatches ... Closes the waiting batches that are full or have reached their maximum age under the batch policy of their asset,
+// recording why, and opens a new waiting batch for the asset. Batches closing on schedule are left to the batch processing run
+func CloseDueBatches(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, batchRepository database.BatchRepository) {
+	logger.Info("Batcher process begins")
+
+	batchService := services.BatchService{BaseService: services.BaseService{Config: config, Cache: cache, Logger: logger}}
+	waitingBatches := []model.BatchRequest{}
+	if err := batchRepository.FetchByFieldName(&model.BatchRequest{Status: model.BatchStatus.WAIT_MODE}, &waitingBatches); err != nil {
+		logger.Error("Batcher : could not get the waiting batches : %s", err)
+		return
+	}
+
+	for _, batch := range waitingBatches {
+		closeReason, err := batchService.ReviewWaitingBatch(&batchRepository, batch, time.Now(), false)
+		if err != nil {
+			logger.Error("Batcher : could not review batch %s of %s on %s : %s", batch.ID, batch.AssetSymbol, batch.Network, err)
+			continue
+		}
+		if closeReason == "" {
+			continue
+		}
+
+		// the batch processing run holds the same lock while it works on a batch
+		lockerServiceToken, err := AcquireLock(batch.ID.String(), utility.SIX_HUNDRED_MILLISECONDS, cache, logger, config, dto.ServicesRequestErr{})
+		if err != nil {
+			continue
+		}
+		// the batch may have been closed and sent while the lock was taken
+		if err := batchRepository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: batch.ID}}, &batch); err != nil {
+			logger.Error("Batcher : could not get batch %s : %s", batch.ID, err)
+			releaseBatchLock(cache, logger, config, batch.ID.String(), lockerServiceToken)
+			continue
+		}
+		if batch.Status != model.BatchStatus.WAIT_MODE {
+			releaseBatchLock(cache, logger, config, batch.ID.String(), lockerServiceToken)
+			continue
+		}
+		if err := batchService.CloseBatch(&batchRepository, &batch, closeReason, ""); err != nil {
+			releaseBatchLock(cache, logger, config, batch.ID.String(), lockerServiceToken)
+			continue
+		}
+		releaseBatchLock(cache, logger, config, batch.ID.String(), lockerServiceToken)
+
+		if err := batchService.OpenWaitingBatch(&batchRepository, batch.AssetSymbol, batch.Network); err != nil {
+			logger.Error("Batcher : could not open a new batch for %s on %s : %s", batch.AssetSymbol, batch.Network, err)
+		}
+	}
+
+	logger.Info("Batcher process ends")
+}
+
+func releaseBatchLock(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, identifier, lockerServiceToken string) {
+	lockReleaseRequest := dto.LockReleaseRequest{
+		Identifier: fmt.Sprintf("%s%s", config.LockerPrefix, identifier),
+		Token:      lockerServiceToken,
+	}
+	if err := services.ReleaseLock(cache, logger, config, lockReleaseRequest, &dto.ServicesRequestSuccess{}, &dto.ServicesRequestErr{}); err != nil {
+		logger.Error("Batcher : could not release the lock of batch %s : %s", identifier, err)
+	}
+}
+
+func ExecuteBatcherCronJob(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, batchRepository database.BatchRepository) {
+	c := cron.New()
+	c.AddFunc(config.BatcherCronInterval, func() { CloseDueBatches(cache, logger, config, batchRepository) })
+	c.Start()
+}
@@ -0,0 +1,231 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// GetSurplusColdWallet ... Returns the verified cold wallet taking a share of the float surplus of an asset, if any
+func GetSurplusColdWallet(repository database.BaseRepository, assetSymbol, network string) (model.ColdWallet, bool, error) {
+	coldWallets := []model.ColdWallet{}
+	if err := repository.FetchByFieldName(&model.ColdWallet{AssetSymbol: assetSymbol, Network: network, IsVerified: true}, &coldWallets); err != nil {
+		return model.ColdWallet{}, false, err
+	}
+	for _, coldWallet := range coldWallets {
+		if coldWallet.SurplusPercent > 0 {
+			return coldWallet, true, nil
+		}
+	}
+	return model.ColdWallet{}, false, nil
+}
+
+// GetColdShare ... Returns the part of the float surplus, in the smallest unit of the asset, sent to a cold wallet.
+// It is the wallet's share of the surplus, capped at its maximum transfer amount when one is set
+func GetColdShare(coldWallet model.ColdWallet, floatSurplus *big.Int, decimals int) *big.Int {
+	coldShare := new(big.Int).Mul(floatSurplus, big.NewInt(coldWallet.SurplusPercent))
+	coldShare.Quo(coldShare, big.NewInt(100))
+	if coldWallet.MaxTransferAmount > 0 {
+		maxTransfer, _ := new(big.Float).Mul(big.NewFloat(coldWallet.MaxTransferAmount), big.NewFloat(math.Pow(10, float64(decimals)))).Int(nil)
+		if coldShare.Cmp(maxTransfer) > 0 {
+			coldShare = maxTransfer
+		}
+	}
+	return coldShare
+}
+
+// ColdTransferNeedsApproval ... Transfers above the approval threshold of a wallet requiring approval wait for it before they are sent
+func ColdTransferNeedsApproval(coldWallet model.ColdWallet, amount *big.Float) bool {
+	return coldWallet.RequiresApproval && amount.Cmp(big.NewFloat(coldWallet.ApprovalThreshold)) > 0
+}
+
+// transferSurplusToCold sends the cold wallet's share of the float surplus, or records it for approval, and returns what is left for the brokerage.
+// While a transfer waits for approval no other is created for the asset, the share stays in the float until it is reviewed
+func transferSurplusToCold(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, floatAccount model.HotWalletAsset, floatNetworkAsset model.Network, floatSurplus *big.Int) (*big.Int, string) {
+	coldWallet, hasColdWallet, err := GetSurplusColdWallet(repository, floatAccount.AssetSymbol, floatAccount.Network)
+	if err != nil {
+		logger.Error("Float manager : could not get the cold wallet of %s on %s, sending the surplus to the brokerage : %s", floatAccount.AssetSymbol, floatAccount.Network, err)
+		return floatSurplus, ""
+	}
+	if !hasColdWallet {
+		return floatSurplus, ""
+	}
+	coldShare := GetColdShare(coldWallet, floatSurplus, floatNetworkAsset.NativeDecimals)
+	// what a cap keeps from the cold wallet goes to the brokerage
+	brokerageShare := new(big.Int).Sub(floatSurplus, coldShare)
+	if coldShare.Sign() <= 0 {
+		return brokerageShare, ""
+	}
+
+	pendingTransfers := []model.ColdTransfer{}
+	if err := repository.FetchByFieldName(&model.ColdTransfer{AssetSymbol: floatAccount.AssetSymbol, Network: floatAccount.Network, Status: model.ColdTransferStatus.PENDING_APPROVAL}, &pendingTransfers); err != nil {
+		logger.Error("Float manager : could not get the cold transfers waiting for approval for %s : %s", floatAccount.AssetSymbol, err)
+		return brokerageShare, ""
+	}
+	if len(pendingTransfers) > 0 {
+		return brokerageShare, fmt.Sprintf("Cold transfer %s of %s is waiting for approval", pendingTransfers[0].Reference, floatAccount.AssetSymbol)
+	}
+
+	amount := ConvertBigIntToDecimalUnit(*coldShare, floatNetworkAsset)
+	coldTransfer := model.ColdTransfer{
+		ColdWalletID: coldWallet.ID,
+		AssetSymbol:  floatAccount.AssetSymbol,
+		Network:      floatAccount.Network,
+		FromAddress:  floatAccount.Address,
+		ToAddress:    coldWallet.Address,
+		Memo:         coldWallet.Memo,
+		Amount:       amount.Text('f', floatNetworkAsset.NativeDecimals),
+		BaseAmount:   coldShare.String(),
+		Reference:    uuid.NewV1().String(),
+		Status:       model.ColdTransferStatus.PENDING_APPROVAL,
+	}
+	if err := repository.Create(&coldTransfer); err != nil {
+		logger.Error("Float manager : could not record the cold transfer of %s : %s", floatAccount.AssetSymbol, err)
+		return brokerageShare, ""
+	}
+	if ColdTransferNeedsApproval(coldWallet, amount) {
+		logger.Info("Float manager : cold transfer %s of %s %s is waiting for approval", coldTransfer.Reference, coldTransfer.Amount, floatAccount.AssetSymbol)
+		return brokerageShare, fmt.Sprintf("Cold transfer %s of %s %s is waiting for approval", coldTransfer.Reference, coldTransfer.Amount, floatAccount.AssetSymbol)
+	}
+	if err := SendColdTransfer(cache, logger, config, repository, &coldTransfer, ""); err != nil {
+		return brokerageShare, fmt.Sprintf("Cold transfer %s of %s %s failed", coldTransfer.Reference, coldTransfer.Amount, floatAccount.AssetSymbol)
+	}
+
+	params := map[string]string{
+		"amount":             coldTransfer.Amount,
+		"assetSymbol":        floatAccount.AssetSymbol,
+		"depositAddress":     coldWallet.Address,
+		"depositAddressMemo": coldWallet.Memo,
+		"network":            floatAccount.Network,
+		"label":              coldWallet.Label,
+	}
+	_ = notifyColdWalletUsers("ColdStorage", params, config, nil, cache, logger, dto.ServicesRequestErr{})
+	return brokerageShare, fmt.Sprintf("Sent %s %s to cold wallet %s", coldTransfer.Amount, floatAccount.AssetSymbol, coldWallet.Label)
+}
+
+// SendColdTransfer ... Sends a cold transfer from the float, reviewedBy is set when the transfer was approved
+func SendColdTransfer(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, coldTransfer *model.ColdTransfer, reviewedBy string) error {
+	amount, _ := new(big.Int).SetString(coldTransfer.BaseAmount, 10)
+	serviceErr := dto.ServicesRequestErr{}
+	sendSingleTransactionRequest := dto.SendSingleTransactionRequest{
+		FromAddress: coldTransfer.FromAddress,
+		ToAddress:   coldTransfer.ToAddress,
+		Memo:        coldTransfer.Memo,
+		Amount:      amount,
+		AssetSymbol: coldTransfer.AssetSymbol,
+		Network:     coldTransfer.Network,
+		IsSweep:     false,
+		ProcessType: utility.FLOATPROCESS,
+		Reference:   coldTransfer.Reference,
+	}
+	update := model.ColdTransfer{Status: model.ColdTransferStatus.SENT, ReviewedBy: reviewedBy}
+	if reviewedBy != "" {
+		reviewedAt := time.Now()
+		update.ReviewedAt = &reviewedAt
+	}
+	sendSingleTransactionResponse := dto.SendTransactionResponse{}
+	sendErr := services.SendSingleTransaction(cache, logger, config, sendSingleTransactionRequest, &sendSingleTransactionResponse, &serviceErr)
+	if sendErr != nil {
+		logger.Error("Error response from float manager : %+v. While sending cold transfer %s of %s", sendErr, coldTransfer.Reference, coldTransfer.AssetSymbol)
+		update.Status, update.Error = model.ColdTransferStatus.FAILED, sendErr.Error()
+	}
+	update.TransactionHash = sendSingleTransactionResponse.TransactionHash
+	if err := repository.Update(coldTransfer, &update); err != nil {
+		logger.Error("Float manager : could not update cold transfer %s : %s", coldTransfer.Reference, err)
+		return err
+	}
+	return sendErr
+}
+
+// GetFloatSurplus ... Returns how far the on-chain float balance of an asset is above its maximum float balance, in the smallest unit of
+// the asset. The maximum is worked out from the current float parameters and user balances, in FORECAST mode it is raised to the target
+// forecast on the last float manager run
+func GetFloatSurplus(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, floatAccount model.HotWalletAsset, floatNetworkAsset model.Network) (*big.Int, error) {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	floatManagerParams, err := getFloatParamFor(floatAccount.AssetSymbol, floatAccount.Network, repository, logger)
+	if err != nil {
+		return nil, err
+	}
+	onchainBalanceResponse := dto.OnchainBalanceResponse{}
+	if err := services.GetOnchainBalance(cache, logger, config, dto.OnchainBalanceRequest{AssetSymbol: floatAccount.AssetSymbol, Network: floatAccount.Network, Address: floatAccount.Address},
+		&onchainBalanceResponse, dto.ServicesRequestErr{}); err != nil {
+		return nil, err
+	}
+	floatOnChainBalance, ok := new(big.Float).SetString(onchainBalanceResponse.Balance)
+	if !ok {
+		return nil, fmt.Errorf("invalid float balance %s for %s on %s", onchainBalanceResponse.Balance, floatAccount.AssetSymbol, floatAccount.Network)
+	}
+	totalUserBalance, err := GetTotalUserBalance(repository, floatAccount.AssetSymbol, floatNetworkAsset.NativeDecimals, logger, userAssetRepository)
+	if err != nil {
+		return nil, err
+	}
+	maxUserBalance, err := GetMaxUserBalanceFor(userAssetRepository, floatAccount.AssetSymbol)
+	if err != nil {
+		return nil, err
+	}
+	maximumFloatBalance := GetMaxFloatBalance(floatManagerParams, logger, totalUserBalance, maxUserBalance)
+	if floatManagerParams.TargetMode == model.FloatTargetMode.FORECAST {
+		lastRun := model.FloatManager{}
+		if err := repository.DB.Where("asset_symbol = ? AND network = ?", floatAccount.AssetSymbol, floatAccount.Network).Order("created_at desc").First(&lastRun).Error; err != nil && !gorm.IsRecordNotFoundError(err) {
+			return nil, err
+		}
+		maximumFloatBalance = utility.MaxFloat(maximumFloatBalance, big.NewFloat(lastRun.ForecastTarget))
+	}
+
+	floatSurplus, _ := new(big.Float).Sub(floatOnChainBalance, maximumFloatBalance).Int(nil)
+	if floatSurplus.Sign() < 0 {
+		return big.NewInt(0), nil
+	}
+	return floatSurplus, nil
+}
+
+// PrepareApprovedColdTransfer ... Checks a cold transfer again when it is approved, as the float may have changed since it was created.
+// Nothing is sent to a cold wallet that is no longer verified, and the amount is cut down to the wallet's share of the float surplus at
+// approval time. It never goes above the amount that was approved
+func PrepareApprovedColdTransfer(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, coldTransfer *model.ColdTransfer) error {
+	coldWallet := model.ColdWallet{}
+	if err := repository.Get(&model.ColdWallet{BaseModel: model.BaseModel{ID: coldTransfer.ColdWalletID}}, &coldWallet); err != nil {
+		return err
+	}
+	if !coldWallet.IsVerified || coldWallet.Address != coldTransfer.ToAddress {
+		return errors.New(errorcode.COLD_WALLET_NOT_VERIFIED)
+	}
+
+	floatAccount := model.HotWalletAsset{}
+	if err := repository.GetByFieldName(&model.HotWalletAsset{AssetSymbol: coldTransfer.AssetSymbol, Network: coldTransfer.Network}, &floatAccount); err != nil {
+		return err
+	}
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	floatNetworkAsset, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, coldTransfer.Network, coldTransfer.AssetSymbol)
+	if err != nil {
+		return err
+	}
+	floatSurplus, err := GetFloatSurplus(cache, logger, config, repository, floatAccount, floatNetworkAsset)
+	if err != nil {
+		return err
+	}
+	coldShare := GetColdShare(coldWallet, floatSurplus, floatNetworkAsset.NativeDecimals)
+	if coldShare.Sign() <= 0 {
+		return errors.New(errorcode.COLD_TRANSFER_NO_SURPLUS)
+	}
+	approvedAmount, _ := new(big.Int).SetString(coldTransfer.BaseAmount, 10)
+	if approvedAmount == nil || coldShare.Cmp(approvedAmount) >= 0 {
+		return nil
+	}
+
+	amount := ConvertBigIntToDecimalUnit(*coldShare, floatNetworkAsset)
+	logger.Info("Float manager : cold transfer %s of %s cut from %s to %s, the float surplus went down since it was created", coldTransfer.Reference, coldTransfer.AssetSymbol, coldTransfer.BaseAmount, coldShare)
+	return repository.Update(coldTransfer, &model.ColdTransfer{Amount: amount.Text('f', floatNetworkAsset.NativeDecimals), BaseAmount: coldShare.String()})
+}
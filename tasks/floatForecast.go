@@ -0,0 +1,153 @@
+package tasks
+
+import (
+	"math"
+	"math/big"
+	"sort"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+)
+
+const (
+	defaultFloatForecastWindowDays   = 28
+	defaultFloatForecastHorizonHours = 24
+	floatForecastPercentile          = 0.95
+)
+
+// FloatForecast ... The withdrawals a float is expected to pay before it is topped up again, in native units. HasHistory is false
+// when there were no float manager runs in the window to measure the withdrawal velocity over
+type FloatForecast struct {
+	HourlyOutflow      float64
+	Seasonality        float64
+	PendingWithdrawals float64
+	Target             float64
+	HasHistory         bool
+}
+
+// outflowSample is the hourly withdrawal rate between two float manager runs, dated by the later run
+type outflowSample struct {
+	at   time.Time
+	rate float64
+}
+
+// GetFloatForecastWindowDays ... Returns how many days of float manager runs the withdrawal velocity is measured over
+func GetFloatForecastWindowDays(config Config.Data) int {
+	if config.FloatForecastWindowDays <= 0 {
+		return defaultFloatForecastWindowDays
+	}
+	return config.FloatForecastWindowDays
+}
+
+// GetFloatForecastHorizonHours ... Returns how many hours of withdrawals a forecast float target covers
+func GetFloatForecastHorizonHours(config Config.Data) int {
+	if config.FloatForecastHorizonHours <= 0 {
+		return defaultFloatForecastHorizonHours
+	}
+	return config.FloatForecastHorizonHours
+}
+
+// ForecastFloatDemand ... Forecasts the withdrawals the float of an asset has to cover over the horizon. The hourly outflow is the p95
+// of the withdrawal sums of the float manager runs in the window divided by the hours between runs, including the run in progress.
+// It is scaled by how busy the weekdays of the horizon are compared to the window, and the withdrawals still queued or waiting for float are added on top
+func ForecastFloatDemand(config Config.Data, repository database.BaseRepository, floatAccount model.HotWalletAsset, now time.Time, withdrawalSumFromLastRun *big.Float) (FloatForecast, error) {
+	forecast := FloatForecast{Seasonality: 1}
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+
+	runs := []model.FloatManager{}
+	from := now.AddDate(0, 0, -GetFloatForecastWindowDays(config))
+	if err := userAssetRepository.FetchFloatHistory(floatAccount.AssetSymbol, floatAccount.Network, from, now, &runs); err != nil {
+		return forecast, err
+	}
+	samples := []outflowSample{}
+	for i := 1; i < len(runs); i++ {
+		samples = appendOutflowSample(samples, runs[i-1].CreatedAt, runs[i].CreatedAt, runs[i].WithdrawalSum)
+	}
+	if len(runs) > 0 && withdrawalSumFromLastRun != nil {
+		withdrawalSum, _ := withdrawalSumFromLastRun.Float64()
+		samples = appendOutflowSample(samples, runs[len(runs)-1].CreatedAt, now, withdrawalSum)
+	}
+
+	// withdrawals parked for want of float are still to be paid
+	for _, status := range []string{model.TransactionStatus.PENDING, model.TransactionStatus.AWAITING_FLOAT} {
+		queuedWithdrawals := []model.TransactionQueue{}
+		if err := repository.FetchByFieldName(&model.TransactionQueue{AssetSymbol: floatAccount.AssetSymbol, Network: floatAccount.Network, TransactionStatus: status}, &queuedWithdrawals); err != nil {
+			return forecast, err
+		}
+		for _, queuedWithdrawal := range queuedWithdrawals {
+			value, _ := queuedWithdrawal.Value.Float64()
+			forecast.PendingWithdrawals += value
+		}
+	}
+
+	horizonHours := GetFloatForecastHorizonHours(config)
+	forecast.HasHistory = len(samples) > 0
+	forecast.HourlyOutflow = outflowPercentile(samples, floatForecastPercentile)
+	forecast.Seasonality = weekdaySeasonality(samples, now, horizonHours)
+	forecast.Target = forecast.HourlyOutflow*forecast.Seasonality*float64(horizonHours) + forecast.PendingWithdrawals
+	return forecast, nil
+}
+
+// ApplyFloatForecast ... Returns the float range a forecast sets, its target is the minimum and the maximum is raised to it if needed.
+// A forecast without history keeps the static range, as the target would only cover the queued withdrawals
+func ApplyFloatForecast(forecast FloatForecast, minimumFloatBalance, maximumFloatBalance *big.Float) (*big.Float, *big.Float) {
+	if !forecast.HasHistory {
+		return minimumFloatBalance, maximumFloatBalance
+	}
+	minimumFloatBalance = big.NewFloat(forecast.Target)
+	return minimumFloatBalance, utility.MaxFloat(maximumFloatBalance, minimumFloatBalance)
+}
+
+func appendOutflowSample(samples []outflowSample, from, to time.Time, withdrawalSum float64) []outflowSample {
+	hours := to.Sub(from).Hours()
+	if hours <= 0 {
+		return samples
+	}
+	return append(samples, outflowSample{at: to, rate: withdrawalSum / hours})
+}
+
+// outflowPercentile returns the nearest rank percentile of the hourly rates
+func outflowPercentile(samples []outflowSample, percentile float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	rates := make([]float64, len(samples))
+	for i, sample := range samples {
+		rates[i] = sample.rate
+	}
+	sort.Float64s(rates)
+	rank := int(math.Ceil(percentile*float64(len(rates)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return rates[rank]
+}
+
+// weekdaySeasonality returns the average, over the hours of the horizon, of the mean rate of their weekday relative to the mean rate
+// of the window. Weekdays without samples count as average
+func weekdaySeasonality(samples []outflowSample, now time.Time, horizonHours int) float64 {
+	var total float64
+	weekdayTotals, weekdayCounts := map[time.Weekday]float64{}, map[time.Weekday]int{}
+	for _, sample := range samples {
+		total += sample.rate
+		weekdayTotals[sample.at.Weekday()] += sample.rate
+		weekdayCounts[sample.at.Weekday()]++
+	}
+	if total == 0 || horizonHours <= 0 {
+		return 1
+	}
+	mean := total / float64(len(samples))
+
+	var seasonality float64
+	for hour := 0; hour < horizonHours; hour++ {
+		weekday := now.Add(time.Duration(hour) * time.Hour).Weekday()
+		if weekdayCounts[weekday] == 0 {
+			seasonality++
+			continue
+		}
+		seasonality += weekdayTotals[weekday] / float64(weekdayCounts[weekday]) / mean
+	}
+	return seasonality / float64(horizonHours)
+}
@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 	Config "wallet-adapter/config"
 	"wallet-adapter/database"
@@ -30,6 +31,9 @@ func ManageFloat(cache *utility.MemoryCache, logger *utility.Logger, config Conf
 		return
 	}
 
+	// settle earlier funding requests first, so a topped up float can be asked for funds again
+	FollowUpFundingRequests(cache, logger, config, repository)
+
 	floatAccounts, err := GetFloatAccounts(repository, logger)
 	if err != nil {
 		return
@@ -48,6 +52,21 @@ func ManageFloat(cache *utility.MemoryCache, logger *utility.Logger, config Conf
 			logger.Error(fmt.Sprintf("error with getting network asset for assetSymbol : %s and network : %s : %s", floatAccount.AssetSymbol, floatAccount.Network, err))
 			continue
 		}
+		if err := services.CheckMaintenance(&userAssetRepository, floatAccount.AssetSymbol, floatAccount.Network, model.MaintenanceScope.FLOAT); err != nil {
+			logger.Info("Float manager : skipping float management of %s on %s network : %s", floatAccount.AssetSymbol, floatAccount.Network, err)
+			continue
+		}
+
+		// Get float manager parameters to calculate minimum and maximum float range
+		floatManagerParams, err := getFloatParamFor(floatAccount.AssetSymbol, floatAccount.Network, repository, logger)
+		if err != nil {
+			logger.Info("Error getting float manager params : %s", err)
+			continue
+		}
+		if floatManagerParams.IsPaused {
+			logger.Info("Float manager : skipping float management of %s on %s network, paused by %s", floatAccount.AssetSymbol, floatAccount.Network, floatManagerParams.UpdatedBy)
+			continue
+		}
 
 		// Get float chain balance
 		onchainBalanceRequest := dto.OnchainBalanceRequest{
@@ -93,21 +112,25 @@ func ManageFloat(cache *utility.MemoryCache, logger *utility.Logger, config Conf
 		}
 		logger.Info("maximum user balanace for asset %s is %+v", floatAccount.AssetSymbol, maxUserBalance)
 
-		// Get float manager parameters to calculate minimum and maximum float range
-		floatManagerParams, err := getFloatParamFor(floatAccount.AssetSymbol, floatAccount.Network, repository, logger)
-		if err != nil {
-			logger.Info("Error getting float manager params : %s", err)
-			continue
-		}
-
 		// GetMinimum
 		minimumFloatBalance := GetMinFloatBalance(floatManagerParams, logger, totalUserBalance, maxUserBalance)
+		maximumFloatBalance := GetMaxFloatBalance(floatManagerParams, logger, totalUserBalance, maxUserBalance)
+		forecast := FloatForecast{}
+		if floatManagerParams.TargetMode == model.FloatTargetMode.FORECAST {
+			if forecast, err = ForecastFloatDemand(config, repository, floatAccount, time.Now(), withdrawalSumFromLastRun); err != nil {
+				logger.Error("Float manager : could not forecast the withdrawals of %s on %s, using the static float range : %s", floatAccount.AssetSymbol, floatAccount.Network, err)
+			} else if !forecast.HasHistory {
+				logger.Info("Float manager : no float history to forecast the withdrawals of %s on %s from, using the static float range", floatAccount.AssetSymbol, floatAccount.Network)
+			} else {
+				logger.Info("Float manager : forecast for %s on %s is %+v", floatAccount.AssetSymbol, floatAccount.Network, forecast)
+			}
+			minimumFloatBalance, maximumFloatBalance = ApplyFloatForecast(forecast, minimumFloatBalance, maximumFloatBalance)
+		}
 		minimumTriggerLevel := new(big.Float)
 		minimumTriggerLevel.Mul(big.NewFloat(floatManagerParams.PercentMinimumTriggerLevel), minimumFloatBalance)
 		logger.Info("minimum balance for this hot wallet %+v is %+v and minimum trigger amount is %v", floatAccount.AssetSymbol, minimumFloatBalance, minimumTriggerLevel)
 
 		// GetMaximum
-		maximumFloatBalance := GetMaxFloatBalance(floatManagerParams, logger, totalUserBalance, maxUserBalance)
 		maximumTriggerLevel := new(big.Float)
 		maximumTriggerLevel.Mul(big.NewFloat(floatManagerParams.PercentMaximumTriggerLevel), maximumFloatBalance)
 		logger.Info("maximum balance for this hot wallet %+v is %+v and maximum trigger amount is %v", floatAccount.AssetSymbol, maximumFloatBalance, maximumTriggerLevel)
@@ -135,20 +158,8 @@ func ManageFloat(cache *utility.MemoryCache, logger *utility.Logger, config Conf
 			floatDeficitInDecimalUnits.Quo(floatDeficit, big.NewFloat(math.Pow(10, denominationDecimal)))
 			logger.Info("deficitInDecimalUnits for this hot wallet %s is %+v", floatAccount.AssetSymbol, floatDeficitInDecimalUnits)
 
-			// Ensure email has not been sent already
-			emailSent, _ := IsSentColdWalletMail(repository, floatDeficit, floatAccount.AssetSymbol, floatAccount.Network)
-			if !emailSent {
-
-				floatAction = fmt.Sprintf("floatOnChainBalance <= minimumFloatBalance %+v - %s, so sending an email to fund hot wallet for amount %+v in decimal units", floatAccount.AssetSymbol, floatAccount.Network, floatDeficitInDecimalUnits)
-
-				params := map[string]string{
-					"amount":      floatDeficitInDecimalUnits.String(),
-					"assetSymbol": floatAccount.AssetSymbol,
-					"network": floatAccount.Network,
-				}
-				err = notifyColdWalletUsers("Fund", params, config, err, cache, logger, serviceErr)
-			}
-			floatAction = fmt.Sprintf("Email to fund hot wallet (%s) of %+v has already been sent", floatAccount.AssetSymbol, floatDeficitInDecimalUnits)
+			// the cold wallet users are emailed once per funding request, it stays open until the float is topped up or it expires
+			floatAction = requestFloatFunding(cache, logger, config, repository, floatAccount, floatNetworkAsset, floatDeficit, floatOnChainBalance)
 			logger.Info(floatAction)
 		}
 
@@ -163,42 +174,47 @@ func ManageFloat(cache *utility.MemoryCache, logger *utility.Logger, config Conf
 			if floatSurplus.Cmp(maximumTriggerLevel) < 0 {
 				continue
 			}
-			logger.Info("floatOnChainBalance > maximum, so withdrawing excess %+v %+v to binance brokage", floatSurplus, floatAccount.AssetSymbol)
-
-			// Get binance broker deposit address, pass network as maincoin in the case of tokens
-			depositAddressResponse := dto.DepositAddressResponse{}
-
-			if *floatNetworkAsset.IsToken {
-				if err := services.GetDepositAddress(cache, logger, config, floatAccount.AssetSymbol, floatNetworkAsset.NativeAsset, &depositAddressResponse, serviceErr); err != nil {
-					logger.Error("Error response from Float manager : %+v while trying to get brokerage deposit ", err)
-					continue
+			// verified cold wallets take their share of the surplus first, the brokerage gets the rest
+			brokerageShare, coldAction := transferSurplusToCold(cache, logger, config, repository, floatAccount, floatNetworkAsset, floatSurplusInBigInt)
+			floatAction = coldAction
+			if brokerageShare.Sign() > 0 {
+				logger.Info("floatOnChainBalance > maximum, so withdrawing excess %+v %+v to binance brokage", brokerageShare, floatAccount.AssetSymbol)
+
+				// Get binance broker deposit address, pass network as maincoin in the case of tokens
+				depositAddressResponse := dto.DepositAddressResponse{}
+
+				if *floatNetworkAsset.IsToken {
+					if err := services.GetDepositAddress(cache, logger, config, floatAccount.AssetSymbol, floatNetworkAsset.NativeAsset, &depositAddressResponse, serviceErr); err != nil {
+						logger.Error("Error response from Float manager : %+v while trying to get brokerage deposit ", err)
+						continue
+					}
+				} else {
+					if err := services.GetDepositAddress(cache, logger, config, floatAccount.AssetSymbol, "", &depositAddressResponse, serviceErr); err != nil {
+						logger.Error("Error response from Float manager : %+v while trying to get brokerage deposit ", err)
+						continue
+					}
 				}
-			} else {
-				if err := services.GetDepositAddress(cache, logger, config, floatAccount.AssetSymbol, "", &depositAddressResponse, serviceErr); err != nil {
-					logger.Error("Error response from Float manager : %+v while trying to get brokerage deposit ", err)
+
+				// Sign and send transaction to chain
+				if err := sendSingleTransactionToChain(cache, repository, brokerageShare, depositAddressResponse, logger, config, floatAccount, serviceErr); err != nil {
 					continue
 				}
-			}
-
-			// Sign and send transaction to chain
-			if err := sendSingleTransactionToChain(cache, repository, floatSurplusInBigInt, depositAddressResponse, logger, config, floatAccount, serviceErr); err != nil {
-				continue
-			}
 
-			// Send email to cold wallet recipients
-			floatSurplusInDecimal.Quo(floatSurplus, big.NewFloat(math.Pow(10, float64(floatNetworkAsset.NativeDecimals))))
-			params := map[string]string{
-				"amount":             floatSurplusInDecimal.String(),
-				"assetSymbol":        floatAccount.AssetSymbol,
-				"depositAddress":     depositAddressResponse.Address,
-				"depositAddressMemo": depositAddressResponse.Tag,
-				"network": floatAccount.Network,
+				// Send email to cold wallet recipients
+				floatSurplusInDecimal.Quo(new(big.Float).SetInt(brokerageShare), big.NewFloat(math.Pow(10, float64(floatNetworkAsset.NativeDecimals))))
+				params := map[string]string{
+					"amount":             floatSurplusInDecimal.String(),
+					"assetSymbol":        floatAccount.AssetSymbol,
+					"depositAddress":     depositAddressResponse.Address,
+					"depositAddressMemo": depositAddressResponse.Tag,
+					"network": floatAccount.Network,
+				}
+				err = notifyColdWalletUsers("Withdraw", params, config, err, cache, logger, serviceErr)
 			}
-			err = notifyColdWalletUsers("Withdraw", params, config, err, cache, logger, serviceErr)
 
 		}
 
-		if err := saveFloatVariables(repository, logger, depositSumFromLastRun, totalUserBalance, withdrawalSumFromLastRun, floatOnChainBalance, maximumFloatBalance, minimumFloatBalance, floatDeficit, floatSurplus, float64(floatAccount.ReservedBalance), floatAction, floatAccount.AssetSymbol, floatAccount.Network); err != nil {
+		if err := saveFloatVariables(repository, logger, depositSumFromLastRun, totalUserBalance, withdrawalSumFromLastRun, floatOnChainBalance, maximumFloatBalance, minimumFloatBalance, floatDeficit, floatSurplus, float64(floatAccount.ReservedBalance), floatAction, floatAccount.AssetSymbol, floatAccount.Network, floatManagerParams, forecast); err != nil {
 			logger.Error("Error with saving float manager run variables for %s : %s", floatAccount.AssetSymbol, err)
 		}
 
@@ -213,7 +229,7 @@ func ManageFloat(cache *utility.MemoryCache, logger *utility.Logger, config Conf
 }
 
 //save float variables to db
-func saveFloatVariables(repository database.BaseRepository, logger *utility.Logger, depositSumFromLastRun, totalUserBalance, withdrawalSumFromLastRun, floatOnChainBalance, maximum, minimum, deficit *big.Float, surplus *big.Float, reservedBalance float64, floatAction, assetSymbol, network string) error {
+func saveFloatVariables(repository database.BaseRepository, logger *utility.Logger, depositSumFromLastRun, totalUserBalance, withdrawalSumFromLastRun, floatOnChainBalance, maximum, minimum, deficit *big.Float, surplus *big.Float, reservedBalance float64, floatAction, assetSymbol, network string, floatManagerParams model.FloatManagerParam, forecast FloatForecast) error {
 	DepositSum, _ := depositSumFromLastRun.Float64()
 	ResidualAmount := reservedBalance
 	TotalUserBalance, _ := totalUserBalance.Float64()
@@ -224,7 +240,8 @@ func saveFloatVariables(repository database.BaseRepository, logger *utility.Logg
 	Deficit, _ := deficit.Float64()
 	Surplus, _ := surplus.Float64()
 
-	if err := repository.Create(&model.FloatManager{ResidualAmount: ResidualAmount, AssetSymbol: assetSymbol, Network: network, TotalUserBalance: TotalUserBalance, DepositSum: DepositSum, WithdrawalSum: WithdrawalSum, FloatOnChainBalance: FloatOnChainBalance, MaximumFloatRange: MaximumFloatRange, MinimumFloatRange: MinimumFloatRange, Deficit: Deficit, Surplus: Surplus, Action: floatAction, LastRunTime: time.Now()}); err != nil {
+	if err := repository.Create(&model.FloatManager{ResidualAmount: ResidualAmount, AssetSymbol: assetSymbol, Network: network, TotalUserBalance: TotalUserBalance, DepositSum: DepositSum, WithdrawalSum: WithdrawalSum, FloatOnChainBalance: FloatOnChainBalance, MaximumFloatRange: MaximumFloatRange, MinimumFloatRange: MinimumFloatRange, Deficit: Deficit, Surplus: Surplus, Action: floatAction, LastRunTime: time.Now(), FloatParamID: &floatManagerParams.ID, FloatParamVersion: floatManagerParams.Version, TargetMode: floatManagerParams.TargetMode,
+		ForecastHourlyOutflow: forecast.HourlyOutflow, ForecastSeasonality: forecast.Seasonality, ForecastPendingWithdrawals: forecast.PendingWithdrawals, ForecastTarget: forecast.Target}); err != nil {
 		return err
 	}
 	return nil
@@ -282,6 +299,24 @@ func notifyColdWalletUsers(emailType string, params map[string]string, config Co
 			ID:     config.ColdWalletEmailTemplateId,
 			Params: params,
 		}
+	case "FundEscalation":
+		escalationEmails := []dto.EmailUser{}
+		for _, email := range strings.Split(config.FundingRequestEscalationEmails, ",") {
+			if email = strings.TrimSpace(email); email != "" {
+				escalationEmails = append(escalationEmails, dto.EmailUser{Name: "Bundle float escalation contact", Email: email})
+			}
+		}
+		sendEmailRequest.Receivers = escalationEmails
+		if config.SENTRY_ENVIRONMENT == utility.ENV_PRODUCTION {
+			sendEmailRequest.Subject = "Live: Unacknowledged request to fund Bundle hot wallet address for " + params["assetSymbol"] + " - "+params["network"]
+		} else {
+			sendEmailRequest.Subject = "Test: Unacknowledged request to fund Bundle hot wallet address for " + params["assetSymbol"] + " - "+params["network"]
+		}
+		sendEmailRequest.Content = fmt.Sprintf(`
+		Attention:
+		The request (%s) opened at %s to fund the HotWallet Address with %+v %s - %s has not been acknowledged by the cold wallet users.
+		Please fund the hot wallet and acknowledge the request at %s.
+		`, params["fundingRequestId"], params["openedAt"], params["amount"], params["assetSymbol"], params["network"], params["ackLink"])
 	case "Withdraw":
 		if config.SENTRY_ENVIRONMENT == utility.ENV_PRODUCTION {
 			sendEmailRequest.Subject = "Live: Withdrawing excess funds to brokerage for " + params["assetSymbol"] + " - "+params["network"]
@@ -293,6 +328,17 @@ func notifyColdWalletUsers(emailType string, params map[string]string, config Co
 		To regulate float account, %+v %s - %s has been moved from the HotWallet Address to the Brokerage Account Address %s with Memo (%s).
 		Please check to verify that movement was successful.
 		`, params["amount"], params["assetSymbol"], params["network"], params["depositAddress"], params["depositAddressMemo"])
+	case "ColdStorage":
+		if config.SENTRY_ENVIRONMENT == utility.ENV_PRODUCTION {
+			sendEmailRequest.Subject = "Live: Moving excess funds to cold storage for " + params["assetSymbol"] + " - "+params["network"]
+		} else {
+			sendEmailRequest.Subject = "Test: Moving excess funds to cold storage for " + params["assetSymbol"] + " - "+params["network"]
+		}
+		sendEmailRequest.Content = fmt.Sprintf(`
+		Attention:
+		To regulate float account, %+v %s - %s has been moved from the HotWallet Address to the cold wallet %s, Address %s with Memo (%s).
+		Please check to verify that movement was successful.
+		`, params["amount"], params["assetSymbol"], params["network"], params["label"], params["depositAddress"], params["depositAddressMemo"])
 	}
 
 	sendEmailResponse := dto.SendEmailResponse{}
@@ -320,8 +366,8 @@ func GetTotalUserBalance(repository database.BaseRepository, assetSymbol string,
 
 func getFloatParamFor(assetSymbol, network string, repository database.BaseRepository, logger *utility.Logger) (model.FloatManagerParam, error) {
 	//Get float manager params
-	floatManagerParam := model.FloatManagerParam{AssetSymbol: assetSymbol, Network : network}
-	if err := repository.GetByFieldName(floatManagerParam, &floatManagerParam); err != nil {
+	floatManagerParam, err := services.GetFloatParams(&database.UserAssetRepository{BaseRepository: repository}, assetSymbol, network)
+	if err != nil {
 		logger.Error("Error response from Float manager : %+v while trying to get float manager params", err)
 		return model.FloatManagerParam{}, err
 	}
@@ -354,6 +400,21 @@ func Abs(x int64) int64 {
 }
 
 func getDepositsSumForAssetFromDate(repository database.BaseRepository, assetSymbol, network string,  logger *utility.Logger, hotWallet model.HotWalletAsset) (*big.Float, error) {
+	sum, lastCreatedAt, err := sumDepositsForAssetFromDate(repository, assetSymbol, network, logger, hotWallet)
+	if err != nil {
+		return nil, err
+	}
+	if lastCreatedAt != nil {
+		if err := repository.Update(&hotWallet, &model.HotWalletAsset{LastDepositCreatedAt: lastCreatedAt}); err != nil {
+			logger.Error("Error occured while updating hot wallet LastDepositCreatedAt to On-going : %s", err)
+		}
+	}
+	return sum, nil
+}
+
+// sumDepositsForAssetFromDate returns the sum of the deposits since the last float manager run and when the latest was made,
+// leaving the hot wallet as it is
+func sumDepositsForAssetFromDate(repository database.BaseRepository, assetSymbol, network string, logger *utility.Logger, hotWallet model.HotWalletAsset) (*big.Float, *time.Time, error) {
 	deposits := []model.Transaction{}
 	if err := repository.FetchByFieldNameFromDate(model.Transaction{
 		TransactionTag: "DEPOSIT",
@@ -361,7 +422,7 @@ func getDepositsSumForAssetFromDate(repository database.BaseRepository, assetSym
 		Network: network,
 	}, &deposits, hotWallet.LastDepositCreatedAt); err != nil {
 		logger.Error("Error response from Float manager : %+v while trying to get deposits", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	sum := new(big.Float)
@@ -375,7 +436,7 @@ func getDepositsSumForAssetFromDate(repository database.BaseRepository, assetSym
 	recipientNetworkAsset, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, network, assetSymbol)
 	if err != nil {
 		logger.Error(fmt.Sprintf("error with getting network asset for deposit txn with assetSymbol : %s and network : %s : %s", assetSymbol, network, err))
-		return nil, err
+		return nil, nil, err
 	}
 	for _, deposit := range deposits {
 		//convert to native units
@@ -385,15 +446,26 @@ func getDepositsSumForAssetFromDate(repository database.BaseRepository, assetSym
 		sum = sum.Add(sum, scaledBalance)
 		lastCreatedAt = &deposit.CreatedAt
 	}
+	return sum, lastCreatedAt, nil
+}
+
+func getWithdrawalsSumForAssetFromDate(repository database.BaseRepository, assetSymbol, network string, logger *utility.Logger, hotWallet model.HotWalletAsset) (*big.Float, error) {
+	sum, lastCreatedAt, err := sumWithdrawalsForAssetFromDate(repository, assetSymbol, network, logger, hotWallet)
+	if err != nil {
+		return nil, err
+	}
 	if lastCreatedAt != nil {
-		if err := repository.Update(&hotWallet, &model.HotWalletAsset{LastDepositCreatedAt: lastCreatedAt}); err != nil {
-			logger.Error("Error occured while updating hot wallet LastDepositCreatedAt to On-going : %s", err)
+		if err := repository.Update(&hotWallet, &model.HotWalletAsset{LastWithdrawalCreatedAt: lastCreatedAt}); err != nil {
+			logger.Error("Error occured while updating hot wallet LastWithdrawalCreatedAt to On-going : %s", err)
 		}
 	}
+
 	return sum, nil
 }
 
-func getWithdrawalsSumForAssetFromDate(repository database.BaseRepository, assetSymbol, network string, logger *utility.Logger, hotWallet model.HotWalletAsset) (*big.Float, error) {
+// sumWithdrawalsForAssetFromDate returns the sum of the withdrawals since the last float manager run and when the latest was made,
+// leaving the hot wallet as it is
+func sumWithdrawalsForAssetFromDate(repository database.BaseRepository, assetSymbol, network string, logger *utility.Logger, hotWallet model.HotWalletAsset) (*big.Float, *time.Time, error) {
 	withdrawals := []model.Transaction{}
 	if err := repository.FetchByFieldNameFromDate(model.Transaction{
 		TransactionTag: "WITHDRAW",
@@ -401,7 +473,7 @@ func getWithdrawalsSumForAssetFromDate(repository database.BaseRepository, asset
 		Network: network,
 	}, &withdrawals, hotWallet.LastWithdrawalCreatedAt); err != nil {
 		logger.Error("Error response from Float manager : %+v while trying to get withdrawals", err)
-		return nil, err
+		return nil, nil, err
 	}
 	var lastCreatedAt *time.Time
 	sum := new(big.Float)
@@ -414,7 +486,7 @@ func getWithdrawalsSumForAssetFromDate(repository database.BaseRepository, asset
 	withdrawalNetworkAsset, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, network, assetSymbol)
 	if err != nil {
 		logger.Error(fmt.Sprintf("error with getting network asset for deposit txn with assetSymbol : %s and network : %s : %s", assetSymbol, network, err))
-		return nil, err
+		return nil, nil, err
 	}
 	for _, withdrawal := range withdrawals {
 		//convert to native units
@@ -424,13 +496,7 @@ func getWithdrawalsSumForAssetFromDate(repository database.BaseRepository, asset
 		sum = sum.Add(sum, scaledBalance)
 		lastCreatedAt = &withdrawal.CreatedAt
 	}
-	if lastCreatedAt != nil {
-		if err := repository.Update(&hotWallet, &model.HotWalletAsset{LastWithdrawalCreatedAt: lastCreatedAt}); err != nil {
-			logger.Error("Error occured while updating hot wallet LastWithdrawalCreatedAt to On-going : %s", err)
-		}
-	}
-
-	return sum, nil
+	return sum, lastCreatedAt, nil
 }
 
 func sendSingleTransactionToChain(cache *utility.MemoryCache, repository database.BaseRepository, amount *big.Int, depositAccount dto.DepositAddressResponse, logger *utility.Logger, config Config.Data, floatAccount model.HotWalletAsset, serviceErr dto.ServicesRequestErr) error {
@@ -519,25 +585,3 @@ func GetMaxFloatBalance(floatManagerParams model.FloatManagerParam, logger *util
 	maximumFloatBalance := utility.MaxFloat(maxPercentageValueOfTotalUserBalance, C)
 	return maximumFloatBalance
 }
-
-func IsSentColdWalletMail(repository database.BaseRepository, deficit *big.Float, assetSymbol, network string) (bool, error) {
-	floatManager := []model.FloatManager{}
-	if err := repository.FetchByLastRunDate(assetSymbol, network, time.Now().Format("2006-01-02"), &floatManager); err != nil {
-		if errorcode.SQL_404 == err.Error() {
-			return true, nil
-		}
-		return false, err
-	}
-	if len(floatManager) == 0 {
-		return false, nil
-	}
-
-	deficitValue, _ := deficit.Float64()
-	if floatManager[0].Deficit == float64(0) {
-		return false, nil
-	} else if floatManager[0].Deficit == deficitValue {
-		return true, nil
-	}
-
-	return false, nil
-}
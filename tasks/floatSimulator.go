@@ -0,0 +1,164 @@
+package tasks
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+)
+
+// currentFloatParamsLabel labels the parameters the float manager runs with today in a simulation report
+const currentFloatParamsLabel = "current"
+
+// SimulateFloat ... Replays the deposits, withdrawals and float manager runs of an asset over a period against the current
+// float parameters and each candidate set, reporting how often the float would have run dry, how much surplus would have sat idle
+// and how many top up emails the cold wallet users would have received.
+// The float starts at the on-chain balance of the first run in the period and, as the float manager assumes, every deposit adds
+// to it and every withdrawal is paid from it. Top ups are assumed to arrive before the next run and surplus is moved out at once
+func SimulateFloat(logger *utility.Logger, repository database.BaseRepository, request dto.FloatSimulationRequest) (dto.FloatSimulationReport, error) {
+	report := dto.FloatSimulationReport{AssetSymbol: request.AssetSymbol, Network: request.Network, From: request.From, To: request.To}
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+
+	floatNetworkAsset, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, request.Network, request.AssetSymbol)
+	if err != nil {
+		return report, err
+	}
+	runs := []model.FloatManager{}
+	if err := userAssetRepository.FetchFloatHistory(request.AssetSymbol, request.Network, request.From, request.To, &runs); err != nil {
+		return report, err
+	}
+	if len(runs) == 0 {
+		return report, errors.New(errorcode.FLOAT_SIMULATION_NO_HISTORY)
+	}
+	transactions := []model.Transaction{}
+	if err := userAssetRepository.FetchTransactionsBetween(request.AssetSymbol, request.Network, []string{model.TransactionTag.DEPOSIT, model.TransactionTag.WITHDRAW},
+		runs[0].CreatedAt, runs[len(runs)-1].CreatedAt, &transactions); err != nil {
+		return report, err
+	}
+	// the runs do not record the maximum user balance, the current one stands in for it
+	maxUserBalance, err := GetMaxUserBalanceFor(userAssetRepository, request.AssetSymbol)
+	if err != nil {
+		logger.Info("Float simulation : could not get the maximum user balance of %s, simulating without it : %s", request.AssetSymbol, err)
+	}
+
+	paramSets := request.ParamSets
+	if currentParams, err := getFloatParamFor(request.AssetSymbol, request.Network, repository, logger); err == nil {
+		paramSets = append([]dto.FloatSimulationParams{floatSimulationParamsOf(currentParams)}, paramSets...)
+	}
+
+	scale := math.Pow(10, float64(floatNetworkAsset.NativeDecimals))
+	report.Runs = len(runs)
+	report.StartingBalance = runs[0].FloatOnChainBalance / scale
+	report.MaxUserBalance, _ = maxUserBalance.Float64()
+	for _, transaction := range transactions {
+		if transaction.TransactionTag == model.TransactionTag.DEPOSIT {
+			report.DepositCount++
+		} else {
+			report.WithdrawalCount++
+		}
+	}
+	for _, params := range paramSets {
+		result := replayFloat(logger, runs, transactions, maxUserBalance, scale, params)
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// replayFloat runs the float manager's decisions for one parameter set over the recorded runs, amounts are kept in native units
+// like the runs and converted to decimal units for the result
+func replayFloat(logger *utility.Logger, runs []model.FloatManager, transactions []model.Transaction, maxUserBalance *big.Float, scale float64, params dto.FloatSimulationParams) dto.FloatSimulationResult {
+	result := dto.FloatSimulationResult{Params: params}
+	floatManagerParams := floatManagerParamOf(params)
+	balance := runs[0].FloatOnChainBalance
+	idleSurplusSum := 0.0
+	next := 0
+
+	for _, run := range runs {
+		depositSum, withdrawalSum, ranDry := 0.0, 0.0, false
+		for ; next < len(transactions) && !transactions[next].CreatedAt.After(run.CreatedAt); next++ {
+			value, _ := strconv.ParseFloat(transactions[next].Value, 64)
+			amount := value * scale
+			if transactions[next].TransactionTag == model.TransactionTag.DEPOSIT {
+				depositSum += amount
+				balance += amount
+				continue
+			}
+			withdrawalSum += amount
+			if amount > balance {
+				// the withdrawal would have waited for a top up, count the gap and carry on from an empty float
+				result.Shortfall += (amount - balance) / scale
+				ranDry = true
+				balance = 0
+				continue
+			}
+			balance -= amount
+		}
+		if ranDry {
+			result.DryRuns++
+		}
+
+		totalUserBalance := big.NewFloat(run.TotalUserBalance)
+		minimumFloatBalance, _ := GetMinFloatBalance(floatManagerParams, logger, totalUserBalance, maxUserBalance).Float64()
+		maximumFloatBalance, _ := GetMaxFloatBalance(floatManagerParams, logger, totalUserBalance, maxUserBalance).Float64()
+
+		if balance <= floatManagerParams.PercentMinimumTriggerLevel*minimumFloatBalance {
+			deficit := minimumFloatBalance - balance
+			if depositSum < withdrawalSum {
+				deficit = maximumFloatBalance - balance
+			}
+			// the top up is taken as funded by the next run, so each one is a funding request of its own and one email
+			result.TopUpEmails++
+			result.TopUpAmount += deficit / scale
+			balance += deficit
+		}
+
+		idleSurplus := 0.0
+		if balance > maximumFloatBalance {
+			surplus := balance - maximumFloatBalance
+			if surplus >= floatManagerParams.PercentMaximumTriggerLevel*maximumFloatBalance {
+				result.SurplusTransfers++
+				result.SurplusTransferAmount += surplus / scale
+				balance = maximumFloatBalance
+			} else {
+				idleSurplus = surplus / scale
+			}
+		}
+		idleSurplusSum += idleSurplus
+		result.MaxIdleSurplus = math.Max(result.MaxIdleSurplus, idleSurplus)
+	}
+
+	result.AverageIdleSurplus = idleSurplusSum / float64(len(runs))
+	result.EndingBalance = balance / scale
+	return result
+}
+
+func floatSimulationParamsOf(params model.FloatManagerParam) dto.FloatSimulationParams {
+	return dto.FloatSimulationParams{
+		Label:                          currentFloatParamsLabel,
+		MinPercentMaxUserBalance:       params.MinPercentMaxUserBalance,
+		MaxPercentMaxUserBalance:       params.MaxPercentMaxUserBalance,
+		MinPercentTotalUserBalance:     params.MinPercentTotalUserBalance,
+		AveragePercentTotalUserBalance: params.AveragePercentTotalUserBalance,
+		MaxPercentTotalUserBalance:     params.MaxPercentTotalUserBalance,
+		PercentMinimumTriggerLevel:     params.PercentMinimumTriggerLevel,
+		PercentMaximumTriggerLevel:     params.PercentMaximumTriggerLevel,
+	}
+}
+
+func floatManagerParamOf(params dto.FloatSimulationParams) model.FloatManagerParam {
+	return model.FloatManagerParam{
+		MinPercentMaxUserBalance:       params.MinPercentMaxUserBalance,
+		MaxPercentMaxUserBalance:       params.MaxPercentMaxUserBalance,
+		MinPercentTotalUserBalance:     params.MinPercentTotalUserBalance,
+		AveragePercentTotalUserBalance: params.AveragePercentTotalUserBalance,
+		MaxPercentTotalUserBalance:     params.MaxPercentTotalUserBalance,
+		PercentMinimumTriggerLevel:     params.PercentMinimumTriggerLevel,
+		PercentMaximumTriggerLevel:     params.PercentMaximumTriggerLevel,
+	}
+}
@@ -0,0 +1,246 @@
+package tasks
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	defaultFundingRequestEscalationMinutes = 60
+	defaultFundingRequestExpiryHours       = 24
+)
+
+// GetFundingRequestEscalationMinutes ... Returns how long a funding request may stay unacknowledged before it is escalated
+func GetFundingRequestEscalationMinutes(config Config.Data) int {
+	if config.FundingRequestEscalationMinutes <= 0 {
+		return defaultFundingRequestEscalationMinutes
+	}
+	return config.FundingRequestEscalationMinutes
+}
+
+// GetFundingRequestExpiryHours ... Returns how long a funding request stays open before the float manager may open a new one
+func GetFundingRequestExpiryHours(config Config.Data) int {
+	if config.FundingRequestExpiryHours <= 0 {
+		return defaultFundingRequestExpiryHours
+	}
+	return config.FundingRequestExpiryHours
+}
+
+// GetFundingRequestAckLink ... Returns the link the cold wallet users follow to acknowledge a funding request
+func GetFundingRequestAckLink(config Config.Data, fundingRequest model.FundingRequest) string {
+	return strings.TrimRight(config.FundingRequestAckURL, "/") + "/" + fundingRequest.AckToken
+}
+
+// GetFundingSources ... Returns the addresses whose deposits to the float count towards a funding request of an asset,
+// the cold wallets registered for the asset on the network and the configured treasury addresses
+func GetFundingSources(config Config.Data, repository database.BaseRepository, assetSymbol, network string) ([]string, error) {
+	coldWallets := []model.ColdWallet{}
+	if err := repository.FetchByFieldName(&model.ColdWallet{AssetSymbol: assetSymbol, Network: network}, &coldWallets); err != nil && err.Error() != errorcode.SQL_404 {
+		return nil, err
+	}
+	sources := []string{}
+	for _, coldWallet := range coldWallets {
+		sources = append(sources, coldWallet.Address)
+	}
+	for _, treasuryAddress := range strings.Split(config.TreasuryAddresses, ",") {
+		if treasuryAddress = strings.TrimSpace(treasuryAddress); treasuryAddress != "" {
+			sources = append(sources, treasuryAddress)
+		}
+	}
+	return sources, nil
+}
+
+// MatchFundingDeposits ... Sums the deposits to the float sent from a funding source since the funding request was opened,
+// it returns the funded amount in the smallest unit of the asset and the hashes of the matched deposits
+func MatchFundingDeposits(fundingRequest model.FundingRequest, deposits []dto.AddressDeposit, sources []string) (*big.Int, []string) {
+	fundedAmount := big.NewInt(0)
+	transactionHashes := []string{}
+	for _, deposit := range deposits {
+		if deposit.CreatedAt.Before(fundingRequest.CreatedAt) || !isFundingSource(deposit.SenderAddress, sources) {
+			continue
+		}
+		value, ok := new(big.Int).SetString(deposit.Value, 10)
+		if !ok {
+			continue
+		}
+		fundedAmount.Add(fundedAmount, value)
+		transactionHashes = append(transactionHashes, deposit.TransactionHash)
+	}
+	return fundedAmount, transactionHashes
+}
+
+func isFundingSource(address string, sources []string) bool {
+	for _, source := range sources {
+		if strings.EqualFold(address, source) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReviewFundingRequest ... Decides what becomes of an open or acknowledged funding request given the amount funded from a funding source
+// since it was opened, a nil amount only lets the request expire or escalate. It returns the new status and whether the escalation contacts are to be told
+func ReviewFundingRequest(config Config.Data, fundingRequest model.FundingRequest, fundedAmount *big.Int, now time.Time) (string, bool) {
+	if fundedAmount != nil {
+		requestedAmount, _ := new(big.Int).SetString(fundingRequest.BaseAmount, 10)
+		if requestedAmount != nil && fundedAmount.Cmp(requestedAmount) >= 0 {
+			return model.FundingRequestStatus.FULFILLED, false
+		}
+	}
+	if now.After(fundingRequest.ExpiresAt) {
+		return model.FundingRequestStatus.EXPIRED, false
+	}
+	escalationDue := fundingRequest.CreatedAt.Add(time.Duration(GetFundingRequestEscalationMinutes(config)) * time.Minute)
+	escalate := fundingRequest.Status == model.FundingRequestStatus.OPEN && fundingRequest.EscalatedAt == nil &&
+		config.FundingRequestEscalationEmails != "" && !now.Before(escalationDue)
+	return fundingRequest.Status, escalate
+}
+
+// requestFloatFunding opens a funding request for the float deficit and emails the cold wallet users its acknowledgement link.
+// While a request of the asset is open or acknowledged no other is opened, it returns the float action to record
+func requestFloatFunding(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, floatAccount model.HotWalletAsset, floatNetworkAsset model.Network, floatDeficit, floatOnChainBalance *big.Float) string {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	openRequests := []model.FundingRequest{}
+	if err := userAssetRepository.FetchFundingRequests(floatAccount.AssetSymbol, floatAccount.Network, []string{model.FundingRequestStatus.OPEN, model.FundingRequestStatus.ACKNOWLEDGED}, &openRequests); err != nil {
+		logger.Error("Float manager : could not get the open funding requests of %s on %s : %s", floatAccount.AssetSymbol, floatAccount.Network, err)
+		return ""
+	}
+	if len(openRequests) > 0 {
+		return fmt.Sprintf("Funding request %s of %s %s - %s is %s", openRequests[0].ID, openRequests[0].Amount, floatAccount.AssetSymbol, floatAccount.Network, openRequests[0].Status)
+	}
+
+	baseAmount, _ := floatDeficit.Int(nil)
+	floatBalance, _ := floatOnChainBalance.Int(nil)
+	amount := ConvertBigIntToDecimalUnit(*baseAmount, floatNetworkAsset)
+	fundingRequest := model.FundingRequest{
+		AssetSymbol:  floatAccount.AssetSymbol,
+		Network:      floatAccount.Network,
+		FloatAddress: floatAccount.Address,
+		Amount:       amount.Text('f', floatNetworkAsset.NativeDecimals),
+		BaseAmount:   baseAmount.String(),
+		FloatBalance: floatBalance.String(),
+		Status:       model.FundingRequestStatus.OPEN,
+		AckToken:     strings.Replace(uuid.NewV4().String(), "-", "", -1),
+		ExpiresAt:    time.Now().Add(time.Duration(GetFundingRequestExpiryHours(config)) * time.Hour),
+	}
+	if err := repository.Create(&fundingRequest); err != nil {
+		logger.Error("Float manager : could not record the funding request of %s on %s : %s", floatAccount.AssetSymbol, floatAccount.Network, err)
+		return ""
+	}
+
+	params := map[string]string{
+		"amount":           fundingRequest.Amount,
+		"assetSymbol":      floatAccount.AssetSymbol,
+		"network":          floatAccount.Network,
+		"fundingRequestId": fundingRequest.ID.String(),
+		"ackLink":          GetFundingRequestAckLink(config, fundingRequest),
+	}
+	_ = notifyColdWalletUsers("Fund", params, config, nil, cache, logger, dto.ServicesRequestErr{})
+	return fmt.Sprintf("floatOnChainBalance <= minimumFloatBalance %s - %s, so opened funding request %s for amount %s in decimal units", floatAccount.AssetSymbol, floatAccount.Network, fundingRequest.ID, fundingRequest.Amount)
+}
+
+// FollowUpFundingRequests ... Marks open and acknowledged funding requests fulfilled once deposits to the float from a cold wallet or
+// treasury address add up to the requested amount, expires those past their expiry and escalates those left unacknowledged past the escalation timeout
+func FollowUpFundingRequests(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository) {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	fundingRequests := []model.FundingRequest{}
+	if err := userAssetRepository.FetchFundingRequests("", "", []string{model.FundingRequestStatus.OPEN, model.FundingRequestStatus.ACKNOWLEDGED}, &fundingRequests); err != nil {
+		logger.Error("Funding requests : could not get the open funding requests : %s", err)
+		return
+	}
+
+	for _, fundingRequest := range fundingRequests {
+		var fundedAmount *big.Int
+		var fundingTxHashes []string
+		if sources, err := GetFundingSources(config, repository, fundingRequest.AssetSymbol, fundingRequest.Network); err != nil {
+			logger.Error("Funding requests : could not get the funding sources of %s on %s, only checking expiry and escalation : %s", fundingRequest.AssetSymbol, fundingRequest.Network, err)
+		} else {
+			addressDepositsResponse := dto.AddressDepositsResponse{}
+			if err := services.GetAddressDeposits(cache, logger, config, dto.AddressDepositsRequest{AssetSymbol: fundingRequest.AssetSymbol, Network: fundingRequest.Network, Address: fundingRequest.FloatAddress, From: fundingRequest.CreatedAt},
+				&addressDepositsResponse, dto.ServicesRequestErr{}); err != nil {
+				logger.Error("Funding requests : could not get the float deposits of %s on %s, only checking expiry and escalation : %s", fundingRequest.AssetSymbol, fundingRequest.Network, err)
+			} else {
+				fundedAmount, fundingTxHashes = MatchFundingDeposits(fundingRequest, addressDepositsResponse.Deposits, sources)
+			}
+		}
+
+		now := time.Now()
+		status, escalate := ReviewFundingRequest(config, fundingRequest, fundedAmount, now)
+		updates := map[string]interface{}{}
+		switch status {
+		case model.FundingRequestStatus.FULFILLED:
+			updates["status"], updates["fulfilled_at"] = status, now
+			updates["fulfilled_amount"], updates["funding_tx_hashes"] = fundedAmount.String(), strings.Join(fundingTxHashes, ",")
+		case model.FundingRequestStatus.EXPIRED:
+			updates["status"] = status
+		}
+		if escalate {
+			params := map[string]string{
+				"amount":           fundingRequest.Amount,
+				"assetSymbol":      fundingRequest.AssetSymbol,
+				"network":          fundingRequest.Network,
+				"fundingRequestId": fundingRequest.ID.String(),
+				"ackLink":          GetFundingRequestAckLink(config, fundingRequest),
+				"openedAt":         fundingRequest.CreatedAt.Format(time.RFC3339),
+			}
+			if err := notifyColdWalletUsers("FundEscalation", params, config, nil, cache, logger, dto.ServicesRequestErr{}); err == nil {
+				updates["escalated_at"] = now
+			}
+		}
+		if len(updates) == 0 {
+			continue
+		}
+		if err := repository.Update(&model.FundingRequest{BaseModel: model.BaseModel{ID: fundingRequest.ID}}, updates); err != nil {
+			logger.Error("Funding requests : could not update funding request %s : %s", fundingRequest.ID, err)
+			continue
+		}
+		logger.Info("Funding requests : funding request %s of %s on %s updated with %+v", fundingRequest.ID, fundingRequest.AssetSymbol, fundingRequest.Network, updates)
+	}
+}
+
+// GetFundingRequestByAckToken ... Returns the funding request of an acknowledgement token without changing it
+func GetFundingRequestByAckToken(repository database.BaseRepository, ackToken string) (model.FundingRequest, error) {
+	fundingRequest := model.FundingRequest{}
+	if ackToken == "" {
+		return fundingRequest, errors.New(errorcode.SQL_404)
+	}
+	if err := repository.GetByFieldName(&model.FundingRequest{AckToken: ackToken}, &fundingRequest); err != nil {
+		return fundingRequest, err
+	}
+	return fundingRequest, nil
+}
+
+// AcknowledgeFundingRequest ... Marks the funding request of an acknowledgement token acknowledged, acknowledging twice changes nothing
+func AcknowledgeFundingRequest(repository database.BaseRepository, ackToken, acknowledgedBy string) (model.FundingRequest, error) {
+	fundingRequest, err := GetFundingRequestByAckToken(repository, ackToken)
+	if err != nil {
+		return fundingRequest, err
+	}
+	switch fundingRequest.Status {
+	case model.FundingRequestStatus.ACKNOWLEDGED:
+		return fundingRequest, nil
+	case model.FundingRequestStatus.OPEN:
+	default:
+		return fundingRequest, errors.New(errorcode.FUNDING_REQUEST_CLOSED)
+	}
+
+	now := time.Now()
+	if err := repository.Update(&model.FundingRequest{BaseModel: model.BaseModel{ID: fundingRequest.ID}}, map[string]interface{}{
+		"status": model.FundingRequestStatus.ACKNOWLEDGED, "acknowledged_by": acknowledgedBy, "acknowledged_at": now}); err != nil {
+		return fundingRequest, err
+	}
+	fundingRequest.Status, fundingRequest.AcknowledgedBy, fundingRequest.AcknowledgedAt = model.FundingRequestStatus.ACKNOWLEDGED, acknowledgedBy, &now
+	return fundingRequest, nil
+}
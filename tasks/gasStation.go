@@ -0,0 +1,343 @@
+package tasks
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+)
+
+const defaultGasStationFeeBufferPercent = 20
+
+// GetGasStationFeeBufferPercent ... Returns the percentage added on top of the estimated fee when funding a sweep,
+// so a fee rise between funding and sweeping does not leave the address short
+func GetGasStationFeeBufferPercent(config Config.Data) int {
+	if config.GasStationFeeBufferPercent <= 0 {
+		return defaultGasStationFeeBufferPercent
+	}
+	return config.GasStationFeeBufferPercent
+}
+
+// RequiresSweepGas ... Tokens are swept with the native coin of their network paying the fee,
+// so deposit addresses holding only the token have to be funded first
+func RequiresSweepGas(network model.Network) bool {
+	return network.IsToken != nil && *network.IsToken
+}
+
+// GetSweepGasFee ... Returns the native amount a deposit address needs to sweep a token, the network's static sweep fee is used
+// when the crypto adapter cannot estimate the fee
+func GetSweepGasFee(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, network model.Network) int64 {
+	serviceErr := dto.ServicesRequestErr{}
+	networkFeeResponse := dto.NetworkFeeResponse{}
+	request := dto.NetworkFeeRequest{AssetSymbol: network.AssetSymbol, Network: network.Network}
+	if err := services.GetNetworkFee(cache, logger, config, request, &networkFeeResponse, &serviceErr); err != nil {
+		logger.Error("Gas station : could not estimate the fee of sweeping %s on %s, using the static sweep fee : %s", network.AssetSymbol, network.Network, err)
+		return network.SweepFee
+	}
+	fee, err := strconv.ParseInt(networkFeeResponse.Fee, 10, 64)
+	if err != nil || fee <= 0 {
+		logger.Error("Gas station : invalid fee %s estimated for %s on %s, using the static sweep fee", networkFeeResponse.Fee, network.AssetSymbol, network.Network)
+		return network.SweepFee
+	}
+	return fee + fee*int64(GetGasStationFeeBufferPercent(config))/100
+}
+
+// PendingGasFunding ... Returns the funding of an address that is still waiting to be confirmed, if any
+func PendingGasFunding(repository database.BaseRepository, address, network string) (model.GasFunding, bool, error) {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	fundings := []model.GasFunding{}
+	if err := userAssetRepository.FetchGasFundings(address, network, []string{model.GasFundingStatus.PENDING}, &fundings); err != nil {
+		return model.GasFunding{}, false, err
+	}
+	if len(fundings) == 0 {
+		return model.GasFunding{}, false, nil
+	}
+	return fundings[0], true, nil
+}
+
+// ApplyGasFundingStatus ... Moves a pending funding to CONFIRMED or FAILED following the status of its on-chain transaction,
+// it returns true once the funding is confirmed
+func ApplyGasFundingStatus(repository database.BaseRepository, funding *model.GasFunding, transactionStatus string) (bool, error) {
+	switch transactionStatus {
+	case utility.SUCCESSFUL:
+		confirmedAt := time.Now()
+		if err := repository.Update(funding, &model.GasFunding{Status: model.GasFundingStatus.CONFIRMED, ConfirmedAt: &confirmedAt}); err != nil {
+			return false, err
+		}
+		return true, nil
+	case utility.FAILED:
+		if err := repository.Update(funding, &model.GasFunding{Status: model.GasFundingStatus.FAILED}); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// MarkSweepGasSpent ... Marks the confirmed fundings of an address as spent by the token sweep of the reference, what is left of them
+// once the sweep's fee is paid is recovered to the fee wallet on a later sweep run
+func MarkSweepGasSpent(repository database.BaseRepository, address, network, sweepReference string, logger *utility.Logger) error {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	fundings := []model.GasFunding{}
+	if err := userAssetRepository.FetchGasFundings(address, network, []string{model.GasFundingStatus.CONFIRMED}, &fundings); err != nil {
+		return err
+	}
+	for i := range fundings {
+		if err := repository.Update(&fundings[i], &model.GasFunding{Status: model.GasFundingStatus.SPENT, SweepReference: sweepReference}); err != nil {
+			logger.Error("Gas station : could not mark funding %s of %s as spent : %s", fundings[i].Reference, address, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFeeWalletAddress ... Returns the address sweeps of a network are funded from and gas dust goes back to. Networks without a fee wallet
+// use the hot wallet of their native asset, a disabled fee wallet stops funding on its network
+func GetFeeWalletAddress(repository database.BaseRepository, network, nativeAsset string) (string, error) {
+	feeWallet := model.FeeWallet{}
+	err := repository.GetByFieldName(&model.FeeWallet{Network: network}, &feeWallet)
+	if err == nil {
+		if feeWallet.IsDisabled {
+			return "", fmt.Errorf("fee wallet of network %s is disabled", network)
+		}
+		return feeWallet.Address, nil
+	}
+	if err.Error() != errorcode.SQL_404 {
+		return "", err
+	}
+	hotWallet := model.HotWalletAsset{}
+	if err := repository.GetByFieldName(&model.HotWalletAsset{AssetSymbol: nativeAsset, Network: network}, &hotWallet); err != nil || hotWallet.Address == "" {
+		return "", fmt.Errorf("no fee wallet or %s hot wallet for network %s", nativeAsset, network)
+	}
+	return hotWallet.Address, nil
+}
+
+// EnsureSweepGas ... Makes sure a deposit address holds enough of the native coin to sweep its token. A funding sent from the fee wallet
+// has to be confirmed before the token is swept, so the address is skipped until then and funded at most once in the meantime
+func EnsureSweepGas(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, network model.Network, address string, gasFee int64) (bool, error) {
+	pendingFunding, isPending, err := PendingGasFunding(repository, address, network.Network)
+	if err != nil {
+		return false, err
+	}
+	if isPending {
+		found, transactionStatus, err := services.GetBroadcastedTXNDetailsByRef(pendingFunding.Reference, pendingFunding.NativeAsset, pendingFunding.Network, cache, logger, config)
+		if err != nil {
+			return false, err
+		}
+		if !found && pendingFunding.TransactionHash == "" {
+			// the funding was recorded but the crypto adapter never took it, so the address is funded again on the next run
+			logger.Info("Gas station : funding %s of %s was never broadcast", pendingFunding.Reference, address)
+			return false, repository.Update(&pendingFunding, &model.GasFunding{Status: model.GasFundingStatus.FAILED})
+		}
+		if !found {
+			logger.Info("Gas station : funding %s of %s is not confirmed yet", pendingFunding.Reference, address)
+			return false, nil
+		}
+		if confirmed, err := ApplyGasFundingStatus(repository, &pendingFunding, transactionStatus.Status); err != nil || !confirmed {
+			return false, err
+		}
+	}
+
+	serviceErr := dto.ServicesRequestErr{}
+	request := dto.OnchainBalanceRequest{
+		AssetSymbol: network.NativeAsset,
+		Network:     network.Network,
+		Address:     address,
+	}
+	nativeBalanceResponse := dto.OnchainBalanceResponse{}
+	if err := services.GetOnchainBalance(cache, logger, config, request, &nativeBalanceResponse, &serviceErr); err != nil {
+		logger.Error("Error response from Sweep job : %+v while getting on-chain balance for %+v", err, address)
+		return false, err
+	}
+	nativeBalance, _ := strconv.ParseInt(nativeBalanceResponse.Balance, 10, 64)
+	if nativeBalance >= gasFee {
+		return true, nil
+	}
+
+	feeWalletAddress, err := GetFeeWalletAddress(repository, network.Network, network.NativeAsset)
+	if err != nil {
+		logger.Error("Gas station : could not fund %s : %s", address, err)
+		return false, err
+	}
+
+	funding := model.GasFunding{
+		Address:          address,
+		Network:          network.Network,
+		NativeAsset:      network.NativeAsset,
+		AssetSymbol:      network.AssetSymbol,
+		FeeWalletAddress: feeWalletAddress,
+		Amount:           gasFee - nativeBalance,
+		Reference:        fmt.Sprintf("GAS-%s-%d", address, time.Now().UnixNano()),
+		Status:           model.GasFundingStatus.PENDING,
+	}
+	sendSingleTransactionRequest := dto.SendSingleTransactionRequest{
+		FromAddress: feeWalletAddress,
+		ToAddress:   address,
+		Amount:      big.NewInt(funding.Amount),
+		AssetSymbol: network.NativeAsset,
+		Network:     network.Network,
+		ProcessType: utility.FLOATPROCESS,
+		Reference:   funding.Reference,
+	}
+	if network.RequiresMemo {
+		sendSingleTransactionRequest.Memo = utility.SWEEPMEMOBNB
+	}
+	// the funding is recorded before it is sent, so a run that fails after sending cannot fund the address twice
+	if err := repository.Create(&funding); err != nil {
+		logger.Error("Gas station : could not record funding %s of %s : %s", funding.Reference, address, err)
+		return false, err
+	}
+	sendSingleTransactionResponse := dto.SendTransactionResponse{}
+	if err := services.SendSingleTransaction(cache, logger, config, sendSingleTransactionRequest, &sendSingleTransactionResponse, &serviceErr); err != nil {
+		logger.Error("Error response from Sweep job : %+v while funding sweep fee for %+v", err, address)
+		if serviceErr.StatusCode == http.StatusBadRequest {
+			_ = repository.Update(&funding, &model.GasFunding{Status: model.GasFundingStatus.FAILED})
+		}
+		return false, err
+	}
+	if err := repository.Update(&funding, &model.GasFunding{TransactionHash: sendSingleTransactionResponse.TransactionHash}); err != nil {
+		logger.Error("Gas station : funding %s of %s was broadcast but its hash could not be recorded : %s", funding.Reference, address, err)
+		return false, err
+	}
+	logger.Info("Gas station : funded %s with %d %s, sweep resumes once it is confirmed", address, funding.Amount, network.NativeAsset)
+	return false, nil
+}
+
+// GetRecoverableGasDust ... Returns what may go back to the fee wallet from an address, what was funded less the gas its sweeps spent
+// and the fee of sending it back. The rest of the native balance of the address is not the fee wallet's
+func GetRecoverableGasDust(funded, gasSpent, recoveryFee int64) int64 {
+	if dust := funded - gasSpent - recoveryFee; dust > 0 {
+		return dust
+	}
+	return 0
+}
+
+// recoverGasDust sends what is left of spent fundings back to the fee wallet. Addresses with deposits of the native coin not swept yet,
+// or deposits waiting to be swept in this run, are left alone until those are swept
+func recoverGasDust(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, candidateAddresses map[string]bool) {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	fundings := []model.GasFunding{}
+	if err := userAssetRepository.FetchGasFundings("", "", []string{model.GasFundingStatus.SPENT}, &fundings); err != nil {
+		logger.Error("Gas station : could not fetch spent fundings : %s", err)
+		return
+	}
+
+	fundingsPerAddress := map[string][]model.GasFunding{}
+	addresses := []string{}
+	for _, funding := range fundings {
+		key := funding.Address + utility.SWEEP_GROUPING_SEPERATOR + funding.Network
+		if _, ok := fundingsPerAddress[key]; !ok {
+			addresses = append(addresses, key)
+		}
+		fundingsPerAddress[key] = append(fundingsPerAddress[key], funding)
+	}
+	for _, key := range addresses {
+		spentFundings := fundingsPerAddress[key]
+		funding := spentFundings[0]
+		if candidateAddresses[funding.Address] {
+			continue
+		}
+		unsweptDeposits := 0
+		if err := userAssetRepository.CountUnsweptDeposits(funding.Address, funding.NativeAsset, &unsweptDeposits); err != nil || unsweptDeposits > 0 {
+			logger.Info("Gas station : leaving the dust of %s until its %s deposits are swept", funding.Address, funding.NativeAsset)
+			continue
+		}
+		gasSpent, isSettled := getSweepGasSpent(cache, logger, config, spentFundings)
+		if !isSettled {
+			continue
+		}
+		feeWalletAddress, err := GetFeeWalletAddress(repository, funding.Network, funding.NativeAsset)
+		if err != nil {
+			logger.Error("Gas station : leaving the dust of %s : %s", funding.Address, err)
+			continue
+		}
+
+		var funded int64
+		for _, spentFunding := range spentFundings {
+			funded += spentFunding.Amount
+		}
+		recoveryFee, err := getNativeTransferFee(cache, logger, config, funding.NativeAsset, funding.Network)
+		if err != nil {
+			logger.Error("Gas station : could not estimate the fee of recovering the dust of %s, retrying on the next run : %s", funding.Address, err)
+			continue
+		}
+		update := model.GasFunding{Status: model.GasFundingStatus.RECOVERED}
+		if dust := GetRecoverableGasDust(funded, gasSpent, recoveryFee); dust > 0 {
+			serviceErr := dto.ServicesRequestErr{}
+			sendSingleTransactionRequest := dto.SendSingleTransactionRequest{
+				FromAddress: funding.Address,
+				ToAddress:   feeWalletAddress,
+				Amount:      big.NewInt(dust),
+				AssetSymbol: funding.NativeAsset,
+				Network:     funding.Network,
+				ProcessType: utility.SWEEPPROCESS,
+				Reference:   fmt.Sprintf("GAS-RECOVERY-%s-%d", funding.Address, time.Now().UnixNano()),
+			}
+			sendSingleTransactionResponse := dto.SendTransactionResponse{}
+			if err := services.SendSingleTransaction(cache, logger, config, sendSingleTransactionRequest, &sendSingleTransactionResponse, &serviceErr); err != nil {
+				// dust that cannot pay for its own transfer is not worth retrying
+				if serviceErr.Code != errorcode.INSUFFICIENT_FUNDS {
+					logger.Error("Gas station : could not recover the dust of %s, retrying on the next run : %s", funding.Address, err)
+					continue
+				}
+				logger.Info("Gas station : dust left on %s is below the fee of recovering it", funding.Address)
+			} else {
+				update.RecoveredAmount = dust
+			}
+			update.RecoveryReference, update.RecoveryTransactionHash = sendSingleTransactionRequest.Reference, sendSingleTransactionResponse.TransactionHash
+		} else {
+			logger.Info("Gas station : nothing of the fundings of %s is left to recover after the sweep fee", funding.Address)
+		}
+		for i := range spentFundings {
+			if err := repository.Update(&spentFundings[i], &update); err != nil {
+				logger.Error("Gas station : could not mark funding %s of %s as recovered : %s", spentFundings[i].Reference, funding.Address, err)
+			}
+		}
+	}
+}
+
+// getSweepGasSpent returns the fee paid by the token sweeps that spent the fundings, and false while one of them is not settled.
+// A funding spent without a sweep reference is taken as spent in full
+func getSweepGasSpent(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, spentFundings []model.GasFunding) (int64, bool) {
+	var gasSpent int64
+	sweepReferences := map[string]bool{}
+	for _, funding := range spentFundings {
+		if funding.SweepReference == "" {
+			gasSpent += funding.Amount
+			continue
+		}
+		if sweepReferences[funding.SweepReference] {
+			continue
+		}
+		sweepReferences[funding.SweepReference] = true
+
+		found, sweepDetails, err := services.GetBroadcastedTXNDetailsByRef(funding.SweepReference, funding.AssetSymbol, funding.Network, cache, logger, config)
+		if err != nil || !found || (sweepDetails.Status != utility.SUCCESSFUL && sweepDetails.Status != utility.FAILED) {
+			logger.Info("Gas station : sweep %s of %s is not settled, leaving its dust until it is", funding.SweepReference, funding.Address)
+			return 0, false
+		}
+		fee, err := strconv.ParseInt(sweepDetails.TransactionFee, 10, 64)
+		if err != nil {
+			logger.Error("Gas station : invalid fee %s for sweep %s of %s, leaving its dust", sweepDetails.TransactionFee, funding.SweepReference, funding.Address)
+			return 0, false
+		}
+		gasSpent += fee
+	}
+	return gasSpent, true
+}
+
+func getNativeTransferFee(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, nativeAsset, network string) (int64, error) {
+	networkFeeResponse := dto.NetworkFeeResponse{}
+	request := dto.NetworkFeeRequest{AssetSymbol: nativeAsset, Network: network}
+	if err := services.GetNetworkFee(cache, logger, config, request, &networkFeeResponse, &dto.ServicesRequestErr{}); err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(networkFeeResponse.Fee, 10, 64)
+}
@@ -1,6 +1,7 @@
 package tasks
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/big"
@@ -25,38 +26,121 @@ type (
 	SweepParam struct {
 		FloatAddress     string
 		BrokerageAddress string
+		ColdAddress      string
 		FloatPercent     int64
 		BrokeragePercent int64
+		ColdPercent      int64
+		Memo             string
+		Policy           string
 	}
 )
 
 func SweepTransactions(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository) {
+	SweepTransactionsWithContext(context.Background(), cache, logger, config, repository)
+}
+
+// SweepTransactionsWithContext ... Sweeps deposits to the float and brokerage accounts, addresses are swept concurrently by a bounded pool of workers.
+// Cancelling the context stops new sweeps from starting, the sweeps in flight are completed and recorded before the lock is released
+func SweepTransactionsWithContext(ctx context.Context, cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository) {
 	logger.Info("Sweep operation begins")
 	serviceErr := dto.ServicesRequestErr{}
-	token, err := AcquireLock("sweep", utility.SIX_HUNDRED_MILLISECONDS, cache, logger, config, serviceErr)
+	lockTTL := GetSweepLockTTL(config)
+	token, err := AcquireLock("sweep", lockTTL, cache, logger, config, serviceErr)
 	if err != nil {
 		logger.Error("Could not acquire lock", err)
 		return
 	}
-
-	var transactions []model.Transaction
-	if err := repository.FetchSweepCandidates(&transactions); err != nil {
-		logger.Error("Error response from Sweep job : could not fetch sweep candidates %+v", err)
-		if err := releaseLock(cache, logger, config, token, serviceErr); err != nil {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lease := startSweepLease(cache, logger, config, token, lockTTL, cancel)
+	defer func() {
+		lease.Stop()
+		if err := releaseLock(cache, logger, config, lease.Token(), serviceErr); err != nil {
 			logger.Error("Could not release lock", err)
 			return
 		}
+		logger.Info("Sweep operation ends, lock released")
+	}()
+
+	sweepRun := StartSweepRun(repository, logger)
+	var runErr error
+	defer func() {
+		if runErr == nil && lease.Err() != nil {
+			runErr = lease.Err()
+		} else if runErr == nil {
+			runErr = ctx.Err()
+		}
+		FinishSweepRun(repository, &sweepRun, runErr, logger)
+	}()
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	transactions, err := getSweepCandidates(repository, userAssetRepository, logger)
+	if err != nil {
+		runErr = err
+		return
+	}
+	sweepRun.CandidateCount = len(transactions)
+	logger.Info("Fetched %d sweep candidates", len(transactions))
+
+	batchAssetTransactionsToSweep, batchAddresses, err := getBatchTransactions(repository, userAssetRepository, transactions, logger)
+	if err != nil {
+		runErr = err
 		return
 	}
+	//remove btc transactions from list of remaining transactions
+	transactions = RemoveBatchTransactions(transactions, batchAssetTransactionsToSweep)
+	//Do other Coins apart from batchable assets
+	transactionsPerAddressPerAssetSymbol, err := GroupTxByAddressByAssetSymbolAndNetwork(transactions, repository, logger)
+	if err != nil {
+		logger.Error("Error grouping By Address", err)
+		runErr = err
+		return
+	}
+	candidateAddresses := map[string]bool{}
+	for _, address := range batchAddresses {
+		candidateAddresses[address] = true
+	}
+	for addressAndAssetSymbol := range transactionsPerAddressPerAssetSymbol {
+		candidateAddresses[strings.Split(addressAndAssetSymbol, utility.SWEEP_GROUPING_SEPERATOR)[0]] = true
+	}
+	recoverGasDust(cache, logger, config, repository, candidateAddresses)
+
+	sweepAddresses(ctx, cache, logger, config, repository, userAssetRepository, serviceErr, &sweepRun, transactionsPerAddressPerAssetSymbol)
+
+	//batch process btc
+	if len(batchAddresses) > 0 && ctx.Err() == nil {
+		transactionsPerAssetSymbol, batchAddressesPerAssetSymbol, _ := GroupTxByAssetSymbol(batchAssetTransactionsToSweep, repository, logger)
+		for assetSymbol, addressTransactions := range transactionsPerAssetSymbol{
+			if ctx.Err() != nil {
+				break
+			}
+			sweep, err := planBatchSweep(cache, logger, config, repository, userAssetRepository, assetSymbol, batchAddressesPerAssetSymbol[assetSymbol], addressTransactions, false)
+			if err == nil {
+				err = sweepBatchTx(cache, logger, config, repository, serviceErr, &sweep, batchAddressesPerAssetSymbol[assetSymbol], addressTransactions)
+			}
+			RecordSweepItem(repository, &sweepRun, sweep.item, err, logger)
+			if err != nil {
+				logger.Error("Error response from Sweep job : %+v while sweeping batch transactions", err)
+				runErr = err
+				return
+			}
+		}
+
+	}
+}
+
+// getSweepCandidates fetches the deposits to sweep, binance internal deposits without a valid reference and networks under maintenance are left out
+func getSweepCandidates(repository database.BaseRepository, userAssetRepository database.UserAssetRepository, logger *utility.Logger) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	if err := repository.FetchSweepCandidates(&transactions); err != nil {
+		logger.Error("Error response from Sweep job : could not fetch sweep candidates %+v", err)
+		return nil, err
+	}
 
 	var binanceDepositTransactions []model.Transaction
 	if err := repository.FetchBinanceOnchainSweepCandidates(&binanceDepositTransactions); err != nil {
 		logger.Error("Error response from Sweep job : could not fetch binance internal deposit sweep candidates %+v", err)
-		if err := releaseLock(cache, logger, config, token, serviceErr); err != nil {
-			logger.Error("Could not release lock", err)
-			return
-		}
-		return
+		return nil, err
 	}
 	for _, transaction := range binanceDepositTransactions {
 		if utility.IsValidUUID(transaction.TransactionReference) {
@@ -64,29 +148,23 @@ func SweepTransactions(cache *utility.MemoryCache, logger *utility.Logger, confi
 		}
 	}
 
-	logger.Info("Fetched %d sweep candidates", len(transactions))
+	return RemoveTransactionsUnderMaintenance(userAssetRepository, transactions, logger), nil
+}
 
+// getBatchTransactions returns the candidates on batchable networks and their recipient addresses, these are swept in one batch per asset
+func getBatchTransactions(repository database.BaseRepository, userAssetRepository database.UserAssetRepository, transactions []model.Transaction, logger *utility.Logger) ([]model.Transaction, []string, error) {
 	var batchAddresses []string
 	var batchAssetTransactionsToSweep []model.Transaction
-	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
 	for _, tx := range transactions {
 		recipientAsset := model.UserAsset{}
 		if err := userAssetRepository.GetAssetsByID(&model.UserAsset{BaseModel: model.BaseModel{ID: tx.RecipientID}}, &recipientAsset); err != nil {
 			logger.Error("Error sweeping for asset with id : %+v, could not get recipient asset for transaction. Error : %+v", recipientAsset.ID, err)
-			if err := releaseLock(cache, logger, config, token, serviceErr); err != nil {
-				logger.Error("Could not release lock", err)
-				return
-			}
-			return
+			return nil, nil, err
 		}
 		txNetworkAsset, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, tx.Network, tx.AssetSymbol)
 		if err != nil {
 			logger.Error("Error sweeping for asset with id : %+v, could not get network asset for transaction with assetSymbol : %s, network : %s. Error : %+v", recipientAsset.ID, tx.AssetSymbol, tx.Network,  err)
-			if err := releaseLock(cache, logger, config, token, serviceErr); err != nil {
-				logger.Error("Could not release lock", err)
-				return
-			}
-			return
+			return nil, nil, err
 		}
 
 
@@ -104,45 +182,28 @@ func SweepTransactions(cache *utility.MemoryCache, logger *utility.Logger, confi
 			batchAssetTransactionsToSweep = append(batchAssetTransactionsToSweep, tx)
 		}
 	}
-	batchAddresses = ToUniqueAddresses(batchAddresses)
-	//remove btc transactions from list of remaining transactions
-	transactions = RemoveBatchTransactions(transactions, batchAssetTransactionsToSweep)
-	//Do other Coins apart from batchable assets
-	transactionsPerAddressPerAssetSymbol, err := GroupTxByAddressByAssetSymbolAndNetwork(transactions, repository, logger)
-	if err != nil {
-		logger.Error("Error grouping By Address", err)
-		return
-	}
-	for addressAndAssetSymbol, addressTransactions := range transactionsPerAddressPerAssetSymbol {
-		stringSlice := strings.Split(addressAndAssetSymbol, utility.SWEEP_GROUPING_SEPERATOR)
-		var address = stringSlice[0]
-		sum := CalculateSum(addressTransactions)
-		logger.Info("Sweeping %s with total of %d", address, sum)
-		if err := sweepPerAddress(cache, logger, config, repository, userAssetRepository, serviceErr, addressTransactions, sum, address); err != nil {
-			logger.Error("Error response from Sweep job : %+v while sweepPerAddress for address %s", err, address)
-			continue
-		}
-	}
-	//batch process btc
-	if len(batchAddresses) > 0 {
-		transactionsPerAssetSymbol, batchAddressesPerAssetSymbol, _ := GroupTxByAssetSymbol(batchAssetTransactionsToSweep, repository, logger)
-		for assetSymbol, addressTransactions := range transactionsPerAssetSymbol{
-			if err := sweepBatchTx(cache, logger, config, repository, userAssetRepository, serviceErr, batchAddressesPerAssetSymbol[assetSymbol], addressTransactions); err != nil {
-				logger.Error("Error response from Sweep job : %+v while sweeping batch transactions", err)
-				if err := releaseLock(cache, logger, config, token, serviceErr); err != nil {
-					logger.Error("Could not release lock", err)
-					return
-				}
-				return
+	return batchAssetTransactionsToSweep, ToUniqueAddresses(batchAddresses), nil
+}
+
+// RemoveTransactionsUnderMaintenance ... Leaves out deposits on networks where sweeping is paused by a maintenance window, they are swept after the window ends
+func RemoveTransactionsUnderMaintenance(userAssetRepository database.UserAssetRepository, transactions []model.Transaction, logger *utility.Logger) []model.Transaction {
+	pausedNetworks := map[string]bool{}
+	sweepableTransactions := []model.Transaction{}
+	for _, tx := range transactions {
+		key := tx.AssetSymbol + utility.SWEEP_GROUPING_SEPERATOR + tx.Network
+		isPaused, isChecked := pausedNetworks[key]
+		if !isChecked {
+			if err := services.CheckMaintenance(&userAssetRepository, tx.AssetSymbol, tx.Network, model.MaintenanceScope.SWEEP); err != nil {
+				logger.Info("Sweep job : skipping sweep of %s on %s network : %s", tx.AssetSymbol, tx.Network, err)
+				isPaused = true
 			}
+			pausedNetworks[key] = isPaused
+		}
+		if !isPaused {
+			sweepableTransactions = append(sweepableTransactions, tx)
 		}
-
-	}
-	if err := releaseLock(cache, logger, config, token, serviceErr); err != nil {
-		logger.Error("Could not release lock", err)
-		return
 	}
-	logger.Info("Sweep operation ends successfully, lock released")
+	return sweepableTransactions
 }
 
 func CalculateSum(addressTransactions []model.Transaction) float64 {
@@ -165,62 +226,39 @@ func CalculateSumOfBatch(addressTransactions []model.Transaction) float64 {
 	return sum
 }
 
-func sweepBatchTx(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, userAssetRepository database.UserAssetRepository, serviceErr dto.ServicesRequestErr, batchAddresses []string, batchAssetTransactionsToSweep []model.Transaction) error {
-
-	txNetworkAsset, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, batchAssetTransactionsToSweep[0].Network, batchAssetTransactionsToSweep[0].AssetSymbol)
-	if err != nil {
-		return err
+func sweepBatchTx(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, serviceErr dto.ServicesRequestErr, sweep *plannedSweep, batchAddresses []string, batchAssetTransactionsToSweep []model.Transaction) error {
+	if sweep.item.SkipReason != "" {
+		return nil
 	}
 
+	sweepParam := sweepParamOf(sweep.item)
 	recipientData := []dto.BatchRecipients{}
-	//get float
-	floatAccount, err := getFloatDetails(repository, txNetworkAsset.AssetSymbol, txNetworkAsset.Network, logger)
-	if err != nil {
-		return err
-	}
-
-	//check total sum threshold for this batch
-	totalSweepSum := CalculateSumOfBatch(batchAssetTransactionsToSweep)
-	if totalSweepSum < txNetworkAsset.MinimumSweepable {
-		logger.Error("Error response from sweep job : Total sweep sum %v for asset (%s) is below the minimum sweep %v, so terminating sweep process", totalSweepSum, txNetworkAsset.AssetSymbol, txNetworkAsset.MinimumSweepable, err)
-		return err
-	}
-
-	sweepParam, err := GetSweepParams(cache, logger, config, repository, floatAccount, txNetworkAsset, totalSweepSum)
-	if err != nil {
-		logger.Error("Error response from Sweep job : %+v while getting sweep params for %s", err, floatAccount.AssetSymbol)
-		return err
-	}
-
-	if sweepParam.FloatPercent != int64(0) {
-		floatRecipient := dto.BatchRecipients{
-			Address: sweepParam.FloatAddress,
-			Value:   sweepParam.FloatPercent,
-		}
-		recipientData = append(recipientData, floatRecipient)
-	}
-	if sweepParam.BrokeragePercent != int64(0) {
-		brokerageRecipient := dto.BatchRecipients{
-			Address: sweepParam.BrokerageAddress,
-			Value:   sweepParam.BrokeragePercent,
+	for _, recipient := range []dto.BatchRecipients{
+		{Address: sweepParam.FloatAddress, Value: sweepParam.FloatPercent},
+		{Address: sweepParam.BrokerageAddress, Value: sweepParam.BrokeragePercent},
+		{Address: sweepParam.ColdAddress, Value: sweepParam.ColdPercent},
+	} {
+		if recipient.Value != int64(0) {
+			recipientData = append(recipientData, recipient)
 		}
-		recipientData = append(recipientData, brokerageRecipient)
 	}
 	sendBatchTransactionRequest := dto.BatchRequest{
-		AssetSymbol:   txNetworkAsset.AssetSymbol,
-		ChangeAddress: sweepParam.BrokerageAddress,
+		AssetSymbol:   sweep.txNetworkAsset.AssetSymbol,
+		ChangeAddress: sweepParam.ChangeAddress(),
 		IsSweep:       true,
 		Origins:       batchAddresses,
 		Recipients:    recipientData,
 		ProcessType:   utility.SWEEPPROCESS,
-		Network: txNetworkAsset.Network,
-		Reference:     fmt.Sprintf("SWEEP-%s-%d", txNetworkAsset.AssetSymbol, time.Now().Unix()),
+		Network: sweep.txNetworkAsset.Network,
+		Reference:     fmt.Sprintf("SWEEP-%s-%d", sweep.txNetworkAsset.AssetSymbol, time.Now().Unix()),
 	}
+	sweep.item.Reference = sendBatchTransactionRequest.Reference
 	sendBatchTransactionResponse := dto.SendTransactionResponse{}
 	if err := services.SendBatchTransaction(nil, cache, logger, config, sendBatchTransactionRequest, &sendBatchTransactionResponse, serviceErr); err != nil {
 		logger.Error("Error response from SendBatchTransaction : %+v while sweeping batch transactions", err)
 		return err
 	}
+	sweep.item.TransactionHash = sendBatchTransactionResponse.TransactionHash
 	if err := updateSweptStatus(batchAssetTransactionsToSweep, repository, logger); err != nil {
 		return err
 	}
@@ -228,97 +266,65 @@ func sweepBatchTx(cache *utility.MemoryCache, logger *utility.Logger, config Con
 
 }
 
-func sweepPerAddress(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, userAssetRepository database.UserAssetRepository, serviceErr dto.ServicesRequestErr, addressTransactions []model.Transaction, sum float64, recipientAddress string) error {
-	transactionListInfo, e := getTransactionListInfo(repository, addressTransactions, logger)
-	if e != nil {
-		return e
-	}
-
-	txNetworkAsset, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, transactionListInfo.Network, transactionListInfo.AssetSymbol)
-	if  err != nil {
-		logger.Error("Error response from sweep job : %+v while trying to get network asset of float asset, network is %+v and assetSymbol is %+v", err, transactionListInfo.Network, transactionListInfo.AssetSymbol)
-		return err
-	}
+func sweepPerAddress(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, serviceErr dto.ServicesRequestErr, sweep *plannedSweep, addressTransactions []model.Transaction) error {
+	recipientAddress := sweep.item.Address
+	txNetworkAsset := sweep.txNetworkAsset
 
-	var userAddress model.UserAddress
-	var queryParam *model.UserAddress
-	if txNetworkAsset.RequiresMemo {
-		queryParam = &model.UserAddress{V2Address: recipientAddress}
-	} else {
-		queryParam = &model.UserAddress{Address: recipientAddress}
-	}
-
-	err = repository.GetByFieldName(queryParam, &userAddress)
-	if err != nil {
-		logger.Error(fmt.Sprintf("Error getting address provider for address : %s, defaulting to BUNDLE", recipientAddress))
-		userAddress.AddressProvider = model.AddressProvider.BUNDLE
-	}
-
-	if userAddress.AddressProvider == model.AddressProvider.BINANCE {
+	if sweep.userAddress.AddressProvider == model.AddressProvider.BINANCE {
 		//call Binance brokerage service
 		service := services.BaseService{Config: config, Cache: cache, Logger: logger}
-		_, sweepErr := service.SweepUserAddress(transactionListInfo.UserId, transactionListInfo.AssetSymbol, utility.FloatToString(sum))
+		sweepResponse, sweepErr := service.SweepUserAddress(sweep.transactionListInfo.UserId, sweep.transactionListInfo.AssetSymbol, sweep.item.Amount)
 		if sweepErr != nil {
 			logger.Error("Error response from Binance Brokerage service : %+v while sweeping for address with id %+v", sweepErr, recipientAddress)
 			return sweepErr
 		}
+		sweep.item.Reference, sweep.item.TransactionHash = sweepResponse.ClientTranId, sweepResponse.TxnId
 		if err := updateSweptStatus(addressTransactions, repository, logger); err != nil {
 			return err
 		}
 		return nil
 	}
 
-	if txNetworkAsset.CoinType == constants.TRX_COINTYPE {
-		isExceededLimit := HasExceededTrxSweepLimit(userAddress, logger, transactionListInfo.AssetSymbol, repository)
-		if isExceededLimit {
-			return nil
-		}
-	}
-
-	//Check that sweep amount is not below the minimum sweep amount
-	isAmountSufficient, err := CheckSweepMinimum(txNetworkAsset, config, sum, logger)
-	if !isAmountSufficient {
-		return err
-	}
-
-	floatAccount, err := getFloatDetails(repository, transactionListInfo.AssetSymbol, transactionListInfo.Network, logger)
-	if err != nil {
-		return err
-	}
-
-	toAddress, addressMemo, err := GetSweepAddressAndMemo(cache, logger, config, repository, floatAccount, txNetworkAsset)
-	if err != nil {
-		logger.Error("Error response from Sweep job : %+v while getting sweep toAddress and memo for %s", err, floatAccount.AssetSymbol)
-		return err
+	switch sweep.item.SkipReason {
+	case model.SweepSkipReason.TRX_DAILY_LIMIT:
+		// resets the daily counter of addresses that reached the limit
+		HasExceededTrxSweepLimit(sweep.userAddress, logger, sweep.transactionListInfo.AssetSymbol, repository)
+		return nil
+	case model.SweepSkipReason.BELOW_MINIMUM:
+		return nil
 	}
 
-	//Do this only for BEp-2 tokens and not for BNB itself
-	if txNetworkAsset.RequiresMemo && *txNetworkAsset.IsToken {
-		//send sweep fee to main address
-		err, _ := fundSweepFee(floatAccount, txNetworkAsset, recipientAddress, userAddress.Network, cache, logger, config, serviceErr, addressTransactions, repository)
+	//tokens are swept once the address holds enough of the native coin for the fee
+	if RequiresSweepGas(txNetworkAsset) {
+		isFunded, err := EnsureSweepGas(cache, logger, config, repository, txNetworkAsset, recipientAddress, sweep.gasFee)
 		if err != nil {
 			return err
 		}
-		time.Sleep(time.Second * utility.FUND_SWEEP_FEE_WAIT_TIME)
+		if !isFunded {
+			sweep.item.SkipReason = model.SweepSkipReason.AWAITING_GAS
+			return nil
+		}
 	}
 
 	sendSingleTransactionRequest := dto.SendSingleTransactionRequest{
 		FromAddress: recipientAddress,
-		ToAddress:   toAddress,
-		Memo:        addressMemo,
+		ToAddress:   sweep.toAddress,
+		Memo:        sweep.item.Memo,
 		Amount:      big.NewInt(0),
-		AssetSymbol: transactionListInfo.AssetSymbol,
-		Network: transactionListInfo.Network,
+		AssetSymbol: sweep.transactionListInfo.AssetSymbol,
+		Network: sweep.transactionListInfo.Network,
 		IsSweep:     true,
 		ProcessType: utility.SWEEPPROCESS,
 		Reference:   fmt.Sprintf("%s-%d", recipientAddress, time.Now().Unix()),
 	}
 
+	sweep.item.Reference = sendSingleTransactionRequest.Reference
 	sendSingleTransactionResponse := dto.SendTransactionResponse{}
 	if err := services.SendSingleTransaction(cache, logger, config, sendSingleTransactionRequest, &sendSingleTransactionResponse, &serviceErr); err != nil {
 		logger.Error("Error response from SendSingleTransaction : %+v while sweeping for address with id %+v", err, recipientAddress)
 		switch serviceErr.Code {
 		case errorcode.INSUFFICIENT_FUNDS:
+			sweep.item.SkipReason = model.SweepSkipReason.INSUFFICIENT_FUNDS
 			if err := updateSweptStatus(addressTransactions, repository, logger); err != nil {
 				return err
 			}
@@ -327,9 +333,13 @@ func sweepPerAddress(cache *utility.MemoryCache, logger *utility.Logger, config
 			return err
 		}
 	}
+	sweep.item.TransactionHash = sendSingleTransactionResponse.TransactionHash
 
+	if RequiresSweepGas(txNetworkAsset) {
+		_ = MarkSweepGasSpent(repository, recipientAddress, txNetworkAsset.Network, sendSingleTransactionRequest.Reference, logger)
+	}
 	if txNetworkAsset.CoinType == constants.TRX_COINTYPE {
-		_ = incrementTRXSweepCount(repository, userAddress)
+		_ = incrementTRXSweepCount(repository, sweep.userAddress)
 	}
 
 	if err := updateSweptStatus(addressTransactions, repository, logger); err != nil {
@@ -339,18 +349,20 @@ func sweepPerAddress(cache *utility.MemoryCache, logger *utility.Logger, config
 }
 
 func HasExceededTrxSweepLimit(userAddress model.UserAddress, logger *utility.Logger, assetSymbol string, repository database.BaseRepository) bool {
-	if userAddress.NextSweepTime == nil {
-		nextSweepTime := time.Now()
-		userAddress.NextSweepTime = &nextSweepTime
-	}
 	if userAddress.SweepCount >= constants.DAILY_TRX_SWEEP_COUNT {
 		logger.Error("Daily sweep limit exceeded for %s, postponing sweep to reset counter", assetSymbol)
 		_ = ResetTRXSweepCount(repository, &userAddress)
 		return true
-	} else if userAddress.SweepCount == 0 && userAddress.NextSweepTime.UnixNano() > time.Now().UnixNano() {
+	}
+	return exceedsTrxSweepLimit(userAddress)
+}
+
+// exceedsTrxSweepLimit reports whether an address used up its daily TRX sweeps, or is waiting for its counter to reset
+func exceedsTrxSweepLimit(userAddress model.UserAddress) bool {
+	if userAddress.SweepCount >= constants.DAILY_TRX_SWEEP_COUNT {
 		return true
 	}
-	return false
+	return userAddress.SweepCount == 0 && userAddress.NextSweepTime != nil && userAddress.NextSweepTime.After(time.Now())
 }
 
 func ResetTRXSweepCount(repository database.BaseRepository, userAddress *model.UserAddress) error {
@@ -513,55 +525,39 @@ func ToUniqueAddresses(addresses []string) []string {
 	return list
 }
 
-func fundSweepFee(floatAccount model.HotWalletAsset, txNetworkAsset model.Network, recipientAddress, network string, cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, serviceErr dto.ServicesRequestErr, assetTransactions []model.Transaction, repository database.BaseRepository) (error, bool) {
-
-	request := dto.OnchainBalanceRequest{
-		AssetSymbol: txNetworkAsset.NativeAsset,
-		Network: network,
-		Address:     recipientAddress,
-	}
-	mainCoinOnChainBalanceResponse := dto.OnchainBalanceResponse{}
-	if err := services.GetOnchainBalance(cache, logger, config, request, &mainCoinOnChainBalanceResponse, serviceErr); err != nil {
-		logger.Error("Error response from Sweep job : %+v while getting on-chain balance for %+v", err, recipientAddress)
-		return err, true
-	}
-	mainCoinOnChainBalance, _ := strconv.ParseUint(mainCoinOnChainBalanceResponse.Balance, 10, 64)
-	//check if onchain balance in main coin asset is less than floatAccount.SweepFee
-	if int64(mainCoinOnChainBalance) < txNetworkAsset.SweepFee {
-
-		sendSingleTransactionRequest := dto.SendSingleTransactionRequest{
-			FromAddress: floatAccount.Address,
-			ToAddress:   recipientAddress,
-			Amount:      big.NewInt(txNetworkAsset.SweepFee),
-			AssetSymbol: txNetworkAsset.NativeAsset,
-			Network: network,
-			//this currently only supports coins that supports Memo,  other coins will be ignored
-			Memo:        utility.SWEEPMEMOBNB,
-			ProcessType: utility.FLOATPROCESS,
-			Reference:   fmt.Sprintf("%s-%s", txNetworkAsset.AssetSymbol, assetTransactions[0].TransactionReference),
-		}
-		sendSingleTransactionResponse := dto.SendTransactionResponse{}
-		if err := services.SendSingleTransaction(cache, logger, config, sendSingleTransactionRequest, &sendSingleTransactionResponse, &serviceErr); err != nil {
-			logger.Error("Error response from Sweep job : %+v while funding sweep fee for  %+v", err, recipientAddress)
-			return err, true
-		}
-		//return immediately after broadcasting sweep fee, this allows for confirmation, next time sweep runs,
-		// int64(mainCoinOnChainBalance) will be > floatAccount.SweepFee, and so this if block will be skipped
-		//i.e sweep fee will not be resent to user address
-		return nil, true
+func GetSweepParams(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, floatAccount model.HotWalletAsset, txNetworkAsset model.Network, sweepFund float64, isDryRun bool) (SweepParam, error) {
+
+	serviceErr := dto.ServicesRequestErr{}
+	floatPercent, err := getFloatFillPercent(cache, logger, config, repository, floatAccount, txNetworkAsset, sweepFund, isDryRun)
+	if err != nil {
+		return SweepParam{}, err
+	}
+
+	brokerageAccountResponse, err := GetBrokerAccountFor(floatAccount.AssetSymbol, txNetworkAsset, repository, cache, config, logger, serviceErr)
+	if err != nil {
+		return SweepParam{}, err
 	}
-	return nil, false
+
+	sweepParam := SweepParam{
+		FloatAddress:     floatAccount.Address,
+		FloatPercent:     floatPercent,
+		BrokerageAddress: brokerageAccountResponse.Address,
+		BrokeragePercent: int64(100) - floatPercent,
+	}
+
+	return sweepParam, nil
 }
 
-func GetSweepParams(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, floatAccount model.HotWalletAsset, txNetworkAsset model.Network, sweepFund float64) (SweepParam, error) {
+// getFloatFillPercent returns the percentage of the sweep funds that tops the float up to its target, what is left goes to the brokerage or cold storage.
+// A dry run reads the deposits and withdrawals since the last float manager run without moving the float manager on past them
+func getFloatFillPercent(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, floatAccount model.HotWalletAsset, txNetworkAsset model.Network, sweepFund float64, isDryRun bool) (int64, error) {
 
-	sweepParam := SweepParam{}
 	serviceErr := dto.ServicesRequestErr{}
 
 	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
 	totalUsersBalance, err := GetTotalUserBalance(repository, floatAccount.AssetSymbol, txNetworkAsset.NativeDecimals,  logger, userAssetRepository)
 	if err != nil {
-		return sweepParam, err
+		return 0, err
 	}
 	logger.Info("SWEEP_OPERATION : Total users balance for this hot wallet %+v is %+v and total amount to sweep is %+v", floatAccount.AssetSymbol, totalUsersBalance, sweepFund)
 
@@ -575,7 +571,7 @@ func GetSweepParams(cache *utility.MemoryCache, logger *utility.Logger, config C
 	floatOnChainBalanceResponse := dto.OnchainBalanceResponse{}
 	if err := services.GetOnchainBalance(cache, logger, config, onchainBalanceRequest, &floatOnChainBalanceResponse, serviceErr); err != nil {
 		logger.Error("Error response from Sweep job : %+v while getting float on-chain balance for %+v", err, floatAccount.AssetSymbol)
-		return sweepParam, err
+		return 0, err
 	}
 	floatOnChainBalance, _ := new(big.Float).SetPrec(prec).SetString(floatOnChainBalanceResponse.Balance)
 	logger.Info("SWEEP_OPERATION : Float on-chain balance for this hot wallet %+v is %+v", floatAccount.AssetSymbol, floatOnChainBalance)
@@ -583,43 +579,39 @@ func GetSweepParams(cache *utility.MemoryCache, logger *utility.Logger, config C
 	// Get float manager parameters to calculate float range
 	floatManagerParams, err := getFloatParamFor(floatAccount.AssetSymbol, floatAccount.Network, repository, logger)
 	if err != nil {
-		return sweepParam, err
+		return 0, err
 	}
 	minimumFloatBalance, maximumFloatBalance := GetFloatBalanceRange(floatManagerParams, totalUsersBalance, logger)
 
 	// Get total deposit sum from the last run of this job
-	depositSumFromLastRun, err := getDepositsSumForAssetFromDate(repository, floatAccount.AssetSymbol, floatAccount.Network, logger, floatAccount)
+	var depositSumFromLastRun, withdrawalSumFromLastRun *big.Float
+	if isDryRun {
+		depositSumFromLastRun, _, err = sumDepositsForAssetFromDate(repository, floatAccount.AssetSymbol, floatAccount.Network, logger, floatAccount)
+	} else {
+		depositSumFromLastRun, err = getDepositsSumForAssetFromDate(repository, floatAccount.AssetSymbol, floatAccount.Network, logger, floatAccount)
+	}
 	if err != nil {
 		logger.Info("error with float manager process, while trying to get the total deposit sum from last run : %+v", err)
-		return sweepParam, err
+		return 0, err
 	}
 	logger.Info("depositSumFromLastRun for this hot wallet (%s) is %+v", floatAccount.AssetSymbol, depositSumFromLastRun)
 
 	// Get total withdrawal sum from the last run of this job
-	withdrawalSumFromLastRun, err := getWithdrawalsSumForAssetFromDate(repository, floatAccount.AssetSymbol, floatAccount.Network, logger, floatAccount)
+	if isDryRun {
+		withdrawalSumFromLastRun, _, err = sumWithdrawalsForAssetFromDate(repository, floatAccount.AssetSymbol, floatAccount.Network, logger, floatAccount)
+	} else {
+		withdrawalSumFromLastRun, err = getWithdrawalsSumForAssetFromDate(repository, floatAccount.AssetSymbol, floatAccount.Network, logger, floatAccount)
+	}
 	if err != nil {
 		logger.Info("error with float manager process, while trying to get the total withdrawal sum from last run : %+v", err)
-		return sweepParam, err
+		return 0, err
 	}
 	logger.Info("withdrawalSumFromLastRun for this hot wallet %+v is %+v", floatAccount.AssetSymbol, withdrawalSumFromLastRun)
 
 	floatDeficit := GetFloatDeficit(depositSumFromLastRun, withdrawalSumFromLastRun, minimumFloatBalance, maximumFloatBalance, floatOnChainBalance, logger)
 
-	brokerageAccountResponse, err := GetBrokerAccountFor(floatAccount.AssetSymbol, txNetworkAsset, repository, cache, config, logger, serviceErr)
-	if err != nil {
-		return sweepParam, err
-	}
-
-	floatPercent, brokeragePercent := GetSweepPercentages(floatOnChainBalance, minimumFloatBalance, floatDeficit, big.NewFloat(sweepFund), totalUsersBalance, floatManagerParams, logger)
-
-	sweepParam = SweepParam{
-		FloatAddress:     floatAccount.Address,
-		FloatPercent:     floatPercent,
-		BrokerageAddress: brokerageAccountResponse.Address,
-		BrokeragePercent: brokeragePercent,
-	}
-
-	return sweepParam, nil
+	floatPercent, _ := GetSweepPercentages(floatOnChainBalance, minimumFloatBalance, floatDeficit, big.NewFloat(sweepFund), totalUsersBalance, floatManagerParams, logger)
+	return floatPercent, nil
 }
 
 func GetSweepPercentages(floatOnChainBalance, minimumFloatBalance, floatDeficit, sweepFund, totalUsersBalance *big.Float, floatManagerParams model.FloatManagerParam, logger *utility.Logger) (int64, int64) {
@@ -705,10 +697,38 @@ func GeTFloatPercent(accountDeficit, sweepFund *big.Float) *big.Int {
 
 func GetSweepAddressAndMemo(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, floatAccount model.HotWalletAsset, txNetworkAsset model.Network) (string, string, error) {
 
+	isBelowMinimum, err := isFloatBelowMinimum(cache, logger, config, repository, floatAccount, txNetworkAsset)
+	if err != nil {
+		return "", "", err
+	}
+	if isBelowMinimum {
+		return floatAccount.Address, "", nil
+	}
+
+	// Get broker account
+	serviceErr := dto.ServicesRequestErr{}
+	brokerageAccountResponse := dto.DepositAddressResponse{}
+
+	if *txNetworkAsset.IsToken {
+		err = services.GetDepositAddress(cache, logger, config, floatAccount.AssetSymbol, txNetworkAsset.NativeAsset, &brokerageAccountResponse, serviceErr)
+	} else {
+		err = services.GetDepositAddress(cache, logger, config, floatAccount.AssetSymbol, "", &brokerageAccountResponse, serviceErr)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	logger.Info("SWEEP_OPERATION : Brokerage account for this hot wallet %+v is %+v", floatAccount.AssetSymbol, brokerageAccountResponse)
+
+	return brokerageAccountResponse.Address, brokerageAccountResponse.Tag, nil
+}
+
+// isFloatBelowMinimum checks the float on-chain balance against the minimum percentage of the total user balance it should hold
+func isFloatBelowMinimum(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, floatAccount model.HotWalletAsset, txNetworkAsset model.Network) (bool, error) {
+
 	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
 	totalUsersBalance, err := GetTotalUserBalance(repository, floatAccount.AssetSymbol, txNetworkAsset.NativeDecimals, logger, userAssetRepository)
 	if err != nil {
-		return "", "", err
+		return false, err
 	}
 	logger.Info("SWEEP_OPERATION : Total users balance for this hot wallet %+v is %+v", floatAccount.AssetSymbol, totalUsersBalance)
 
@@ -723,28 +743,15 @@ func GetSweepAddressAndMemo(cache *utility.MemoryCache, logger *utility.Logger,
 	floatOnChainBalanceResponse := dto.OnchainBalanceResponse{}
 	if err := services.GetOnchainBalance(cache, logger, config, onchainBalanceRequest, &floatOnChainBalanceResponse, serviceErr); err != nil {
 		logger.Error("SWEEP_OPERATION, err : %+v while getting float on-chain balance for %+v", err, floatAccount.AssetSymbol)
-		return "", "", err
+		return false, err
 	}
 	floatOnChainBalance, _ := new(big.Float).SetPrec(prec).SetString(floatOnChainBalanceResponse.Balance)
 	logger.Info("SWEEP_OPERATION : Float on-chain balance for this hot wallet %+v is %+v", floatAccount.AssetSymbol, floatOnChainBalance)
 
-	// Get broker account
-	brokerageAccountResponse := dto.DepositAddressResponse{}
-
-	if *txNetworkAsset.IsToken {
-		err = services.GetDepositAddress(cache, logger, config, floatAccount.AssetSymbol, txNetworkAsset.NativeAsset, &brokerageAccountResponse, serviceErr)
-	} else {
-		err = services.GetDepositAddress(cache, logger, config, floatAccount.AssetSymbol, "", &brokerageAccountResponse, serviceErr)
-	}
-	if err != nil {
-		return "", "", err
-	}
-	logger.Info("SWEEP_OPERATION : Brokerage account for this hot wallet %+v is %+v", floatAccount.AssetSymbol, brokerageAccountResponse)
-
 	// Get float manager parameters to calculate minimum float
 	floatManagerParams, err := getFloatParamFor(floatAccount.AssetSymbol, floatAccount.Network, repository, logger)
 	if err != nil {
-		return "", "", err
+		return false, err
 	}
 	valueOfMinimumFloatPercent := new(big.Float)
 	valueOfMinimumFloatPercent.Mul(big.NewFloat(floatManagerParams.MinPercentTotalUserBalance), totalUsersBalance)
@@ -752,12 +759,11 @@ func GetSweepAddressAndMemo(cache *utility.MemoryCache, logger *utility.Logger,
 	if floatOnChainBalance.Cmp(valueOfMinimumFloatPercent) <= 0 {
 		logger.Info("SWEEP_OPERATION : FloatOnChainBalance for this hot wallet %+v is %+v, this is below %v of total user balance %v, moving sweep funds to float account ",
 			floatAccount.AssetSymbol, floatOnChainBalance, floatManagerParams.MinPercentTotalUserBalance, totalUsersBalance)
-		return floatAccount.Address, "", err
+		return true, nil
 	}
-	logger.Info("SWEEP_OPERATION : FloatOnChainBalance for this hot wallet %+v is %+v, this is above %v of total user balance %v, moving sweep funds to brokerage ",
+	logger.Info("SWEEP_OPERATION : FloatOnChainBalance for this hot wallet %+v is %+v, this is above %v of total user balance %v, moving sweep funds away from the float ",
 		floatAccount.AssetSymbol, floatOnChainBalance, floatManagerParams.MinPercentTotalUserBalance, totalUsersBalance)
-
-	return brokerageAccountResponse.Address, brokerageAccountResponse.Tag, nil
+	return false, nil
 }
 
 func getFloatDetails(repository database.BaseRepository, symbol, network string, logger *utility.Logger) (model.HotWalletAsset, error) {
@@ -0,0 +1,210 @@
+package tasks
+
+import (
+	"sort"
+	"strings"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+	"wallet-adapter/utility/constants"
+)
+
+// plannedSweep ... What sweeping an address or a batch would do, with the records looked up to work it out
+type plannedSweep struct {
+	item                model.SweepItem
+	toAddress           string
+	transactionListInfo dto.TransactionListInfo
+	txNetworkAsset      model.Network
+	userAddress         model.UserAddress
+	floatAccount        model.HotWalletAsset
+	gasFee              int64
+}
+
+// PlanSweep ... Works out what a sweep run would do with the current deposits, minimums and float parameters.
+// The sweep lock is not acquired, nothing is sent and the swept status of deposits and the float manager's place in them are left unchanged
+func PlanSweep(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository) (dto.SweepPlan, error) {
+	plan := dto.SweepPlan{GeneratedAt: time.Now(), Items: []dto.SweepPlanItem{}}
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: repository}
+	transactions, err := getSweepCandidates(repository, userAssetRepository, logger)
+	if err != nil {
+		return plan, err
+	}
+	plan.CandidateCount = len(transactions)
+
+	batchAssetTransactionsToSweep, batchAddresses, err := getBatchTransactions(repository, userAssetRepository, transactions, logger)
+	if err != nil {
+		return plan, err
+	}
+	transactions = RemoveBatchTransactions(transactions, batchAssetTransactionsToSweep)
+	transactionsPerAddressPerAssetSymbol, err := GroupTxByAddressByAssetSymbolAndNetwork(transactions, repository, logger)
+	if err != nil {
+		return plan, err
+	}
+	for addressAndAssetSymbol, addressTransactions := range transactionsPerAddressPerAssetSymbol {
+		stringSlice := strings.Split(addressAndAssetSymbol, utility.SWEEP_GROUPING_SEPERATOR)
+		sweep, err := planAddressSweep(cache, logger, config, repository, userAssetRepository, addressTransactions, CalculateSum(addressTransactions), stringSlice[0], stringSlice[1], stringSlice[2], true)
+		plan.Items = append(plan.Items, toSweepPlanItem(sweep, err))
+	}
+	if len(batchAddresses) > 0 {
+		transactionsPerAssetSymbol, batchAddressesPerAssetSymbol, _ := GroupTxByAssetSymbol(batchAssetTransactionsToSweep, repository, logger)
+		for assetSymbol, addressTransactions := range transactionsPerAssetSymbol {
+			sweep, err := planBatchSweep(cache, logger, config, repository, userAssetRepository, assetSymbol, batchAddressesPerAssetSymbol[assetSymbol], addressTransactions, true)
+			plan.Items = append(plan.Items, toSweepPlanItem(sweep, err))
+		}
+	}
+
+	sort.SliceStable(plan.Items, func(i, j int) bool {
+		if plan.Items[i].AssetSymbol != plan.Items[j].AssetSymbol {
+			return plan.Items[i].AssetSymbol < plan.Items[j].AssetSymbol
+		}
+		return plan.Items[i].Address < plan.Items[j].Address
+	})
+	return plan, nil
+}
+
+// planAddressSweep works out where the deposits to an address would be swept, or why they would be skipped. A dry run leaves the records as they are
+func planAddressSweep(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, userAssetRepository database.UserAssetRepository, addressTransactions []model.Transaction, sum float64, recipientAddress, assetSymbol, network string, isDryRun bool) (plannedSweep, error) {
+	sweep := plannedSweep{item: newSweepItem(recipientAddress, assetSymbol, network, addressTransactions, sum)}
+
+	transactionListInfo, e := getTransactionListInfo(repository, addressTransactions, logger)
+	if e != nil {
+		return sweep, e
+	}
+	sweep.transactionListInfo = transactionListInfo
+
+	txNetworkAsset, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, transactionListInfo.Network, transactionListInfo.AssetSymbol)
+	if err != nil {
+		logger.Error("Error response from sweep job : %+v while trying to get network asset of float asset, network is %+v and assetSymbol is %+v", err, transactionListInfo.Network, transactionListInfo.AssetSymbol)
+		return sweep, err
+	}
+	sweep.txNetworkAsset = txNetworkAsset
+
+	var queryParam *model.UserAddress
+	if txNetworkAsset.RequiresMemo {
+		queryParam = &model.UserAddress{V2Address: recipientAddress}
+	} else {
+		queryParam = &model.UserAddress{Address: recipientAddress}
+	}
+	if err := repository.GetByFieldName(queryParam, &sweep.userAddress); err != nil {
+		logger.Error("Error getting address provider for address : %s, defaulting to BUNDLE", recipientAddress)
+		sweep.userAddress.AddressProvider = model.AddressProvider.BUNDLE
+	}
+
+	if sweep.userAddress.AddressProvider == model.AddressProvider.BINANCE {
+		// addresses provided by Binance are swept to the brokerage by the Binance brokerage service
+		sweep.item.BrokeragePercent = 100
+		return sweep, nil
+	}
+
+	if txNetworkAsset.CoinType == constants.TRX_COINTYPE && exceedsTrxSweepLimit(sweep.userAddress) {
+		sweep.item.SkipReason = model.SweepSkipReason.TRX_DAILY_LIMIT
+		return sweep, nil
+	}
+
+	//Check that sweep amount is not below the minimum sweep amount
+	if isAmountSufficient, _ := CheckSweepMinimum(txNetworkAsset, config, sum, logger); !isAmountSufficient {
+		sweep.item.SkipReason = model.SweepSkipReason.BELOW_MINIMUM
+		return sweep, nil
+	}
+
+	sweep.floatAccount, err = getFloatDetails(repository, transactionListInfo.AssetSymbol, transactionListInfo.Network, logger)
+	if err != nil {
+		return sweep, err
+	}
+
+	router, err := GetSweepRouter(cache, logger, config, repository, sweep.floatAccount, txNetworkAsset, isDryRun)
+	if err != nil {
+		return sweep, err
+	}
+	sweepParam, err := router.Route(sum)
+	if err != nil {
+		logger.Error("Error response from Sweep job : %+v while getting sweep toAddress and memo for %s", err, sweep.floatAccount.AssetSymbol)
+		return sweep, err
+	}
+	applySweepParam(&sweep.item, sweepParam)
+	sweep.toAddress = sweepParam.ToAddress()
+	if RequiresSweepGas(txNetworkAsset) {
+		sweep.gasFee = GetSweepGasFee(cache, logger, config, txNetworkAsset)
+	}
+	return sweep, nil
+}
+
+// planBatchSweep works out the float and brokerage split of a batch sweep, or why it would be skipped. A dry run leaves the records as they are
+func planBatchSweep(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, userAssetRepository database.UserAssetRepository, assetSymbol string, batchAddresses []string, batchAssetTransactionsToSweep []model.Transaction, isDryRun bool) (plannedSweep, error) {
+	sweep := plannedSweep{item: newBatchSweepItem(assetSymbol, batchAddresses, batchAssetTransactionsToSweep)}
+
+	txNetworkAsset, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, batchAssetTransactionsToSweep[0].Network, batchAssetTransactionsToSweep[0].AssetSymbol)
+	if err != nil {
+		return sweep, err
+	}
+	sweep.txNetworkAsset = txNetworkAsset
+
+	//get float
+	sweep.floatAccount, err = getFloatDetails(repository, txNetworkAsset.AssetSymbol, txNetworkAsset.Network, logger)
+	if err != nil {
+		return sweep, err
+	}
+
+	//check total sum threshold for this batch
+	totalSweepSum := CalculateSumOfBatch(batchAssetTransactionsToSweep)
+	if totalSweepSum < txNetworkAsset.MinimumSweepable {
+		logger.Error("Error response from sweep job : Total sweep sum %v for asset (%s) is below the minimum sweep %v, so terminating sweep process", totalSweepSum, txNetworkAsset.AssetSymbol, txNetworkAsset.MinimumSweepable)
+		sweep.item.SkipReason = model.SweepSkipReason.BELOW_MINIMUM
+		return sweep, nil
+	}
+
+	router, err := GetSweepRouter(cache, logger, config, repository, sweep.floatAccount, txNetworkAsset, isDryRun)
+	if err != nil {
+		return sweep, err
+	}
+	sweepParam, err := router.Split(totalSweepSum)
+	if err != nil {
+		logger.Error("Error response from Sweep job : %+v while getting sweep params for %s", err, sweep.floatAccount.AssetSymbol)
+		return sweep, err
+	}
+	applySweepParam(&sweep.item, sweepParam)
+	return sweep, nil
+}
+
+func toSweepPlanItem(sweep plannedSweep, err error) dto.SweepPlanItem {
+	planItem := dto.SweepPlanItem{
+		Address:          sweep.item.Address,
+		AssetSymbol:      sweep.item.AssetSymbol,
+		Network:          sweep.item.Network,
+		IsBatch:          sweep.item.IsBatch,
+		TransactionCount: sweep.item.TransactionCount,
+		Amount:           sweep.item.Amount,
+		WillSweep:        err == nil && sweep.item.SkipReason == "",
+		SkipReason:       sweep.item.SkipReason,
+		AddressProvider:  sweep.userAddress.AddressProvider,
+		FloatAddress:     sweep.item.FloatAddress,
+		FloatPercent:     sweep.item.FloatPercent,
+		BrokerageAddress: sweep.item.BrokerageAddress,
+		BrokeragePercent: sweep.item.BrokeragePercent,
+		ColdAddress:      sweep.item.ColdAddress,
+		ColdPercent:      sweep.item.ColdPercent,
+		SweepPolicy:      sweep.item.SweepPolicy,
+		Memo:             sweep.item.Memo,
+	}
+	if sweep.item.Origins != "" {
+		planItem.Origins = strings.Split(sweep.item.Origins, ",")
+	}
+	if err != nil {
+		planItem.Error = err.Error()
+	}
+	if planItem.WillSweep {
+		planItem.FeeAsset = sweep.txNetworkAsset.NativeAsset
+		planItem.SweepFee = sweep.txNetworkAsset.SweepFee
+		// tokens get the sweep fee sent from the fee wallet of their network before they are swept
+		planItem.FundsSweepFee = RequiresSweepGas(sweep.txNetworkAsset) && sweep.userAddress.AddressProvider != model.AddressProvider.BINANCE
+		if planItem.FundsSweepFee {
+			planItem.SweepFee = sweep.gasFee
+		}
+	}
+	return planItem
+}
@@ -0,0 +1,229 @@
+package tasks
+
+import (
+	"fmt"
+	"strconv"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+)
+
+// SweepRouter ... Decides where the funds of a sweep go, following the sweep policy of the asset
+type SweepRouter interface {
+	// Split divides a batch sweep between the float and the brokerage or cold storage
+	Split(sweepFund float64) (SweepParam, error)
+	// Route picks where an address sweep goes, address sweeps move the whole balance to one place
+	Route(sweepFund float64) (SweepParam, error)
+}
+
+// sweepRoute ... What a router needs to look up the float and the brokerage of an asset
+type sweepRoute struct {
+	cache          *utility.MemoryCache
+	logger         *utility.Logger
+	config         Config.Data
+	repository     database.BaseRepository
+	floatAccount   model.HotWalletAsset
+	txNetworkAsset model.Network
+	policy         model.SweepPolicy
+	isDryRun       bool
+}
+
+// GetSweepRouter ... Returns the router of the sweep policy set for the asset, assets without a policy fill the float to its target
+// and send the rest to the brokerage. The routers of a dry run leave the float manager's place in the deposits and withdrawals as it is
+func GetSweepRouter(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, floatAccount model.HotWalletAsset, txNetworkAsset model.Network, isDryRun bool) (SweepRouter, error) {
+	route := sweepRoute{cache: cache, logger: logger, config: config, repository: repository, floatAccount: floatAccount, txNetworkAsset: txNetworkAsset, isDryRun: isDryRun}
+	if err := repository.GetByFieldName(&model.SweepPolicy{AssetSymbol: txNetworkAsset.AssetSymbol, Network: txNetworkAsset.Network}, &route.policy); err != nil {
+		if err.Error() != errorcode.SQL_404 {
+			return nil, err
+		}
+		route.policy = model.SweepPolicy{AssetSymbol: txNetworkAsset.AssetSymbol, Network: txNetworkAsset.Network, Type: model.SweepPolicyType.FLOAT_THEN_BROKERAGE}
+	}
+	return newSweepRouter(route)
+}
+
+// newSweepRouter returns the router implementing the sweep policy of the route
+func newSweepRouter(route sweepRoute) (SweepRouter, error) {
+	policy := route.policy
+	switch policy.Type {
+	case model.SweepPolicyType.FLOAT_THEN_BROKERAGE:
+		return floatThenBrokerageRouter{route}, nil
+	case model.SweepPolicyType.FIXED_PERCENTAGES:
+		return fixedPercentagesRouter{route}, nil
+	case model.SweepPolicyType.FLOAT_THEN_COLD:
+		return floatThenColdRouter{route}, nil
+	case model.SweepPolicyType.ALL_TO_COLD:
+		return allToColdRouter{route}, nil
+	}
+	return nil, fmt.Errorf("unknown sweep policy %s for %s on %s", policy.Type, policy.AssetSymbol, policy.Network)
+}
+
+// ToAddress ... Returns the destination of an address sweep, the one getting the whole amount
+func (sweepParam SweepParam) ToAddress() string {
+	switch {
+	case sweepParam.ColdPercent == 100:
+		return sweepParam.ColdAddress
+	case sweepParam.BrokeragePercent == 100:
+		return sweepParam.BrokerageAddress
+	}
+	return sweepParam.FloatAddress
+}
+
+// ChangeAddress ... Returns where the change of a batch sweep goes, away from the float unless the float takes it all
+func (sweepParam SweepParam) ChangeAddress() string {
+	switch {
+	case sweepParam.BrokerageAddress != "":
+		return sweepParam.BrokerageAddress
+	case sweepParam.ColdAddress != "":
+		return sweepParam.ColdAddress
+	}
+	return sweepParam.FloatAddress
+}
+
+func applySweepParam(item *model.SweepItem, sweepParam SweepParam) {
+	item.FloatAddress, item.FloatPercent = sweepParam.FloatAddress, sweepParam.FloatPercent
+	item.BrokerageAddress, item.BrokeragePercent = sweepParam.BrokerageAddress, sweepParam.BrokeragePercent
+	item.ColdAddress, item.ColdPercent = sweepParam.ColdAddress, sweepParam.ColdPercent
+	item.Memo, item.SweepPolicy = sweepParam.Memo, sweepParam.Policy
+}
+
+func sweepParamOf(item model.SweepItem) SweepParam {
+	return SweepParam{
+		FloatAddress:     item.FloatAddress,
+		FloatPercent:     item.FloatPercent,
+		BrokerageAddress: item.BrokerageAddress,
+		BrokeragePercent: item.BrokeragePercent,
+		ColdAddress:      item.ColdAddress,
+		ColdPercent:      item.ColdPercent,
+		Memo:             item.Memo,
+		Policy:           item.SweepPolicy,
+	}
+}
+
+func (route sweepRoute) toFloat(percent int64) SweepParam {
+	return SweepParam{FloatAddress: route.floatAccount.Address, FloatPercent: percent, Policy: route.policy.Type}
+}
+
+func (route sweepRoute) toCold(sweepParam SweepParam, percent int64) SweepParam {
+	sweepParam.ColdAddress, sweepParam.ColdPercent, sweepParam.Policy = route.policy.ColdAddress, percent, route.policy.Type
+	if percent == 100 {
+		sweepParam.Memo = route.policy.ColdMemo
+	}
+	return sweepParam
+}
+
+func (route sweepRoute) toBrokerage(sweepParam SweepParam, percent int64) (SweepParam, error) {
+	brokerageAccountResponse, err := GetBrokerAccountFor(route.floatAccount.AssetSymbol, route.txNetworkAsset, route.repository, route.cache, route.config, route.logger, dto.ServicesRequestErr{})
+	if err != nil {
+		return sweepParam, err
+	}
+	sweepParam.BrokerageAddress, sweepParam.BrokeragePercent, sweepParam.Policy = brokerageAccountResponse.Address, percent, route.policy.Type
+	if percent == 100 {
+		sweepParam.Memo = brokerageAccountResponse.Tag
+	}
+	return sweepParam, nil
+}
+
+// floatThenBrokerageRouter ... Fills the float to its target and sends the rest to the brokerage
+type floatThenBrokerageRouter struct{ sweepRoute }
+
+func (router floatThenBrokerageRouter) Split(sweepFund float64) (SweepParam, error) {
+	sweepParam, err := GetSweepParams(router.cache, router.logger, router.config, router.repository, router.floatAccount, router.txNetworkAsset, sweepFund, router.isDryRun)
+	sweepParam.Policy = router.policy.Type
+	return sweepParam, err
+}
+
+func (router floatThenBrokerageRouter) Route(sweepFund float64) (SweepParam, error) {
+	toAddress, addressMemo, err := GetSweepAddressAndMemo(router.cache, router.logger, router.config, router.repository, router.floatAccount, router.txNetworkAsset)
+	if err != nil {
+		return SweepParam{}, err
+	}
+	if toAddress == router.floatAccount.Address {
+		return router.toFloat(100), nil
+	}
+	return SweepParam{BrokerageAddress: toAddress, BrokeragePercent: 100, Memo: addressMemo, Policy: router.policy.Type}, nil
+}
+
+// fixedPercentagesRouter ... Sends a fixed share to the float and the rest to cold storage when a cold address is set, to the brokerage otherwise
+type fixedPercentagesRouter struct{ sweepRoute }
+
+func (router fixedPercentagesRouter) Split(sweepFund float64) (SweepParam, error) {
+	return router.split(router.policy.FloatPercent)
+}
+
+func (router fixedPercentagesRouter) Route(sweepFund float64) (SweepParam, error) {
+	// an address sweep cannot be split, so it goes whole to the side that keeps the amounts swept under the policy closest to its percentages
+	floatTotal, sweptTotal, err := router.sweptTotals()
+	if err != nil {
+		return SweepParam{}, err
+	}
+	targetFloatTotal := float64(router.policy.FloatPercent) / 100 * (sweptTotal + sweepFund)
+	if floatTotal+sweepFund/2 <= targetFloatTotal {
+		return router.split(100)
+	}
+	return router.split(0)
+}
+
+// sweptTotals returns the amount sent to the float and the total amount swept since the policy was last set
+func (router fixedPercentagesRouter) sweptTotals() (floatTotal, sweptTotal float64, err error) {
+	var sweepItems []model.SweepItem
+	query := model.SweepItem{AssetSymbol: router.policy.AssetSymbol, Network: router.policy.Network, SweepPolicy: router.policy.Type, Status: model.SweepItemStatus.SWEPT}
+	if err := router.repository.FetchByFieldNameFromDate(query, &sweepItems, &router.policy.UpdatedAt); err != nil {
+		return 0, 0, err
+	}
+	for _, sweepItem := range sweepItems {
+		amount, err := strconv.ParseFloat(sweepItem.Amount, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		floatTotal += amount * float64(sweepItem.FloatPercent) / 100
+		sweptTotal += amount
+	}
+	return floatTotal, sweptTotal, nil
+}
+
+func (router fixedPercentagesRouter) split(floatPercent int64) (SweepParam, error) {
+	sweepParam := router.toFloat(floatPercent)
+	if floatPercent == 100 {
+		return sweepParam, nil
+	}
+	if router.policy.ColdAddress != "" {
+		return router.toCold(sweepParam, 100-floatPercent), nil
+	}
+	return router.toBrokerage(sweepParam, 100-floatPercent)
+}
+
+// floatThenColdRouter ... Fills the float to its target and sends the rest to cold storage
+type floatThenColdRouter struct{ sweepRoute }
+
+func (router floatThenColdRouter) Split(sweepFund float64) (SweepParam, error) {
+	floatPercent, err := getFloatFillPercent(router.cache, router.logger, router.config, router.repository, router.floatAccount, router.txNetworkAsset, sweepFund, router.isDryRun)
+	if err != nil {
+		return SweepParam{}, err
+	}
+	return router.toCold(router.toFloat(floatPercent), 100-floatPercent), nil
+}
+
+func (router floatThenColdRouter) Route(sweepFund float64) (SweepParam, error) {
+	isBelowMinimum, err := isFloatBelowMinimum(router.cache, router.logger, router.config, router.repository, router.floatAccount, router.txNetworkAsset)
+	if err != nil {
+		return SweepParam{}, err
+	}
+	if isBelowMinimum {
+		return router.toFloat(100), nil
+	}
+	return router.toCold(SweepParam{}, 100), nil
+}
+
+// allToColdRouter ... Sends every sweep to cold storage, the float is left to the float manager
+type allToColdRouter struct{ sweepRoute }
+
+func (router allToColdRouter) Split(sweepFund float64) (SweepParam, error) {
+	return router.toCold(SweepParam{}, 100), nil
+}
+
+func (router allToColdRouter) Route(sweepFund float64) (SweepParam, error) {
+	return router.toCold(SweepParam{}, 100), nil
+}
@@ -0,0 +1,78 @@
+package tasks
+
+import (
+	"context"
+	"strings"
+	"time"
+	"wallet-adapter/database"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// StartSweepRun ... Records the start of a sweep run, the sweep goes ahead when the record cannot be saved
+func StartSweepRun(repository database.BaseRepository, logger *utility.Logger) model.SweepRun {
+	sweepRun := model.SweepRun{Status: model.SweepRunStatus.RUNNING, StartedAt: time.Now()}
+	if err := repository.Create(&sweepRun); err != nil {
+		logger.Error("Error response from Sweep job : %+v while recording sweep run", err)
+	}
+	return sweepRun
+}
+
+// RecordSweepItem ... Records the outcome of sweeping an address or a batch, an item that errored is FAILED and one with a skip reason is SKIPPED
+func RecordSweepItem(repository database.BaseRepository, sweepRun *model.SweepRun, sweepItem model.SweepItem, sweepErr error, logger *utility.Logger) {
+	sweepItem.SweepRunID = sweepRun.ID
+	switch {
+	case sweepErr != nil:
+		sweepItem.Status = model.SweepItemStatus.FAILED
+		sweepItem.Error = sweepErr.Error()
+		sweepRun.FailedCount++
+	case sweepItem.SkipReason != "":
+		sweepItem.Status = model.SweepItemStatus.SKIPPED
+		sweepRun.SkippedCount++
+	default:
+		sweepItem.Status = model.SweepItemStatus.SWEPT
+		sweepRun.SweptCount++
+	}
+	if err := repository.Create(&sweepItem); err != nil {
+		logger.Error("Error response from Sweep job : %+v while recording sweep of %s %s", err, sweepItem.AssetSymbol, sweepItem.Address)
+	}
+}
+
+// FinishSweepRun ... Records the end of a sweep run with its counts, runs that stopped on an error are FAILED and cancelled runs CANCELLED
+func FinishSweepRun(repository database.BaseRepository, sweepRun *model.SweepRun, runErr error, logger *utility.Logger) {
+	if sweepRun.ID == uuid.Nil {
+		return
+	}
+	finishedAt := time.Now()
+	sweepRun.FinishedAt = &finishedAt
+	sweepRun.Status = model.SweepRunStatus.COMPLETED
+	if runErr == context.Canceled {
+		sweepRun.Status = model.SweepRunStatus.CANCELLED
+		sweepRun.Error = runErr.Error()
+	} else if runErr != nil {
+		sweepRun.Status = model.SweepRunStatus.FAILED
+		sweepRun.Error = runErr.Error()
+	}
+	if err := repository.Update(&model.SweepRun{BaseModel: model.BaseModel{ID: sweepRun.ID}}, sweepRun); err != nil {
+		logger.Error("Error response from Sweep job : %+v while recording end of sweep run %s", err, sweepRun.ID)
+	}
+}
+
+func newSweepItem(address, assetSymbol, network string, transactions []model.Transaction, sum float64) model.SweepItem {
+	return model.SweepItem{
+		Address:          address,
+		AssetSymbol:      assetSymbol,
+		Network:          network,
+		TransactionCount: len(transactions),
+		Amount:           utility.FloatToString(sum),
+	}
+}
+
+func newBatchSweepItem(assetSymbol string, batchAddresses []string, transactions []model.Transaction) model.SweepItem {
+	sweepItem := newSweepItem("", assetSymbol, transactions[0].Network, transactions, CalculateSumOfBatch(transactions))
+	sweepItem.IsBatch = true
+	sweepItem.Origins = strings.Join(ToUniqueAddresses(batchAddresses), ",")
+	return sweepItem
+}
@@ -0,0 +1,220 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	Config "wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+)
+
+const (
+	defaultSweepWorkers            = 8
+	defaultSweepNetworkConcurrency = 4
+)
+
+// sweepJob ... Deposits to one address for one asset on one network, as grouped by GroupTxByAddressByAssetSymbolAndNetwork
+type sweepJob struct {
+	address      string
+	assetSymbol  string
+	network      string
+	transactions []model.Transaction
+}
+
+// GetSweepWorkers ... Returns the number of addresses swept concurrently
+func GetSweepWorkers(config Config.Data) int {
+	if config.SweepWorkers <= 0 {
+		return defaultSweepWorkers
+	}
+	return config.SweepWorkers
+}
+
+// GetSweepLockTTL ... Returns the lease of the sweep lock in milliseconds, it is renewed while the run is in flight
+func GetSweepLockTTL(config Config.Data) int64 {
+	if config.SweepLockTTL <= 0 {
+		return utility.SIX_HUNDRED_MILLISECONDS
+	}
+	return config.SweepLockTTL
+}
+
+// GetSweepNetworkConcurrency ... Returns the number of addresses swept concurrently on a network, limits are configured per network
+// with a default for the networks not listed
+func GetSweepNetworkConcurrency(config Config.Data, network string) int {
+	for limitedNetwork, limit := range config.SweepNetworkConcurrencyLimits {
+		if strings.EqualFold(limitedNetwork, network) && limit > 0 {
+			return limit
+		}
+	}
+	if config.SweepNetworkConcurrency <= 0 {
+		return defaultSweepNetworkConcurrency
+	}
+	return config.SweepNetworkConcurrency
+}
+
+// sweepAddresses sweeps the address groups with a bounded pool of workers. The jobs of an address run one after the other on the same worker,
+// so sweep fee funding and nonces of an address never race. Once the context is cancelled no new job is started
+func sweepAddresses(ctx context.Context, cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, repository database.BaseRepository, userAssetRepository database.UserAssetRepository, serviceErr dto.ServicesRequestErr, sweepRun *model.SweepRun, transactionsPerAddressPerAssetSymbol map[string][]model.Transaction) {
+	jobsPerAddress := map[string][]sweepJob{}
+	networkSlots := map[string]chan struct{}{}
+	for addressAndAssetSymbol, addressTransactions := range transactionsPerAddressPerAssetSymbol {
+		stringSlice := strings.Split(addressAndAssetSymbol, utility.SWEEP_GROUPING_SEPERATOR)
+		job := sweepJob{address: stringSlice[0], assetSymbol: stringSlice[1], network: stringSlice[2], transactions: addressTransactions}
+		jobsPerAddress[job.address] = append(jobsPerAddress[job.address], job)
+		if _, ok := networkSlots[job.network]; !ok {
+			networkSlots[job.network] = make(chan struct{}, GetSweepNetworkConcurrency(config, job.network))
+		}
+	}
+	addresses := make([]string, 0, len(jobsPerAddress))
+	for address := range jobsPerAddress {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	var recordMutex sync.Mutex
+	var waitGroup sync.WaitGroup
+	addressQueue := make(chan string)
+	workers := GetSweepWorkers(config)
+	if workers > len(addresses) {
+		workers = len(addresses)
+	}
+	logger.Info("Sweeping %d addresses with %d workers", len(addresses), workers)
+	for i := 0; i < workers; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for address := range addressQueue {
+				for _, job := range jobsPerAddress[address] {
+					if !acquireNetworkSlot(ctx, networkSlots[job.network]) {
+						break
+					}
+					sum := CalculateSum(job.transactions)
+					logger.Info("Sweeping %s with total of %v", job.address, sum)
+					sweep, err := planAddressSweep(cache, logger, config, repository, userAssetRepository, job.transactions, sum, job.address, job.assetSymbol, job.network, false)
+					if err == nil {
+						err = sweepPerAddress(cache, logger, config, repository, serviceErr, &sweep, job.transactions)
+					}
+					<-networkSlots[job.network]
+
+					recordMutex.Lock()
+					RecordSweepItem(repository, sweepRun, sweep.item, err, logger)
+					recordMutex.Unlock()
+					if err != nil {
+						logger.Error("Error response from Sweep job : %+v while sweepPerAddress for address %s", err, job.address)
+					}
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, address := range addresses {
+		select {
+		case <-ctx.Done():
+			logger.Info("Sweep job : run cancelled, %s and the addresses after it are left for the next run", address)
+			break dispatch
+		case addressQueue <- address:
+		}
+	}
+	close(addressQueue)
+	waitGroup.Wait()
+}
+
+// acquireNetworkSlot waits for a free sweep slot on a network, it gives up once the context is cancelled
+func acquireNetworkSlot(ctx context.Context, networkSlot chan struct{}) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case networkSlot <- struct{}{}:
+	}
+	if ctx.Err() != nil {
+		<-networkSlot
+		return false
+	}
+	return true
+}
+
+// sweepLease ... Renews the sweep lock while a run is in flight, losing the lease cancels the run so no new sweep starts
+type sweepLease struct {
+	mutex sync.Mutex
+	token string
+	err   error
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+func startSweepLease(cache *utility.MemoryCache, logger *utility.Logger, config Config.Data, token string, ttl int64, cancel context.CancelFunc) *sweepLease {
+	lease := &sweepLease{token: token, stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(lease.done)
+		// renew well before the lease runs out so a slow locker service response does not let it lapse
+		ticker := time.NewTicker(time.Duration(ttl/3) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lease.stop:
+				return
+			case <-ticker.C:
+				token, err := renewLock("sweep", lease.Token(), ttl, cache, logger, config)
+				lease.mutex.Lock()
+				if err != nil {
+					lease.err = err
+				} else if token != "" {
+					lease.token = token
+				}
+				lease.mutex.Unlock()
+				if err != nil {
+					logger.Error("Sweep job : could not renew lock, stopping sweep run : %s", err)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	return lease
+}
+
+// Token ... Returns the current lock token
+func (lease *sweepLease) Token() string {
+	lease.mutex.Lock()
+	defer lease.mutex.Unlock()
+	return lease.token
+}
+
+// Err ... Returns the error the lease was lost with
+func (lease *sweepLease) Err() error {
+	lease.mutex.Lock()
+	defer lease.mutex.Unlock()
+	return lease.err
+}
+
+// Stop ... Stops renewing the lock, it returns once no renewal is in flight
+func (lease *sweepLease) Stop() {
+	close(lease.stop)
+	<-lease.done
+}
+
+func renewLock(identifier, token string, ttl int64, cache *utility.MemoryCache, logger *utility.Logger, config Config.Data) (string, error) {
+	serviceErr := dto.ServicesRequestErr{}
+	lockerServiceRequest := dto.LockerServiceRequest{
+		Identifier:   fmt.Sprintf("%s%s", config.LockerPrefix, identifier),
+		Token:        token,
+		ExpiresAfter: ttl,
+	}
+	lockerServiceResponse := dto.LockerServiceResponse{}
+	if err := services.RenewLock(cache, logger, config, lockerServiceRequest, &lockerServiceResponse, &serviceErr); err != nil {
+		logger.Error("Error occured while renewing lock : %+v; %s", serviceErr, err)
+		if !serviceErr.Success && serviceErr.Message != "" {
+			return "", errors.New(serviceErr.Message)
+		}
+		return "", err
+	}
+	return lockerServiceResponse.Token, nil
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_PooledAddressIsAssignedOnceAndReportsDepth() {
+	s.DB.AutoMigrate(&model.PooledAddress{})
+	defer s.DB.DropTableIfExists(&model.PooledAddress{})
+	viper.Set("ADDRESSPOOLLOWWATERMARK.ETH_ETH", 2)
+	defer viper.Set("ADDRESSPOOLLOWWATERMARK.ETH_ETH", 0)
+
+	for index := 0; index < 2; index++ {
+		pooledAddress := model.PooledAddress{PoolIdentity: uuid.NewV4(), Address: fmt.Sprintf("0xpooled%d", index), AssetSymbol: "ETH", Network: "ETH", CoinType: 60, Status: model.PooledAddressStatus.AVAILABLE}
+		if err := s.DB.Create(&pooledAddress).Error; err != nil {
+			require.NoError(s.T(), err)
+		}
+	}
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	networkAsset := dto.NetworkAsset{AssetSymbol: "ETH", Network: "ETH", CoinType: 60, AddressProvider: model.AddressProvider.BUNDLE, AssetID: uuid.NewV4(), UserID: uuid.NewV4()}
+	address, err := services.GenerateV1Address(&userAssetRepository, s.Logger, authCache, s.Config, networkAsset, model.UserAddress{}, true)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), "0xpooled0", address, "Expected the oldest pooled address to be assigned")
+
+	claimed := model.PooledAddress{}
+	if err := userAssetRepository.ClaimPooledAddress("ETH", "ETH", "", uuid.NewV4(), &claimed); err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), "0xpooled1", claimed.Address, "Expected an assigned address not to be handed out twice")
+
+	err = userAssetRepository.ClaimPooledAddress("ETH", "ETH", "", uuid.NewV4(), &model.PooledAddress{})
+	appErr, ok := err.(utility.AppError)
+	assert.True(s.T(), ok && appErr.ErrType == errorcode.RECORD_NOT_FOUND, "Expected a drained pool to return record not found")
+
+	poolDepth, err := services.GetAddressPoolDepth(&userAssetRepository)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	if len(poolDepth) != 1 {
+		require.NoError(s.T(), fmt.Errorf("Expected one pool depth entry, got %d", len(poolDepth)))
+	}
+	assert.Equal(s.T(), int64(0), poolDepth[0].Available)
+	assert.Equal(s.T(), int64(2), poolDepth[0].Assigned)
+	assert.True(s.T(), poolDepth[0].BelowLowWaterMark, "Expected drained pool to be below its low-water mark")
+}
+
+func (s *Suite) Test_MultiAddressAssetIsAssignedPooledAddressesAndReturnsThemWhenSaveFails() {
+	s.DB.AutoMigrate(&model.PooledAddress{})
+	defer s.DB.DropTableIfExists(&model.PooledAddress{})
+	viper.Set("ADDRESSPOOLLOWWATERMARK.BTC_BTC", 2)
+	defer viper.Set("ADDRESSPOOLLOWWATERMARK.BTC_BTC", 0)
+
+	isMultiAddresses := true
+	assert.True(s.T(), services.IsPoolableNetwork(model.Network{AssetSymbol: "BTC", Network: "BTC", AddressProvider: model.AddressProvider.BUNDLE, IsMultiAddresses: &isMultiAddresses}),
+		"Expected a multi address network to be poolable")
+
+	poolIdentity := uuid.NewV4()
+	pooledAddresses := map[string]string{utility.ADDRESS_TYPE_SEGWIT: "bc1qpooled", utility.ADDRESS_TYPE_LEGACY: "1pooled"}
+	for addressType, address := range pooledAddresses {
+		pooledAddress := model.PooledAddress{PoolIdentity: poolIdentity, Address: address, AddressType: addressType, AssetSymbol: "BTC", Network: "BTC", Status: model.PooledAddressStatus.AVAILABLE}
+		if err := s.DB.Create(&pooledAddress).Error; err != nil {
+			require.NoError(s.T(), err)
+		}
+	}
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	service := services.BaseService{Config: s.Config, Cache: authCache, Logger: s.Logger}
+	networkAsset := dto.NetworkAsset{AssetSymbol: "BTC", Network: "BTC", CoinType: 0, AddressProvider: model.AddressProvider.BUNDLE, AssetID: uuid.NewV4(), UserID: uuid.NewV4()}
+
+	s.DB.Callback().Create().Before("gorm:create").Register("test:fail_user_address", func(scope *gorm.Scope) {
+		if scope.TableName() == "user_addresses" {
+			scope.Err(errors.New("user address not saved"))
+		}
+	})
+	_, err := service.GenerateAndCreateAssetMultipleAddresses(&userAssetRepository, networkAsset, "", true, "BTC")
+	s.DB.Callback().Create().Remove("test:fail_user_address")
+	assert.Error(s.T(), err, "Expected the failed save to be reported")
+	availableCount := 0
+	s.DB.Model(&model.PooledAddress{}).Where("status = ? AND asset_id IS NULL", model.PooledAddressStatus.AVAILABLE).Count(&availableCount)
+	assert.Equal(s.T(), 2, availableCount, "Expected the claimed addresses to be returned to the pool")
+
+	addresses, err := service.GenerateAndCreateAssetMultipleAddresses(&userAssetRepository, networkAsset, "", true, "BTC")
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	require.Len(s.T(), addresses, 2)
+	for _, address := range addresses {
+		assert.Equal(s.T(), pooledAddresses[address.Type], address.Data, "Expected the pooled address of each type to be assigned")
+		userAddress := model.UserAddress{}
+		if err := userAssetRepository.GetByFieldName(&model.UserAddress{Address: address.Data}, &userAddress); err != nil {
+			require.NoError(s.T(), err)
+		}
+		assert.Equal(s.T(), networkAsset.AssetID, userAddress.AssetID)
+		assert.Equal(s.T(), model.SubscriptionStatus.ACTIVE, userAddress.SubscriptionStatus, "Expected pooled addresses to be already subscribed")
+		if assert.NotNil(s.T(), userAddress.KeyIdentity) {
+			assert.Equal(s.T(), poolIdentity, *userAddress.KeyIdentity)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+	"wallet-adapter/controllers"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	validation "gopkg.in/go-playground/validator.v9"
+)
+
+func (s *Suite) onchainDepositTo(assetID uuid.UUID, address, reference string) dto.TransactionReceipt {
+	onchainCreditAssetInputData := []byte(fmt.Sprintf(`{"assetId" : "%s","value" : 0.5,"transactionReference" : "%s","memo" :"Test rotation deposit","chainData": {"status": true,"transactionHash": "%s","transactionFee": "string","blockHeight": 0, "recipientAddress": "%s", "network": "BTC"}}`, assetID, reference, reference, address))
+	onchainCreditAssetRequest, _ := http.NewRequest("POST", test.OnchainDepositEndpoint, bytes.NewBuffer(onchainCreditAssetInputData))
+	onchainCreditAssetRequest.Header.Set("x-auth-token", authToken)
+	onchainCreditAssetResponse := httptest.NewRecorder()
+	s.Router.ServeHTTP(onchainCreditAssetResponse, onchainCreditAssetRequest)
+
+	receipt := dto.TransactionReceipt{}
+	resBody, _ := ioutil.ReadAll(onchainCreditAssetResponse.Body)
+	_ = json.Unmarshal(resBody, &receipt)
+	assert.Equal(s.T(), http.StatusOK, onchainCreditAssetResponse.Code, "Expected deposit to be recorded")
+	return receipt
+}
+
+func (s *Suite) Test_AddressRotatesAfterDepositsAndInvalidatedAddressDepositsAreHeld() {
+	s.DB.AutoMigrate(&model.ScreeningHold{})
+	defer s.DB.DropTableIfExists(&model.ScreeningHold{})
+
+	createAssetInputData := []byte(`{"assets" : ["BTC"],"userId" : "c3f1a8e2-5b7d-4c1e-9a2f-6d8e0b4c7a91"}`)
+	createAssetRequest, _ := http.NewRequest("POST", test.CreateAssetEndpoint, bytes.NewBuffer(createAssetInputData))
+	createAssetRequest.Header.Set("x-auth-token", authToken)
+	createResponse := httptest.NewRecorder()
+	s.Router.ServeHTTP(createResponse, createAssetRequest)
+	resBody, err := ioutil.ReadAll(createResponse.Body)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	createAssetResponse := dto.UserAssetResponse{}
+	err = json.Unmarshal(resBody, &createAssetResponse)
+	if createResponse.Code != http.StatusCreated || len(createAssetResponse.Assets) < 1 {
+		require.NoError(s.T(), errors.New("Expected asset creation to not error"))
+	}
+	assetID := createAssetResponse.Assets[0].ID
+
+	if err := s.DB.Model(&model.Network{}).Where("asset_symbol = ? AND network = ?", "BTC", "BTC").Updates(map[string]interface{}{"minimum_deposit": 0, "requires_memo": false, "rotate_after_deposits": 1, "rotation_window_hours": 24}).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	defer s.DB.Model(&model.Network{}).Where("asset_symbol = ? AND network = ?", "BTC", "BTC").Updates(map[string]interface{}{"requires_memo": true, "rotate_after_deposits": 0, "rotation_window_hours": 0})
+
+	depositAddress := model.UserAddress{AssetID: assetID, Address: "bc1qrotatedaddress", AddressType: utility.ADDRESS_TYPE_SEGWIT, Network: "BTC", IsPrimaryAddress: true, IsValid: true}
+	if err := s.DB.Create(&depositAddress).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+
+	receipt := s.onchainDepositTo(assetID, depositAddress.Address, "rotationdeposit1")
+	assert.Equal(s.T(), model.TransactionStatus.COMPLETED, receipt.TransactionStatus, "Expected deposit to a primary address to be credited")
+
+	rotatedAddress := model.UserAddress{}
+	if err := s.DB.Where("id = ?", depositAddress.ID).First(&rotatedAddress).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), 1, rotatedAddress.DepositCount)
+	assert.False(s.T(), rotatedAddress.IsPrimaryAddress, "Expected address to rotate after the configured number of deposits")
+	assert.NotNil(s.T(), rotatedAddress.RetiredAt, "Expected rotated address to be retired")
+
+	receipt = s.onchainDepositTo(assetID, depositAddress.Address, "rotationdeposit2")
+	assert.Equal(s.T(), model.TransactionStatus.COMPLETED, receipt.TransactionStatus, "Expected deposit to a retired address to be credited by default")
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	invalidated, err := userAssetRepository.InvalidateUserAddresses(depositAddress.Address, "private key exposed")
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), int64(1), invalidated)
+
+	receipt = s.onchainDepositTo(assetID, depositAddress.Address, "rotationdeposit3")
+	assert.Equal(s.T(), model.TransactionStatus.SCREENING_HOLD, receipt.TransactionStatus, "Expected deposit to an invalidated address to be held")
+
+	screeningHold := model.ScreeningHold{}
+	if err := s.DB.Where("provider = ?", utility.ADDRESS_HOLD_INVALIDATED).First(&screeningHold).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), model.ScreeningHoldStatus.HELD, screeningHold.Status)
+
+	expiredAddress := model.UserAddress{BaseModel: model.BaseModel{CreatedAt: time.Now().Add(-48 * time.Hour)}, AssetID: assetID, Address: "bc1qexpiredaddress", AddressType: utility.ADDRESS_TYPE_LEGACY, Network: "BTC", IsPrimaryAddress: true, IsValid: true}
+	if err := s.DB.Create(&expiredAddress).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	network, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, "BTC", "BTC")
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	retired, err := services.RotateExpiredAddresses(&userAssetRepository, network)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), int64(1), retired, "Expected the address past its rotation window to be retired")
+}
+
+func (s *Suite) Test_RotatedAddressIsDerivedUnderAFreshIdentityAndInvalidatedOneIsUnsubscribed() {
+	createAssetInputData := []byte(`{"assets" : ["ETH"],"userId" : "4b9e2d7a-1c3f-4e8b-a6d5-0f2c9b7e3a18"}`)
+	createAssetRequest, _ := http.NewRequest("POST", test.CreateAssetEndpoint, bytes.NewBuffer(createAssetInputData))
+	createAssetRequest.Header.Set("x-auth-token", authToken)
+	createResponse := httptest.NewRecorder()
+	s.Router.ServeHTTP(createResponse, createAssetRequest)
+	createAssetResponse := dto.UserAssetResponse{}
+	require.NoError(s.T(), json.NewDecoder(createResponse.Body).Decode(&createAssetResponse))
+	require.Equal(s.T(), http.StatusCreated, createResponse.Code)
+	require.NotEmpty(s.T(), createAssetResponse.Assets)
+	assetID := createAssetResponse.Assets[0].ID
+
+	require.NoError(s.T(), s.DB.Model(&model.Network{}).Where("asset_symbol = ? AND network = ?", "ETH", "ERC20").Updates(map[string]interface{}{"requires_memo": false, "is_multi_addresses": false}).Error)
+	defer s.DB.Model(&model.Network{}).Where("asset_symbol = ? AND network = ?", "ETH", "ERC20").Updates(map[string]interface{}{"requires_memo": true, "is_multi_addresses": true})
+
+	// key-management derives one address per identity, like the real service
+	subscribed, cancelled := map[string]bool{}, map[string]bool{}
+	stubServices := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/token":
+			_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+		case "/address/create-all-versions":
+			requestData := dto.GenerateAddressRequest{}
+			_ = json.NewDecoder(r.Body).Decode(&requestData)
+			_ = json.NewEncoder(w).Encode(dto.GenerateAllAddressesResponse{UserID: requestData.UserID,
+				Addresses: []dto.AllAddressResponse{{Type: requestData.Network, Network: requestData.Network, Data: "0x" + requestData.UserID.String()}}})
+		case "/subscription/register", "/subscription/cancel":
+			requestData := dto.SubscriptionRequestV2{}
+			_ = json.NewDecoder(r.Body).Decode(&requestData)
+			for _, addresses := range requestData.Subscriptions {
+				for _, address := range addresses {
+					if r.URL.Path == "/subscription/register" {
+						subscribed[address] = true
+					} else {
+						cancelled[address] = true
+					}
+				}
+			}
+			_ = json.NewEncoder(w).Encode(dto.SubscriptionResponse{})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer stubServices.Close()
+	stubConfig := s.Config
+	stubConfig.AuthenticationService, stubConfig.KeyManagementService, stubConfig.CryptoAdapterService = stubServices.URL, stubServices.URL, stubServices.URL
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	controller := controllers.NewUserAssetController(utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, stubConfig, validation.New(), &userAssetRepository)
+	rotate := func() {
+		request, _ := http.NewRequest(http.MethodPost, "/assets/"+assetID.String()+"/rotate-address?network=ERC20", nil)
+		request = mux.SetURLVars(request, map[string]string{"assetId": assetID.String()})
+		response := httptest.NewRecorder()
+		controller.RotateAssetAddress(response, request)
+		require.Equal(s.T(), http.StatusOK, response.Code, response.Body.String())
+	}
+	primaryAddress := func() model.UserAddress {
+		userAddress := model.UserAddress{}
+		require.NoError(s.T(), s.DB.Where("asset_id = ? AND network = ? AND is_primary_address = ?", assetID, "ERC20", true).First(&userAddress).Error)
+		return userAddress
+	}
+
+	rotate()
+	first := primaryAddress()
+	rotate()
+	second := primaryAddress()
+	assert.NotEqual(s.T(), first.Address, second.Address, "Expected the rotated address to be replaced by a new one")
+	require.NotNil(s.T(), second.KeyIdentity, "Expected the replacement to be derived under its own identity")
+	assert.Equal(s.T(), "0x"+second.KeyIdentity.String(), second.Address)
+	assert.True(s.T(), subscribed[second.Address], "Expected the replacement address to be subscribed")
+	assert.Equal(s.T(), model.SubscriptionStatus.ACTIVE, second.SubscriptionStatus)
+
+	request, _ := http.NewRequest(http.MethodPost, "/assets/by-address/"+second.Address+"/invalidate", bytes.NewBuffer([]byte(`{"invalidatedBy" : "security", "reason" : "private key exposed"}`)))
+	request = mux.SetURLVars(request, map[string]string{"address": second.Address})
+	response := httptest.NewRecorder()
+	controller.InvalidateAddress(response, request)
+	require.Equal(s.T(), http.StatusOK, response.Code, response.Body.String())
+	assert.True(s.T(), cancelled[second.Address], "Expected the subscription of the invalidated address to be cancelled")
+	assert.False(s.T(), cancelled[first.Address])
+	require.NoError(s.T(), s.DB.First(&second, "id = ?", second.ID).Error)
+	assert.Equal(s.T(), model.SubscriptionStatus.CANCELLED, second.SubscriptionStatus)
+
+	rotate()
+	third := primaryAddress()
+	assert.NotContains(s.T(), []string{first.Address, second.Address}, third.Address, "Expected the invalidated address not to be issued again")
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_SubscriptionReconcilerResubscribesUnwatchedAddresses() {
+	createAssetInputData := []byte(`{"assets" : ["BTC"],"userId" : "8e2b6c1d-4f3a-4d7e-b9a0-2c5f7e1d3b64"}`)
+	createAssetRequest, _ := http.NewRequest("POST", test.CreateAssetEndpoint, bytes.NewBuffer(createAssetInputData))
+	createAssetRequest.Header.Set("x-auth-token", authToken)
+	createResponse := httptest.NewRecorder()
+	s.Router.ServeHTTP(createResponse, createAssetRequest)
+	resBody, err := ioutil.ReadAll(createResponse.Body)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	createAssetResponse := dto.UserAssetResponse{}
+	err = json.Unmarshal(resBody, &createAssetResponse)
+	if createResponse.Code != http.StatusCreated || len(createAssetResponse.Assets) < 1 {
+		require.NoError(s.T(), errors.New("Expected asset creation to not error"))
+	}
+	assetID := createAssetResponse.Assets[0].ID
+
+	failedAddress := model.UserAddress{AssetID: assetID, Address: "bc1qfailedsubscription", Network: "BTC", IsPrimaryAddress: true, SubscriptionStatus: model.SubscriptionStatus.FAILED}
+	activeAddress := model.UserAddress{AssetID: assetID, Address: "bc1qactivesubscription", Network: "BTC", SubscriptionStatus: model.SubscriptionStatus.ACTIVE}
+	for _, userAddress := range []*model.UserAddress{&failedAddress, &activeAddress} {
+		if err := s.DB.Create(userAddress).Error; err != nil {
+			require.NoError(s.T(), err)
+		}
+	}
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	unwatchedAddresses := []dto.UnwatchedAddress{}
+	if err := userAssetRepository.FetchUnwatchedAddresses(model.SubscriptionStatus.FAILED, &unwatchedAddresses); err != nil {
+		require.NoError(s.T(), err)
+	}
+	reported := dto.UnwatchedAddress{}
+	for _, unwatchedAddress := range unwatchedAddresses {
+		if unwatchedAddress.ID == failedAddress.ID {
+			reported = unwatchedAddress
+		}
+	}
+	assert.Equal(s.T(), failedAddress.Address, reported.Address, "Expected the failed address in the report")
+	assert.Equal(s.T(), "BTC", reported.AssetSymbol)
+
+	adapterAvailable := false
+	subscribedAddresses := map[string]bool{}
+	cryptoAdapter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !adapterAvailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		requestData := dto.SubscriptionRequestV2{}
+		_ = json.NewDecoder(r.Body).Decode(&requestData)
+		for _, address := range requestData.Subscriptions["0"] {
+			subscribedAddresses[address] = true
+		}
+		_ = json.NewEncoder(w).Encode(dto.SubscriptionResponse{Message: "subscribed", Status: true})
+	}))
+	defer cryptoAdapter.Close()
+
+	config := s.Config
+	config.CryptoAdapterService = cryptoAdapter.URL
+	config.SubscriptionBatchSize = 1
+	service := services.BaseService{Config: config, Cache: authCache, Logger: s.Logger}
+
+	reconciliation, err := service.ReconcileAddressSubscriptions(&userAssetRepository)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), reconciliation.Attempted, reconciliation.Failed, "Expected every batch to fail while the crypto-adapter is down")
+	reloaded := model.UserAddress{}
+	s.DB.Where("id = ?", failedAddress.ID).First(&reloaded)
+	assert.Equal(s.T(), model.SubscriptionStatus.FAILED, reloaded.SubscriptionStatus)
+
+	adapterAvailable = true
+	reconciliation, err = service.ReconcileAddressSubscriptions(&userAssetRepository)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), reconciliation.Attempted, reconciliation.Subscribed)
+	assert.True(s.T(), subscribedAddresses[failedAddress.Address], "Expected the failed address to be re-subscribed")
+	assert.False(s.T(), subscribedAddresses[activeAddress.Address], "Expected an active address not to be re-subscribed")
+	s.DB.Where("id = ?", failedAddress.ID).First(&reloaded)
+	assert.Equal(s.T(), model.SubscriptionStatus.ACTIVE, reloaded.SubscriptionStatus)
+	assert.NotNil(s.T(), reloaded.SubscribedAt)
+}
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"encoding/json"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_AssetConfigOverridesSurviveReseeding() {
+	s.DB.AutoMigrate(&model.AssetConfigOverride{}, &model.AssetConfigChange{})
+	defer s.DB.DropTableIfExists(&model.AssetConfigOverride{}, &model.AssetConfigChange{})
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	seededNetwork, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, "BTC", "BTC")
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	seededDenomination := model.Denomination{}
+	if err := s.DB.Where("asset_symbol = ?", "BTC").First(&seededDenomination).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	defer s.DB.Model(&model.Network{}).Where("asset_symbol = ? AND network = ?", "BTC", "BTC").Updates(map[string]interface{}{"sweep_fee": seededNetwork.SweepFee, "deposit_activity": seededNetwork.DepositActivity})
+	defer s.DB.Where("asset_symbol = ? AND network = ?", "BTC", "LIGHTNING").Delete(model.Network{})
+
+	sweepFee, depositActivity := int64(1500), "UNAVAILABLE"
+	fields, err := services.GetConfigFields(dto.NetworkConfig{SweepFee: &sweepFee, DepositActivity: &depositActivity})
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	network := seededNetwork
+	change, err := services.ChangeAssetConfig(&userAssetRepository, &network, fields, model.AssetConfigChange{EntityType: model.AssetConfigEntity.NETWORK,
+		Action: model.AssetConfigAction.UPDATE, AssetSymbol: "BTC", Network: "BTC", ChangedBy: "ops@bundle.africa"})
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	fieldChanges := map[string]dto.AssetConfigFieldChange{}
+	if err := json.Unmarshal([]byte(change.Changes), &fieldChanges); err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), `"ACTIVE"`, string(fieldChanges["depositActivity"].From), "Expected the previous value to be recorded")
+	assert.Equal(s.T(), "1500", string(fieldChanges["sweepFee"].To))
+
+	fields, _ = services.GetConfigFields(map[string]interface{}{"network": "LIGHTNING", "depositActivity": "ACTIVE"})
+	if _, err := services.ChangeAssetConfig(&userAssetRepository, &model.Network{AssetSymbol: "BTC"}, fields, model.AssetConfigChange{EntityType: model.AssetConfigEntity.NETWORK,
+		Action: model.AssetConfigAction.CREATE, AssetSymbol: "BTC", Network: "LIGHTNING", ChangedBy: "ops@bundle.africa"}); err != nil {
+		require.NoError(s.T(), err)
+	}
+
+	// re-seeding with the upstream values keeps the admin changes and the locally added network
+	seededDenomination.Networks = []model.Network{seededNetwork}
+	services.SaveSupportedAssets(s.DB, s.Logger, []model.Denomination{seededDenomination})
+
+	reseededNetwork, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, "BTC", "BTC")
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), sweepFee, reseededNetwork.SweepFee, "Expected the overridden sweep fee to survive re-seeding")
+	assert.Equal(s.T(), depositActivity, reseededNetwork.DepositActivity)
+	assert.Equal(s.T(), seededNetwork.AddressProvider, reseededNetwork.AddressProvider, "Expected fields without overrides to follow the seeded value")
+
+	localNetwork, err := services.GetNetworkByAssetAndNetwork(&userAssetRepository, "LIGHTNING", "BTC")
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), "ACTIVE", localNetwork.DepositActivity, "Expected the locally added network to be kept")
+
+	changes := []model.AssetConfigChange{}
+	if err := userAssetRepository.FetchAssetConfigChanges("BTC", "", &changes); err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), 2, len(changes))
+}
@@ -0,0 +1,91 @@
+package test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeAssetCatalogue(path string, catalogue dto.AssetCatalogue) error {
+	content, err := json.Marshal(catalogue)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+func (s *Suite) Test_AssetSeedingFallsBackToLocalCatalogueAndKeepsNetworkIDs() {
+	s.DB.AutoMigrate(&model.AssetConfigOverride{})
+	defer s.DB.DropTableIfExists(&model.AssetConfigOverride{})
+	defer s.DB.Where("asset_symbol IN (?)", []string{"XLM", "USDT"}).Delete(model.Network{})
+	defer s.DB.Where("asset_symbol IN (?)", []string{"XLM", "USDT"}).Delete(model.Denomination{})
+
+	catalogueDir, err := ioutil.TempDir("", "asset-catalogue")
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	defer os.RemoveAll(catalogueDir)
+
+	catalogue := dto.AssetCatalogue{
+		Version: 1,
+		Denominations: []dto.AssetDenomination{
+			{Name: "Stellar", Symbol: "XLM", CoinType: 148, TokenType: "NATIVE", NativeDecimal: 7, RequiresMemo: true, Network: "XLM",
+				TradeActivity: "ACTIVE", TransferActivity: "ACTIVE", DepositActivity: "ACTIVE", WithdrawActivity: "ACTIVE"},
+			{Name: "Tether", Symbol: "USDT", CoinType: 60, TokenType: "ERC20", NativeDecimal: 6, Network: "ERC20",
+				TradeActivity: "ACTIVE", TransferActivity: "ACTIVE", DepositActivity: "ACTIVE", WithdrawActivity: "ACTIVE",
+				AdditionalNetworks: []dto.AdditionalNetwork{{NativeAsset: "TRX", CoinType: 195, NativeDecimal: 6, Network: "TRC20", DepositActivity: "ACTIVE", WithdrawActivity: "ACTIVE"}}},
+		},
+		TWDenominations: []dto.TWDenomination{{Name: "Stellar", Symbol: "XLM", CoinId: 148}, {Name: "Ethereum", Symbol: "ETH", CoinId: 60}, {Name: "Tron", Symbol: "TRX", CoinId: 195}},
+	}
+	config := s.Config
+	config.AssetSeedingMode = services.AssetSeedingMode.MERGE
+	config.AssetCataloguePath = filepath.Join(catalogueDir, "assets.json")
+	config.RateServiceUrl = "http://127.0.0.1:1"
+	if err := writeAssetCatalogue(config.AssetCataloguePath, catalogue); err != nil {
+		require.NoError(s.T(), err)
+	}
+
+	assets, report, err := services.PlanAssetSeeding(s.DB, s.Logger, config, authCache)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), services.AssetSeedingMode.LOCAL, report.Source, "Expected seeding to fall back to the local catalogue")
+	assert.Equal(s.T(), 1, report.CatalogueVersion)
+	assert.Equal(s.T(), 5, len(report.Changes), "Expected two denominations and three networks to be created")
+	services.SaveSupportedAssets(s.DB, s.Logger, assets)
+
+	seededNetwork := model.Network{}
+	if err := s.DB.Where("asset_symbol = ? AND network = ?", "USDT", "TRC20").First(&seededNetwork).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), "TRX", seededNetwork.NativeAsset)
+
+	catalogue.Version = 2
+	catalogue.Denominations[1].AdditionalNetworks[0].DepositActivity = "UNAVAILABLE"
+	if err := writeAssetCatalogue(config.AssetCataloguePath, catalogue); err != nil {
+		require.NoError(s.T(), err)
+	}
+	assets, report, err = services.PlanAssetSeeding(s.DB, s.Logger, config, authCache)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	if assert.Equal(s.T(), 1, len(report.Changes), "Expected only the changed network to be reported") {
+		assert.Equal(s.T(), model.AssetConfigAction.UPDATE, report.Changes[0].Action)
+		assert.Equal(s.T(), `"UNAVAILABLE"`, string(report.Changes[0].Changes["depositActivity"].To))
+	}
+	services.SaveSupportedAssets(s.DB, s.Logger, assets)
+
+	reseededNetwork := model.Network{}
+	if err := s.DB.Where("asset_symbol = ? AND network = ?", "USDT", "TRC20").First(&reseededNetwork).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), seededNetwork.ID, reseededNetwork.ID, "Expected re-seeding to update the network in place")
+	assert.Equal(s.T(), "UNAVAILABLE", reseededNetwork.DepositActivity)
+}
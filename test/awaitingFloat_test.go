@@ -0,0 +1,188 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+	"wallet-adapter/controllers"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_AwaitingFloatWithdrawalsResumeFirstInFirstOut() {
+	start := time.Date(2021, 9, 27, 9, 0, 0, 0, time.UTC)
+	batchID := uuid.NewV4()
+	queued := []model.TransactionQueue{
+		{BaseModel: model.BaseModel{ID: uuid.NewV4(), CreatedAt: start}, AssetSymbol: "ETH", Network: "ETH", TransactionId: uuid.NewV4(), Value: decimal.NewFromInt(40)},
+		{BaseModel: model.BaseModel{ID: uuid.NewV4(), CreatedAt: start.Add(time.Minute)}, AssetSymbol: "ETH", Network: "ETH", TransactionId: uuid.NewV4(), BatchID: batchID, Value: decimal.NewFromInt(30)},
+		{BaseModel: model.BaseModel{ID: uuid.NewV4(), CreatedAt: start.Add(2 * time.Minute)}, AssetSymbol: "ETH", Network: "ETH", TransactionId: uuid.NewV4(), Value: decimal.NewFromInt(50)},
+		{BaseModel: model.BaseModel{ID: uuid.NewV4(), CreatedAt: start.Add(3 * time.Minute)}, AssetSymbol: "ETH", Network: "ETH", TransactionId: uuid.NewV4(), BatchID: batchID, Value: decimal.NewFromInt(20)},
+		{BaseModel: model.BaseModel{ID: uuid.NewV4(), CreatedAt: start.Add(4 * time.Minute)}, AssetSymbol: "ETH", Network: "ETH", TransactionId: uuid.NewV4(), Value: decimal.NewFromInt(5)},
+	}
+
+	parked := tasks.GroupParkedWithdrawals(queued)
+	require.Len(s.T(), parked, 4, "Expected the two batched withdrawals to be parked together")
+	assert.Equal(s.T(), batchID, parked[1].BatchID)
+	assert.Equal(s.T(), "50", parked[1].Amount.String())
+	assert.Len(s.T(), parked[1].TransactionIDs, 2)
+
+	resumable := tasks.ResumableWithdrawals(big.NewInt(100), parked)
+	require.Len(s.T(), resumable, 2, "Expected the 50 withdrawal to wait, and the 5 withdrawal queued after it to wait behind it")
+	assert.Equal(s.T(), queued[0].ID, resumable[0].QueueIDs[0])
+	assert.Equal(s.T(), batchID, resumable[1].BatchID)
+	assert.Empty(s.T(), tasks.ResumableWithdrawals(big.NewInt(39), parked))
+}
+
+func (s *Suite) Test_FloatTopUpEstimateFromFundingRequests() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+
+	estimate, err := tasks.EstimateFloatTopUp(baseRepository, "XRP", "XRP")
+	require.NoError(s.T(), err)
+	assert.Nil(s.T(), estimate, "Expected no estimate without an open funding request")
+
+	now := time.Now()
+	openRequest := model.FundingRequest{BaseModel: model.BaseModel{CreatedAt: now.Add(-time.Hour)}, AssetSymbol: "XRP", Network: "XRP", FloatAddress: "rFloat",
+		Amount: "100", BaseAmount: "100000000", FloatBalance: "0", Status: model.FundingRequestStatus.OPEN, AckToken: "xrp-open", ExpiresAt: now.Add(23 * time.Hour)}
+	require.NoError(s.T(), baseRepository.Create(&openRequest))
+	estimate, err = tasks.EstimateFloatTopUp(baseRepository, "XRP", "XRP")
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), estimate)
+	assert.WithinDuration(s.T(), openRequest.ExpiresAt, *estimate, time.Second, "Expected the expiry to stand in without past fulfilments")
+
+	// past requests took three hours to fulfil on average
+	for i, hours := range []int{2, 4} {
+		opened := now.AddDate(0, 0, -i-1)
+		fulfilled := opened.Add(time.Duration(hours) * time.Hour)
+		require.NoError(s.T(), baseRepository.Create(&model.FundingRequest{BaseModel: model.BaseModel{CreatedAt: opened}, AssetSymbol: "XRP", Network: "XRP", FloatAddress: "rFloat",
+			Amount: "100", BaseAmount: "100000000", FloatBalance: "0", Status: model.FundingRequestStatus.FULFILLED, AckToken: uuid.NewV4().String(), ExpiresAt: opened.Add(24 * time.Hour), FulfilledAt: &fulfilled}))
+	}
+	estimate, err = tasks.EstimateFloatTopUp(baseRepository, "XRP", "XRP")
+	require.NoError(s.T(), err)
+	require.NotNil(s.T(), estimate)
+	assert.WithinDuration(s.T(), openRequest.CreatedAt.Add(3*time.Hour), *estimate, time.Second)
+}
+
+func (s *Suite) Test_ProcessTransactionsSendsResumedWithdrawalsWhenFloatCoversPartOfTheQueue() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	userAssetRepository := database.UserAssetRepository{BaseRepository: baseRepository}
+	require.NoError(s.T(), baseRepository.Create(&model.HotWalletAsset{Address: "LFloat", AssetSymbol: "LTC", Network: "LTC"}))
+
+	// the float covers the oldest parked withdrawal but not the next one
+	now := time.Now()
+	queue := func(status string, value int64, queuedAt time.Time) model.TransactionQueue {
+		transaction := model.Transaction{BaseModel: model.BaseModel{CreatedAt: queuedAt}, TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(),
+			Value: "0", PreviousBalance: "0", AvailableBalance: "0", AssetSymbol: "LTC", Network: "LTC", TransactionStatus: status}
+		require.NoError(s.T(), baseRepository.Create(&transaction))
+		queuedTransaction := model.TransactionQueue{BaseModel: model.BaseModel{CreatedAt: queuedAt}, Recipient: "LRecipient", Value: decimal.NewFromInt(value), AssetSymbol: "LTC",
+			Network: "LTC", DebitReference: uuid.NewV4().String(), TransactionId: transaction.ID, TransactionStatus: status}
+		require.NoError(s.T(), baseRepository.Create(&queuedTransaction))
+		return queuedTransaction
+	}
+	covered := queue(model.TransactionStatus.AWAITING_FLOAT, 100, now.Add(-3*time.Hour))
+	uncovered := queue(model.TransactionStatus.AWAITING_FLOAT, 200, now.Add(-2*time.Hour))
+	later := queue(model.TransactionStatus.PENDING, 10, now.Add(-time.Hour))
+
+	var mutex sync.Mutex
+	sent := []string{}
+	services := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/token":
+			_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+		case "/locks/acquire":
+			_ = json.NewEncoder(w).Encode(dto.LockerServiceResponse{Token: "lock-token"})
+		case "/locks/release":
+			_ = json.NewEncoder(w).Encode(dto.ServicesRequestSuccess{Success: true})
+		case "/onchain-balance":
+			_ = json.NewEncoder(w).Encode(dto.OnchainBalanceResponse{Balance: "150", AssetSymbol: "LTC"})
+		case "/transactions/send-single":
+			request := dto.SendSingleTransactionRequest{}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			mutex.Lock()
+			sent = append(sent, request.Reference)
+			mutex.Unlock()
+			_ = json.NewEncoder(w).Encode(dto.SendTransactionResponse{TransactionHash: "0x" + request.Reference})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer services.Close()
+
+	config := s.Config
+	config.AuthenticationService, config.LockerService, config.CryptoAdapterService, config.TransactionSignersURL = services.URL, services.URL, services.URL, services.URL
+	controller := controllers.NewUserAssetController(utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, config, nil, &userAssetRepository)
+	request, _ := http.NewRequest(http.MethodPost, "/assets/process-transaction", nil)
+	controller.ProcessTransactions(httptest.NewRecorder(), request)
+
+	assert.Equal(s.T(), []string{covered.DebitReference}, sent, "Expected only the covered withdrawal to be sent")
+	for queueID, status := range map[uuid.UUID]string{covered.ID: model.TransactionStatus.PROCESSING, uncovered.ID: model.TransactionStatus.AWAITING_FLOAT,
+		later.ID: model.TransactionStatus.AWAITING_FLOAT} {
+		queuedTransaction := model.TransactionQueue{}
+		require.NoError(s.T(), baseRepository.Get(&model.TransactionQueue{BaseModel: model.BaseModel{ID: queueID}}, &queuedTransaction))
+		assert.Equal(s.T(), status, queuedTransaction.TransactionStatus)
+	}
+}
+
+func (s *Suite) Test_HeldWithdrawalInParkedBatchIsNotResumedOrSent() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	batchRepository := database.BatchRepository{BaseRepository: baseRepository}
+	require.NoError(s.T(), baseRepository.Create(&model.HotWalletAsset{Address: "DFloat", AssetSymbol: "DOGE", Network: "DOGE"}))
+	batch := model.BatchRequest{AssetSymbol: "DOGE", Network: "DOGE", Status: model.BatchStatus.START_MODE}
+	require.NoError(s.T(), batchRepository.Create(&batch))
+	pending := s.queueBatchedWithdrawal(batchRepository, batch, model.TransactionStatus.PENDING)
+	held := s.queueBatchedWithdrawal(batchRepository, batch, model.TransactionStatus.SCREENING_HOLD)
+
+	processor := &controllers.BatchTransactionProcessor{Logger: s.Logger, Config: s.Config, Repository: &batchRepository}
+	require.NoError(s.T(), processor.UpdateBatchedTransactionsStatus(batch, model.ChainTransaction{}, model.BatchStatus.AWAITING_FLOAT))
+
+	var mutex sync.Mutex
+	sentRecipients := [][]dto.BatchRecipients{}
+	services := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/token":
+			_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+		case "/locks/acquire":
+			_ = json.NewEncoder(w).Encode(dto.LockerServiceResponse{Token: "lock-token"})
+		case "/locks/release":
+			_ = json.NewEncoder(w).Encode(dto.ServicesRequestSuccess{Success: true})
+		case "/onchain-balance":
+			_ = json.NewEncoder(w).Encode(dto.OnchainBalanceResponse{Balance: "1000000000", AssetSymbol: "DOGE"})
+		case "/transactions/send-batch":
+			request := dto.BatchRequest{}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			mutex.Lock()
+			sentRecipients = append(sentRecipients, request.Recipients)
+			mutex.Unlock()
+			_ = json.NewEncoder(w).Encode(dto.SendTransactionResponse{TransactionHash: "0xdoge"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer services.Close()
+
+	config := s.Config
+	config.AuthenticationService, config.LockerService, config.CryptoAdapterService, config.TransactionSignersURL = services.URL, services.URL, services.URL, services.URL
+	controller := controllers.NewBatchController(utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, config, nil, &batchRepository)
+	request, _ := http.NewRequest(http.MethodPost, "/assets/process-batched-transactions?assetSymbol=DOGE", nil)
+	controller.ProcessBatchBTCTransactions(httptest.NewRecorder(), request)
+
+	require.Len(s.T(), sentRecipients, 1, "Expected the resumed batch to be sent")
+	assert.Len(s.T(), sentRecipients[0], 1, "Expected the batch to be sent without the held withdrawal")
+	for transactionID, status := range map[uuid.UUID]string{pending.ID: model.TransactionStatus.PROCESSING, held.ID: model.TransactionStatus.SCREENING_HOLD} {
+		transaction, queuedTransaction := model.Transaction{}, model.TransactionQueue{}
+		require.NoError(s.T(), baseRepository.Get(&model.Transaction{BaseModel: model.BaseModel{ID: transactionID}}, &transaction))
+		require.NoError(s.T(), baseRepository.Get(&model.TransactionQueue{TransactionId: transactionID}, &queuedTransaction))
+		assert.Equal(s.T(), status, transaction.TransactionStatus)
+		assert.Equal(s.T(), status, queuedTransaction.TransactionStatus)
+	}
+}
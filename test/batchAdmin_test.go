@@ -0,0 +1,117 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"wallet-adapter/controllers"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	validation "gopkg.in/go-playground/validator.v9"
+)
+
+func (s *Suite) queueBatchedWithdrawal(batchRepository database.BatchRepository, batch model.BatchRequest, status string) model.Transaction {
+	transaction := model.Transaction{TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(), Value: "0.1", PreviousBalance: "0",
+		AvailableBalance: "0", AssetSymbol: batch.AssetSymbol, Network: batch.Network, BatchID: batch.ID, TransactionStatus: status}
+	require.NoError(s.T(), batchRepository.Create(&transaction))
+	require.NoError(s.T(), batchRepository.Create(&model.TransactionQueue{Recipient: "recipient", Value: decimal.NewFromInt(10000000), AssetSymbol: batch.AssetSymbol,
+		Network: batch.Network, DebitReference: uuid.NewV4().String(), TransactionId: transaction.ID, BatchID: batch.ID, TransactionStatus: status}))
+	return transaction
+}
+
+func (s *Suite) Test_UnbatchTransactionsMovesWithdrawalsToSingleProcessing() {
+	batchRepository := database.BatchRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	batchService := services.BatchService{BaseService: services.BaseService{Config: s.Config, Logger: s.Logger}}
+	batch := model.BatchRequest{AssetSymbol: "DOGE", Network: "DOGE", Status: model.BatchStatus.START_MODE}
+	require.NoError(s.T(), batchRepository.Create(&batch))
+	pending := s.queueBatchedWithdrawal(batchRepository, batch, model.TransactionStatus.PENDING)
+	awaitingFloat := s.queueBatchedWithdrawal(batchRepository, batch, model.TransactionStatus.AWAITING_FLOAT)
+
+	transactionIds, err := batchService.UnbatchTransactions(&batchRepository, batch, "operations")
+	require.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), []uuid.UUID{pending.ID, awaitingFloat.ID}, transactionIds)
+
+	leftInBatch := []model.TransactionQueue{}
+	require.NoError(s.T(), batchRepository.FetchByFieldName(&model.TransactionQueue{BatchID: batch.ID}, &leftInBatch))
+	assert.Empty(s.T(), leftInBatch, "Expected no queued withdrawal left in the batch")
+	for _, expected := range []model.Transaction{pending, awaitingFloat} {
+		transaction := model.Transaction{}
+		require.NoError(s.T(), batchRepository.Get(&model.Transaction{BaseModel: model.BaseModel{ID: expected.ID}}, &transaction))
+		assert.Equal(s.T(), uuid.Nil, transaction.BatchID)
+		assert.Equal(s.T(), expected.TransactionStatus, transaction.TransactionStatus, "Expected the withdrawal to keep its status")
+	}
+	require.NoError(s.T(), batchRepository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: batch.ID}}, &batch))
+	assert.Equal(s.T(), "operations", batch.UpdatedBy)
+}
+
+func (s *Suite) Test_RetryBatchRequeuesOnlyTerminatedWithdrawals() {
+	batchRepository := database.BatchRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	batch := model.BatchRequest{AssetSymbol: "DASH", Network: "DASH", Status: model.BatchStatus.TERMINATED}
+	require.NoError(s.T(), batchRepository.Create(&batch))
+	terminated := s.queueBatchedWithdrawal(batchRepository, batch, model.TransactionStatus.TERMINATED)
+	held := s.queueBatchedWithdrawal(batchRepository, batch, model.TransactionStatus.SCREENING_HOLD)
+
+	services := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/token":
+			_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+		case "/locks/acquire":
+			_ = json.NewEncoder(w).Encode(dto.LockerServiceResponse{Token: "lock-token"})
+		case "/locks/release":
+			_ = json.NewEncoder(w).Encode(dto.ServicesRequestSuccess{Success: true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer services.Close()
+	config := s.Config
+	config.AuthenticationService, config.LockerService = services.URL, services.URL
+	controller := controllers.NewBatchController(utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, config, validation.New(), &batchRepository)
+	retry := func() *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/batches/%s/retry", batch.ID), bytes.NewBuffer([]byte(`{"requestedBy" : "operations"}`)))
+		request = mux.SetURLVars(request, map[string]string{"batchId": batch.ID.String()})
+		response := httptest.NewRecorder()
+		controller.RetryBatch(response, request)
+		return response
+	}
+
+	response := retry()
+	require.Equal(s.T(), http.StatusOK, response.Code, response.Body.String())
+	retryBatch := model.BatchRequest{}
+	require.NoError(s.T(), batchRepository.Db().Where("asset_symbol = ? AND id <> ?", "DASH", batch.ID).First(&retryBatch).Error)
+	assert.Equal(s.T(), model.BatchStatus.START_MODE, retryBatch.Status)
+	assert.Equal(s.T(), model.BatchCloseReason.FORCED, retryBatch.CloseReason)
+	assert.Equal(s.T(), 1, retryBatch.NoOfRecords, "Expected the retry batch to carry only the terminated withdrawal")
+	assert.Equal(s.T(), "operations", retryBatch.UpdatedBy)
+	require.NoError(s.T(), batchRepository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: batch.ID}}, &batch))
+	assert.Equal(s.T(), "operations", batch.UpdatedBy)
+
+	for transactionID, expected := range map[uuid.UUID]struct {
+		batchID uuid.UUID
+		status  string
+	}{terminated.ID: {retryBatch.ID, model.TransactionStatus.PENDING}, held.ID: {batch.ID, model.TransactionStatus.SCREENING_HOLD}} {
+		transaction, queuedTransaction := model.Transaction{}, model.TransactionQueue{}
+		require.NoError(s.T(), batchRepository.Get(&model.Transaction{BaseModel: model.BaseModel{ID: transactionID}}, &transaction))
+		require.NoError(s.T(), batchRepository.Get(&model.TransactionQueue{TransactionId: transactionID}, &queuedTransaction))
+		assert.Equal(s.T(), expected.batchID, transaction.BatchID)
+		assert.Equal(s.T(), expected.status, transaction.TransactionStatus)
+		assert.Equal(s.T(), expected.batchID, queuedTransaction.BatchID)
+		assert.Equal(s.T(), expected.status, queuedTransaction.TransactionStatus)
+	}
+
+	response = retry()
+	assert.Equal(s.T(), http.StatusBadRequest, response.Code, "Expected a requeued batch not to be retried twice")
+	assert.Contains(s.T(), response.Body.String(), errorcode.BATCH_EMPTY)
+}
@@ -0,0 +1,159 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_BatchCloseReasons() {
+	opened := time.Date(2021, 9, 27, 9, 0, 0, 0, time.UTC)
+	policy := model.BatchPolicy{MaxRecipients: 3, MaxTotalValue: decimal.NewFromInt(2), MaxAgeMinutes: 60, MinAgeMinutes: 10}
+
+	assert.Equal(s.T(), "", services.GetBatchCloseReason(policy, opened, 0, decimal.Zero, opened.Add(2*time.Hour), true), "Expected an empty batch to stay open")
+	assert.Equal(s.T(), model.BatchCloseReason.MAX_RECIPIENTS, services.GetBatchCloseReason(policy, opened, 3, decimal.NewFromFloat(0.3), opened, false))
+	assert.Equal(s.T(), model.BatchCloseReason.MAX_VALUE, services.GetBatchCloseReason(policy, opened, 1, decimal.NewFromFloat(2), opened, false))
+	assert.Equal(s.T(), model.BatchCloseReason.MAX_AGE, services.GetBatchCloseReason(policy, opened, 1, decimal.NewFromFloat(0.1), opened.Add(time.Hour), false))
+	assert.Equal(s.T(), "", services.GetBatchCloseReason(policy, opened, 1, decimal.NewFromFloat(0.1), opened.Add(30*time.Minute), false), "Expected the batcher to leave scheduled closing to the processing run")
+	assert.Equal(s.T(), "", services.GetBatchCloseReason(policy, opened, 1, decimal.NewFromFloat(0.1), opened.Add(5*time.Minute), true), "Expected a scheduled run to leave a batch below its minimum age")
+	assert.Equal(s.T(), model.BatchCloseReason.SCHEDULED, services.GetBatchCloseReason(policy, opened, 1, decimal.NewFromFloat(0.1), opened.Add(10*time.Minute), true))
+	assert.Equal(s.T(), model.BatchCloseReason.SCHEDULED, services.GetBatchCloseReason(model.BatchPolicy{}, opened, 1, decimal.NewFromFloat(0.1), opened, true), "Expected batches without a policy to close on every scheduled run")
+}
+
+func (s *Suite) Test_FullBatchIsClosedBeforeTakingAnotherWithdrawal() {
+	batchRepository := database.BatchRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	batchService := services.BatchService{BaseService: services.BaseService{Config: s.Config, Logger: s.Logger}}
+	require.NoError(s.T(), batchRepository.Create(&model.BatchPolicy{AssetSymbol: "LTC", Network: "LTC", MaxRecipients: 2, UpdatedBy: "ops"}))
+
+	firstBatchID, err := batchService.GetWaitingBatchId(&batchRepository, "LTC", "LTC", decimal.NewFromFloat(0.5))
+	require.NoError(s.T(), err)
+	batchWithdrawal := func(status string) {
+		require.NoError(s.T(), batchRepository.Create(&model.Transaction{TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(),
+			Value: "0.5", PreviousBalance: "0", AvailableBalance: "0", AssetSymbol: "LTC", Network: "LTC", BatchID: firstBatchID, TransactionStatus: status}))
+	}
+	batchWithdrawal(model.TransactionStatus.PENDING)
+	batchWithdrawal(model.TransactionStatus.SCREENING_HOLD)
+	sameBatchID, err := batchService.GetWaitingBatchId(&batchRepository, "LTC", "LTC", decimal.NewFromFloat(0.5))
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), firstBatchID, sameBatchID, "Expected a held withdrawal not to count toward the maximum recipients")
+	batchWithdrawal(model.TransactionStatus.PENDING)
+
+	secondBatchID, err := batchService.GetWaitingBatchId(&batchRepository, "LTC", "LTC", decimal.NewFromFloat(0.5))
+	require.NoError(s.T(), err)
+	assert.NotEqual(s.T(), firstBatchID, secondBatchID, "Expected a new batch once the waiting one has the maximum recipients")
+
+	firstBatch := model.BatchRequest{}
+	require.NoError(s.T(), batchRepository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: firstBatchID}}, &firstBatch))
+	assert.Equal(s.T(), model.BatchStatus.START_MODE, firstBatch.Status)
+	assert.Equal(s.T(), model.BatchCloseReason.MAX_RECIPIENTS, firstBatch.CloseReason)
+	assert.NotNil(s.T(), firstBatch.ClosedAt)
+}
+
+func (s *Suite) Test_WithdrawalJoinsTheBatchStillWaitingUnderItsLock() {
+	batchRepository := database.BatchRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	closedBatch := model.BatchRequest{AssetSymbol: "LTC", Network: "LTC"}
+	require.NoError(s.T(), batchRepository.Create(&closedBatch))
+
+	var mutex sync.Mutex
+	locked := []string{}
+	stubServices := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/token":
+			_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+		case "/locks/acquire":
+			request := dto.LockerServiceRequest{}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			mutex.Lock()
+			locked = append(locked, request.Identifier)
+			mutex.Unlock()
+			// the processing run closes the waiting batch while the withdrawal waits for its lock
+			if request.Identifier == s.Config.LockerPrefix+closedBatch.ID.String() {
+				require.NoError(s.T(), s.DB.Model(&closedBatch).Update("status", model.BatchStatus.START_MODE).Error)
+			}
+			_ = json.NewEncoder(w).Encode(dto.LockerServiceResponse{Token: "lock-token"})
+		case "/locks/release":
+			_ = json.NewEncoder(w).Encode(dto.ServicesRequestSuccess{Success: true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer stubServices.Close()
+	config := s.Config
+	config.AuthenticationService, config.LockerService = stubServices.URL, stubServices.URL
+	batchService := services.BatchService{BaseService: services.BaseService{Config: config, Cache: utility.InitializeCache(cacheDuration, purgeInterval), Logger: s.Logger}}
+
+	batchID, release, err := batchService.JoinWaitingBatch(&batchRepository, "LTC", "LTC", decimal.NewFromFloat(0.5))
+	require.NoError(s.T(), err)
+	release()
+	assert.NotEqual(s.T(), closedBatch.ID, batchID, "Expected the withdrawal to pass over the batch closed before its lock was taken")
+	waitingBatch := model.BatchRequest{}
+	require.NoError(s.T(), batchRepository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: batchID}}, &waitingBatch))
+	assert.Equal(s.T(), model.BatchStatus.WAIT_MODE, waitingBatch.Status)
+	assert.Equal(s.T(), []string{s.Config.LockerPrefix + services.BatchingLockID("LTC", "LTC"), s.Config.LockerPrefix + closedBatch.ID.String(),
+		s.Config.LockerPrefix + batchID.String()}, locked, "Expected the batching lock to be held while the batch locks are taken")
+}
+
+func (s *Suite) Test_BatcherLeavesABatchThatMovedOnBeforeItsLock() {
+	batchRepository := database.BatchRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	require.NoError(s.T(), batchRepository.Create(&model.BatchPolicy{AssetSymbol: "LTC", Network: "LTC", MaxAgeMinutes: 60, UpdatedBy: "ops"}))
+	sentBatch := model.BatchRequest{AssetSymbol: "LTC", Network: "LTC", Status: model.BatchStatus.WAIT_MODE}
+	require.NoError(s.T(), batchRepository.Create(&sentBatch))
+	require.NoError(s.T(), s.DB.Model(&sentBatch).UpdateColumn("created_at", time.Now().Add(-2*time.Hour)).Error)
+	require.NoError(s.T(), batchRepository.Create(&model.Transaction{TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(),
+		Value: "0.5", PreviousBalance: "0", AvailableBalance: "0", AssetSymbol: "LTC", Network: "LTC", BatchID: sentBatch.ID, TransactionStatus: model.TransactionStatus.PENDING}))
+	closedAt := time.Now().Add(-time.Minute)
+
+	stubServices := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/token":
+			_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+		case "/locks/acquire":
+			request := dto.LockerServiceRequest{}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			// the processing run closes and sends the batch while the batcher waits for its lock
+			if request.Identifier == s.Config.LockerPrefix+sentBatch.ID.String() {
+				require.NoError(s.T(), s.DB.Model(&sentBatch).Updates(map[string]interface{}{"status": model.BatchStatus.COMPLETED,
+					"closed_at": closedAt, "close_reason": model.BatchCloseReason.SCHEDULED}).Error)
+			}
+			_ = json.NewEncoder(w).Encode(dto.LockerServiceResponse{Token: "lock-token"})
+		case "/locks/release":
+			_ = json.NewEncoder(w).Encode(dto.ServicesRequestSuccess{Success: true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer stubServices.Close()
+	config := s.Config
+	config.AuthenticationService, config.LockerService = stubServices.URL, stubServices.URL
+	cache := utility.InitializeCache(cacheDuration, purgeInterval)
+
+	tasks.CloseDueBatches(cache, s.Logger, config, batchRepository)
+
+	batch := model.BatchRequest{}
+	require.NoError(s.T(), batchRepository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: sentBatch.ID}}, &batch))
+	assert.Equal(s.T(), model.BatchStatus.COMPLETED, batch.Status, "Expected the batcher to leave a batch that was sent before its lock was taken")
+	assert.Equal(s.T(), model.BatchCloseReason.SCHEDULED, batch.CloseReason)
+	require.NotNil(s.T(), batch.ClosedAt)
+	assert.WithinDuration(s.T(), closedAt, *batch.ClosedAt, time.Second)
+
+	batchService := services.BatchService{BaseService: services.BaseService{Config: config, Cache: cache, Logger: s.Logger}}
+	err := batchService.CloseBatch(&batchRepository, &batch, model.BatchCloseReason.MAX_AGE, "")
+	require.Error(s.T(), err)
+	assert.Equal(s.T(), errorcode.BATCH_NOT_WAITING, err.Error())
+	require.NoError(s.T(), batchRepository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: sentBatch.ID}}, &batch))
+	assert.Equal(s.T(), model.BatchStatus.COMPLETED, batch.Status, "Expected closing a batch that is no longer waiting to leave it as it is")
+}
@@ -0,0 +1,152 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"wallet-adapter/controllers"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	validation "gopkg.in/go-playground/validator.v9"
+)
+
+func (s *Suite) Test_ColdWalletTakesCappedShareOfSurplus() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+
+	require.NoError(s.T(), baseRepository.Create(&model.ColdWallet{AssetSymbol: "BTC", Network: "BTC", Address: "bc1unverified", Label: "Unverified vault",
+		SurplusPercent: 50, CreatedBy: "treasury"}))
+	_, hasColdWallet, err := tasks.GetSurplusColdWallet(baseRepository, "BTC", "BTC")
+	require.NoError(s.T(), err)
+	assert.False(s.T(), hasColdWallet, "Expected an unverified cold wallet to receive nothing")
+
+	require.NoError(s.T(), baseRepository.Create(&model.ColdWallet{AssetSymbol: "BTC", Network: "BTC", Address: "bc1vault", Label: "Vault",
+		IsVerified: true, VerifiedBy: "security", SurplusPercent: 50, MaxTransferAmount: 2, RequiresApproval: true, ApprovalThreshold: 1, CreatedBy: "treasury"}))
+	coldWallet, hasColdWallet, err := tasks.GetSurplusColdWallet(baseRepository, "BTC", "BTC")
+	require.NoError(s.T(), err)
+	require.True(s.T(), hasColdWallet)
+	assert.Equal(s.T(), "bc1vault", coldWallet.Address)
+
+	// half of 3 BTC is below the 2 BTC cap
+	assert.Equal(s.T(), "150000000", tasks.GetColdShare(coldWallet, big.NewInt(300000000), 8).String())
+	// half of 10 BTC is capped at 2 BTC
+	assert.Equal(s.T(), "200000000", tasks.GetColdShare(coldWallet, big.NewInt(1000000000), 8).String())
+
+	assert.False(s.T(), tasks.ColdTransferNeedsApproval(coldWallet, big.NewFloat(0.5)))
+	assert.True(s.T(), tasks.ColdTransferNeedsApproval(coldWallet, big.NewFloat(1.5)))
+	coldWallet.RequiresApproval = false
+	assert.False(s.T(), tasks.ColdTransferNeedsApproval(coldWallet, big.NewFloat(1.5)))
+}
+
+func (s *Suite) Test_ColdTransferApprovalIsClaimedOnceAndRechecked() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	userAssetRepository := database.UserAssetRepository{BaseRepository: baseRepository}
+	require.NoError(s.T(), baseRepository.Create(&model.HotWalletAsset{Address: "bc1float", AssetSymbol: "BTC", Network: "BTC"}))
+	// without float percentages the maximum float balance is nothing, the whole float balance is surplus
+	require.NoError(s.T(), baseRepository.Create(&model.FloatManagerParam{AssetSymbol: "BTC", Network: "BTC"}))
+	s.createDebitedUserAsset("0", "0")
+	coldWallet := model.ColdWallet{AssetSymbol: "BTC", Network: "BTC", Address: "bc1vault", Label: "Vault", IsVerified: true, VerifiedBy: "security",
+		SurplusPercent: 50, RequiresApproval: true, CreatedBy: "treasury"}
+	require.NoError(s.T(), baseRepository.Create(&coldWallet))
+	pendingTransfer := func(baseAmount string) model.ColdTransfer {
+		coldTransfer := model.ColdTransfer{ColdWalletID: coldWallet.ID, AssetSymbol: "BTC", Network: "BTC", FromAddress: "bc1float", ToAddress: coldWallet.Address,
+			Amount: "2", BaseAmount: baseAmount, Reference: uuid.NewV4().String(), Status: model.ColdTransferStatus.PENDING_APPROVAL}
+		require.NoError(s.T(), baseRepository.Create(&coldTransfer))
+		return coldTransfer
+	}
+
+	var mutex sync.Mutex
+	sent := map[string]string{}
+	services := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/token":
+			_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+		case "/onchain-balance":
+			_ = json.NewEncoder(w).Encode(dto.OnchainBalanceResponse{Balance: "300000000", AssetSymbol: "BTC"})
+		case "/transactions/send-single":
+			request := dto.SendSingleTransactionRequest{}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			mutex.Lock()
+			sent[request.Reference] = request.Amount.String()
+			mutex.Unlock()
+			_ = json.NewEncoder(w).Encode(dto.SendTransactionResponse{TransactionHash: "0x" + request.Reference})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer services.Close()
+
+	config := s.Config
+	config.AuthenticationService, config.CryptoAdapterService, config.TransactionSignersURL = services.URL, services.URL, services.URL
+	controller := controllers.NewUserAssetController(utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, config, validation.New(), &userAssetRepository)
+	approve := func(transferID uuid.UUID) int {
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/assets/cold-transfers/%s/approve", transferID), bytes.NewBuffer([]byte(`{"reviewedBy" : "treasury-lead"}`)))
+		request = mux.SetURLVars(request, map[string]string{"transferId": transferID.String()})
+		response := httptest.NewRecorder()
+		controller.ApproveColdTransfer(response, request)
+		return response.Code
+	}
+
+	// 2 BTC were approved, half of the 3 BTC surplus left at approval time is sent
+	shrunkTransfer := pendingTransfer("200000000")
+	assert.Equal(s.T(), http.StatusOK, approve(shrunkTransfer.ID))
+	assert.Equal(s.T(), http.StatusBadRequest, approve(shrunkTransfer.ID), "Expected a transfer to be approved once")
+	assert.Equal(s.T(), map[string]string{shrunkTransfer.Reference: "150000000"}, sent)
+	require.NoError(s.T(), baseRepository.Get(&model.ColdTransfer{BaseModel: model.BaseModel{ID: shrunkTransfer.ID}}, &shrunkTransfer))
+	assert.Equal(s.T(), model.ColdTransferStatus.SENT, shrunkTransfer.Status)
+	assert.Equal(s.T(), "150000000", shrunkTransfer.BaseAmount)
+	assert.Equal(s.T(), "treasury-lead", shrunkTransfer.ReviewedBy)
+
+	// the wallet lost its verification after the transfer was created
+	require.NoError(s.T(), s.DB.Model(&coldWallet).Update("is_verified", false).Error)
+	unverifiedTransfer := pendingTransfer("100000000")
+	assert.Equal(s.T(), http.StatusBadRequest, approve(unverifiedTransfer.ID))
+	assert.Len(s.T(), sent, 1, "Expected nothing sent to an unverified cold wallet")
+	require.NoError(s.T(), baseRepository.Get(&model.ColdTransfer{BaseModel: model.BaseModel{ID: unverifiedTransfer.ID}}, &unverifiedTransfer))
+	assert.Equal(s.T(), model.ColdTransferStatus.FAILED, unverifiedTransfer.Status)
+}
+
+func (s *Suite) Test_ColdWalletLimitsAreChangedApartFromOtherSettings() {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	coldWallet := model.ColdWallet{AssetSymbol: "ETH", Network: "ETH", Address: "0xvault", Label: "Vault", IsVerified: true, VerifiedBy: "security",
+		SurplusPercent: 50, MaxTransferAmount: 10, RequiresApproval: true, ApprovalThreshold: 5, CreatedBy: "treasury"}
+	require.NoError(s.T(), s.DB.Create(&coldWallet).Error)
+	controller := controllers.NewUserAssetController(utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, s.Config, validation.New(), &userAssetRepository)
+	update := func(handler http.HandlerFunc, path, body string) int {
+		request, _ := http.NewRequest(http.MethodPut, path, bytes.NewBuffer([]byte(body)))
+		request = mux.SetURLVars(request, map[string]string{"walletId": coldWallet.ID.String()})
+		response := httptest.NewRecorder()
+		handler(response, request)
+		return response.Code
+	}
+
+	// the limits in the request are not part of the general settings and are ignored
+	assert.Equal(s.T(), http.StatusOK, update(controller.UpdateColdWallet, "/cold-wallets/"+coldWallet.ID.String(),
+		`{"label" : "Main vault", "surplusPercent" : 60, "requiresApproval" : false, "maxTransferAmount" : 0, "updatedBy" : "treasury"}`))
+	require.NoError(s.T(), s.DB.First(&coldWallet, "id = ?", coldWallet.ID).Error)
+	assert.Equal(s.T(), "Main vault", coldWallet.Label)
+	assert.Equal(s.T(), int64(60), coldWallet.SurplusPercent)
+	assert.True(s.T(), coldWallet.RequiresApproval)
+	assert.Equal(s.T(), float64(10), coldWallet.MaxTransferAmount)
+	assert.Equal(s.T(), "treasury", coldWallet.UpdatedBy)
+
+	assert.Equal(s.T(), http.StatusBadRequest, update(controller.UpdateColdWalletLimits, "/cold-wallets/"+coldWallet.ID.String()+"/limits",
+		`{"requiresApproval" : false}`), "Expected who changed the limits to be required")
+	assert.Equal(s.T(), http.StatusOK, update(controller.UpdateColdWalletLimits, "/cold-wallets/"+coldWallet.ID.String()+"/limits",
+		`{"requiresApproval" : false, "maxTransferAmount" : 20, "approvalThreshold" : 0, "updatedBy" : "treasury-lead"}`))
+	require.NoError(s.T(), s.DB.First(&coldWallet, "id = ?", coldWallet.ID).Error)
+	assert.False(s.T(), coldWallet.RequiresApproval)
+	assert.Equal(s.T(), float64(20), coldWallet.MaxTransferAmount)
+	assert.Equal(s.T(), "treasury-lead", coldWallet.UpdatedBy)
+}
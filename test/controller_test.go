@@ -148,7 +148,7 @@ func (s *Suite) SetupTest() {
 }
 
 func (s *Suite) TearDownTest() {
-	s.DB.DropTableIfExists(&model.Denomination{}, &model.BatchRequest{}, &model.ChainTransaction{}, &model.Transaction{}, &model.UserAddress{}, &model.UserAsset{}, &model.HotWalletAsset{}, &model.TransactionQueue{}, &model.Network{})
+	s.DB.DropTableIfExists(&model.Denomination{}, &model.BatchRequest{}, &model.ChainTransaction{}, &model.Transaction{}, &model.UserAddress{}, &model.UserAsset{}, &model.HotWalletAsset{}, &model.TransactionQueue{}, &model.Network{}, &model.MaintenanceWindow{}, &model.SweepRun{}, &model.SweepItem{}, &model.FeeWallet{}, &model.GasFunding{}, &model.SweepPolicy{}, &model.ColdWallet{}, &model.ColdTransfer{}, &model.FloatManager{}, &model.FloatManagerParam{}, &model.FloatManagerParamVersion{}, &model.FundingRequest{}, &model.BatchPolicy{})
 }
 
 // RegisterRoutes ...
@@ -185,7 +185,7 @@ func (s *Suite) RegisterRoutes(logger *utility.Logger, Config config.Data, route
 
 // RunDbMigrations ... This creates corresponding tables for dtos on the db for testing
 func (s *Suite) RunMigration() {
-	s.DB.AutoMigrate(&model.Denomination{}, &model.BatchRequest{}, &model.SharedAddress{}, &model.ChainTransaction{}, &model.Transaction{}, &model.UserAddress{}, &model.UserAsset{}, &model.HotWalletAsset{}, &model.TransactionQueue{},  &model.Network{})
+	s.DB.AutoMigrate(&model.Denomination{}, &model.BatchRequest{}, &model.SharedAddress{}, &model.ChainTransaction{}, &model.Transaction{}, &model.UserAddress{}, &model.UserAsset{}, &model.HotWalletAsset{}, &model.TransactionQueue{},  &model.Network{}, &model.MaintenanceWindow{}, &model.SweepRun{}, &model.SweepItem{}, &model.FeeWallet{}, &model.GasFunding{}, &model.SweepPolicy{}, &model.ColdWallet{}, &model.ColdTransfer{}, &model.FloatManager{}, &model.FloatManagerParam{}, &model.FloatManagerParamVersion{}, &model.FundingRequest{}, &model.BatchPolicy{})
 }
 
 // DBSeeder .. This seeds supported assets into the database for testing
@@ -445,7 +445,12 @@ func (s *Suite) Test_ExternalTransfer() {
 	externalTransferRequest, _ := http.NewRequest("POST", test.TransferExternalEndpoint, bytes.NewBuffer(externalTransferInputData))
 	externalTransferRequest.Header.Set("x-auth-token", authToken)
 	externalTransferResponse := httptest.NewRecorder()
-	s.Router.ServeHTTP(externalTransferResponse, externalTransferRequest)
+	// BNB is batchable, the withdrawal joins its waiting batch under the batch locks
+	lockerConfig, closeLocker := s.stubLockerConfig()
+	defer closeLocker()
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	controllers.NewUserAssetController(utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, lockerConfig, validation.New(), &userAssetRepository).
+		ExternalTransfer(externalTransferResponse, externalTransferRequest)
 	if externalTransferResponse.Code != http.StatusOK {
 		require.NoError(s.T(), errors.New(fmt.Sprintf("Expected external transfer asset to not error >> %+v", externalTransferResponse)))
 	}
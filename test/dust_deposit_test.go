@@ -0,0 +1,69 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_OnchainDepositBelowMinimumIsNotCredited() {
+	createAssetInputData := []byte(`{"assets" : ["BTC"],"userId" : "a10fce7b-7844-43af-9ed1-e130723a1ea3"}`)
+	createAssetRequest, _ := http.NewRequest("POST", test.CreateAssetEndpoint, bytes.NewBuffer(createAssetInputData))
+	createAssetRequest.Header.Set("x-auth-token", authToken)
+	createResponse := httptest.NewRecorder()
+	s.Router.ServeHTTP(createResponse, createAssetRequest)
+	resBody, err := ioutil.ReadAll(createResponse.Body)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	createAssetResponse := dto.UserAssetResponse{}
+	err = json.Unmarshal(resBody, &createAssetResponse)
+	if createResponse.Code != http.StatusCreated || len(createAssetResponse.Assets) < 1 {
+		require.NoError(s.T(), errors.New("Expected asset creation to not error"))
+	}
+
+	if err := s.DB.Model(&model.Network{}).Where("asset_symbol = ? AND network = ?", "BTC", "BTC").Updates(map[string]interface{}{"minimum_deposit": 0.001, "dust_policy": model.DustPolicy.BUCKET}).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+
+	onchainCreditAssetInputData := []byte(fmt.Sprintf(`{"assetId" : "%s","value" : 0.0002,"transactionReference" : "ra29bv7y111p945e17600","memo" :"Test dust deposit","chainData": {"status": true,"transactionHash": "dusthash","transactionFee": "string","blockHeight": 0, "recipientAddress": "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "network": "BTC"}}`, createAssetResponse.Assets[0].ID))
+	onchainCreditAssetRequest, _ := http.NewRequest("POST", test.OnchainDepositEndpoint, bytes.NewBuffer(onchainCreditAssetInputData))
+	onchainCreditAssetRequest.Header.Set("x-auth-token", authToken)
+	onchainCreditAssetResponse := httptest.NewRecorder()
+	s.Router.ServeHTTP(onchainCreditAssetResponse, onchainCreditAssetRequest)
+
+	receipt := dto.TransactionReceipt{}
+	resBody, _ = ioutil.ReadAll(onchainCreditAssetResponse.Body)
+	_ = json.Unmarshal(resBody, &receipt)
+	assert.Equal(s.T(), http.StatusOK, onchainCreditAssetResponse.Code, "Expected dust deposit to be recorded")
+	assert.Equal(s.T(), model.TransactionStatus.DUST, receipt.TransactionStatus, "Expected dust deposit status")
+
+	userAsset := model.UserAsset{}
+	if err := s.DB.Where("id = ?", createAssetResponse.Assets[0].ID).First(&userAsset).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), "0", userAsset.AvailableBalance, "Expected available balance to be unchanged")
+	assert.Equal(s.T(), "0.0002", userAsset.DustBalance, "Expected dust bucket to be credited")
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	dustReport := []dto.DustDepositSummary{}
+	if err := userAssetRepository.FetchDustDepositSummary("BTC", "", &dustReport); err != nil {
+		require.NoError(s.T(), err)
+	}
+	if len(dustReport) != 1 {
+		require.NoError(s.T(), fmt.Errorf("Expected one dust report entry, got %d", len(dustReport)))
+	}
+	assert.Equal(s.T(), "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", dustReport[0].Address)
+	assert.Equal(s.T(), int64(1), dustReport[0].DepositCount)
+	assert.Equal(s.T(), 0.0002, dustReport[0].TotalValue)
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"math/big"
+	"time"
+	"wallet-adapter/database"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_FloatForecastCoversPeakOutflowAndQueuedWithdrawals() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	floatAccount := model.HotWalletAsset{AssetSymbol: "DOGE", Network: "DOGE"}
+	// a Monday, the runs before it were on the weekend
+	now := time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC)
+
+	runs := []struct {
+		hoursAgo      int
+		withdrawalSum float64
+	}{{48, 0}, {36, 120}, {24, 240}, {12, 120}}
+	for _, run := range runs {
+		require.NoError(s.T(), baseRepository.Create(&model.FloatManager{BaseModel: model.BaseModel{CreatedAt: now.Add(-time.Duration(run.hoursAgo) * time.Hour)},
+			AssetSymbol: "DOGE", Network: "DOGE", WithdrawalSum: run.withdrawalSum}))
+	}
+	queued := []struct {
+		value  int64
+		status string
+	}{{100, model.TransactionStatus.PENDING}, {50, model.TransactionStatus.PENDING}, {999, model.TransactionStatus.COMPLETED}}
+	for _, queuedWithdrawal := range queued {
+		require.NoError(s.T(), baseRepository.Create(&model.TransactionQueue{Recipient: "doge-recipient", Value: decimal.NewFromInt(queuedWithdrawal.value), AssetSymbol: "DOGE",
+			Network: "DOGE", DebitReference: uuid.NewV4().String(), TransactionId: uuid.NewV4(), TransactionStatus: queuedWithdrawal.status}))
+	}
+
+	// hourly rates are 10, 20 and 10 over the weekend and 30 in the run in progress
+	forecast, err := tasks.ForecastFloatDemand(s.Config, baseRepository, floatAccount, now, big.NewFloat(360))
+	require.NoError(s.T(), err)
+	assert.True(s.T(), forecast.HasHistory)
+	assert.Equal(s.T(), float64(30), forecast.HourlyOutflow, "Expected the p95 hourly outflow")
+	assert.InDelta(s.T(), 30/17.5, forecast.Seasonality, 0.0001, "Expected Mondays to weigh as the Monday rate over the mean rate")
+	assert.Equal(s.T(), float64(150), forecast.PendingWithdrawals, "Expected only pending queued withdrawals")
+	assert.InDelta(s.T(), 30*24*30/17.5+150, forecast.Target, 0.0001)
+
+	forecast, err = tasks.ForecastFloatDemand(s.Config, baseRepository, model.HotWalletAsset{AssetSymbol: "DOGE", Network: "BEP20"}, now, big.NewFloat(0))
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), float64(0), forecast.Target, "Expected no demand without float history")
+	assert.Equal(s.T(), float64(1), forecast.Seasonality)
+	assert.False(s.T(), forecast.HasHistory)
+}
+
+func (s *Suite) Test_FloatForecastWithoutHistoryKeepsTheStaticRange() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	now := time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC)
+	// a single run has no earlier run to measure its withdrawals against
+	require.NoError(s.T(), baseRepository.Create(&model.FloatManager{BaseModel: model.BaseModel{CreatedAt: now.Add(-12 * time.Hour)}, AssetSymbol: "DOGE", Network: "DOGE", WithdrawalSum: 120}))
+	require.NoError(s.T(), baseRepository.Create(&model.TransactionQueue{Recipient: "doge-recipient", Value: decimal.NewFromInt(100), AssetSymbol: "DOGE",
+		Network: "DOGE", DebitReference: uuid.NewV4().String(), TransactionId: uuid.NewV4(), TransactionStatus: model.TransactionStatus.PENDING}))
+
+	forecast, err := tasks.ForecastFloatDemand(s.Config, baseRepository, model.HotWalletAsset{AssetSymbol: "DOGE", Network: "DOGE"}, now, nil)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), forecast.HasHistory, "Expected no outflow samples from a single run")
+	assert.Equal(s.T(), float64(100), forecast.Target, "Expected the target to only cover the queued withdrawals")
+
+	minimum, maximum := tasks.ApplyFloatForecast(forecast, big.NewFloat(500), big.NewFloat(800))
+	assert.Equal(s.T(), 0, minimum.Cmp(big.NewFloat(500)), "Expected the static minimum without float history, got %v", minimum)
+	assert.Equal(s.T(), 0, maximum.Cmp(big.NewFloat(800)), "Expected the static maximum without float history, got %v", maximum)
+
+	forecast.HasHistory, forecast.Target = true, 1000
+	minimum, maximum = tasks.ApplyFloatForecast(forecast, big.NewFloat(500), big.NewFloat(800))
+	assert.Equal(s.T(), 0, minimum.Cmp(big.NewFloat(1000)), "Expected the forecast target as the minimum, got %v", minimum)
+	assert.Equal(s.T(), 0, maximum.Cmp(big.NewFloat(1000)), "Expected the maximum raised to the forecast target, got %v", maximum)
+}
@@ -0,0 +1,56 @@
+package test
+
+import (
+	"wallet-adapter/database"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_FloatParamsAreVersionedWithAssetFallback() {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	floatParam := model.FloatManagerParam{AssetSymbol: "ETH", MinPercentMaxUserBalance: 0.1, MaxPercentMaxUserBalance: 0.3,
+		MinPercentTotalUserBalance: 0.1, AveragePercentTotalUserBalance: 0.2, MaxPercentTotalUserBalance: 0.3,
+		PercentMinimumTriggerLevel: 0.8, PercentMaximumTriggerLevel: 0.3, UpdatedBy: "treasury"}
+
+	invalid := floatParam
+	invalid.AveragePercentTotalUserBalance = 0.35
+	assert.EqualError(s.T(), services.ValidateFloatParams(invalid), errorcode.FLOAT_PARAMS_RANGE_INVALID)
+	seeded := floatParam
+	seeded.AveragePercentTotalUserBalance, seeded.MaxPercentTotalUserBalance = 0.2, 0.2
+	assert.NoError(s.T(), services.ValidateFloatParams(seeded), "Expected the seeded parameters, averaging at the maximum, to be valid")
+	invalid = floatParam
+	invalid.PercentMaximumTriggerLevel = 1.5
+	assert.EqualError(s.T(), services.ValidateFloatParams(invalid), errorcode.FLOAT_PARAMS_TRIGGER_INVALID)
+	require.NoError(s.T(), services.ValidateFloatParams(floatParam))
+
+	require.NoError(s.T(), services.SaveFloatParams(&userAssetRepository, &floatParam))
+	floatParam.IsPaused = true
+	require.NoError(s.T(), services.SaveFloatParams(&userAssetRepository, &floatParam))
+	assert.Equal(s.T(), int64(2), floatParam.Version)
+
+	versions := []model.FloatManagerParamVersion{}
+	require.NoError(s.T(), userAssetRepository.FetchFloatParamVersions(floatParam.ID, &versions))
+	require.Len(s.T(), versions, 2)
+	assert.True(s.T(), versions[0].IsPaused, "Expected the latest version first")
+	assert.False(s.T(), versions[1].IsPaused)
+
+	// networks without their own parameters use the parameters of the asset
+	effective, err := services.GetFloatParams(&userAssetRepository, "ETH", "ERC20")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), floatParam.ID, effective.ID)
+	assert.True(s.T(), effective.IsPaused)
+
+	override := floatParam
+	override.ID, override.Network, override.Version, override.IsPaused = uuid.Nil, "ERC20", 0, false
+	require.NoError(s.T(), services.SaveFloatParams(&userAssetRepository, &override))
+	effective, err = services.GetFloatParams(&userAssetRepository, "ETH", "ERC20")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), override.ID, effective.ID)
+	assert.False(s.T(), effective.IsPaused, "Expected the network parameters to override the asset parameters")
+}
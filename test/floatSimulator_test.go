@@ -0,0 +1,70 @@
+package test
+
+import (
+	"time"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_FloatSimulationReplaysHistoryPerParamSet() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	start := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	btcInSatoshis := 100000000.0
+
+	// four runs an hour apart on 100 BTC of user balances, the float starts at 20 BTC
+	for hour := 0; hour < 4; hour++ {
+		require.NoError(s.T(), baseRepository.Create(&model.FloatManager{BaseModel: model.BaseModel{CreatedAt: start.Add(time.Duration(hour) * time.Hour)},
+			AssetSymbol: "BTC", Network: "BTC", TotalUserBalance: 100 * btcInSatoshis, FloatOnChainBalance: 20 * btcInSatoshis}))
+	}
+	movements := []struct {
+		tag   string
+		value string
+		after time.Duration
+	}{
+		{model.TransactionTag.WITHDRAW, "25", 30 * time.Minute},
+		{model.TransactionTag.DEPOSIT, "2", 90 * time.Minute},
+		{model.TransactionTag.DEPOSIT, "5", 150 * time.Minute},
+	}
+	for _, movement := range movements {
+		require.NoError(s.T(), baseRepository.Create(&model.Transaction{BaseModel: model.BaseModel{CreatedAt: start.Add(movement.after)},
+			TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(), TransactionTag: movement.tag,
+			Value: movement.value, PreviousBalance: "0", AvailableBalance: "0", AssetSymbol: "BTC", Network: "BTC"}))
+	}
+
+	report, err := tasks.SimulateFloat(s.Logger, baseRepository, dto.FloatSimulationRequest{AssetSymbol: "BTC", Network: "BTC", From: start, To: start.Add(24 * time.Hour),
+		ParamSets: []dto.FloatSimulationParams{
+			{Label: "lean", MinPercentTotalUserBalance: 0.125, AveragePercentTotalUserBalance: 0.25, MaxPercentTotalUserBalance: 0.375,
+				PercentMinimumTriggerLevel: 1, PercentMaximumTriggerLevel: 0.125},
+			{Label: "generous", MinPercentTotalUserBalance: 0.25, AveragePercentTotalUserBalance: 0.5, MaxPercentTotalUserBalance: 0.5,
+				PercentMinimumTriggerLevel: 1, PercentMaximumTriggerLevel: 0.125},
+		}})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 4, report.Runs)
+	assert.Equal(s.T(), 2, report.DepositCount)
+	assert.Equal(s.T(), 1, report.WithdrawalCount)
+	require.Len(s.T(), report.Results, 2)
+
+	lean := report.Results[0]
+	assert.Equal(s.T(), 1, lean.DryRuns, "Expected the 25 BTC withdrawal to drain the lean float")
+	assert.Equal(s.T(), float64(5), lean.Shortfall)
+	assert.Equal(s.T(), 1, lean.TopUpEmails)
+	assert.Equal(s.T(), 37.5, lean.TopUpAmount, "Expected the float to be raised to its maximum after withdrawals outpaced deposits")
+	assert.Equal(s.T(), 1, lean.SurplusTransfers)
+	assert.Equal(s.T(), float64(7), lean.SurplusTransferAmount)
+	assert.Equal(s.T(), float64(2), lean.MaxIdleSurplus, "Expected a surplus below the trigger level to sit idle")
+	assert.Equal(s.T(), 0.5, lean.AverageIdleSurplus)
+	assert.Equal(s.T(), 37.5, lean.EndingBalance)
+
+	generous := report.Results[1]
+	assert.Equal(s.T(), 0, generous.DryRuns)
+	assert.Equal(s.T(), 2, generous.TopUpEmails)
+
+	_, err = tasks.SimulateFloat(s.Logger, baseRepository, dto.FloatSimulationRequest{AssetSymbol: "BTC", Network: "BTC", From: start.AddDate(-1, 0, 0), To: start.AddDate(0, 0, -1)})
+	assert.Error(s.T(), err, "Expected a period without float manager runs to be rejected")
+}
@@ -0,0 +1,125 @@
+package test
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+	"wallet-adapter/config"
+	"wallet-adapter/controllers"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/errorcode"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	validation "gopkg.in/go-playground/validator.v9"
+
+	"github.com/gorilla/mux"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_FundingRequestLifecycle() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	opened := time.Date(2021, 9, 20, 10, 0, 0, 0, time.UTC)
+	cfg := config.Data{FundingRequestEscalationEmails: "treasury@bundle.africa", FundingRequestEscalationMinutes: 30}
+
+	// 1 BTC requested while the float held 0.2 BTC
+	fundingRequest := model.FundingRequest{BaseModel: model.BaseModel{CreatedAt: opened}, AssetSymbol: "BTC", Network: "BTC", FloatAddress: "bc1float",
+		Amount: "1", BaseAmount: "100000000", FloatBalance: "20000000", Status: model.FundingRequestStatus.OPEN, AckToken: "ack-token", ExpiresAt: opened.Add(24 * time.Hour)}
+
+	status, escalate := tasks.ReviewFundingRequest(cfg, fundingRequest, big.NewInt(60000000), opened.Add(10*time.Minute))
+	assert.Equal(s.T(), model.FundingRequestStatus.OPEN, status, "Expected a partial funding to leave the request open")
+	assert.False(s.T(), escalate, "Expected no escalation before the timeout")
+
+	status, escalate = tasks.ReviewFundingRequest(cfg, fundingRequest, nil, opened.Add(30*time.Minute))
+	assert.Equal(s.T(), model.FundingRequestStatus.OPEN, status)
+	assert.True(s.T(), escalate, "Expected an unacknowledged request to escalate after the timeout")
+	_, escalate = tasks.ReviewFundingRequest(config.Data{FundingRequestEscalationMinutes: 30}, fundingRequest, nil, opened.Add(30*time.Minute))
+	assert.False(s.T(), escalate, "Expected no escalation without escalation contacts")
+
+	status, _ = tasks.ReviewFundingRequest(cfg, fundingRequest, big.NewInt(120000000), opened.Add(25*time.Hour))
+	assert.Equal(s.T(), model.FundingRequestStatus.FULFILLED, status, "Expected funding of the requested amount to fulfil the request, even late")
+	status, _ = tasks.ReviewFundingRequest(cfg, fundingRequest, big.NewInt(60000000), opened.Add(25*time.Hour))
+	assert.Equal(s.T(), model.FundingRequestStatus.EXPIRED, status)
+
+	require.NoError(s.T(), baseRepository.Create(&fundingRequest))
+	acknowledged, err := tasks.AcknowledgeFundingRequest(baseRepository, "ack-token", "cold@bundle.africa")
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), model.FundingRequestStatus.ACKNOWLEDGED, acknowledged.Status)
+	assert.Equal(s.T(), "cold@bundle.africa", acknowledged.AcknowledgedBy)
+
+	_, escalate = tasks.ReviewFundingRequest(cfg, acknowledged, nil, opened.Add(time.Hour))
+	assert.False(s.T(), escalate, "Expected an acknowledged request not to escalate")
+
+	_, err = tasks.AcknowledgeFundingRequest(baseRepository, "", "cold@bundle.africa")
+	assert.Error(s.T(), err, "Expected an empty token to match no request")
+
+	require.NoError(s.T(), baseRepository.Update(&model.FundingRequest{BaseModel: model.BaseModel{ID: fundingRequest.ID}}, map[string]interface{}{"status": model.FundingRequestStatus.FULFILLED}))
+	_, err = tasks.AcknowledgeFundingRequest(baseRepository, "ack-token", "cold@bundle.africa")
+	require.Error(s.T(), err)
+	assert.Equal(s.T(), errorcode.FUNDING_REQUEST_CLOSED, err.Error())
+}
+
+func (s *Suite) Test_FundingRequestIsFundedOnlyByDepositsFromFundingSources() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	opened := time.Date(2021, 9, 21, 10, 0, 0, 0, time.UTC)
+	require.NoError(s.T(), baseRepository.Create(&model.ColdWallet{AssetSymbol: "LINK", Network: "ERC20", Address: "0xVault", Label: "Vault", IsVerified: true, CreatedBy: "treasury"}))
+
+	sources, err := tasks.GetFundingSources(config.Data{TreasuryAddresses: " 0xtreasury , "}, baseRepository, "LINK", "ERC20")
+	require.NoError(s.T(), err)
+	assert.ElementsMatch(s.T(), []string{"0xVault", "0xtreasury"}, sources)
+
+	fundingRequest := model.FundingRequest{BaseModel: model.BaseModel{CreatedAt: opened}, AssetSymbol: "LINK", Network: "ERC20", FloatAddress: "0xfloat",
+		Amount: "1", BaseAmount: "1000000000000000000", FloatBalance: "0", Status: model.FundingRequestStatus.OPEN, ExpiresAt: opened.Add(24 * time.Hour)}
+	deposits := []dto.AddressDeposit{
+		{TransactionHash: "0xbefore", SenderAddress: "0xvault", Value: "1000000000000000000", CreatedAt: opened.Add(-time.Minute)},
+		{TransactionHash: "0xsweep", SenderAddress: "0xuserdeposit", Value: "1000000000000000000", CreatedAt: opened.Add(time.Minute)},
+		{TransactionHash: "0xcold", SenderAddress: "0xvault", Value: "400000000000000000", CreatedAt: opened.Add(2 * time.Minute)},
+	}
+
+	fundedAmount, hashes := tasks.MatchFundingDeposits(fundingRequest, deposits, sources)
+	assert.Equal(s.T(), "400000000000000000", fundedAmount.String(), "Expected only deposits from funding sources since the request opened to count")
+	assert.Equal(s.T(), []string{"0xcold"}, hashes)
+	status, _ := tasks.ReviewFundingRequest(config.Data{}, fundingRequest, fundedAmount, opened.Add(time.Hour))
+	assert.Equal(s.T(), model.FundingRequestStatus.OPEN, status, "Expected a sweep into the float not to fulfil the request")
+
+	deposits = append(deposits, dto.AddressDeposit{TransactionHash: "0xtreasurytx", SenderAddress: "0xTreasury", Value: "600000000000000000", CreatedAt: opened.Add(3 * time.Minute)})
+	fundedAmount, hashes = tasks.MatchFundingDeposits(fundingRequest, deposits, sources)
+	assert.Equal(s.T(), []string{"0xcold", "0xtreasurytx"}, hashes)
+	status, _ = tasks.ReviewFundingRequest(config.Data{}, fundingRequest, fundedAmount, opened.Add(time.Hour))
+	assert.Equal(s.T(), model.FundingRequestStatus.FULFILLED, status)
+}
+
+func (s *Suite) Test_FundingRequestLinkOnlyAcknowledgesOnPost() {
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	fundingRequest := model.FundingRequest{AssetSymbol: "BTC", Network: "BTC", FloatAddress: "bc1float", Amount: "1", BaseAmount: "100000000", FloatBalance: "0",
+		Status: model.FundingRequestStatus.OPEN, AckToken: "link-token", ExpiresAt: time.Now().Add(24 * time.Hour)}
+	require.NoError(s.T(), userAssetRepository.Create(&fundingRequest))
+	controller := controllers.NewUserAssetController(utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, s.Config, validation.New(), &userAssetRepository)
+
+	request, _ := http.NewRequest(http.MethodGet, "/funding-requests/acknowledge/link-token?by=cold@bundle.africa", nil)
+	request = mux.SetURLVars(request, map[string]string{"token": "link-token"})
+	response := httptest.NewRecorder()
+	controller.ShowFundingRequestAcknowledgement(response, request)
+	assert.Equal(s.T(), http.StatusOK, response.Code)
+	assert.Contains(s.T(), response.Body.String(), `<form method="post">`)
+	assert.Contains(s.T(), response.Body.String(), `value="cold@bundle.africa"`)
+	require.NoError(s.T(), s.DB.First(&fundingRequest, "id = ?", fundingRequest.ID).Error)
+	assert.Equal(s.T(), model.FundingRequestStatus.OPEN, fundingRequest.Status, "Expected following the link not to acknowledge the request")
+
+	request, _ = http.NewRequest(http.MethodPost, "/funding-requests/acknowledge/link-token", strings.NewReader(url.Values{"by": {"cold@bundle.africa"}}.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request = mux.SetURLVars(request, map[string]string{"token": "link-token"})
+	response = httptest.NewRecorder()
+	controller.AcknowledgeFundingRequest(response, request)
+	assert.Equal(s.T(), http.StatusOK, response.Code)
+	require.NoError(s.T(), s.DB.First(&fundingRequest, "id = ?", fundingRequest.ID).Error)
+	assert.Equal(s.T(), model.FundingRequestStatus.ACKNOWLEDGED, fundingRequest.Status)
+	assert.Equal(s.T(), "cold@bundle.africa", fundingRequest.AcknowledgedBy)
+}
@@ -0,0 +1,151 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_GasFundingIsSentOnceAndSpentAfterSweep() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	userAssetRepository := database.UserAssetRepository{BaseRepository: baseRepository}
+
+	isToken, isNative := true, false
+	assert.True(s.T(), tasks.RequiresSweepGas(model.Network{AssetSymbol: "USDT", Network: "TRC20", IsToken: &isToken}), "Expected tokens to need gas")
+	assert.False(s.T(), tasks.RequiresSweepGas(model.Network{AssetSymbol: "ETH", Network: "ERC20", IsToken: &isNative}), "Expected native coins to pay their own fee")
+
+	funding := model.GasFunding{Address: "0xdeposit", Network: "ERC20", NativeAsset: "ETH", AssetSymbol: "USDT", FeeWalletAddress: "0xfeewallet",
+		Amount: 420000, Reference: "GAS-0xdeposit-1", Status: model.GasFundingStatus.PENDING}
+	require.NoError(s.T(), baseRepository.Create(&funding))
+
+	pendingFunding, isPending, err := tasks.PendingGasFunding(baseRepository, "0xdeposit", "ERC20")
+	require.NoError(s.T(), err)
+	require.True(s.T(), isPending, "Expected the unconfirmed funding to hold back another one")
+	assert.Equal(s.T(), "GAS-0xdeposit-1", pendingFunding.Reference)
+
+	_, isPending, err = tasks.PendingGasFunding(baseRepository, "0xdeposit", "BEP20")
+	require.NoError(s.T(), err)
+	assert.False(s.T(), isPending, "Expected fundings to be tracked per network")
+
+	confirmed, err := tasks.ApplyGasFundingStatus(baseRepository, &pendingFunding, "BROADCASTED")
+	require.NoError(s.T(), err)
+	assert.False(s.T(), confirmed, "Expected a broadcast funding to stay pending")
+	confirmed, err = tasks.ApplyGasFundingStatus(baseRepository, &pendingFunding, utility.SUCCESSFUL)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), confirmed)
+
+	_, isPending, err = tasks.PendingGasFunding(baseRepository, "0xdeposit", "ERC20")
+	require.NoError(s.T(), err)
+	assert.False(s.T(), isPending)
+
+	require.NoError(s.T(), tasks.MarkSweepGasSpent(baseRepository, "0xdeposit", "ERC20", "0xdeposit-1", s.Logger))
+	fundings := []model.GasFunding{}
+	require.NoError(s.T(), userAssetRepository.FetchGasFundings("0xdeposit", "", nil, &fundings))
+	require.Equal(s.T(), 1, len(fundings))
+	assert.Equal(s.T(), model.GasFundingStatus.SPENT, fundings[0].Status, "Expected the leftover to be recovered on the next run")
+	assert.NotNil(s.T(), fundings[0].ConfirmedAt)
+	assert.Equal(s.T(), "0xdeposit-1", fundings[0].SweepReference)
+}
+
+func (s *Suite) Test_GasDustRecoveryIsCappedAtTheFundingLeft() {
+	assert.Equal(s.T(), int64(300), tasks.GetRecoverableGasDust(1000, 600, 100))
+	assert.Equal(s.T(), int64(0), tasks.GetRecoverableGasDust(1000, 950, 100), "Expected dust below the recovery fee to stay")
+	assert.Equal(s.T(), int64(0), tasks.GetRecoverableGasDust(1000, 1000, 0), "Expected nothing to be recovered from a funding spent in full")
+}
+
+func (s *Suite) Test_UnsweptNativeDepositsAreCounted() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	userAssetRepository := database.UserAssetRepository{BaseRepository: baseRepository}
+
+	chainTransaction := model.ChainTransaction{TransactionHash: "0xnativedeposit", RecipientAddress: "0xdustaddress", AssetSymbol: "ETH", Network: "ERC20"}
+	require.NoError(s.T(), baseRepository.Create(&chainTransaction))
+	deposit := model.Transaction{TransactionReference: "dust-deposit", PaymentReference: "dust-deposit", Value: "0.1", PreviousBalance: "0", AvailableBalance: "0",
+		AssetSymbol: "ETH", Network: "ERC20", TransactionTag: "DEPOSIT", TransactionStatus: "PENDING", OnChainTxId: chainTransaction.ID}
+	require.NoError(s.T(), baseRepository.Create(&deposit))
+
+	count := 0
+	require.NoError(s.T(), userAssetRepository.CountUnsweptDeposits("0xdustaddress", "ETH", &count))
+	assert.Equal(s.T(), 1, count, "Expected an unconfirmed native deposit to hold back the dust recovery")
+	require.NoError(s.T(), userAssetRepository.CountUnsweptDeposits("0xdustaddress", "USDT", &count))
+	assert.Equal(s.T(), 0, count)
+}
+
+func (s *Suite) Test_SweepGasIsRecordedBeforeSendingAndSentOnce() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	isToken := true
+	network := model.Network{AssetSymbol: "GUSD", Network: "GASNET", NativeAsset: "GAS", IsToken: &isToken}
+	require.NoError(s.T(), baseRepository.Create(&model.HotWalletAsset{AssetSymbol: "GAS", Network: "GASNET", Address: "0xgashotwallet"}))
+
+	nativeBalance, transactionStatus, sendFails := "0", "", true
+	sent := []dto.SendSingleTransactionRequest{}
+	stubServices := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/token":
+			_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+		case "/onchain-balance":
+			_ = json.NewEncoder(w).Encode(dto.OnchainBalanceResponse{Balance: nativeBalance, AssetSymbol: "GAS"})
+		case "/transaction-status":
+			if transactionStatus == "" {
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(dto.ServicesRequestErr{Message: "transaction not found"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(dto.TransactionStatusResponse{Status: transactionStatus})
+		case "/transactions/send-single":
+			request := dto.SendSingleTransactionRequest{}
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			// the funding is on record by the time it is sent
+			pendingFunding, isPending, err := tasks.PendingGasFunding(baseRepository, "0xgasdeposit", "GASNET")
+			assert.NoError(s.T(), err)
+			assert.True(s.T(), isPending, "Expected the funding to be recorded before it is sent")
+			assert.Equal(s.T(), request.Reference, pendingFunding.Reference)
+			sent = append(sent, request)
+			if sendFails {
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).Encode(dto.ServicesRequestErr{Message: "signer unavailable"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(dto.SendTransactionResponse{TransactionHash: "0xfundinghash"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer stubServices.Close()
+	stubConfig := s.Config
+	stubConfig.AuthenticationService, stubConfig.CryptoAdapterService, stubConfig.TransactionSignersURL = stubServices.URL, stubServices.URL, stubServices.URL
+	cache := utility.InitializeCache(cacheDuration, purgeInterval)
+	ensureSweepGas := func() bool {
+		isFunded, _ := tasks.EnsureSweepGas(cache, s.Logger, stubConfig, baseRepository, network, "0xgasdeposit", 500)
+		return isFunded
+	}
+
+	assert.False(s.T(), ensureSweepGas())
+	require.Len(s.T(), sent, 1)
+	assert.Equal(s.T(), "0xgashotwallet", sent[0].FromAddress, "Expected a network without a fee wallet to be funded from the native hot wallet")
+	assert.Equal(s.T(), "500", sent[0].Amount.String())
+
+	// a funding the signer never took is failed and sent again on the next run
+	assert.False(s.T(), ensureSweepGas())
+	assert.Len(s.T(), sent, 1, "Expected the unsent funding to be failed first")
+	sendFails = false
+	assert.False(s.T(), ensureSweepGas())
+	require.Len(s.T(), sent, 2)
+
+	// a broadcast funding is waited for and not sent again
+	transactionStatus = "PENDING"
+	assert.False(s.T(), ensureSweepGas())
+	assert.False(s.T(), ensureSweepGas())
+	assert.Len(s.T(), sent, 2, "Expected an unconfirmed funding not to be sent again")
+
+	transactionStatus, nativeBalance = utility.SUCCESSFUL, "500"
+	assert.True(s.T(), ensureSweepGas(), "Expected the address to be swept once its funding is confirmed")
+	assert.Len(s.T(), sent, 2)
+}
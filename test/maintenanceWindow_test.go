@@ -0,0 +1,53 @@
+package test
+
+import (
+	"time"
+	"wallet-adapter/database"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/tasks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_MaintenanceWindowPausesWithdrawalsAndSweeps() {
+	defer s.DB.Delete(model.MaintenanceWindow{})
+
+	now := time.Now()
+	windows := []model.MaintenanceWindow{
+		{Network: "BTC", Scope: model.MaintenanceScope.WITHDRAW, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(2 * time.Hour), Message: "node upgrade", CreatedBy: "ops@bundle.africa"},
+		{Network: "BTC", Scope: model.MaintenanceScope.SWEEP, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Message: "node upgrade", CreatedBy: "ops@bundle.africa"},
+		{Network: "BTC", Scope: model.MaintenanceScope.DEPOSIT, StartsAt: now.Add(time.Hour), EndsAt: now.Add(3 * time.Hour), Message: "chain fork", CreatedBy: "ops@bundle.africa"},
+	}
+	for index := range windows {
+		if err := s.DB.Create(&windows[index]).Error; err != nil {
+			require.NoError(s.T(), err)
+		}
+	}
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	service := services.NewService(authCache, s.Logger, s.Config)
+
+	isActive, err := service.IsWithdrawalActive("BTC", "BTC", &userAssetRepository)
+	assert.False(s.T(), isActive, "Expected withdrawals to be paused by the maintenance window")
+	if maintenanceErr, ok := err.(services.MaintenanceError); assert.True(s.T(), ok, "Expected a maintenance error") {
+		assert.True(s.T(), maintenanceErr.Window.EndsAt.Equal(windows[0].EndsAt), "Expected the error to carry the expected end of the window")
+	}
+
+	isActive, err = service.IsDepositActive("BTC", "BTC", &userAssetRepository)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), isActive, "Expected deposits to stay active until their window starts")
+
+	sweepable := tasks.RemoveTransactionsUnderMaintenance(userAssetRepository, []model.Transaction{{AssetSymbol: "BTC", Network: "BTC"}, {AssetSymbol: "ETH", Network: "ERC20"}}, s.Logger)
+	if assert.Equal(s.T(), 1, len(sweepable), "Expected deposits on the network under maintenance to be left out of the sweep") {
+		assert.Equal(s.T(), "ETH", sweepable[0].AssetSymbol)
+	}
+
+	status, err := services.GetMaintenanceStatus(&userAssetRepository)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), 2, len(status.Active))
+	assert.Equal(s.T(), 1, len(status.Upcoming))
+}
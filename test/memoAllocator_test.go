@@ -0,0 +1,108 @@
+package test
+
+import (
+	"strconv"
+	"testing"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateMemoCandidateFollowsNetworkRules(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		xrpMemo, _ := services.GenerateMemoCandidate(services.GetMemoRule(144))
+		tag, err := strconv.ParseUint(xrpMemo, 10, 32)
+		if err != nil || len(xrpMemo) != 10 || !services.ValidateMemo(144, xrpMemo) {
+			t.Fatalf("Expected a valid XRP destination tag below 2^32, got %s (%d) : %v", xrpMemo, tag, err)
+		}
+
+		xlmMemo, _ := services.GenerateMemoCandidate(services.GetMemoRule(148))
+		if len(xlmMemo) != 13 || !services.ValidateMemo(148, xlmMemo) {
+			t.Fatalf("Expected a valid 13 digit XLM id memo, got %s", xlmMemo)
+		}
+
+		bnbMemo, _ := services.GenerateMemoCandidate(services.GetMemoRule(714))
+		if len(bnbMemo) != 10 || !services.ValidateMemo(714, bnbMemo) {
+			t.Fatalf("Expected a valid 10 character BNB memo, got %s", bnbMemo)
+		}
+		for _, symbol := range bnbMemo {
+			if !(symbol >= '0' && symbol <= '9' || symbol >= 'A' && symbol <= 'Z') {
+				t.Fatalf("Expected an alphanumeric BNB memo, got %s", bnbMemo)
+			}
+		}
+	}
+}
+
+func TestValidateMemoCatchesTypos(t *testing.T) {
+	memo, _ := services.GenerateMemoCandidate(services.GetMemoRule(144))
+	for index := 0; index < len(memo); index++ {
+		for digit := byte('0'); digit <= '9'; digit++ {
+			if digit == memo[index] {
+				continue
+			}
+			typo := memo[:index] + string(digit) + memo[index+1:]
+			if services.ValidateMemo(144, typo) {
+				t.Errorf("Expected single digit typo %s of %s to fail validation", typo, memo)
+			}
+		}
+	}
+	for index := 0; index < len(memo)-1; index++ {
+		if memo[index] == memo[index+1] {
+			continue
+		}
+		transposed := memo[:index] + string(memo[index+1]) + string(memo[index]) + memo[index+2:]
+		if services.ValidateMemo(144, transposed) {
+			t.Errorf("Expected transposition %s of %s to fail validation", transposed, memo)
+		}
+	}
+}
+
+func (s *Suite) Test_AllocateMemoReusesPrimaryMemoPerNetwork() {
+	s.DB.AutoMigrate(&model.UserMemo{})
+	defer s.DB.DropTableIfExists(&model.UserMemo{})
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	userID := uuid.NewV4()
+	xrpAsset := dto.NetworkAsset{UserID: userID, AssetSymbol: "XRP", Network: "XRP", CoinType: 144, RequiresMemo: true}
+	bnbAsset := dto.NetworkAsset{UserID: userID, AssetSymbol: "BNB", Network: "BEP2", CoinType: 714, RequiresMemo: true}
+
+	primaryMemo, err := services.AllocateMemo(&userAssetRepository, xrpAsset, true)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	reusedMemo, err := services.AllocateMemo(&userAssetRepository, xrpAsset, true)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), primaryMemo, reusedMemo, "Expected the primary memo to be reused on the network")
+
+	auxiliaryMemo, err := services.AllocateMemo(&userAssetRepository, xrpAsset, false)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.NotEqual(s.T(), primaryMemo, auxiliaryMemo, "Expected auxiliary memos to be newly allocated")
+
+	bnbMemo, err := services.AllocateMemo(&userAssetRepository, bnbAsset, true)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.True(s.T(), services.ValidateMemo(714, bnbMemo), "Expected a BNB text memo on BNB")
+
+	legacyUserID := uuid.NewV4()
+	if err := s.DB.Create(&model.UserMemo{UserID: legacyUserID, Memo: "123456789", IsPrimaryAddress: true}).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	legacyMemo, err := services.AllocateMemo(&userAssetRepository, dto.NetworkAsset{UserID: legacyUserID, Network: "XLM", CoinType: 148}, true)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), "123456789", legacyMemo, "Expected a memo allocated before per-network memos to be kept")
+
+	err = s.DB.Create(&model.UserMemo{UserID: uuid.NewV4(), Memo: primaryMemo}).Error
+	assert.Error(s.T(), err, "Expected the unique constraint to reject a duplicate memo")
+}
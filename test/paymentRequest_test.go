@@ -0,0 +1,120 @@
+package test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility/qrcode"
+
+	"github.com/makiuchi-d/gozxing"
+	zxingqrcode "github.com/makiuchi-d/gozxing/qrcode"
+)
+
+func TestBuildPaymentURIFollowsNetworkStandards(t *testing.T) {
+	isToken, isNative := true, false
+	testCases := []struct {
+		name     string
+		network  model.Network
+		address  dto.AssetAddress
+		amount   string
+		label    string
+		expected string
+	}{
+		{"BIP21", model.Network{CoinType: 0, NativeAsset: "BTC", IsToken: &isNative}, dto.AssetAddress{Address: "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"}, "0.5", "Bundle deposit",
+			"bitcoin:1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH?amount=0.5&label=Bundle%20deposit"},
+		{"BIP21 cashaddr", model.Network{CoinType: 145, NativeAsset: "BCH"}, dto.AssetAddress{Address: "bitcoincash:qp63uahgrxged4z5jswyt5dn5v3lzsem6cy4spdc2h"}, "", "",
+			"bitcoincash:qp63uahgrxged4z5jswyt5dn5v3lzsem6cy4spdc2h"},
+		{"EIP-681 native", model.Network{CoinType: 60, NativeAsset: "ETH", NativeDecimals: 18, IsToken: &isNative}, dto.AssetAddress{Address: "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"}, "1.5", "",
+			"ethereum:0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf@1?value=1500000000000000000"},
+		{"EIP-681 token", model.Network{CoinType: 20000714, NativeAsset: "BNB", NativeDecimals: 18, IsToken: &isToken, ChainDenomId: "0xe9e7CEA3DedcA5984780Bafc599bD69ADd087D56"}, dto.AssetAddress{Address: "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"}, "25", "",
+			"ethereum:0xe9e7CEA3DedcA5984780Bafc599bD69ADd087D56@56/transfer?address=0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf&uint256=25000000000000000000"},
+		{"BNB memo", model.Network{CoinType: 714, NativeAsset: "BNB"}, dto.AssetAddress{Address: "bnb136ns6lfw4zs5hg4n85vdthaad7hq5m4gtkgf23", Memo: "123456789"}, "2", "",
+			"bnb:bnb136ns6lfw4zs5hg4n85vdthaad7hq5m4gtkgf23?memo=123456789&amount=2"},
+		{"XRP tag", model.Network{CoinType: 144, NativeAsset: "XRP"}, dto.AssetAddress{Address: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh", Memo: "123456789"}, "", "",
+			"ripple:rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh?dt=123456789"},
+		{"XLM memo", model.Network{CoinType: 148, NativeAsset: "XLM"}, dto.AssetAddress{Address: "GCKFBEIYTKP5RDBQMTVVALONAOPBXICILMAFRLYDUJHDIZ5AQCZLMIBQ", Memo: "123456789"}, "10", "",
+			"web+stellar:pay?destination=GCKFBEIYTKP5RDBQMTVVALONAOPBXICILMAFRLYDUJHDIZ5AQCZLMIBQ&amount=10&memo=123456789&memo_type=MEMO_ID"},
+	}
+
+	for _, testCase := range testCases {
+		uri, err := services.BuildPaymentURI(testCase.network, testCase.address, testCase.amount, testCase.label)
+		if err != nil || uri != testCase.expected {
+			t.Errorf("%s : expected %s, got %s, %v\n", testCase.name, testCase.expected, uri, err)
+		}
+	}
+
+	if _, err := services.BuildPaymentURI(model.Network{CoinType: 0}, dto.AssetAddress{Address: "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"}, "-1", ""); err == nil {
+		t.Errorf("Expected a negative amount to be rejected\n")
+	}
+}
+
+func TestPaymentRequestQRCodeRendering(t *testing.T) {
+	code, err := qrcode.Encode("bitcoin:1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH?amount=0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code.Size < 21 || (code.Size-17)%4 != 0 {
+		t.Errorf("Expected the size of a QR code symbol, got %d\n", code.Size)
+	}
+
+	image, err := code.PNG(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(image))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Bounds().Dx() != (code.Size+8)*4 {
+		t.Errorf("Expected png to include the quiet zone, got width %d\n", decoded.Bounds().Dx())
+	}
+	if svg := code.SVG(4); !strings.HasPrefix(svg, "<?xml") || !strings.Contains(svg, "<path d=\"M") {
+		t.Errorf("Expected an svg document with a module path, got %s\n", svg)
+	}
+
+	if _, err := qrcode.Encode(strings.Repeat("a", 3000)); err == nil {
+		t.Errorf("Expected oversized payload to be rejected\n")
+	}
+}
+
+func TestPaymentRequestQRCodeDecodesToThePayload(t *testing.T) {
+	payloads := []string{
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		"bitcoin:1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH?amount=0.5&label=Bundle%20deposit",
+		"ethereum:0xe9e7CEA3DedcA5984780Bafc599bD69ADd087D56@56/transfer?address=0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf&uint256=25000000000000000000",
+		"web+stellar:pay?destination=GCKFBEIYTKP5RDBQMTVVALONAOPBXICILMAFRLYDUJHDIZ5AQCZLMIBQ&amount=10&memo=123456789&memo_type=MEMO_ID",
+		// a payment URI with a long memo takes a larger symbol
+		strings.Repeat("bnb:bnb136ns6lfw4zs5hg4n85vdthaad7hq5m4gtkgf23?memo=123456789&amount=2|", 3)[:210],
+	}
+	reader := zxingqrcode.NewQRCodeReader()
+	for _, payload := range payloads {
+		code, err := qrcode.Encode(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		image, err := code.PNG(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := png.Decode(bytes.NewReader(image))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bitmap, err := gozxing.NewBinaryBitmapFromImage(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := reader.Decode(bitmap, nil)
+		if err != nil {
+			t.Errorf("Expected the %d module symbol of %s to decode, got %v\n", code.Size, payload, err)
+			continue
+		}
+		if result.GetText() != payload {
+			t.Errorf("Expected the %d module symbol to decode to %s, got %s\n", code.Size, payload, result.GetText())
+		}
+	}
+}
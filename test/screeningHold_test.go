@@ -0,0 +1,242 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"wallet-adapter/config"
+	"wallet-adapter/controllers"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/utility"
+
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	validation "gopkg.in/go-playground/validator.v9"
+)
+
+// stubLockerConfig returns the suite config with the locker and authentication services stubbed, every lock is granted
+func (s *Suite) stubLockerConfig() (config.Data, func()) {
+	locker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/token":
+			_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+		case "/locks/acquire":
+			_ = json.NewEncoder(w).Encode(dto.LockerServiceResponse{Token: "lock-token"})
+		case "/locks/release":
+			_ = json.NewEncoder(w).Encode(dto.ServicesRequestSuccess{Success: true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	stubConfig := s.Config
+	stubConfig.AuthenticationService, stubConfig.LockerService = locker.URL, locker.URL
+	return stubConfig, locker.Close
+}
+
+// screeningController returns a controller screening against a sanctions list holding the address, with a stub locker for batching
+func (s *Suite) screeningController(sanctionedAddress string) (*controllers.UserAssetController, func()) {
+	dir, err := ioutil.TempDir("", "sanctions")
+	require.NoError(s.T(), err)
+	listPath := filepath.Join(dir, "sanctions.csv")
+	require.NoError(s.T(), ioutil.WriteFile(listPath, []byte("address,network,reason\n"+sanctionedAddress+",,OFAC SDN\n"), 0644))
+
+	config, closeLocker := s.stubLockerConfig()
+	config.ScreeningProvider, config.SanctionsListPath = utility.SCREENING_PROVIDER_LOCAL, listPath
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	controller := controllers.NewUserAssetController(utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, config, validation.New(), &userAssetRepository)
+	return controller, func() {
+		closeLocker()
+		os.RemoveAll(dir)
+	}
+}
+
+// createDebitedUserAsset creates a BTC user asset and a completed debit of the value, as a withdrawal is debited before it is sent
+func (s *Suite) createDebitedUserAsset(balance, value string) (model.UserAsset, model.Transaction) {
+	denomination := model.Denomination{}
+	require.NoError(s.T(), s.DB.Where("asset_symbol = ?", "BTC").First(&denomination).Error)
+	userAsset := model.UserAsset{UserID: uuid.NewV4(), DenominationID: denomination.ID, AvailableBalance: balance}
+	require.NoError(s.T(), s.DB.Create(&userAsset).Error)
+	debit := model.Transaction{RecipientID: userAsset.ID, TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(), Memo: utility.NO_MEMO,
+		TransactionTag: model.TransactionTag.DEBIT, TransactionStatus: model.TransactionStatus.COMPLETED, Value: value, PreviousBalance: balance, AvailableBalance: balance,
+		AssetSymbol: "BTC", Network: "BTC"}
+	require.NoError(s.T(), s.DB.Create(&debit).Error)
+	return userAsset, debit
+}
+
+func (s *Suite) externalTransfer(controller *controllers.UserAssetController, debit model.Transaction, recipientAddress string) model.Transaction {
+	requestBody := []byte(fmt.Sprintf(`{"recipientAddress" : "%s", "value" : %s, "debitReference" : "%s", "transactionReference" : "%s"}`,
+		recipientAddress, debit.Value, debit.TransactionReference, uuid.NewV4()))
+	request, _ := http.NewRequest(http.MethodPost, test.TransferExternalEndpoint, bytes.NewBuffer(requestBody))
+	response := httptest.NewRecorder()
+	controller.ExternalTransfer(response, request)
+	require.Equal(s.T(), http.StatusOK, response.Code, response.Body.String())
+
+	transaction := model.Transaction{}
+	require.NoError(s.T(), s.DB.Where("debit_reference = ?", debit.TransactionReference).First(&transaction).Error)
+	return transaction
+}
+
+func (s *Suite) reviewScreeningHold(controller *controllers.UserAssetController, holdID uuid.UUID, action string) int {
+	request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/assets/screening-holds/%s/%s", holdID, action), bytes.NewBuffer([]byte(`{"reviewedBy" : "compliance"}`)))
+	request = mux.SetURLVars(request, map[string]string{"holdId": holdID.String()})
+	response := httptest.NewRecorder()
+	if action == "release" {
+		controller.ReleaseScreeningHold(response, request)
+	} else {
+		controller.RejectScreeningHold(response, request)
+	}
+	return response.Code
+}
+
+func (s *Suite) Test_HeldWithdrawalIsNotBatchedOrSentWithTheBatch() {
+	s.DB.AutoMigrate(&model.ScreeningHold{})
+	defer s.DB.DropTableIfExists(&model.ScreeningHold{})
+	controller, cleanUp := s.screeningController("1Sanctioned")
+	defer cleanUp()
+	batchRepository := database.BatchRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+
+	_, heldDebit := s.createDebitedUserAsset("1", "0.1")
+	_, clearedDebit := s.createDebitedUserAsset("1", "0.2")
+	held := s.externalTransfer(controller, heldDebit, "1Sanctioned")
+	cleared := s.externalTransfer(controller, clearedDebit, "1Cleared")
+
+	assert.Equal(s.T(), model.TransactionStatus.SCREENING_HOLD, held.TransactionStatus)
+	assert.Equal(s.T(), uuid.Nil, held.BatchID, "Expected the held withdrawal not to be put in a batch")
+	assert.NotEqual(s.T(), uuid.Nil, cleared.BatchID, "Expected the cleared withdrawal to be batched")
+
+	// a withdrawal held before held withdrawals were kept out of batches is not carried with its batch
+	batch := model.BatchRequest{}
+	require.NoError(s.T(), batchRepository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: cleared.BatchID}}, &batch))
+	legacyHeld := s.queueBatchedWithdrawal(batchRepository, batch, model.TransactionStatus.SCREENING_HOLD)
+	processor := &controllers.BatchTransactionProcessor{Cache: controller.Cache, Logger: s.Logger, Config: s.Config, Repository: &batchRepository}
+	require.NoError(s.T(), processor.UpdateBatchedTransactionsStatus(batch, model.ChainTransaction{}, model.BatchStatus.PROCESSING))
+	require.NoError(s.T(), processor.UpdateBatchedTransactionsStatus(batch, model.ChainTransaction{}, model.BatchStatus.COMPLETED))
+
+	for transactionID, status := range map[uuid.UUID]string{cleared.ID: model.TransactionStatus.COMPLETED, legacyHeld.ID: model.TransactionStatus.SCREENING_HOLD} {
+		transaction, queuedTransaction := model.Transaction{}, model.TransactionQueue{}
+		require.NoError(s.T(), s.DB.Where("id = ?", transactionID).First(&transaction).Error)
+		require.NoError(s.T(), s.DB.Where("transaction_id = ?", transactionID).First(&queuedTransaction).Error)
+		assert.Equal(s.T(), status, transaction.TransactionStatus)
+		assert.Equal(s.T(), status, queuedTransaction.TransactionStatus)
+	}
+	require.NoError(s.T(), batchRepository.Get(&model.BatchRequest{BaseModel: model.BaseModel{ID: batch.ID}}, &batch))
+	assert.Equal(s.T(), 1, batch.NoOfRecords, "Expected the batch to count only the withdrawal it was sent with")
+}
+
+func (s *Suite) Test_ReleasedWithdrawalLeavesItsBatchAndIsReviewedOnce() {
+	s.DB.AutoMigrate(&model.ScreeningHold{})
+	defer s.DB.DropTableIfExists(&model.ScreeningHold{})
+	controller, cleanUp := s.screeningController("1Sanctioned")
+	defer cleanUp()
+	batchRepository := database.BatchRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+
+	// a held withdrawal left in a batch that has since been sent
+	batch := model.BatchRequest{AssetSymbol: "BTC", Network: "BTC", Status: model.BatchStatus.COMPLETED}
+	require.NoError(s.T(), batchRepository.Create(&batch))
+	held := s.queueBatchedWithdrawal(batchRepository, batch, model.TransactionStatus.SCREENING_HOLD)
+	held.TransactionTag = model.TransactionTag.WITHDRAW
+	require.NoError(s.T(), s.DB.Model(&held).Updates(model.Transaction{TransactionTag: model.TransactionTag.WITHDRAW}).Error)
+	hold := model.ScreeningHold{TransactionID: held.ID, TransactionTag: held.TransactionTag, Address: "1Sanctioned", AssetSymbol: "BTC", Network: "BTC", Value: held.Value,
+		Status: model.ScreeningHoldStatus.HELD}
+	require.NoError(s.T(), s.DB.Create(&hold).Error)
+
+	assert.Equal(s.T(), http.StatusOK, s.reviewScreeningHold(controller, hold.ID, "release"))
+	assert.Equal(s.T(), http.StatusBadRequest, s.reviewScreeningHold(controller, hold.ID, "reject"), "Expected a reviewed hold not to be reviewed again")
+
+	transaction, queuedTransaction := model.Transaction{}, model.TransactionQueue{}
+	require.NoError(s.T(), s.DB.Where("id = ?", held.ID).First(&transaction).Error)
+	require.NoError(s.T(), s.DB.Where("transaction_id = ?", held.ID).First(&queuedTransaction).Error)
+	assert.Equal(s.T(), model.TransactionStatus.PENDING, queuedTransaction.TransactionStatus)
+	assert.Equal(s.T(), uuid.Nil, queuedTransaction.BatchID, "Expected the released withdrawal to go out on its own")
+	assert.Equal(s.T(), uuid.Nil, transaction.BatchID)
+	require.NoError(s.T(), s.DB.Where("id = ?", hold.ID).First(&hold).Error)
+	assert.Equal(s.T(), model.ScreeningHoldStatus.RELEASED, hold.Status)
+	assert.Equal(s.T(), "compliance", hold.ReviewedBy)
+}
+
+func (s *Suite) Test_RejectedWithdrawalIsRefunded() {
+	s.DB.AutoMigrate(&model.ScreeningHold{})
+	defer s.DB.DropTableIfExists(&model.ScreeningHold{})
+	controller, cleanUp := s.screeningController("1Sanctioned")
+	defer cleanUp()
+
+	userAsset, debit := s.createDebitedUserAsset("0.9", "0.1")
+	held := s.externalTransfer(controller, debit, "1Sanctioned")
+	hold := model.ScreeningHold{}
+	require.NoError(s.T(), s.DB.Where("transaction_id = ?", held.ID).First(&hold).Error)
+
+	assert.Equal(s.T(), http.StatusOK, s.reviewScreeningHold(controller, hold.ID, "reject"))
+	assert.Equal(s.T(), http.StatusBadRequest, s.reviewScreeningHold(controller, hold.ID, "reject"), "Expected the value not to be refunded twice")
+
+	require.NoError(s.T(), s.DB.Where("id = ?", userAsset.ID).First(&userAsset).Error)
+	assert.Equal(s.T(), "1", userAsset.AvailableBalance, "Expected the rejected withdrawal to be credited back")
+	reversal := model.Transaction{}
+	require.NoError(s.T(), s.DB.Where("transaction_reference = ?", "REVERSAL-"+held.TransactionReference).First(&reversal).Error)
+	assert.Equal(s.T(), model.TransactionTag.CREDIT, reversal.TransactionTag)
+	assert.True(s.T(), decimal.RequireFromString(reversal.Value).Equal(decimal.NewFromFloat(0.1)))
+	queuedTransaction := model.TransactionQueue{}
+	require.NoError(s.T(), s.DB.Where("transaction_id = ?", held.ID).First(&queuedTransaction).Error)
+	assert.Equal(s.T(), model.TransactionStatus.REJECTED, queuedTransaction.TransactionStatus)
+}
+
+func (s *Suite) Test_ReleasedDepositIsCredited() {
+	s.DB.AutoMigrate(&model.ScreeningHold{})
+	defer s.DB.DropTableIfExists(&model.ScreeningHold{})
+	controller, cleanUp := s.screeningController("1Sanctioned")
+	defer cleanUp()
+
+	userAsset, _ := s.createDebitedUserAsset("0.5", "0")
+	deposit := model.Transaction{RecipientID: userAsset.ID, TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(), Memo: utility.NO_MEMO,
+		TransactionType: model.TransactionType.ONCHAIN, TransactionTag: model.TransactionTag.DEPOSIT, TransactionStatus: model.TransactionStatus.SCREENING_HOLD, Value: "0.25",
+		PreviousBalance: "0.5", AvailableBalance: "0.5", AssetSymbol: "BTC", Network: "BTC"}
+	require.NoError(s.T(), s.DB.Create(&deposit).Error)
+	hold := model.ScreeningHold{TransactionID: deposit.ID, TransactionTag: deposit.TransactionTag, Address: "1Sanctioned", AssetSymbol: "BTC", Network: "BTC", Value: deposit.Value,
+		Status: model.ScreeningHoldStatus.HELD}
+	require.NoError(s.T(), s.DB.Create(&hold).Error)
+
+	assert.Equal(s.T(), http.StatusOK, s.reviewScreeningHold(controller, hold.ID, "release"))
+	assert.Equal(s.T(), http.StatusBadRequest, s.reviewScreeningHold(controller, hold.ID, "release"), "Expected the deposit not to be credited twice")
+
+	require.NoError(s.T(), s.DB.Where("id = ?", userAsset.ID).First(&userAsset).Error)
+	assert.Equal(s.T(), "0.75", userAsset.AvailableBalance)
+	require.NoError(s.T(), s.DB.Where("id = ?", deposit.ID).First(&deposit).Error)
+	assert.Equal(s.T(), model.TransactionStatus.COMPLETED, deposit.TransactionStatus)
+	assert.True(s.T(), decimal.RequireFromString(deposit.PreviousBalance).Equal(decimal.NewFromFloat(0.5)))
+	assert.True(s.T(), decimal.RequireFromString(deposit.AvailableBalance).Equal(decimal.NewFromFloat(0.75)))
+}
+
+func (s *Suite) Test_ReleasedDustDepositFollowsTheDustPolicy() {
+	s.DB.AutoMigrate(&model.ScreeningHold{})
+	defer s.DB.DropTableIfExists(&model.ScreeningHold{})
+	controller, cleanUp := s.screeningController("1Sanctioned")
+	defer cleanUp()
+	require.NoError(s.T(), s.DB.Model(&model.Network{}).Where("asset_symbol = ? AND network = ?", "BTC", "BTC").Updates(map[string]interface{}{"minimum_deposit": 0.001, "dust_policy": model.DustPolicy.BUCKET}).Error)
+	defer s.DB.Model(&model.Network{}).Where("asset_symbol = ? AND network = ?", "BTC", "BTC").Updates(map[string]interface{}{"minimum_deposit": 0, "dust_policy": model.DustPolicy.RECORD})
+
+	userAsset, _ := s.createDebitedUserAsset("0.5", "0")
+	deposit := model.Transaction{RecipientID: userAsset.ID, TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(), Memo: utility.NO_MEMO,
+		TransactionType: model.TransactionType.ONCHAIN, TransactionTag: model.TransactionTag.DEPOSIT, TransactionStatus: model.TransactionStatus.SCREENING_HOLD, Value: "0.0004",
+		PreviousBalance: "0.5", AvailableBalance: "0.5", AssetSymbol: "BTC", Network: "BTC"}
+	require.NoError(s.T(), s.DB.Create(&deposit).Error)
+	hold := model.ScreeningHold{TransactionID: deposit.ID, TransactionTag: deposit.TransactionTag, Address: "1Sanctioned", AssetSymbol: "BTC", Network: "BTC", Value: deposit.Value,
+		Status: model.ScreeningHoldStatus.HELD}
+	require.NoError(s.T(), s.DB.Create(&hold).Error)
+
+	assert.Equal(s.T(), http.StatusOK, s.reviewScreeningHold(controller, hold.ID, "release"))
+
+	require.NoError(s.T(), s.DB.Where("id = ?", userAsset.ID).First(&userAsset).Error)
+	assert.True(s.T(), decimal.RequireFromString(userAsset.AvailableBalance).Equal(decimal.NewFromFloat(0.5)), "Expected a released dust deposit not to be credited")
+	assert.True(s.T(), decimal.RequireFromString(userAsset.DustBalance).Equal(decimal.NewFromFloat(0.0004)), "Expected the bucket policy to keep the dust")
+	require.NoError(s.T(), s.DB.Where("id = ?", deposit.ID).First(&deposit).Error)
+	assert.Equal(s.T(), model.TransactionStatus.DUST, deposit.TransactionStatus)
+}
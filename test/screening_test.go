@@ -0,0 +1,59 @@
+package test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"wallet-adapter/services"
+	"wallet-adapter/utility"
+)
+
+func TestLocalListScreenerReloadsChangedList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sanctions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	listPath := filepath.Join(dir, "sanctions.csv")
+	if err := ioutil.WriteFile(listPath, []byte("address,network,reason\n0xBAD,ERC20,OFAC SDN\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	screener := &services.LocalListScreener{Logger: utility.NewLogger(), FilePath: listPath}
+
+	result, err := screener.Screen("0xbad", "ETH", "ERC20")
+	if err != nil || !result.Hit || result.Reason != "OFAC SDN" {
+		t.Errorf("Expected listed address to be a hit, got %+v, %v\n", result, err)
+	}
+	result, _ = screener.Screen("0xbad", "BNB", "BEP20")
+	if result.Hit {
+		t.Errorf("Expected listed address on another network not to be a hit, got %+v\n", result)
+	}
+
+	if err := ioutil.WriteFile(listPath, []byte(`[{"address" : "bnb1sanctioned"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Rename(listPath, filepath.Join(dir, "sanctions.json"))
+	screener.FilePath = filepath.Join(dir, "sanctions.json")
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(screener.FilePath, later, later)
+
+	result, _ = screener.Screen("bnb1sanctioned", "BNB", "BEP2")
+	if !result.Hit {
+		t.Errorf("Expected address added to the reloaded list to be a hit, got %+v\n", result)
+	}
+	result, _ = screener.Screen("0xbad", "ETH", "ERC20")
+	if result.Hit {
+		t.Errorf("Expected address removed from the reloaded list not to be a hit, got %+v\n", result)
+	}
+}
+
+func TestScreenCounterpartyHoldsOnScreeningError(t *testing.T) {
+	screener := &services.LocalListScreener{Logger: utility.NewLogger(), FilePath: "does-not-exist.csv"}
+	result := services.ScreenCounterparty(screener, utility.NewLogger(), "0xabc", "ETH", "ERC20")
+	if !result.Hit {
+		t.Errorf("Expected screening error to hold the transaction, got %+v\n", result)
+	}
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_SweepPlanReportsSkippedBatchWithoutSweeping() {
+	purgeInterval := s.Config.PurgeCacheInterval * time.Second
+	cacheDuration := s.Config.ExpireCacheDuration * time.Second
+	cache := utility.InitializeCache(cacheDuration, purgeInterval)
+	baseRepository := database.BaseRepository{Database: s.Database}
+
+	denomination := model.Denomination{}
+	if err := s.DB.Where("asset_symbol = ?", "ETH").First(&denomination).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	s.DB.Model(&model.Network{}).Where("asset_symbol = ? AND network = ?", "ETH", "ERC20").Update("minimum_sweepable", 1)
+
+	userAsset := model.UserAsset{UserID: uuid.NewV4(), DenominationID: denomination.ID, AvailableBalance: "0.0004"}
+	chainTransaction := model.ChainTransaction{RecipientAddress: "0x5b0e3b1a7b6c1d9b7a4c0f7cf0c3e5a4a3f1e2d1", TransactionHash: "0xdeposit"}
+	floatAccount := model.HotWalletAsset{Address: "0xfloat", AssetSymbol: "ETH", Network: "ERC20"}
+	for _, record := range []interface{}{&userAsset, &chainTransaction, &floatAccount} {
+		if err := s.DB.Create(record).Error; err != nil {
+			require.NoError(s.T(), err)
+		}
+	}
+	deposit := model.Transaction{InitiatorID: userAsset.ID, RecipientID: userAsset.ID, TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(),
+		TransactionType: "ONCHAIN", TransactionStatus: model.TransactionStatus.COMPLETED, TransactionTag: model.TransactionTag.DEPOSIT, Value: "0.0004",
+		PreviousBalance: "0", AvailableBalance: "0.0004", OnChainTxId: chainTransaction.ID, AssetSymbol: "ETH", Network: "ERC20"}
+	if err := s.DB.Create(&deposit).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+
+	sweepPlan, err := tasks.PlanSweep(cache, s.Logger, s.Config, baseRepository)
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), 1, sweepPlan.CandidateCount)
+	require.Equal(s.T(), 1, len(sweepPlan.Items))
+	assert.True(s.T(), sweepPlan.Items[0].IsBatch, "Expected the ERC20 deposit to be planned in a batch")
+	assert.Equal(s.T(), []string{chainTransaction.RecipientAddress}, sweepPlan.Items[0].Origins)
+	assert.False(s.T(), sweepPlan.Items[0].WillSweep)
+	assert.Equal(s.T(), model.SweepSkipReason.BELOW_MINIMUM, sweepPlan.Items[0].SkipReason)
+
+	planned := model.Transaction{}
+	if err := s.DB.Where("id = ?", deposit.ID).First(&planned).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.False(s.T(), planned.SweptStatus, "Expected the dry run to leave the deposit unswept")
+	var sweepRuns int
+	s.DB.Model(&model.SweepRun{}).Count(&sweepRuns)
+	assert.Equal(s.T(), 0, sweepRuns, "Expected the dry run to not record a sweep run")
+}
+
+func (s *Suite) Test_SweepPlanLeavesTheFloatManagerCursorsAsTheyAre() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	denomination := model.Denomination{}
+	require.NoError(s.T(), s.DB.Where("asset_symbol = ?", "ETH").First(&denomination).Error)
+	require.NoError(s.T(), s.DB.Model(&model.Network{}).Where("asset_symbol = ? AND network = ?", "ETH", "ERC20").Update("minimum_sweepable", 0.0001).Error)
+
+	cursor := time.Now().Add(-time.Hour)
+	userAsset := model.UserAsset{UserID: uuid.NewV4(), DenominationID: denomination.ID, AvailableBalance: "0.0004"}
+	chainTransaction := model.ChainTransaction{RecipientAddress: "0x5b0e3b1a7b6c1d9b7a4c0f7cf0c3e5a4a3f1e2d1", TransactionHash: "0xdeposit"}
+	floatAccount := model.HotWalletAsset{Address: "0xfloat", AssetSymbol: "ETH", Network: "ERC20", LastDepositCreatedAt: &cursor, LastWithdrawalCreatedAt: &cursor}
+	for _, record := range []interface{}{&userAsset, &chainTransaction, &floatAccount, &model.FloatManagerParam{AssetSymbol: "ETH", Network: "ERC20"},
+		&model.SweepPolicy{AssetSymbol: "ETH", Network: "ERC20", Type: model.SweepPolicyType.FLOAT_THEN_COLD, ColdAddress: "0xvault", UpdatedBy: "treasury"}} {
+		require.NoError(s.T(), s.DB.Create(record).Error)
+	}
+	newTransaction := func(tag string) model.Transaction {
+		return model.Transaction{InitiatorID: userAsset.ID, RecipientID: userAsset.ID, TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(),
+			TransactionType: "ONCHAIN", TransactionStatus: model.TransactionStatus.COMPLETED, TransactionTag: tag, Value: "0.0004",
+			PreviousBalance: "0", AvailableBalance: "0.0004", AssetSymbol: "ETH", Network: "ERC20"}
+	}
+	deposit, withdrawal := newTransaction(model.TransactionTag.DEPOSIT), newTransaction(model.TransactionTag.WITHDRAW)
+	deposit.OnChainTxId = chainTransaction.ID
+	require.NoError(s.T(), s.DB.Create(&deposit).Error)
+	require.NoError(s.T(), s.DB.Create(&withdrawal).Error)
+
+	services := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/token":
+			_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+		case "/onchain-balance":
+			_ = json.NewEncoder(w).Encode(dto.OnchainBalanceResponse{Balance: "0", AssetSymbol: "ETH"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer services.Close()
+	config := s.Config
+	config.AuthenticationService, config.CryptoAdapterService = services.URL, services.URL
+
+	sweepPlan, err := tasks.PlanSweep(utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, config, baseRepository)
+	require.NoError(s.T(), err)
+	require.Equal(s.T(), 1, len(sweepPlan.Items))
+	assert.True(s.T(), sweepPlan.Items[0].WillSweep, "Expected the batch to be planned from the float parameters, %+v", sweepPlan.Items[0])
+
+	planned := model.HotWalletAsset{}
+	require.NoError(s.T(), s.DB.Where("id = ?", floatAccount.ID).First(&planned).Error)
+	require.NotNil(s.T(), planned.LastDepositCreatedAt)
+	require.NotNil(s.T(), planned.LastWithdrawalCreatedAt)
+	assert.WithinDuration(s.T(), cursor, *planned.LastDepositCreatedAt, time.Second, "Expected the dry run to leave the deposits for the float manager")
+	assert.WithinDuration(s.T(), cursor, *planned.LastWithdrawalCreatedAt, time.Second, "Expected the dry run to leave the withdrawals for the float manager")
+}
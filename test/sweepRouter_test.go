@@ -0,0 +1,63 @@
+package test
+
+import (
+	"wallet-adapter/database"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_SweepRouterFollowsAssetPolicy() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	var cache *utility.MemoryCache
+	isToken := true
+	floatAccount := model.HotWalletAsset{Address: "0xfloat", AssetSymbol: "USDT", Network: "ERC20"}
+	usdt := model.Network{AssetSymbol: "USDT", Network: "ERC20", NativeAsset: "ETH", IsToken: &isToken}
+	busd := model.Network{AssetSymbol: "BUSD", Network: "BEP20", NativeAsset: "BNB", IsToken: &isToken}
+
+	require.NoError(s.T(), baseRepository.Create(&model.SweepPolicy{AssetSymbol: "USDT", Network: "ERC20", Type: model.SweepPolicyType.FIXED_PERCENTAGES,
+		FloatPercent: 30, ColdAddress: "0xcold", UpdatedBy: "treasury"}))
+	require.NoError(s.T(), baseRepository.Create(&model.SweepPolicy{AssetSymbol: "BUSD", Network: "BEP20", Type: model.SweepPolicyType.ALL_TO_COLD,
+		ColdAddress: "0xvault", ColdMemo: "1001", UpdatedBy: "treasury"}))
+
+	router, err := tasks.GetSweepRouter(cache, s.Logger, s.Config, baseRepository, floatAccount, usdt, false)
+	require.NoError(s.T(), err)
+	sweepParam, err := router.Split(1000)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(30), sweepParam.FloatPercent)
+	assert.Equal(s.T(), int64(70), sweepParam.ColdPercent, "Expected the rest to go to cold storage")
+	assert.Equal(s.T(), int64(0), sweepParam.BrokeragePercent)
+	assert.Equal(s.T(), "0xcold", sweepParam.ChangeAddress())
+	assert.Equal(s.T(), model.SweepPolicyType.FIXED_PERCENTAGES, sweepParam.Policy)
+
+	sweepParam, err = router.Route(100)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "0xcold", sweepParam.ToAddress(), "Expected the first address sweep to go to the larger share")
+
+	// address sweeps go whole to one side, the next goes to the float once it falls behind its share
+	sweepRun := model.SweepRun{Status: model.SweepRunStatus.COMPLETED}
+	require.NoError(s.T(), baseRepository.Create(&sweepRun))
+	require.NoError(s.T(), baseRepository.Create(&model.SweepItem{SweepRunID: sweepRun.ID, Address: "0xdeposit1", AssetSymbol: "USDT", Network: "ERC20",
+		Amount: "100", ColdAddress: "0xcold", ColdPercent: 100, SweepPolicy: model.SweepPolicyType.FIXED_PERCENTAGES, Status: model.SweepItemStatus.SWEPT}))
+	sweepParam, err = router.Route(100)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "0xfloat", sweepParam.ToAddress(), "Expected the address sweep to go to the float once it is below its share")
+	assert.Equal(s.T(), int64(100), sweepParam.FloatPercent)
+
+	require.NoError(s.T(), baseRepository.Create(&model.SweepItem{SweepRunID: sweepRun.ID, Address: "0xdeposit2", AssetSymbol: "USDT", Network: "ERC20",
+		Amount: "100", FloatAddress: "0xfloat", FloatPercent: 100, SweepPolicy: model.SweepPolicyType.FIXED_PERCENTAGES, Status: model.SweepItemStatus.SWEPT}))
+	sweepParam, err = router.Route(100)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "0xcold", sweepParam.ToAddress(), "Expected the address sweep to go to cold storage once the float is above its share")
+
+	router, err = tasks.GetSweepRouter(cache, s.Logger, s.Config, baseRepository, model.HotWalletAsset{Address: "0xbusdfloat", AssetSymbol: "BUSD", Network: "BEP20"}, busd, false)
+	require.NoError(s.T(), err)
+	sweepParam, err = router.Route(100)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "0xvault", sweepParam.ToAddress())
+	assert.Equal(s.T(), "1001", sweepParam.Memo)
+	assert.Equal(s.T(), int64(0), sweepParam.FloatPercent, "Expected nothing to go to the float")
+}
@@ -0,0 +1,292 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+	"wallet-adapter/config"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/tasks"
+	"wallet-adapter/utility"
+
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *Suite) Test_SweepRunRecordsOutcomePerAddress() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	userAssetRepository := database.UserAssetRepository{BaseRepository: baseRepository}
+
+	sweepRun := tasks.StartSweepRun(baseRepository, s.Logger)
+	sweepRun.CandidateCount = 4
+	tasks.RecordSweepItem(baseRepository, &sweepRun, model.SweepItem{Address: "0xswept", AssetSymbol: "ETH", Network: "ERC20", TransactionCount: 2, Amount: "1.5",
+		BrokerageAddress: "0xbrokerage", BrokeragePercent: 100, Reference: "0xswept-1628500000", TransactionHash: "0xhash"}, nil, s.Logger)
+	tasks.RecordSweepItem(baseRepository, &sweepRun, model.SweepItem{Address: "0xdust", AssetSymbol: "ETH", Network: "ERC20", TransactionCount: 1, Amount: "0.0001",
+		SkipReason: model.SweepSkipReason.BELOW_MINIMUM}, nil, s.Logger)
+	tasks.RecordSweepItem(baseRepository, &sweepRun, model.SweepItem{Address: "TXfailed", AssetSymbol: "TRX", Network: "TRC20", TransactionCount: 1, Amount: "20"},
+		errors.New("signer unavailable"), s.Logger)
+	tasks.FinishSweepRun(baseRepository, &sweepRun, nil, s.Logger)
+
+	sweepRuns := []model.SweepRun{}
+	if err := userAssetRepository.FetchSweepRuns(10, &sweepRuns); err != nil {
+		require.NoError(s.T(), err)
+	}
+	require.Equal(s.T(), 1, len(sweepRuns))
+	assert.Equal(s.T(), model.SweepRunStatus.COMPLETED, sweepRuns[0].Status)
+	assert.Equal(s.T(), 4, sweepRuns[0].CandidateCount)
+	assert.Equal(s.T(), 1, sweepRuns[0].SweptCount, "Expected one address to be swept")
+	assert.Equal(s.T(), 1, sweepRuns[0].SkippedCount, "Expected one address to be skipped")
+	assert.Equal(s.T(), 1, sweepRuns[0].FailedCount, "Expected one address to fail")
+	assert.NotNil(s.T(), sweepRuns[0].FinishedAt)
+
+	skippedItems := []model.SweepItem{}
+	if err := userAssetRepository.FetchSweepItems(sweepRun.ID, model.SweepItemStatus.SKIPPED, "", &skippedItems); err != nil {
+		require.NoError(s.T(), err)
+	}
+	require.Equal(s.T(), 1, len(skippedItems))
+	assert.Equal(s.T(), "0xdust", skippedItems[0].Address)
+	assert.Equal(s.T(), model.SweepSkipReason.BELOW_MINIMUM, skippedItems[0].SkipReason)
+
+	sweptItems := []model.SweepItem{}
+	if err := userAssetRepository.FetchSweepItems(sweepRun.ID, "", "0xswept", &sweptItems); err != nil {
+		require.NoError(s.T(), err)
+	}
+	require.Equal(s.T(), 1, len(sweptItems))
+	assert.Equal(s.T(), model.SweepItemStatus.SWEPT, sweptItems[0].Status)
+	assert.Equal(s.T(), "0xhash", sweptItems[0].TransactionHash)
+	assert.Equal(s.T(), int64(100), sweptItems[0].BrokeragePercent)
+
+	failedItems := []model.SweepItem{}
+	if err := userAssetRepository.FetchSweepItems(sweepRun.ID, model.SweepItemStatus.FAILED, "", &failedItems); err != nil {
+		require.NoError(s.T(), err)
+	}
+	require.Equal(s.T(), 1, len(failedItems))
+	assert.Equal(s.T(), "signer unavailable", failedItems[0].Error)
+}
+
+func (s *Suite) Test_SweepNetworkConcurrencyAndCancelledRuns() {
+	config := s.Config
+	config.SweepNetworkConcurrency = 3
+	// viper lowercases map keys read from the config file
+	config.SweepNetworkConcurrencyLimits = map[string]int{"bep2": 1}
+	assert.Equal(s.T(), 1, tasks.GetSweepNetworkConcurrency(config, "BEP2"), "Expected the network limit to apply")
+	assert.Equal(s.T(), 3, tasks.GetSweepNetworkConcurrency(config, "ERC20"), "Expected networks without a limit to use the default")
+
+	baseRepository := database.BaseRepository{Database: s.Database}
+	sweepRun := tasks.StartSweepRun(baseRepository, s.Logger)
+	tasks.FinishSweepRun(baseRepository, &sweepRun, context.Canceled, s.Logger)
+
+	cancelledRun := model.SweepRun{}
+	if err := s.DB.Where("id = ?", sweepRun.ID).First(&cancelledRun).Error; err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), model.SweepRunStatus.CANCELLED, cancelledRun.Status)
+}
+
+// sweepStub ... Stands in for the locker service and the transaction signer of a sweep run, recording how many sweeps were in flight at once
+type sweepStub struct {
+	mutex              sync.Mutex
+	signDelay          time.Duration
+	failRenewalAfter   int
+	onSend             func()
+	inFlightPerNetwork map[string]int
+	maxPerNetwork      map[string]int
+	inFlightPerAddress map[string]int
+	maxPerAddress      map[string]int
+	sent               []string
+	renewTokens        []string
+	releasedToken      string
+}
+
+func (stub *sweepStub) serve(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/services/token":
+		_ = json.NewEncoder(w).Encode(dto.UpdateAuthTokenResponse{Token: "service-token"})
+	case "/locks/acquire":
+		_ = json.NewEncoder(w).Encode(dto.LockerServiceResponse{Token: "lock-token-0"})
+	case "/locks/renew":
+		request := dto.LockerServiceRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		stub.mutex.Lock()
+		stub.renewTokens = append(stub.renewTokens, request.Token)
+		renewals := len(stub.renewTokens)
+		stub.mutex.Unlock()
+		if stub.failRenewalAfter > 0 && renewals > stub.failRenewalAfter {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(dto.ServicesRequestErr{Message: "lock expired"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(dto.LockerServiceResponse{Token: fmt.Sprintf("lock-token-%d", renewals)})
+	case "/locks/release":
+		request := dto.LockReleaseRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		stub.mutex.Lock()
+		stub.releasedToken = request.Token
+		stub.mutex.Unlock()
+		_ = json.NewEncoder(w).Encode(dto.ServicesRequestSuccess{Success: true})
+	case "/transactions/send-single":
+		request := dto.SendSingleTransactionRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		stub.mutex.Lock()
+		stub.inFlightPerNetwork[request.Network]++
+		stub.inFlightPerAddress[request.FromAddress]++
+		if stub.inFlightPerNetwork[request.Network] > stub.maxPerNetwork[request.Network] {
+			stub.maxPerNetwork[request.Network] = stub.inFlightPerNetwork[request.Network]
+		}
+		if stub.inFlightPerAddress[request.FromAddress] > stub.maxPerAddress[request.FromAddress] {
+			stub.maxPerAddress[request.FromAddress] = stub.inFlightPerAddress[request.FromAddress]
+		}
+		stub.sent = append(stub.sent, request.FromAddress)
+		onSend := stub.onSend
+		stub.mutex.Unlock()
+		if onSend != nil {
+			onSend()
+		}
+
+		time.Sleep(stub.signDelay)
+		stub.mutex.Lock()
+		stub.inFlightPerNetwork[request.Network]--
+		stub.inFlightPerAddress[request.FromAddress]--
+		stub.mutex.Unlock()
+		_ = json.NewEncoder(w).Encode(dto.SendTransactionResponse{TransactionHash: "0x" + request.Reference})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// startSweepStub returns a config sending the locker and signer calls of a sweep run to the stub
+func (s *Suite) startSweepStub(stub *sweepStub) (config.Data, func()) {
+	stub.inFlightPerNetwork, stub.maxPerNetwork = map[string]int{}, map[string]int{}
+	stub.inFlightPerAddress, stub.maxPerAddress = map[string]int{}, map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(stub.serve))
+	stubConfig := s.Config
+	stubConfig.AuthenticationService, stubConfig.LockerService, stubConfig.TransactionSignersURL = server.URL, server.URL, server.URL
+	return stubConfig, server.Close
+}
+
+// createSweepNetwork sets up a single address network swept whole to cold storage
+func (s *Suite) createSweepNetwork(assetSymbol, network string) {
+	isFalse := false
+	require.NoError(s.T(), s.DB.Create(&model.Network{AssetSymbol: assetSymbol, Network: network, NativeAsset: assetSymbol, CoinType: 60, NativeDecimals: 18,
+		AddressProvider: model.AddressProvider.BUNDLE, IsToken: &isFalse, IsBatchable: &isFalse, IsMultiAddresses: &isFalse}).Error)
+	require.NoError(s.T(), s.DB.Create(&model.HotWalletAsset{AssetSymbol: assetSymbol, Network: network, Address: "0xfloat" + network}).Error)
+	require.NoError(s.T(), s.DB.Create(&model.SweepPolicy{AssetSymbol: assetSymbol, Network: network, Type: model.SweepPolicyType.ALL_TO_COLD,
+		ColdAddress: "0xcold" + network, UpdatedBy: "treasury"}).Error)
+}
+
+// createSweepDeposit records a completed deposit to the address waiting to be swept
+func (s *Suite) createSweepDeposit(address, assetSymbol, network string) {
+	denomination := model.Denomination{}
+	require.NoError(s.T(), s.DB.Where("asset_symbol = ?", assetSymbol).First(&denomination).Error)
+	userAsset := model.UserAsset{UserID: uuid.NewV4(), DenominationID: denomination.ID, AvailableBalance: "1"}
+	require.NoError(s.T(), s.DB.Create(&userAsset).Error)
+	chainTransaction := model.ChainTransaction{TransactionHash: uuid.NewV4().String(), RecipientAddress: address, AssetSymbol: assetSymbol, Network: network}
+	require.NoError(s.T(), s.DB.Create(&chainTransaction).Error)
+	require.NoError(s.T(), s.DB.Create(&model.Transaction{RecipientID: userAsset.ID, TransactionReference: uuid.NewV4().String(), PaymentReference: uuid.NewV4().String(),
+		TransactionTag: model.TransactionTag.DEPOSIT, TransactionStatus: model.TransactionStatus.COMPLETED, Value: "1", PreviousBalance: "0", AvailableBalance: "1",
+		AssetSymbol: assetSymbol, Network: network, OnChainTxId: chainTransaction.ID}).Error)
+}
+
+func (s *Suite) lastSweepRun() model.SweepRun {
+	sweepRuns := []model.SweepRun{}
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	require.NoError(s.T(), userAssetRepository.FetchSweepRuns(1, &sweepRuns))
+	require.Len(s.T(), sweepRuns, 1)
+	return sweepRuns[0]
+}
+
+func (s *Suite) Test_SweepWorkersKeepToTheNetworkLimitAndSweepAnAddressOneJobAtATime() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	s.createSweepNetwork("ETH", "NETA")
+	s.createSweepNetwork("ETH", "NETB")
+	s.createSweepNetwork("BNB", "NETB")
+	for index := 0; index < 3; index++ {
+		s.createSweepDeposit(fmt.Sprintf("0xa%d", index), "ETH", "NETA")
+		s.createSweepDeposit(fmt.Sprintf("0xb%d", index), "ETH", "NETB")
+	}
+	// two assets deposited to one address are swept by the same worker
+	s.createSweepDeposit("0xb0", "BNB", "NETB")
+
+	stub := &sweepStub{signDelay: 50 * time.Millisecond}
+	stubConfig, closeStub := s.startSweepStub(stub)
+	defer closeStub()
+	stubConfig.SweepWorkers, stubConfig.SweepNetworkConcurrency = 4, 3
+	stubConfig.SweepNetworkConcurrencyLimits = map[string]int{"neta": 1}
+
+	tasks.SweepTransactionsWithContext(context.Background(), utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, stubConfig, baseRepository)
+
+	assert.Len(s.T(), stub.sent, 7, "Expected every address to be swept")
+	assert.Equal(s.T(), 1, stub.maxPerNetwork["NETA"], "Expected the network limit to hold")
+	assert.True(s.T(), stub.maxPerNetwork["NETB"] <= 3, "Expected the default network limit to hold, got %d", stub.maxPerNetwork["NETB"])
+	for address, maxInFlight := range stub.maxPerAddress {
+		assert.Equal(s.T(), 1, maxInFlight, "Expected the jobs of %s not to overlap", address)
+	}
+	assert.Equal(s.T(), "lock-token-0", stub.releasedToken)
+	sweepRun := s.lastSweepRun()
+	assert.Equal(s.T(), model.SweepRunStatus.COMPLETED, sweepRun.Status)
+	assert.Equal(s.T(), 7, sweepRun.SweptCount)
+}
+
+func (s *Suite) Test_SweepLeaseIsRenewedAndLosingItStopsTheRun() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	s.createSweepNetwork("ETH", "NETA")
+	for index := 0; index < 6; index++ {
+		s.createSweepDeposit(fmt.Sprintf("0xa%d", index), "ETH", "NETA")
+	}
+
+	// the lease is renewed every 100ms, the second renewal is refused
+	stub := &sweepStub{signDelay: 150 * time.Millisecond, failRenewalAfter: 1}
+	stubConfig, closeStub := s.startSweepStub(stub)
+	defer closeStub()
+	stubConfig.SweepLockTTL = 300
+	stubConfig.SweepNetworkConcurrencyLimits = map[string]int{"neta": 1}
+
+	tasks.SweepTransactionsWithContext(context.Background(), utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, stubConfig, baseRepository)
+
+	require.Len(s.T(), stub.renewTokens, 2, "Expected renewals to stop once the lease is lost")
+	assert.Equal(s.T(), "lock-token-0", stub.renewTokens[0])
+	assert.Equal(s.T(), "lock-token-1", stub.renewTokens[1], "Expected the lease to be renewed with the renewed token")
+	assert.Equal(s.T(), "lock-token-1", stub.releasedToken, "Expected the lock to be released with the last token")
+	assert.True(s.T(), len(stub.sent) < 6, "Expected no sweep to start once the lease is lost, %d were sent", len(stub.sent))
+	sweepRun := s.lastSweepRun()
+	assert.Equal(s.T(), model.SweepRunStatus.FAILED, sweepRun.Status)
+	assert.Equal(s.T(), "lock expired", sweepRun.Error)
+	assert.Equal(s.T(), len(stub.sent), sweepRun.SweptCount, "Expected the sweeps in flight to be recorded")
+}
+
+func (s *Suite) Test_CancelledSweepRunStartsNoNewSweep() {
+	baseRepository := database.BaseRepository{Database: s.Database}
+	s.createSweepNetwork("ETH", "NETA")
+	for index := 0; index < 4; index++ {
+		s.createSweepDeposit(fmt.Sprintf("0xa%d", index), "ETH", "NETA")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stub := &sweepStub{signDelay: 50 * time.Millisecond, onSend: cancel}
+	stubConfig, closeStub := s.startSweepStub(stub)
+	defer closeStub()
+	stubConfig.SweepNetworkConcurrencyLimits = map[string]int{"neta": 1}
+
+	tasks.SweepTransactionsWithContext(ctx, utility.InitializeCache(cacheDuration, purgeInterval), s.Logger, stubConfig, baseRepository)
+
+	assert.Len(s.T(), stub.sent, 1, "Expected the sweep in flight to complete and no other to start")
+	assert.Empty(s.T(), stub.renewTokens)
+	assert.Equal(s.T(), "lock-token-0", stub.releasedToken, "Expected the lock to be released")
+	sweepRun := s.lastSweepRun()
+	assert.Equal(s.T(), model.SweepRunStatus.CANCELLED, sweepRun.Status)
+	assert.Equal(s.T(), 1, sweepRun.SweptCount)
+
+	unswept := 0
+	require.NoError(s.T(), s.DB.Model(&model.Transaction{}).Where("swept_status = ?", false).Count(&unswept).Error)
+	assert.Equal(s.T(), 3, unswept, "Expected the deposits left behind to be swept by the next run")
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"wallet-adapter/config"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTravelRuleRecordIsEncryptedAndExportsIVMS101(t *testing.T) {
+	Config := config.Data{TravelRuleEncryptionKey: base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))}
+	travelRule := &dto.TravelRuleData{
+		Originator:  dto.TravelRuleParty{FirstName: "Ada", LastName: "Obi", AccountNumber: "bc1qoriginator", Country: "NG", DateOfBirth: "1990-01-01", PlaceOfBirth: "Lagos"},
+		Beneficiary: dto.TravelRuleParty{LegalName: "Acme Ltd", AccountNumber: "bc1qbeneficiary", VaspName: "Other Exchange", VaspLEI: "5493001KJTIIGC8Y1R12"},
+	}
+	transaction := model.Transaction{AssetSymbol: "BTC", Network: "BTC", Value: "1.5", TransactionTag: model.TransactionTag.WITHDRAW}
+
+	record, err := services.BuildTravelRuleRecord(Config, transaction, travelRule, decimal.NewFromInt(45000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Status != model.TravelRuleRecordStatus.COMPLETE || strings.Contains(record.EncryptedPayload, "Obi") {
+		t.Errorf("Expected a complete record with encrypted payload, got %+v\n", record)
+	}
+
+	decrypted, err := services.DecryptTravelRuleRecord(Config, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := services.ToIVMS101(decrypted)
+
+	originator := payload.Originator.OriginatorPersons[0].NaturalPerson
+	if originator == nil || originator.Name.NameIdentifier[0].PrimaryIdentifier != "Obi" || originator.DateAndPlaceOfBirth.PlaceOfBirth != "Lagos" {
+		t.Errorf("Expected originator to map to a natural person, got %+v\n", payload.Originator)
+	}
+	beneficiary := payload.Beneficiary.BeneficiaryPersons[0].LegalPerson
+	if beneficiary == nil || beneficiary.Name.NameIdentifier[0].LegalPersonName != "Acme Ltd" || payload.Beneficiary.AccountNumber[0] != "bc1qbeneficiary" {
+		t.Errorf("Expected beneficiary to map to a legal person, got %+v\n", payload.Beneficiary)
+	}
+	if payload.BeneficiaryVASP.BeneficiaryVASP == nil || payload.BeneficiaryVASP.BeneficiaryVASP.LegalPerson.NationalIdentification.NationalIdentifierType != "LEIX" {
+		t.Errorf("Expected beneficiary VASP to carry its LEI, got %+v\n", payload.BeneficiaryVASP)
+	}
+}
+
+func TestTravelRuleRecordWithoutDataIsIncomplete(t *testing.T) {
+	record, err := services.BuildTravelRuleRecord(config.Data{}, model.Transaction{AssetSymbol: "ETH", Value: "10"}, nil, decimal.NewFromInt(30000))
+	if err != nil || record.Status != model.TravelRuleRecordStatus.INCOMPLETE || record.EncryptedPayload != "" {
+		t.Errorf("Expected an incomplete record without payload, got %+v, %v\n", record, err)
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"encoding/hex"
+	"testing"
+	"wallet-adapter/database"
+	"wallet-adapter/dto"
+	"wallet-adapter/model"
+	"wallet-adapter/services"
+	"wallet-adapter/utility/hdwallet"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bip84AccountXpub = "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"
+
+func TestExtendedKeyChildMatchesBIP32Vector(t *testing.T) {
+	masterKey, err := hdwallet.ParseExtendedKey("xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	childKey, err := masterKey.Child(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if childKey.String() != "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH" {
+		t.Errorf("Expected m/0 to match BIP32 test vector 2, got %s\n", childKey.String())
+	}
+	if _, err := masterKey.Child(hdwallet.HardenedKeyStart); err != hdwallet.ErrHardenedChild {
+		t.Errorf("Expected hardened derivation from an xpub to fail, got %v\n", err)
+	}
+}
+
+func TestEncodeAddressFormats(t *testing.T) {
+	// the generator point is the public key of private key 1, a widely published reference
+	generator, _ := hex.DecodeString("0479BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798")
+	payload, _ := hex.DecodeString("0488B21E000000000000000000" + "0000000000000000000000000000000000000000000000000000000000000000")
+	generator[0] = 0x02
+	key, err := hdwallet.ParseExtendedKey(hdwallet.Base58CheckEncode(append(payload, generator...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := hdwallet.ChainParams{PubKeyHashPrefix: 0x00, ScriptHashPrefix: 0x05, Bech32HRP: "bc", CashAddrPrefix: "bitcoincash"}
+	expectedAddresses := map[hdwallet.AddressFormat]string{
+		hdwallet.P2PKH:    "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		hdwallet.P2WPKH:   "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		hdwallet.CashAddr: "bitcoincash:qp63uahgrxged4z5jswyt5dn5v3lzsem6cy4spdc2h",
+		hdwallet.Ethereum: "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+	}
+	for format, expectedAddress := range expectedAddresses {
+		address, err := hdwallet.EncodeAddress(key, format, params)
+		if err != nil || address != expectedAddress {
+			t.Errorf("Expected %s address %s, got %s, %v\n", format, expectedAddress, address, err)
+		}
+	}
+}
+
+func TestParseExtendedKeyRejectsPrivateKeys(t *testing.T) {
+	if _, err := hdwallet.ParseExtendedKey("xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi"); err != hdwallet.ErrPrivateKey {
+		t.Errorf("Expected a private extended key to be rejected, got %v\n", err)
+	}
+}
+
+func (s *Suite) Test_XpubAddressesUseSequentialUnusedIndexes() {
+	s.DB.AutoMigrate(&model.HDAccountIndex{})
+	defer s.DB.DropTableIfExists(&model.HDAccountIndex{})
+	viper.Set("HDXPUB.BTC_BTC_SEGWIT", bip84AccountXpub)
+	defer viper.Set("HDXPUB.BTC_BTC_SEGWIT", "")
+
+	userAssetRepository := database.UserAssetRepository{BaseRepository: database.BaseRepository{Database: s.Database}}
+	networkAsset := dto.NetworkAsset{AssetSymbol: "BTC", Network: "BTC", CoinType: 0, AddressProvider: model.AddressProvider.XPUB}
+
+	firstAddress, firstIndex, err := services.DeriveXpubAddress(&userAssetRepository, networkAsset, "Segwit")
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), int64(0), firstIndex)
+	assert.Equal(s.T(), "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", firstAddress, "Expected BIP84 m/84'/0'/0'/0/0 address")
+
+	secondAddress, secondIndex, err := services.DeriveXpubAddress(&userAssetRepository, networkAsset, "Segwit")
+	if err != nil {
+		require.NoError(s.T(), err)
+	}
+	assert.Equal(s.T(), int64(1), secondIndex)
+	assert.Equal(s.T(), "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", secondAddress, "Expected BIP84 m/84'/0'/0'/0/1 address")
+}
@@ -28,6 +28,7 @@ const (
 	NOTIFICATION_SMS_COUNTRY        = "NG"
 	ONE_HOUR_MILLISECONDS           = 3600000
 	SIX_HUNDRED_MILLISECONDS        = 600000
+	BATCHING_LOCK_WAIT_MILLISECONDS = 5000
 	SEPERATOR                       = "_"
 	FUND_SWEEP_FEE_WAIT_TIME        = 3 // In seconds
 	SWEEP_GROUPING_SEPERATOR        = "|"
@@ -39,4 +40,8 @@ const (
 	COULD_NOT_SUBSCRIBE_ADDRESS     = "COULD_NOT_SUBSCRIBE_ADDRESS"
 	UPDATE_SWEPT_STATUS_SUCCESS     = "UPDATE_SWEPT_STATUS_SUCCESS"
 	UPDATE_SWEPT_STATUS_FAILURE     = "UPDATE_SWEPT_STATUS_FAILURE"
+	SCREENING_PROVIDER_LOCAL        = "local"
+	SCREENING_PROVIDER_HTTP         = "http"
+	ADDRESS_HOLD_INVALIDATED        = "address-invalidated"
+	ADDRESS_HOLD_RETIRED            = "address-retired"
 )
@@ -36,6 +36,12 @@ func GetRequestMetaData(request string, config Config.Data) MetaData {
 			Endpoint: config.CryptoAdapterService,
 			Action:   "/subscription/register",
 		}
+	case "unsubscribeAddressV2":
+		return MetaData{
+			Type:     http.MethodPost,
+			Endpoint: config.CryptoAdapterService,
+			Action:   "/subscription/cancel",
+		}
 	case "transactionStatus":
 		return MetaData{
 			Type:     http.MethodGet,
@@ -48,6 +54,18 @@ func GetRequestMetaData(request string, config Config.Data) MetaData {
 			Endpoint: config.CryptoAdapterService,
 			Action:   "/onchain-balance",
 		}
+	case "getAddressDeposits":
+		return MetaData{
+			Type:     http.MethodGet,
+			Endpoint: config.CryptoAdapterService,
+			Action:   "/address-deposits",
+		}
+	case "getNetworkFee":
+		return MetaData{
+			Type:     http.MethodGet,
+			Endpoint: config.CryptoAdapterService,
+			Action:   "/network-fee",
+		}
 	case "acquireLock":
 		return MetaData{
 			Type:     http.MethodPost,
@@ -138,6 +156,18 @@ func GetRequestMetaData(request string, config Config.Data) MetaData {
 			Endpoint: config.TransactionSignersURL,
 			Action:   "/transactions/send-batch",
 		}
+	case "getAssetRate":
+		return MetaData{
+			Type:     http.MethodGet,
+			Endpoint: config.RateServiceUrl,
+			Action:   "/rates",
+		}
+	case "screenAddress":
+		return MetaData{
+			Type:     http.MethodPost,
+			Endpoint: config.ScreeningServiceURL,
+			Action:   "/addresses/screen",
+		}
 
 	default:
 		return MetaData{}
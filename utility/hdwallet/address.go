@@ -0,0 +1,78 @@
+package hdwallet
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// AddressFormat ...
+type AddressFormat string
+
+// Supported address formats
+const (
+	P2PKH      AddressFormat = "P2PKH"
+	P2SHP2WPKH AddressFormat = "P2SH-P2WPKH"
+	P2WPKH     AddressFormat = "P2WPKH"
+	CashAddr   AddressFormat = "CASHADDR"
+	Ethereum   AddressFormat = "ETHEREUM"
+	Tron       AddressFormat = "TRON"
+)
+
+const tronAddressPrefix = 0x41
+
+// ChainParams ... Address prefixes of a bitcoin style chain
+type ChainParams struct {
+	PubKeyHashPrefix byte
+	ScriptHashPrefix byte
+	Bech32HRP        string
+	CashAddrPrefix   string
+}
+
+// EncodeAddress ... Encodes the public key of a derived key in the given address format
+func EncodeAddress(key *ExtendedKey, format AddressFormat, params ChainParams) (string, error) {
+	switch format {
+	case P2PKH:
+		return Base58CheckEncode(append([]byte{params.PubKeyHashPrefix}, Hash160(key.PublicKey())...)), nil
+	case P2SHP2WPKH:
+		redeemScript := append([]byte{0x00, 0x14}, Hash160(key.PublicKey())...)
+		return Base58CheckEncode(append([]byte{params.ScriptHashPrefix}, Hash160(redeemScript)...)), nil
+	case P2WPKH:
+		if params.Bech32HRP == "" {
+			return "", errors.New("chain does not support segwit addresses")
+		}
+		return SegwitEncode(params.Bech32HRP, Hash160(key.PublicKey())), nil
+	case CashAddr:
+		if params.CashAddrPrefix == "" {
+			return "", errors.New("chain does not support cashaddr addresses")
+		}
+		return CashAddrEncode(params.CashAddrPrefix, Hash160(key.PublicKey())), nil
+	case Ethereum:
+		return checksumEthereumAddress(accountHash(key)), nil
+	case Tron:
+		return Base58CheckEncode(append([]byte{tronAddressPrefix}, accountHash(key)...)), nil
+	}
+	return "", errors.New("unsupported address format")
+}
+
+// accountHash is the last 20 bytes of the keccak hash of the uncompressed key without its prefix byte
+func accountHash(key *ExtendedKey) []byte {
+	return Keccak256(key.UncompressedPublicKey()[1:])[12:]
+}
+
+// checksumEthereumAddress applies the EIP-55 mixed case checksum
+func checksumEthereumAddress(address []byte) string {
+	lower := hex.EncodeToString(address)
+	hash := hex.EncodeToString(Keccak256([]byte(lower)))
+
+	var checksummed strings.Builder
+	checksummed.WriteString("0x")
+	for i, char := range lower {
+		if char >= 'a' && hash[i] >= '8' {
+			checksummed.WriteRune(char - 32)
+			continue
+		}
+		checksummed.WriteRune(char)
+	}
+	return checksummed.String()
+}
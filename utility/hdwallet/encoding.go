@@ -0,0 +1,184 @@
+package hdwallet
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+const (
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	bech32Charset  = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+var errInvalidChecksum = errors.New("invalid base58 checksum")
+
+// Base58Encode ...
+func Base58Encode(data []byte) string {
+	number := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	modulus := new(big.Int)
+
+	var encoded []byte
+	for number.Sign() > 0 {
+		number.DivMod(number, radix, modulus)
+		encoded = append(encoded, base58Alphabet[modulus.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+// Base58Decode ...
+func Base58Decode(encoded string) ([]byte, error) {
+	number := new(big.Int)
+	radix := big.NewInt(58)
+	for _, char := range encoded {
+		index := strings.IndexRune(base58Alphabet, char)
+		if index < 0 {
+			return nil, errors.New("invalid base58 character")
+		}
+		number.Mul(number, radix)
+		number.Add(number, big.NewInt(int64(index)))
+	}
+
+	decoded := number.Bytes()
+	leadingZeros := 0
+	for leadingZeros < len(encoded) && encoded[leadingZeros] == base58Alphabet[0] {
+		leadingZeros++
+	}
+	return append(make([]byte, leadingZeros), decoded...), nil
+}
+
+// Base58CheckEncode ... Appends the first four bytes of the double sha256 of the payload before encoding
+func Base58CheckEncode(payload []byte) string {
+	checksum := doubleSha256(payload)[:4]
+	return Base58Encode(append(append([]byte{}, payload...), checksum...))
+}
+
+// Base58CheckDecode ...
+func Base58CheckDecode(encoded string) ([]byte, error) {
+	decoded, err := Base58Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) < 4 {
+		return nil, errInvalidChecksum
+	}
+	payload, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	if !bytes.Equal(doubleSha256(payload)[:4], checksum) {
+		return nil, errInvalidChecksum
+	}
+	return payload, nil
+}
+
+// convertBits regroups a byte slice from one bit width to another, padding the final group when asked
+func convertBits(data []byte, fromBits, toBits uint, pad bool) []byte {
+	var accumulator, bitCount uint
+	maxValue := uint(1)<<toBits - 1
+	var converted []byte
+	for _, value := range data {
+		accumulator = accumulator<<fromBits | uint(value)
+		bitCount += fromBits
+		for bitCount >= toBits {
+			bitCount -= toBits
+			converted = append(converted, byte(accumulator>>bitCount&maxValue))
+		}
+	}
+	if pad && bitCount > 0 {
+		converted = append(converted, byte(accumulator<<(toBits-bitCount)&maxValue))
+	}
+	return converted
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generators := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	checksum := uint32(1)
+	for _, value := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(value)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				checksum ^= generators[i]
+			}
+		}
+	}
+	return checksum
+}
+
+// SegwitEncode ... Encodes a version 0 witness program as a bech32 address
+func SegwitEncode(hrp string, program []byte) string {
+	data := append([]byte{0}, convertBits(program, 8, 5, true)...)
+
+	values := make([]byte, 0, len(hrp)*2+1+len(data)+6)
+	for _, char := range hrp {
+		values = append(values, byte(char>>5))
+	}
+	values = append(values, 0)
+	for _, char := range hrp {
+		values = append(values, byte(char&31))
+	}
+	values = append(values, data...)
+	values = append(values, make([]byte, 6)...)
+	polymod := bech32Polymod(values) ^ 1
+
+	var encoded strings.Builder
+	encoded.WriteString(hrp)
+	encoded.WriteByte('1')
+	for _, value := range data {
+		encoded.WriteByte(bech32Charset[value])
+	}
+	for i := 0; i < 6; i++ {
+		encoded.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return encoded.String()
+}
+
+func cashAddrPolymod(values []byte) uint64 {
+	generators := [5]uint64{0x98f2bc8e61, 0x79b76d99e2, 0xf33e5fb3c4, 0xae2eabe2a8, 0x1e4f43e470}
+	checksum := uint64(1)
+	for _, value := range values {
+		top := checksum >> 35
+		checksum = (checksum&0x07ffffffff)<<5 ^ uint64(value)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				checksum ^= generators[i]
+			}
+		}
+	}
+	return checksum ^ 1
+}
+
+// CashAddrEncode ... Encodes a pay-to-public-key-hash for bitcoin cash in the cashaddr format
+func CashAddrEncode(prefix string, hash160 []byte) string {
+	// version byte 0 is a P2KH with a 160 bit hash
+	payload := convertBits(append([]byte{0}, hash160...), 8, 5, true)
+
+	values := make([]byte, 0, len(prefix)+1+len(payload)+8)
+	for _, char := range prefix {
+		values = append(values, byte(char&31))
+	}
+	values = append(values, 0)
+	values = append(values, payload...)
+	values = append(values, make([]byte, 8)...)
+	polymod := cashAddrPolymod(values)
+
+	var encoded strings.Builder
+	encoded.WriteString(prefix)
+	encoded.WriteByte(':')
+	for _, value := range payload {
+		encoded.WriteByte(bech32Charset[value])
+	}
+	for i := 0; i < 8; i++ {
+		encoded.WriteByte(bech32Charset[(polymod>>uint(5*(7-i)))&31])
+	}
+	return encoded.String()
+}
@@ -0,0 +1,132 @@
+package hdwallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// HardenedKeyStart ... Child indexes from here on need the private key and cannot be derived from an xpub
+const HardenedKeyStart = uint32(0x80000000)
+
+var (
+	// ErrInvalidChild ... The derived key is unusable (probability below 2^-127), callers move on to the next index
+	ErrInvalidChild  = errors.New("invalid child key, skip to the next index")
+	ErrHardenedChild = errors.New("hardened child keys cannot be derived from an extended public key")
+	ErrPrivateKey    = errors.New("extended private keys are not accepted, configure the account xpub")
+	ErrKeyLength     = errors.New("invalid extended key length")
+)
+
+// ExtendedKey ... Watch-only BIP32 extended public key
+type ExtendedKey struct {
+	version           []byte
+	depth             byte
+	parentFingerprint []byte
+	childNumber       uint32
+	chainCode         []byte
+	publicKey         *btcec.PublicKey
+}
+
+// ParseExtendedKey ... Parses a base58 serialised extended public key (xpub, ypub, zpub, Ltub, ...)
+func ParseExtendedKey(encoded string) (*ExtendedKey, error) {
+	payload, err := Base58CheckDecode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 78 {
+		return nil, ErrKeyLength
+	}
+	if payload[45] == 0x00 {
+		return nil, ErrPrivateKey
+	}
+	publicKey, err := btcec.ParsePubKey(payload[45:])
+	if err != nil {
+		return nil, err
+	}
+	return &ExtendedKey{
+		version:           payload[:4],
+		depth:             payload[4],
+		parentFingerprint: payload[5:9],
+		childNumber:       binary.BigEndian.Uint32(payload[9:13]),
+		chainCode:         payload[13:45],
+		publicKey:         publicKey,
+	}, nil
+}
+
+// Child ... Derives the non-hardened child public key at index (CKDpub)
+func (key *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if index >= HardenedKeyStart {
+		return nil, ErrHardenedChild
+	}
+
+	parentKey := key.publicKey.SerializeCompressed()
+	data := make([]byte, 37)
+	copy(data, parentKey)
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	mac := hmac.New(sha512.New, key.chainCode)
+	mac.Write(data)
+	digest := mac.Sum(nil)
+
+	var tweak btcec.ModNScalar
+	if overflow := tweak.SetByteSlice(digest[:32]); overflow {
+		return nil, ErrInvalidChild
+	}
+	var tweakPoint, parentPoint, childPoint btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&tweak, &tweakPoint)
+	key.publicKey.AsJacobian(&parentPoint)
+	btcec.AddNonConst(&tweakPoint, &parentPoint, &childPoint)
+	if (childPoint.X.IsZero() && childPoint.Y.IsZero()) || childPoint.Z.IsZero() {
+		return nil, ErrInvalidChild
+	}
+	childPoint.ToAffine()
+
+	return &ExtendedKey{
+		version:           key.version,
+		depth:             key.depth + 1,
+		parentFingerprint: Hash160(parentKey)[:4],
+		childNumber:       index,
+		chainCode:         digest[32:],
+		publicKey:         btcec.NewPublicKey(&childPoint.X, &childPoint.Y),
+	}, nil
+}
+
+// Derive ... Walks a path of non-hardened indexes from this key
+func (key *ExtendedKey) Derive(path ...uint32) (*ExtendedKey, error) {
+	derived := key
+	for _, index := range path {
+		child, err := derived.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		derived = child
+	}
+	return derived, nil
+}
+
+// PublicKey ... SEC1 compressed public key
+func (key *ExtendedKey) PublicKey() []byte {
+	return key.publicKey.SerializeCompressed()
+}
+
+// UncompressedPublicKey ... SEC1 uncompressed public key, used by account based chains
+func (key *ExtendedKey) UncompressedPublicKey() []byte {
+	return key.publicKey.SerializeUncompressed()
+}
+
+// String ... Base58 serialisation of the extended public key
+func (key *ExtendedKey) String() string {
+	payload := make([]byte, 0, 78)
+	payload = append(payload, key.version...)
+	payload = append(payload, key.depth)
+	payload = append(payload, key.parentFingerprint...)
+	childNumber := make([]byte, 4)
+	binary.BigEndian.PutUint32(childNumber, key.childNumber)
+	payload = append(payload, childNumber...)
+	payload = append(payload, key.chainCode...)
+	payload = append(payload, key.publicKey.SerializeCompressed()...)
+	return Base58CheckEncode(payload)
+}
@@ -0,0 +1,29 @@
+package hdwallet
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/crypto/ripemd160"
+	"golang.org/x/crypto/sha3"
+)
+
+// Hash160 ... RIPEMD160(SHA256(data)), the hash behind bitcoin style addresses and key fingerprints
+func Hash160(data []byte) []byte {
+	sha := sha256.Sum256(data)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	return hasher.Sum(nil)
+}
+
+func doubleSha256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
+
+// Keccak256 ... The original keccak padding used by ethereum, not the standardised SHA3-256
+func Keccak256(data []byte) []byte {
+	hasher := sha3.NewLegacyKeccak256()
+	hasher.Write(data)
+	return hasher.Sum(nil)
+}
@@ -2,16 +2,34 @@ package utility
 
 var (
 	Permissions = map[string]string{
-		"GetUserAssets":      "get-assets",
-		"CreateUserAssets":   "create-assets",
-		"CreditUserAsset":    "credit-asset",
-		"DebitUserAsset":     "debit-asset",
-		"InternalTransfer":   "do-internal-transfer",
-		"GetAssetAddress":    "get-address",
-		"GetTransaction":     "get-transactions",
-		"OnChainDeposit":     "on-chain-deposit",
-		"ConfirmTransaction": "confirm-transaction",
-		"ExternalTransfer":   "do-external-transfer",
-		"TriggerFloat":       "trigger-float-management",
+		"GetUserAssets":            "get-assets",
+		"CreateUserAssets":         "create-assets",
+		"CreditUserAsset":          "credit-asset",
+		"DebitUserAsset":           "debit-asset",
+		"InternalTransfer":         "do-internal-transfer",
+		"GetAssetAddress":          "get-address",
+		"GetTransaction":           "get-transactions",
+		"OnChainDeposit":           "on-chain-deposit",
+		"ConfirmTransaction":       "confirm-transaction",
+		"ExternalTransfer":         "do-external-transfer",
+		"TriggerFloat":             "trigger-float-management",
+		"GetDustReport":            "get-dust-report",
+		"ReviewScreeningHold":      "review-screening-hold",
+		"GetTravelRule":            "get-travel-rule",
+		"ManageAddressPool":        "manage-address-pool",
+		"ManageAddresses":          "manage-addresses",
+		"ManageAssetConfig":        "manage-asset-config",
+		"ManageMaintenance":        "manage-maintenance",
+		"GetSweepRuns":             "get-sweep-runs",
+		"ManageGasStation":         "manage-gas-station",
+		"ManageSweepPolicies":      "manage-sweep-policies",
+		"ManageColdWallets":        "manage-cold-wallets",
+		"ApproveColdTransfers":     "approve-cold-transfers",
+		"ManageColdTransferLimits": "manage-cold-transfer-limits",
+		"SimulateFloat":            "simulate-float",
+		"ManageFloatParams":        "manage-float-params",
+		"ManageFundingRequests":    "manage-funding-requests",
+		"ManageBatchPolicies":      "manage-batch-policies",
+		"ManageBatches":            "manage-batches",
 	}
 )
@@ -0,0 +1,83 @@
+// Package qrcode renders payment URIs as QR codes at error correction level M
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	zxingqrcode "github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// quietZone ... Light border in modules required around the symbol
+const quietZone = 4
+
+// QRCode ... Module matrix of an encoded symbol with its quiet zone, one bit per module and set for a dark module
+type QRCode struct {
+	// Size ... Width of the symbol in modules, without the quiet zone
+	Size    int
+	modules *gozxing.BitMatrix
+}
+
+// Encode ... Encodes data picking the smallest version that holds it at error correction level M
+func Encode(data string) (*QRCode, error) {
+	hints := map[gozxing.EncodeHintType]interface{}{
+		gozxing.EncodeHintType_ERROR_CORRECTION: "M",
+		gozxing.EncodeHintType_MARGIN:           quietZone,
+	}
+	// without a requested size the matrix is drawn at one bit per module
+	modules, err := zxingqrcode.NewQRCodeWriter().Encode(data, gozxing.BarcodeFormat_QR_CODE, 0, 0, hints)
+	if err != nil {
+		return nil, err
+	}
+	return &QRCode{Size: modules.GetWidth() - 2*quietZone, modules: modules}, nil
+}
+
+// PNG ... Renders the symbol as a grayscale PNG with scale pixels per module
+func (code *QRCode) PNG(scale int) ([]byte, error) {
+	if scale < 1 {
+		scale = 1
+	}
+	dimension := code.modules.GetWidth() * scale
+	img := image.NewGray(image.Rect(0, 0, dimension, dimension))
+	for y := 0; y < dimension; y++ {
+		for x := 0; x < dimension; x++ {
+			if code.modules.Get(x/scale, y/scale) {
+				img.SetGray(x, y, color.Gray{Y: 0x00})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: 0xFF})
+			}
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// SVG ... Renders the symbol as a single path SVG document with scale units per module
+func (code *QRCode) SVG(scale int) string {
+	if scale < 1 {
+		scale = 1
+	}
+	width := code.modules.GetWidth()
+	dimension := width * scale
+
+	var path strings.Builder
+	for y := 0; y < width; y++ {
+		for x := 0; x < width; x++ {
+			if code.modules.Get(x, y) {
+				fmt.Fprintf(&path, "M%d,%dh%dv%dh-%dz", x*scale, y*scale, scale, scale, scale)
+			}
+		}
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" version="1.1" viewBox="0 0 %d %d" width="%d" height="%d" shape-rendering="crispEdges"><rect width="100%%" height="100%%" fill="#FFFFFF"/><path d="%s" fill="#000000"/></svg>
+`, dimension, dimension, dimension, dimension, path.String())
+}